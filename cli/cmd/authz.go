@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/cli"
+	"github.com/OJ/gobuster/v3/gobusterauthz"
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdAuthz *cobra.Command
+
+func runAuthz(cmd *cobra.Command, args []string) error {
+	globalopts, pluginopts, err := parseAuthzOptions()
+	if err != nil {
+		return fmt.Errorf("error on parsing arguments: %w", err)
+	}
+
+	plugins, err := newTargetPlugins(pluginopts.HTTPOptions, func(url string) (*gobusterauthz.GobusterAuthz, error) {
+		opts := *pluginopts
+		opts.HTTPOptions = pluginopts.ForTarget(url)
+		plugin, err := gobusterauthz.NewGobusterAuthz(globalopts, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error on creating gobusterauthz: %w", err)
+		}
+		return plugin, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log := libgobuster.NewLogger(globalopts.Debug)
+	if err := cli.GobusterTargets(mainContext, globalopts, plugins, log); err != nil {
+		log.Debugf("%#v", err)
+		return fmt.Errorf("error on running gobuster: %w", err)
+	}
+	return nil
+}
+
+func parseAuthzOptions() (*libgobuster.Options, *gobusterauthz.OptionsAuthz, error) {
+	globalopts, err := parseGlobalOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginOpts := gobusterauthz.NewOptionsAuthz()
+
+	httpOpts, err := parseCommonHTTPOptions(cmdAuthz)
+	if err != nil {
+		return nil, nil, err
+	}
+	pluginOpts.Password = httpOpts.Password
+	pluginOpts.URL = httpOpts.URL
+	pluginOpts.Targets = httpOpts.Targets
+	pluginOpts.Overrides = httpOpts.Overrides
+	pluginOpts.UserAgent = httpOpts.UserAgent
+	pluginOpts.Username = httpOpts.Username
+	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
+	pluginOpts.Cookies = httpOpts.Cookies
+	pluginOpts.Timeout = httpOpts.Timeout
+	pluginOpts.FollowRedirect = httpOpts.FollowRedirect
+	pluginOpts.NoTLSValidation = httpOpts.NoTLSValidation
+	pluginOpts.Headers = httpOpts.Headers
+	pluginOpts.Method = httpOpts.Method
+	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
+	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
+	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
+	pluginOpts.NoCanonicalizeHeaders = httpOpts.NoCanonicalizeHeaders
+	pluginOpts.CacheBypass = httpOpts.CacheBypass
+
+	authHeaders, err := cmdAuthz.Flags().GetString("auth-headers")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for auth-headers: %w", err)
+	}
+	pluginOpts.AuthHeaders = nil
+	for _, h := range strings.Split(authHeaders, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			pluginOpts.AuthHeaders = append(pluginOpts.AuthHeaders, h)
+		}
+	}
+
+	pluginOpts.SizeTolerance, err = cmdAuthz.Flags().GetInt64("size-tolerance")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for size-tolerance: %w", err)
+	}
+
+	identities, err := cmdAuthz.Flags().GetStringArray("identity")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for identity: %w", err)
+	}
+	pluginOpts.Identities, err = parseIdentities(identities)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for identity: %w", err)
+	}
+
+	pluginOpts.NoAnonymous, err = cmdAuthz.Flags().GetBool("no-anonymous")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for no-anonymous: %w", err)
+	}
+
+	if err := globalopts.Validate("authz", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
+	return globalopts, pluginOpts, nil
+}
+
+// parseIdentities parses values like "alice=Cookie: session=abc". Headers of
+// values with the same name are added to the same identity
+func parseIdentities(values []string) ([]gobusterauthz.Identity, error) {
+	var identities []gobusterauthz.Identity
+	index := make(map[string]int)
+	for _, v := range values {
+		nameAndHeader := strings.SplitN(v, "=", 2)
+		if len(nameAndHeader) != 2 {
+			return nil, fmt.Errorf("invalid identity %q, expected name=Header: value", v)
+		}
+		name := strings.TrimSpace(nameAndHeader[0])
+		keyAndValue := strings.SplitN(nameAndHeader[1], ":", 2)
+		if name == "" || len(keyAndValue) != 2 || strings.TrimSpace(keyAndValue[0]) == "" {
+			return nil, fmt.Errorf("invalid identity %q, expected name=Header: value", v)
+		}
+		header := libgobuster.HTTPHeader{Name: strings.TrimSpace(keyAndValue[0]), Value: strings.TrimSpace(keyAndValue[1])}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(identities)
+			index[name] = i
+			identities = append(identities, gobusterauthz.Identity{Name: name})
+		}
+		identities[i].Headers = append(identities[i].Headers, header)
+	}
+	return identities, nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdAuthz = &cobra.Command{
+		Use:   "authz",
+		Short: "Uses authorization comparison mode. Requests every path anonymously and with every given identity and reports differences",
+		RunE:  runAuthz,
+	}
+
+	if err := addCommonHTTPOptions(cmdAuthz); err != nil {
+		log.Fatalf("%v", err)
+	}
+	cmdAuthz.Flags().String("auth-headers", "Authorization,Cookie", "Headers which are removed from the anonymous requests, comma separated")
+	cmdAuthz.Flags().StringArray("identity", []string{}, "Additional identity to compare like \"alice=Cookie: session=abc\". Can be used multiple times, headers with the same name belong to the same identity")
+	cmdAuthz.Flags().Bool("no-anonymous", false, "Don't send anonymous requests, only compare the identities")
+	cmdAuthz.Flags().Int64("size-tolerance", 0, "Size difference in bytes of successful responses which is still treated as the same response")
+
+	cmdAuthz.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureGlobalOptions()
+	}
+
+	rootCmd.AddCommand(cmdAuthz)
+}
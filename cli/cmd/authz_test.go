@@ -0,0 +1,28 @@
+package cmd
+
+import "testing"
+
+func TestParseIdentities(t *testing.T) {
+	t.Parallel()
+
+	identities, err := parseIdentities([]string{
+		"alice=Cookie: session=a",
+		"bob=Authorization: Bearer b",
+		"alice=X-Tenant: 1",
+	})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(identities) != 2 || identities[0].Name != "alice" || len(identities[0].Headers) != 2 {
+		t.Fatalf("unexpected identities %+v", identities)
+	}
+	if identities[0].Headers[0].Name != "Cookie" || identities[0].Headers[0].Value != "session=a" {
+		t.Fatalf("unexpected header %+v", identities[0].Headers[0])
+	}
+
+	for _, invalid := range []string{"alice", "=Cookie: a", "alice=Cookie"} {
+		if _, err := parseIdentities([]string{invalid}); err == nil {
+			t.Fatalf("expected an error for %q", invalid)
+		}
+	}
+}
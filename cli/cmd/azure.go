@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OJ/gobuster/v3/cli"
+	"github.com/OJ/gobuster/v3/gobusterazure"
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdAzure *cobra.Command
+
+func runAzure(cmd *cobra.Command, args []string) error {
+	globalopts, pluginopts, err := parseAzureOptions()
+	if err != nil {
+		return fmt.Errorf("error on parsing arguments: %w", err)
+	}
+
+	plugin, err := gobusterazure.NewGobusterAzure(globalopts, pluginopts)
+	if err != nil {
+		return fmt.Errorf("error on creating gobusterazure: %w", err)
+	}
+
+	log := libgobuster.NewLogger(globalopts.Debug)
+	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
+		log.Debugf("%#v", err)
+		return fmt.Errorf("error on running gobuster: %w", err)
+	}
+	return nil
+}
+
+func parseAzureOptions() (*libgobuster.Options, *gobusterazure.OptionsAzure, error) {
+	globalopts, err := parseGlobalOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginopts := gobusterazure.NewOptionsAzure()
+
+	httpOpts, err := parseBasicHTTPOptions(cmdAzure)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginopts.UserAgent = httpOpts.UserAgent
+	pluginopts.Proxy = httpOpts.Proxy
+	pluginopts.Proxies = httpOpts.Proxies
+	pluginopts.ProxyRotation = httpOpts.ProxyRotation
+	pluginopts.Timeout = httpOpts.Timeout
+	pluginopts.NoTLSValidation = httpOpts.NoTLSValidation
+	pluginopts.RetryOnTimeout = httpOpts.RetryOnTimeout
+	pluginopts.RetryAttempts = httpOpts.RetryAttempts
+	pluginopts.RetryWait = httpOpts.RetryWait
+	pluginopts.TLSCertificate = httpOpts.TLSCertificate
+	pluginopts.Limits = httpOpts.Limits
+	pluginopts.TLSPolicy = httpOpts.TLSPolicy
+
+	pluginopts.Headers, err = parseHeaders(cmdAzure)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginopts.MaxFilesToList, err = cmdAzure.Flags().GetInt("maxfiles")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for maxfiles: %w", err)
+	}
+
+	pluginopts.Accounts, err = cmdAzure.Flags().GetStringArray("account")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for account: %w", err)
+	}
+
+	pluginopts.Containers, err = cmdAzure.Flags().GetBool("containers")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for containers: %w", err)
+	}
+	if pluginopts.Containers && len(pluginopts.Accounts) > 0 {
+		return nil, nil, fmt.Errorf("containers can not be used with account, the words are already used as containers")
+	}
+	if pluginopts.Containers && globalopts.Wordlist == "-" {
+		return nil, nil, fmt.Errorf("containers can not be used with a wordlist read from stdin as it is read again for every found account")
+	}
+
+	if err := globalopts.Validate("azure", pluginopts); err != nil {
+		return nil, nil, err
+	}
+
+	return globalopts, pluginopts, nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdAzure = &cobra.Command{
+		Use:   "azure",
+		Short: "Uses azure blob container enumeration mode",
+		RunE:  runAzure,
+	}
+
+	addBasicHTTPOptions(cmdAzure)
+	addHeadersOption(cmdAzure)
+	cmdAzure.Flags().IntP("maxfiles", "m", 5, "max files to list when listing containers (only shown in verbose mode)")
+	cmdAzure.Flags().StringArray("account", []string{}, "Storage account the words are tried as containers of, can be set multiple times. Without it the words are tried as storage accounts")
+	cmdAzure.Flags().Bool("containers", false, "Try the words as containers of every found storage account too")
+
+	cmdAzure.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureGlobalOptions()
+	}
+
+	rootCmd.AddCommand(cmdAzure)
+}
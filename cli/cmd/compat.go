@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// printMigratedFlag prints the migrated command line instead of running it
+const printMigratedFlag = "--print-migrated-command"
+
+// renamedFlags maps old flag names to their new names. The old names still
+// work but print a deprecation warning
+// nolint:gochecknoglobals
+var renamedFlags = map[string]string{
+	"excludestatuscodes": "exclude-status-codes",
+	"useragent":          "user-agent",
+}
+
+// legacyFlags maps the single dash flags of gobuster v2 to the current ones.
+// They are only migrated if the mode was selected with -m as the current
+// parser treats them as grouped shorthands
+// nolint:gochecknoglobals
+var legacyFlags = map[string]string{
+	"-np": "--no-progress",
+	"-to": "--timeout",
+	"-cn": "--show-cname",
+}
+
+// legacyDNSFlags are only valid in dns mode
+// nolint:gochecknoglobals
+var legacyDNSFlags = map[string]string{
+	"-fw": "--wildcard",
+}
+
+// removedLegacyFlags lists the gobuster v2 flags which are gone
+// nolint:gochecknoglobals
+var removedLegacyFlags = map[string]string{
+	"-l":  "the length is always shown",
+	"-fw": "forcing wildcard responses is only supported in dns mode, exclude the status code or the length instead",
+}
+
+// nolint:gochecknoglobals
+var shellSafeRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-.,:/=@%+]+$`)
+
+// migrateArgs rewrites old style command lines to the current ones. It
+// returns the new arguments, warnings about every migrated flag and if the
+// migrated command should only be printed
+func migrateArgs(args []string, subcommands []string) ([]string, []string, bool) {
+	var warnings []string
+	printOnly := false
+
+	var stripped []string
+	for _, a := range args {
+		if a == printMigratedFlag {
+			printOnly = true
+			continue
+		}
+		stripped = append(stripped, a)
+	}
+	args = stripped
+
+	mode, args := legacyMode(args, subcommands)
+	if mode != "" {
+		warnings = append(warnings, fmt.Sprintf("selecting the mode with -m is deprecated, use \"gobuster %s\" instead", mode))
+	}
+
+	var ret []string
+	if mode != "" {
+		ret = append(ret, mode)
+	}
+	for _, a := range args {
+		if mode != "" {
+			if n, ok := legacyFlags[a]; ok {
+				warnings = append(warnings, fmt.Sprintf("%s is deprecated, use %s instead", a, n))
+				ret = append(ret, n)
+				continue
+			}
+			if n, ok := legacyDNSFlags[a]; ok && mode == "dns" {
+				warnings = append(warnings, fmt.Sprintf("%s is deprecated, use %s instead", a, n))
+				ret = append(ret, n)
+				continue
+			}
+			if reason, ok := removedLegacyFlags[a]; ok {
+				warnings = append(warnings, fmt.Sprintf("%s was removed and is ignored: %s", a, reason))
+				continue
+			}
+		}
+
+		if strings.HasPrefix(a, "--") {
+			name, value, hasValue := strings.Cut(strings.TrimPrefix(a, "--"), "=")
+			if n, ok := renamedFlags[name]; ok {
+				warnings = append(warnings, fmt.Sprintf("--%s is deprecated, use --%s instead", name, n))
+				a = "--" + n
+				if hasValue {
+					a = fmt.Sprintf("%s=%s", a, value)
+				}
+			}
+		}
+		ret = append(ret, a)
+	}
+
+	return ret, warnings, printOnly
+}
+
+// legacyMode returns the mode selected with -m before any subcommand and the
+// arguments without it
+func legacyMode(args []string, subcommands []string) (string, []string) {
+	for i, a := range args {
+		for _, s := range subcommands {
+			if a == s {
+				return "", args
+			}
+		}
+		if (a == "-m" || a == "-mode") && i+1 < len(args) {
+			ret := append([]string{}, args[:i]...)
+			return args[i+1], append(ret, args[i+2:]...)
+		}
+	}
+	return "", args
+}
+
+// shellJoin joins the arguments to a command line which can be pasted into a shell
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if shellSafeRegex.MatchString(a) {
+			quoted[i] = a
+		} else {
+			quoted[i] = fmt.Sprintf("'%s'", strings.ReplaceAll(a, "'", `'\''`))
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrateArgs(t *testing.T) {
+	t.Parallel()
+
+	subcommands := []string{"dir", "dns", "fuzz"}
+
+	tt := []struct {
+		testName  string
+		args      []string
+		expected  []string
+		warnings  int
+		printOnly bool
+	}{
+		{"Current", []string{"dir", "-u", "http://localhost", "-m", "POST", "-np"}, []string{"dir", "-u", "http://localhost", "-m", "POST", "-np"}, 0, false},
+		{"Renamed", []string{"fuzz", "--excludestatuscodes", "404", "--useragent=test"}, []string{"fuzz", "--exclude-status-codes", "404", "--user-agent=test"}, 2, false},
+		{"Legacy Dir", []string{"-m", "dir", "-u", "http://localhost", "-np", "-fw", "-l"}, []string{"dir", "-u", "http://localhost", "--no-progress"}, 4, false},
+		{"Legacy DNS", []string{"-w", "words", "-mode", "dns", "-fw", "-to", "1s"}, []string{"dns", "-w", "words", "--wildcard", "--timeout", "1s"}, 3, false},
+		{"Print", []string{"dir", "--print-migrated-command"}, []string{"dir"}, 0, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			args, warnings, printOnly := migrateArgs(x.args, subcommands)
+			if !reflect.DeepEqual(args, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, args)
+			}
+			if len(warnings) != x.warnings {
+				t.Fatalf("expected %d warnings, got %v", x.warnings, warnings)
+			}
+			if printOnly != x.printOnly {
+				t.Fatalf("expected printOnly %t, got %t", x.printOnly, printOnly)
+			}
+		})
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	t.Parallel()
+
+	got := shellJoin([]string{"gobuster", "dir", "-H", "X-Test: it's", "-u", "http://localhost:8080/"})
+	expected := `gobuster dir -H 'X-Test: it'\''s' -u http://localhost:8080/`
+	if got != expected {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
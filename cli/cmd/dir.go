@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/OJ/gobuster/v3/cli"
 	"github.com/OJ/gobuster/v3/gobusterdir"
@@ -20,13 +21,37 @@ func runDir(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error on parsing arguments: %w", err)
 	}
 
-	plugin, err := gobusterdir.NewGobusterDir(globalopts, pluginopts)
+	plugins, err := newTargetPlugins(pluginopts.HTTPOptions, func(url string) (*gobusterdir.GobusterDir, error) {
+		// the detections only change the options of their target
+		opts := *pluginopts
+		opts.HTTPOptions = pluginopts.ForTarget(url)
+		opts.ExtensionsParsed = pluginopts.ExtensionsParsed.Clone()
+		opts.TechPaths = append([]string(nil), pluginopts.TechPaths...)
+
+		plugin, err := gobusterdir.NewGobusterDir(globalopts, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error on creating gobusterdir: %w", err)
+		}
+
+		if opts.Tech == gobusterdir.TechAuto {
+			if err := plugin.DetectTech(mainContext); err != nil {
+				return nil, fmt.Errorf("error on detecting the technology: %w", err)
+			}
+		}
+
+		if opts.DetectCase && !opts.CaseInsensitive {
+			if err := plugin.DetectCaseInsensitive(mainContext); err != nil {
+				return nil, fmt.Errorf("error on detecting case insensitivity: %w", err)
+			}
+		}
+		return plugin, nil
+	})
 	if err != nil {
-		return fmt.Errorf("error on creating gobusterdir: %w", err)
+		return err
 	}
 
 	log := libgobuster.NewLogger(globalopts.Debug)
-	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
+	if err := cli.GobusterTargets(mainContext, globalopts, plugins, log); err != nil {
 		var wErr *gobusterdir.ErrWildcard
 		if errors.As(err, &wErr) {
 			return fmt.Errorf("%w. To continue please exclude the status code or the length", wErr)
@@ -51,9 +76,13 @@ func parseDirOptions() (*libgobuster.Options, *gobusterdir.OptionsDir, error) {
 	}
 	pluginOpts.Password = httpOpts.Password
 	pluginOpts.URL = httpOpts.URL
+	pluginOpts.Targets = httpOpts.Targets
+	pluginOpts.Overrides = httpOpts.Overrides
 	pluginOpts.UserAgent = httpOpts.UserAgent
 	pluginOpts.Username = httpOpts.Username
 	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
 	pluginOpts.Cookies = httpOpts.Cookies
 	pluginOpts.Timeout = httpOpts.Timeout
 	pluginOpts.FollowRedirect = httpOpts.FollowRedirect
@@ -62,8 +91,12 @@ func parseDirOptions() (*libgobuster.Options, *gobusterdir.OptionsDir, error) {
 	pluginOpts.Method = httpOpts.Method
 	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
 	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
 	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
 	pluginOpts.NoCanonicalizeHeaders = httpOpts.NoCanonicalizeHeaders
+	pluginOpts.CacheBypass = httpOpts.CacheBypass
 
 	pluginOpts.Extensions, err = cmdDir.Flags().GetString("extensions")
 	if err != nil {
@@ -89,6 +122,48 @@ func parseDirOptions() (*libgobuster.Options, *gobusterdir.OptionsDir, error) {
 		pluginOpts.ExtensionsParsed.AddRange(extensions)
 	}
 
+	pluginOpts.ExcludeExtensions, err = cmdDir.Flags().GetString("exclude-extensions")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for exclude-extensions: %w", err)
+	}
+	excluded, err := libgobuster.ParseExtensions(strings.ToLower(pluginOpts.ExcludeExtensions))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for exclude-extensions: %w", err)
+	}
+	pluginOpts.ExcludeExtensionsParsed = excluded
+
+	pluginOpts.ExtensionsOnly, err = cmdDir.Flags().GetBool("extensions-only")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for extensions-only: %w", err)
+	}
+
+	tech, err := cmdDir.Flags().GetString("tech")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for tech: %w", err)
+	}
+	if tech == gobusterdir.TechAuto {
+		// detected when the plugin is created
+		pluginOpts.Tech = tech
+	} else if tech != "" {
+		preset, err := gobusterdir.GetTechPreset(tech)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value for tech: %w", err)
+		}
+		pluginOpts.ApplyTechPreset(preset)
+	}
+
+	caseInsensitive, err := cmdDir.Flags().GetBool("case-insensitive")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for case-insensitive: %w", err)
+	}
+	// presets might already have set it
+	pluginOpts.CaseInsensitive = pluginOpts.CaseInsensitive || caseInsensitive
+
+	pluginOpts.DetectCase, err = cmdDir.Flags().GetBool("detect-case")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for detect-case: %w", err)
+	}
+
 	// parse normal status codes
 	pluginOpts.StatusCodes, err = cmdDir.Flags().GetString("status-codes")
 	if err != nil {
@@ -111,20 +186,16 @@ func parseDirOptions() (*libgobuster.Options, *gobusterdir.OptionsDir, error) {
 	}
 	pluginOpts.StatusCodesBlacklistParsed = ret3
 
-	if pluginOpts.StatusCodes != "" && pluginOpts.StatusCodesBlacklist != "" {
-		return nil, nil, fmt.Errorf("status-codes (%q) and status-codes-blacklist (%q) are both set - please set only one. status-codes-blacklist is set by default so you might want to disable it by supplying an empty string.",
-			pluginOpts.StatusCodes, pluginOpts.StatusCodesBlacklist)
-	}
-
-	if pluginOpts.StatusCodes == "" && pluginOpts.StatusCodesBlacklist == "" {
-		return nil, nil, fmt.Errorf("status-codes and status-codes-blacklist are both not set, please set one")
-	}
-
 	pluginOpts.UseSlash, err = cmdDir.Flags().GetBool("add-slash")
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for add-slash: %w", err)
 	}
 
+	pluginOpts.SlashDiff, err = cmdDir.Flags().GetBool("slash-diff")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for slash-diff: %w", err)
+	}
+
 	pluginOpts.Expanded, err = cmdDir.Flags().GetBool("expanded")
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for expanded: %w", err)
@@ -149,12 +220,82 @@ func parseDirOptions() (*libgobuster.Options, *gobusterdir.OptionsDir, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for exclude-length: %w", err)
 	}
-	ret4, err := libgobuster.ParseCommaSeparatedInt(pluginOpts.ExcludeLength)
+	ret4, err := libgobuster.ParseIntRanges(pluginOpts.ExcludeLength)
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for exclude-length: %w", err)
 	}
 	pluginOpts.ExcludeLengthParsed = ret4
 
+	pluginOpts.AssessHeaders, err = cmdDir.Flags().GetBool("assess-headers")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for assess-headers: %w", err)
+	}
+
+	pluginOpts.CheckCachePoisoning, err = cmdDir.Flags().GetBool("check-cache-poisoning")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for check-cache-poisoning: %w", err)
+	}
+
+	pluginOpts.WaybackSeed, err = cmdDir.Flags().GetBool("wayback-seed")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for wayback-seed: %w", err)
+	}
+
+	pluginOpts.Crawl, err = cmdDir.Flags().GetBool("crawl")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for crawl: %w", err)
+	}
+
+	encoder, err := cmdDir.Flags().GetString("encoder")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for encoder: %w", err)
+	}
+	pluginOpts.Encoder, err = libgobuster.ParseEncoderChain(encoder)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for encoder: %w", err)
+	}
+
+	pluginOpts.ExtractJS, err = cmdDir.Flags().GetBool("extract-js")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for extract-js: %w", err)
+	}
+
+	pluginOpts.CrawlDepth, err = cmdDir.Flags().GetInt("crawl-depth")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for crawl-depth: %w", err)
+	}
+	if pluginOpts.CrawlDepth < 1 {
+		return nil, nil, fmt.Errorf("crawl-depth must be bigger than 0")
+	}
+
+	pluginOpts.CrawlMaxPages, err = cmdDir.Flags().GetInt("crawl-max-pages")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for crawl-max-pages: %w", err)
+	}
+	if pluginOpts.CrawlMaxPages < 0 {
+		return nil, nil, fmt.Errorf("crawl-max-pages must be bigger or equal to 0")
+	}
+
+	pluginOpts.CertInfo, err = cmdDir.Flags().GetBool("cert-info")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for cert-info: %w", err)
+	}
+
+	pluginOpts.Depth, err = cmdDir.Flags().GetInt("depth")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for depth: %w", err)
+	}
+	if pluginOpts.Depth < 0 {
+		return nil, nil, fmt.Errorf("depth must be bigger or equal to 0")
+	}
+	if pluginOpts.Depth > 0 && globalopts.Wordlist == "-" {
+		return nil, nil, fmt.Errorf("depth can not be used with a wordlist read from stdin as it is read again for every found directory")
+	}
+
+	if err := globalopts.Validate("dir", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
 	return globalopts, pluginOpts, nil
 }
 
@@ -173,12 +314,32 @@ func init() {
 	cmdDir.Flags().StringP("status-codes-blacklist", "b", "404", "Negative status codes (will override status-codes if set). Can also handle ranges like 200,300-400,404.")
 	cmdDir.Flags().StringP("extensions", "x", "", "File extension(s) to search for")
 	cmdDir.Flags().StringP("extensions-file", "X", "", "Read file extension(s) to search from the file")
+	cmdDir.Flags().String("exclude-extensions", "", "Extension(s) which are never appended, even if set by -x, -X or --tech. Words already ending in one of them (e.g. logo.png) are tried without appending extensions")
+	cmdDir.Flags().Bool("extensions-only", false, "Only try the words with the extensions appended, not the bare words")
+	cmdDir.Flags().String("tech", "", fmt.Sprintf("Technology preset adding typical extensions and paths and adjusting the casing behaviour (%s). auto detects the technology of the target", strings.Join(gobusterdir.TechPresetNames(), ", ")))
+	cmdDir.Flags().Bool("case-insensitive", false, "The target ignores the case of paths (like IIS), case variants of already checked words are skipped")
+	cmdDir.Flags().Bool("detect-case", false, "Detect if the target ignores the case of paths and skip case variants of already checked words if so")
 	cmdDir.Flags().BoolP("expanded", "e", false, "Expanded mode, print full URLs")
 	cmdDir.Flags().BoolP("no-status", "n", false, "Don't print status codes")
 	cmdDir.Flags().Bool("hide-length", false, "Hide the length of the body in the output")
 	cmdDir.Flags().BoolP("add-slash", "f", false, "Append / to each request")
+	cmdDir.Flags().Bool("slash-diff", false, "Also request every word with a trailing / and report it if the response differs (like 404 vs 403), revealing hidden directories")
+	cmdDir.Flags().Int("depth", 0, "Brute force found directories again with the same wordlist up to this depth (0 disables the recursion)")
 	cmdDir.Flags().BoolP("discover-backup", "d", false, "Also search for backup files by appending multiple backup extensions")
-	cmdDir.Flags().String("exclude-length", "", "exclude the following content lengths (completely ignores the status). You can separate multiple lengths by comma and it also supports ranges like 203-206")
+	cmdDir.Flags().String("exclude-length", "", "exclude the following content lengths (completely ignores the status). You can separate multiple lengths by comma and it also supports ranges like 203-206 or open ranges like 5000-")
+
+	cmdDir.Flags().String("encoder", "", fmt.Sprintf("Encoders applied in order to every word like urlencode,base64. Valid encoders are %s", strings.Join(libgobuster.EncoderNames(), ",")))
+
+	cmdDir.Flags().Bool("assess-headers", false, "Check found entries for permissive CORS, missing security headers and directory listings and tag the results")
+	cmdDir.Flags().Bool("check-cache-poisoning", false, "Request found entries again with canaries in unkeyed headers like X-Forwarded-Host and tag them if a canary is reflected in a cacheable response")
+
+	cmdDir.Flags().Bool("cert-info", false, "Show the subject, issuer, validity and SANs of the certificate of https targets")
+	cmdDir.Flags().Bool("wayback-seed", false, "Check paths archived by the wayback machine before the wordlist")
+
+	cmdDir.Flags().Bool("crawl", false, "Crawl found pages and check the discovered links within the target url")
+	cmdDir.Flags().Bool("extract-js", false, "Extract endpoints from found javascript files and inline scripts and check them within the target url")
+	cmdDir.Flags().Int("crawl-depth", 2, "Maximum link depth when crawling or extracting javascript endpoints")
+	cmdDir.Flags().Int("crawl-max-pages", 500, "Maximum number of pages discovered by crawling or extracting javascript endpoints (0 means no limit)")
 
 	cmdDir.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		configureGlobalOptions()
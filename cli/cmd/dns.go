@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"log"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/OJ/gobuster/v3/cli"
@@ -29,10 +29,6 @@ func runDNS(cmd *cobra.Command, args []string) error {
 
 	log := libgobuster.NewLogger(globalopts.Debug)
 	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
-		var wErr *gobusterdns.ErrWildcard
-		if errors.As(err, &wErr) {
-			return fmt.Errorf("%w. To force processing of Wildcard DNS, specify the '--wildcard' switch", wErr)
-		}
 		log.Debugf("%#v", err)
 		return fmt.Errorf("error on running gobuster: %w", err)
 	}
@@ -61,9 +57,25 @@ func parseDNSOptions() (*libgobuster.Options, *gobusterdns.OptionsDNS, error) {
 		return nil, nil, fmt.Errorf("invalid value for show-cname: %w", err)
 	}
 
-	pluginOpts.WildcardForced, err = cmdDNS.Flags().GetBool("wildcard")
+	pluginOpts.ShowWildcard, err = cmdDNS.Flags().GetBool("show-wildcard")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for show-wildcard: %w", err)
+	}
+
+	pluginOpts.Depth, err = cmdDNS.Flags().GetInt("dns-depth")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for dns-depth: %w", err)
+	}
+	if pluginOpts.Depth < 0 {
+		return nil, nil, fmt.Errorf("dns-depth must be bigger or equal to 0")
+	}
+	if pluginOpts.Depth > 0 && globalopts.Wordlist == "-" {
+		return nil, nil, fmt.Errorf("dns-depth can not be used with a wordlist read from stdin as it is read again for every found name")
+	}
+
+	pluginOpts.Walk, err = cmdDNS.Flags().GetBool("walk")
 	if err != nil {
-		return nil, nil, fmt.Errorf("invalid value for wildcard: %w", err)
+		return nil, nil, fmt.Errorf("invalid value for walk: %w", err)
 	}
 
 	pluginOpts.Timeout, err = cmdDNS.Flags().GetDuration("timeout")
@@ -76,15 +88,153 @@ func parseDNSOptions() (*libgobuster.Options, *gobusterdns.OptionsDNS, error) {
 		return nil, nil, fmt.Errorf("invalid value for resolver: %w", err)
 	}
 
+	resolversFile, err := cmdDNS.Flags().GetString("resolvers-file")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for resolvers-file: %w", err)
+	}
+	if resolversFile != "" {
+		pluginOpts.Resolvers, err = gobusterdns.ReadResolverFile(resolversFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value for resolvers-file: %w", err)
+		}
+	}
+
+	pluginOpts.ResolverSelection, err = cmdDNS.Flags().GetString("resolver-selection")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for resolver-selection: %w", err)
+	}
+	switch pluginOpts.ResolverSelection {
+	case gobusterdns.SelectionRoundRobin, gobusterdns.SelectionLatency:
+	default:
+		return nil, nil, fmt.Errorf("invalid value for resolver-selection: %q is not supported, use %s or %s", pluginOpts.ResolverSelection, gobusterdns.SelectionRoundRobin, gobusterdns.SelectionLatency)
+	}
+
 	pluginOpts.NoFQDN, err = cmdDNS.Flags().GetBool("no-fqdn")
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for no-fqdn: %w", err)
 	}
 
-	if pluginOpts.Resolver != "" && runtime.GOOS == "windows" {
+	pluginOpts.Protocol, err = cmdDNS.Flags().GetString("protocol")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for protocol: %w", err)
+	}
+	pluginOpts.Protocol = strings.ToLower(pluginOpts.Protocol)
+	switch pluginOpts.Protocol {
+	case "udp", "tcp", "tls", "https":
+	default:
+		return nil, nil, fmt.Errorf("invalid value for protocol: %q, must be udp, tcp, tls or https", pluginOpts.Protocol)
+	}
+
+	ednsSize, err := cmdDNS.Flags().GetUint16("edns0-size")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for edns0-size: %w", err)
+	}
+	if ednsSize > 0 && ednsSize < 512 {
+		return nil, nil, fmt.Errorf("edns0-size must be at least 512")
+	}
+	pluginOpts.EDNS0Size = ednsSize
+
+	pluginOpts.NoTCPFallback, err = cmdDNS.Flags().GetBool("no-tcp-fallback")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for no-tcp-fallback: %w", err)
+	}
+
+	pluginOpts.RawResolver, err = cmdDNS.Flags().GetBool("raw-resolver")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for raw-resolver: %w", err)
+	}
+
+	pluginOpts.Sockets, err = cmdDNS.Flags().GetInt("sockets")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for sockets: %w", err)
+	}
+	if pluginOpts.Sockets <= 0 {
+		return nil, nil, fmt.Errorf("sockets must be greater than 0")
+	}
+
+	pluginOpts.Attempts, err = cmdDNS.Flags().GetInt("attempts")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for attempts: %w", err)
+	}
+	if pluginOpts.Attempts <= 0 {
+		return nil, nil, fmt.Errorf("attempts must be greater than 0")
+	}
+
+	pluginOpts.ProbeHTTP, err = cmdDNS.Flags().GetBool("probe-http")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for probe-http: %w", err)
+	}
+
+	pluginOpts.ProbeThreads, err = cmdDNS.Flags().GetInt("probe-threads")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for probe-threads: %w", err)
+	}
+	if pluginOpts.ProbeThreads <= 0 {
+		return nil, nil, fmt.Errorf("probe-threads must be greater than 0")
+	}
+
+	pluginOpts.ProbeTimeout, err = cmdDNS.Flags().GetDuration("probe-timeout")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for probe-timeout: %w", err)
+	}
+
+	pluginOpts.CertInfo, err = cmdDNS.Flags().GetBool("cert-info")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for cert-info: %w", err)
+	}
+
+	pluginOpts.TopPorts, err = cmdDNS.Flags().GetInt("top-ports")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for top-ports: %w", err)
+	}
+	if pluginOpts.TopPorts < 0 || pluginOpts.TopPorts > gobusterdns.MaxTopPorts() {
+		return nil, nil, fmt.Errorf("top-ports must be between 0 and %d", gobusterdns.MaxTopPorts())
+	}
+
+	pluginOpts.PortThreads, err = cmdDNS.Flags().GetInt("port-threads")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for port-threads: %w", err)
+	}
+	if pluginOpts.PortThreads <= 0 {
+		return nil, nil, fmt.Errorf("port-threads must be greater than 0")
+	}
+
+	pluginOpts.PortTimeout, err = cmdDNS.Flags().GetDuration("port-timeout")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for port-timeout: %w", err)
+	}
+
+	if pluginOpts.Protocol != "udp" && pluginOpts.NoTCPFallback {
+		return nil, nil, fmt.Errorf("no-tcp-fallback can only be used with the udp protocol")
+	}
+
+	if pluginOpts.RawResolver && (pluginOpts.Protocol != "udp" || pluginOpts.NoFQDN) {
+		return nil, nil, fmt.Errorf("raw-resolver can only be used with the udp protocol and without no-fqdn")
+	}
+
+	customResolver := len(pluginOpts.Servers()) > 0
+	if pluginOpts.Walk && !pluginOpts.UDPResolver() && pluginOpts.Protocol != "https" {
+		return nil, nil, fmt.Errorf("walk needs raw queries, use it with raw-resolver or the https protocol")
+	}
+
+	if pluginOpts.Encrypted() && !customResolver {
+		return nil, nil, fmt.Errorf("the %s protocol needs a resolver or resolvers-file", pluginOpts.Protocol)
+	}
+
+	// the udp and DNS over HTTPS resolvers do not depend on the go resolver
+	udpResolver := (pluginOpts.UDPResolver() || pluginOpts.Protocol == "https") && customResolver
+	if customResolver && !udpResolver && runtime.GOOS == "windows" {
 		return nil, nil, fmt.Errorf("currently can not set custom dns resolver on windows. See https://golang.org/pkg/net/#hdr-Name_Resolution")
 	}
 
+	if pluginOpts.CustomTransport() && !udpResolver && runtime.GOOS == "windows" {
+		return nil, nil, fmt.Errorf("currently can not set protocol, edns0-size or no-tcp-fallback on windows. See https://golang.org/pkg/net/#hdr-Name_Resolution")
+	}
+
+	if err := globalopts.Validate("dns"); err != nil {
+		return nil, nil, err
+	}
+
 	return globalopts, pluginOpts, nil
 }
 
@@ -101,8 +251,30 @@ func init() {
 	cmdDNS.Flags().BoolP("show-cname", "c", false, "Show CNAME records (cannot be used with '-i' option)")
 	cmdDNS.Flags().DurationP("timeout", "", time.Second, "DNS resolver timeout")
 	cmdDNS.Flags().BoolP("wildcard", "", false, "Force continued operation when wildcard found")
+	cmdDNS.Flags().Bool("show-wildcard", false, "Show names resolving to the addresses of a wildcard record, marked as wildcard, instead of filtering them")
+	cmdDNS.Flags().Int("dns-depth", 0, "Brute force the labels below found names again with the same wordlist up to this depth (0 only brute forces the domain)")
+	cmdDNS.Flags().Bool("walk", false, "Walk the NSEC chain of a zone signed with NSEC to enumerate its names, the wordlist is only used if the zone uses NSEC3, is not signed or the chain is incomplete")
 	cmdDNS.Flags().BoolP("no-fqdn", "", false, "Do not automatically add a trailing dot to the domain, so the resolver uses the DNS search domain")
-	cmdDNS.Flags().StringP("resolver", "r", "", "Use custom DNS server (format server.com or server.com:port)")
+	cmdDNS.Flags().StringP("resolver", "r", "", "Use custom DNS server (format server.com or server.com:port, an url like https://dns.google/dns-query with --protocol https)")
+	cmdDNS.Flags().String("resolvers-file", "", "File with one DNS server per line, every query is sent to the next one")
+	cmdDNS.Flags().String("resolver-selection", gobusterdns.SelectionRoundRobin, fmt.Sprintf("How the resolvers of the resolvers file get the queries (%s or %s, which prefers the resolvers answering fastest)", gobusterdns.SelectionRoundRobin, gobusterdns.SelectionLatency))
+	cmdDNS.Flags().String("protocol", "udp", "Protocol used for DNS queries (udp, tcp, tls for DNS over TLS or https for DNS over HTTPS)")
+	cmdDNS.Flags().Uint16("edns0-size", 0, "EDNS0 buffer size advertised in DNS queries (defaults to the size of the go resolver)")
+	cmdDNS.Flags().Bool("no-tcp-fallback", false, "Do not retry over TCP if an UDP answer was truncated")
+	cmdDNS.Flags().Bool("raw-resolver", false, "Send raw queries over a few reused UDP sockets instead of using the resolver of the go standard library. Without a resolver only the first nameserver of /etc/resolv.conf is asked and /etc/hosts is not read")
+	cmdDNS.Flags().Int("sockets", 4, "Number of UDP sockets the queries are spread over")
+	cmdDNS.Flags().Int("attempts", 3, "Times a query is sent before it fails, queries timing out or answered with SERVFAIL are sent again")
+	cmdDNS.Flags().Bool("cert-info", false, "Show the certificate served for the domain on port 443 and check the names of its SANs below the domain first")
+	cmdDNS.Flags().Bool("probe-http", false, "Request every found name over https and http and show the status and title")
+	cmdDNS.Flags().Int("probe-threads", 10, "Number of concurrent http probes")
+	cmdDNS.Flags().Duration("probe-timeout", 5*time.Second, "Timeout of a single http probe")
+	cmdDNS.Flags().Int("top-ports", 0, fmt.Sprintf("Connect to the N most common tcp ports of every found name and show the open ones (max %d)", gobusterdns.MaxTopPorts()))
+	cmdDNS.Flags().Int("port-threads", 50, "Number of concurrent port connections")
+	cmdDNS.Flags().Duration("port-timeout", time.Second, "Timeout of a single port connection")
+	// wildcards no longer stop the run, the flag is kept for existing scripts
+	if err := cmdDNS.Flags().MarkDeprecated("wildcard", "wildcard records are filtered automatically, use --show-wildcard to show the filtered names"); err != nil {
+		log.Fatalf("error on marking flag as deprecated: %v", err)
+	}
 	if err := cmdDNS.MarkFlagRequired("domain"); err != nil {
 		log.Fatalf("error on marking flag as required: %v", err)
 	}
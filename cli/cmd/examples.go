@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/gobusterfuzz"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// nolint:gochecknoglobals
+var cmdExamples *cobra.Command
+
+// example is a curated invocation of a mode. The command line is assembled
+// from the flag definitions of the mode, so a renamed or removed flag fails
+// instead of printing a stale example
+type example struct {
+	description string
+	flags       []exampleFlag
+}
+
+// exampleFlag is a flag of an example, the value is empty for boolean flags
+type exampleFlag struct {
+	name  string
+	value string
+}
+
+// nolint:gochecknoglobals
+var modeExamples = map[string][]example{
+	"dir": {
+		{"Find directories and php files", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "words.txt"}, {"extensions", "php"}}},
+		{"Recurse into found directories and hide 404-like responses of 1234 bytes", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "words.txt"}, {"depth", "2"}, {"exclude-length", "1234"}}},
+		{"Crawl found pages and assess the headers of the hits", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "words.txt"}, {"crawl", ""}, {"assess-headers", ""}}},
+		{"Scan every url of a file one after another", []exampleFlag{{"url", "targets.txt"}, {"wordlist", "words.txt"}}},
+		{"Scan through a proxy with a client certificate", []exampleFlag{{"url", "https://admin.example.com"}, {"wordlist", "words.txt"}, {"proxy", "http://127.0.0.1:8080"}, {"client-cert-pem", "client.pem"}}},
+	},
+	"dns": {
+		{"Find subdomains and show their addresses", []exampleFlag{{"domain", "example.com"}, {"wordlist", "subdomains.txt"}, {"show-ips", ""}}},
+		{"Query a custom resolver and probe found names over http", []exampleFlag{{"domain", "example.com"}, {"wordlist", "subdomains.txt"}, {"resolver", "1.1.1.1"}, {"probe-http", ""}}},
+	},
+	"vhost": {
+		{"Find virtual hosts by appending the domain of the url to every word", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "vhosts.txt"}, {"append-domain", ""}}},
+	},
+	"fuzz": {
+		{"Fuzz a query parameter and exclude 404 responses", []exampleFlag{{"url", "https://example.com/?id=" + gobusterfuzz.FuzzKeyword}, {"wordlist", "ids.txt"}, {"exclude-status-codes", "404"}}},
+		{"Fuzz a login form", []exampleFlag{{"url", "https://example.com/login"}, {"wordlist", "passwords.txt"}, {"method", "POST"}, {"body", "user=admin&password=" + gobusterfuzz.FuzzKeyword}}},
+	},
+	"s3": {
+		{"Find public S3 buckets", []exampleFlag{{"wordlist", "buckets.txt"}}},
+	},
+	"gcs": {
+		{"Find public Google Cloud Storage buckets", []exampleFlag{{"wordlist", "buckets.txt"}}},
+	},
+	"azure": {
+		{"Find Azure storage accounts and their publicly listable containers", []exampleFlag{{"wordlist", "names.txt"}, {"containers", ""}}},
+		{"Find publicly listable containers of a known storage account", []exampleFlag{{"wordlist", "containers.txt"}, {"account", "examplecorp"}}},
+	},
+	"tftp": {
+		{"Find files on a TFTP server", []exampleFlag{{"server", "10.0.0.1"}, {"wordlist", "files.txt"}}},
+	},
+	"methods": {
+		{"Check paths for dangerous methods and WebDAV without changing anything", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "paths.txt"}}},
+	},
+	"authz": {
+		{"Compare anonymous access with a logged in user", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "paths.txt"}, {"identity", "alice=Cookie: session=abc"}}},
+	},
+	"passive": {
+		{"Collect subdomains of the domains in the wordlist from crt.sh and the wayback machine", []exampleFlag{{"wordlist", "domains.txt"}, {"sources", "crtsh,wayback"}}},
+	},
+	"grpc": {
+		{"Run an out-of-process plugin", []exampleFlag{{"plugin-cmd", "./myplugin"}, {"wordlist", "words.txt"}, {"plugin-option", "mode=fast"}}},
+	},
+	"wasm": {
+		{"Run a WebAssembly plugin", []exampleFlag{{"url", "https://example.com"}, {"wordlist", "words.txt"}, {"plugin", "plugin.wasm"}}},
+	},
+}
+
+// lookupModeFlag finds the flag of a mode, including the global flags
+func lookupModeFlag(mode *cobra.Command, name string) *pflag.Flag {
+	if f := mode.Flags().Lookup(name); f != nil {
+		return f
+	}
+	return mode.InheritedFlags().Lookup(name)
+}
+
+// exampleCommandLine assembles the command line of an example. It fails if
+// the example uses a flag the mode does not have, gives a value to a boolean
+// flag or misses a required flag
+func exampleCommandLine(mode *cobra.Command, e example) (string, error) {
+	args := []string{rootCmd.Name(), mode.Name()}
+	set := make(map[string]bool)
+	for _, ef := range e.flags {
+		f := lookupModeFlag(mode, ef.name)
+		if f == nil {
+			return "", fmt.Errorf("example %q of %s uses the unknown flag --%s", e.description, mode.Name(), ef.name)
+		}
+		isBool := f.Value.Type() == "bool"
+		if isBool != (ef.value == "") {
+			return "", fmt.Errorf("example %q of %s has an invalid value for --%s", e.description, mode.Name(), ef.name)
+		}
+		set[f.Name] = true
+		args = append(args, "--"+f.Name)
+		if !isBool {
+			args = append(args, ef.value)
+		}
+	}
+
+	var missing []string
+	mode.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, required := f.Annotations[cobra.BashCompOneRequiredFlag]; required && !set[f.Name] {
+			missing = append(missing, "--"+f.Name)
+		}
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("example %q of %s misses the required flags %s", e.description, mode.Name(), strings.Join(missing, ", "))
+	}
+
+	return shellJoin(args), nil
+}
+
+// exampleModes returns the names of the modes with examples
+func exampleModes() []string {
+	modes := make([]string, 0, len(modeExamples))
+	for mode := range modeExamples {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+func runExamples(cmd *cobra.Command, args []string) error {
+	modes := exampleModes()
+	if len(args) == 1 {
+		if _, ok := modeExamples[args[0]]; !ok {
+			return fmt.Errorf("no examples for mode %q, available modes: %s", args[0], strings.Join(modes, ", "))
+		}
+		modes = []string{args[0]}
+	}
+
+	for i, name := range modes {
+		mode, _, err := rootCmd.Find([]string{name})
+		if err != nil {
+			return fmt.Errorf("could not find mode %s: %w", name, err)
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s - %s\n", name, mode.Short)
+		for _, e := range modeExamples[name] {
+			line, err := exampleCommandLine(mode, e)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\n  # %s\n  %s\n", e.description, line)
+		}
+	}
+	return nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdExamples = &cobra.Command{
+		Use:   "examples [mode]",
+		Short: "shows example invocations of all modes or the given mode",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runExamples,
+	}
+
+	rootCmd.AddCommand(cmdExamples)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestModeExamples(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range scanModes() {
+		examples, ok := modeExamples[mode.Name()]
+		if !ok || len(examples) == 0 {
+			t.Fatalf("mode %s has no examples", mode.Name())
+		}
+		for _, e := range examples {
+			if _, err := exampleCommandLine(mode, e); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+		}
+	}
+}
+
+func TestExampleCommandLine(t *testing.T) {
+	t.Parallel()
+
+	mode := &cobra.Command{Use: "test"}
+	mode.Flags().String("url", "", "")
+	mode.Flags().Bool("crawl", false, "")
+	if err := mode.MarkFlagRequired("url"); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	tt := []struct {
+		testName      string
+		flags         []exampleFlag
+		expected      string
+		expectedError bool
+	}{
+		{"Valid", []exampleFlag{{"url", "https://example.com/?a=b"}, {"crawl", ""}}, "gobuster test --url 'https://example.com/?a=b' --crawl", false},
+		{"Unknown flag", []exampleFlag{{"url", "https://example.com"}, {"missing", "x"}}, "", true},
+		{"Value for a boolean flag", []exampleFlag{{"url", "https://example.com"}, {"crawl", "x"}}, "", true},
+		{"No value", []exampleFlag{{"url", ""}}, "", true},
+		{"Missing required flag", []exampleFlag{{"crawl", ""}}, "", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			line, err := exampleCommandLine(mode, example{description: x.testName, flags: x.flags})
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if line != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, line)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 
 	"github.com/OJ/gobuster/v3/cli"
@@ -21,17 +22,34 @@ func runFuzz(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error on parsing arguments: %w", err)
 	}
 
-	if !containsFuzzKeyword(*pluginopts) {
-		return fmt.Errorf("please provide the %s keyword", gobusterfuzz.FuzzKeyword)
-	}
+	plugins, err := newTargetPlugins(pluginopts.HTTPOptions, func(url string) (*gobusterfuzz.GobusterFuzz, error) {
+		opts := *pluginopts
+		opts.HTTPOptions = pluginopts.ForTarget(url)
+
+		if !containsFuzzKeyword(opts, gobusterfuzz.FuzzKeyword) {
+			return nil, fmt.Errorf("please provide the %s keyword", gobusterfuzz.FuzzKeyword)
+		}
+		if opts.CSRFURL != "" && !containsFuzzKeyword(opts, gobusterfuzz.CSRFPlaceholder) && !containsFuzzKeyword(opts, gobusterfuzz.CSRFPlaceholderEncoded) {
+			return nil, fmt.Errorf("please provide the %s or %s placeholder for the CSRF token", gobusterfuzz.CSRFPlaceholder, gobusterfuzz.CSRFPlaceholderEncoded)
+		}
+		for _, w := range globalopts.ExtraWordlists {
+			if !containsFuzzKeyword(opts, w.Keyword) {
+				return nil, fmt.Errorf("please provide the %s keyword for the wordlist %q", w.Keyword, w.Filename)
+			}
+		}
 
-	plugin, err := gobusterfuzz.NewGobusterFuzz(globalopts, pluginopts)
+		plugin, err := gobusterfuzz.NewGobusterFuzz(globalopts, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error on creating gobusterfuzz: %w", err)
+		}
+		return plugin, nil
+	})
 	if err != nil {
-		return fmt.Errorf("error on creating gobusterfuzz: %w", err)
+		return err
 	}
 
 	log := libgobuster.NewLogger(globalopts.Debug)
-	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
+	if err := cli.GobusterTargets(mainContext, globalopts, plugins, log); err != nil {
 		var wErr *gobusterfuzz.ErrWildcard
 		if errors.As(err, &wErr) {
 			return fmt.Errorf("%w. To continue please exclude the status code or the length", wErr)
@@ -56,9 +74,13 @@ func parseFuzzOptions() (*libgobuster.Options, *gobusterfuzz.OptionsFuzz, error)
 	}
 	pluginOpts.Password = httpOpts.Password
 	pluginOpts.URL = httpOpts.URL
+	pluginOpts.Targets = httpOpts.Targets
+	pluginOpts.Overrides = httpOpts.Overrides
 	pluginOpts.UserAgent = httpOpts.UserAgent
 	pluginOpts.Username = httpOpts.Username
 	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
 	pluginOpts.Cookies = httpOpts.Cookies
 	pluginOpts.Timeout = httpOpts.Timeout
 	pluginOpts.FollowRedirect = httpOpts.FollowRedirect
@@ -67,17 +89,40 @@ func parseFuzzOptions() (*libgobuster.Options, *gobusterfuzz.OptionsFuzz, error)
 	pluginOpts.Method = httpOpts.Method
 	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
 	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
 	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
 	pluginOpts.NoCanonicalizeHeaders = httpOpts.NoCanonicalizeHeaders
+	pluginOpts.CacheBypass = httpOpts.CacheBypass
+
+	pluginOpts.RequestFile, err = cmdFuzz.Flags().GetString("request-file")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for request-file: %w", err)
+	}
+	if pluginOpts.RequestFile != "" {
+		raw, err := libgobuster.LoadRawRequest(pluginOpts.RequestFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		pluginOpts.URL, err = raw.URL(pluginOpts.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		pluginOpts.Method = raw.Method
+		// headers given as parameters override the ones of the request
+		pluginOpts.Headers = append(raw.Headers, pluginOpts.Headers...)
+		pluginOpts.RequestBody = raw.Body
+	}
 
 	// blacklist will override the normal status codes
-	pluginOpts.ExcludedStatusCodes, err = cmdFuzz.Flags().GetString("excludestatuscodes")
+	pluginOpts.ExcludedStatusCodes, err = cmdFuzz.Flags().GetString("exclude-status-codes")
 	if err != nil {
-		return nil, nil, fmt.Errorf("invalid value for excludestatuscodes: %w", err)
+		return nil, nil, fmt.Errorf("invalid value for exclude-status-codes: %w", err)
 	}
 	ret, err := libgobuster.ParseCommaSeparatedInt(pluginOpts.ExcludedStatusCodes)
 	if err != nil {
-		return nil, nil, fmt.Errorf("invalid value for excludestatuscodes: %w", err)
+		return nil, nil, fmt.Errorf("invalid value for exclude-status-codes: %w", err)
 	}
 	pluginOpts.ExcludedStatusCodesParsed = ret
 
@@ -85,16 +130,65 @@ func parseFuzzOptions() (*libgobuster.Options, *gobusterfuzz.OptionsFuzz, error)
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for exclude-length: %w", err)
 	}
-	ret2, err := libgobuster.ParseCommaSeparatedInt(pluginOpts.ExcludeLength)
+	ret2, err := libgobuster.ParseIntRanges(pluginOpts.ExcludeLength)
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for exclude-length: %w", err)
 	}
 	pluginOpts.ExcludeLengthParsed = ret2
 
-	pluginOpts.RequestBody, err = cmdFuzz.Flags().GetString("body")
+	body, err := cmdFuzz.Flags().GetString("body")
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for body: %w", err)
 	}
+	if body != "" || pluginOpts.RequestFile == "" {
+		pluginOpts.RequestBody = body
+	}
+
+	extraWordlists, err := cmdFuzz.Flags().GetStringArray("extra-wordlist")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for extra-wordlist: %w", err)
+	}
+	globalopts.ExtraWordlists, err = parseExtraWordlists(extraWordlists)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	globalopts.Strategy, err = cmdFuzz.Flags().GetString("strategy")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for strategy: %w", err)
+	}
+
+	encoders, err := cmdFuzz.Flags().GetStringArray("encoder")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for encoder: %w", err)
+	}
+	if err := parseFuzzEncoders(encoders, globalopts.ExtraWordlists, pluginOpts.Encoders); err != nil {
+		return nil, nil, err
+	}
+
+	pluginOpts.CSRFURL, err = cmdFuzz.Flags().GetString("csrf-url")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for csrf-url: %w", err)
+	}
+
+	pluginOpts.CSRFPattern, err = cmdFuzz.Flags().GetString("csrf-regex")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for csrf-regex: %w", err)
+	}
+
+	pluginOpts.CSRFField, err = cmdFuzz.Flags().GetString("csrf-field")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for csrf-field: %w", err)
+	}
+
+	pluginOpts.CSRFRefresh, err = cmdFuzz.Flags().GetInt("csrf-refresh")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for csrf-refresh: %w", err)
+	}
+
+	if err := globalopts.Validate("fuzz", pluginOpts); err != nil {
+		return nil, nil, err
+	}
 
 	return globalopts, pluginOpts, nil
 }
@@ -103,16 +197,24 @@ func parseFuzzOptions() (*libgobuster.Options, *gobusterfuzz.OptionsFuzz, error)
 func init() {
 	cmdFuzz = &cobra.Command{
 		Use:   "fuzz",
-		Short: fmt.Sprintf("Uses fuzzing mode. Replaces the keyword %s in the URL, Headers and the request body", gobusterfuzz.FuzzKeyword),
+		Short: fmt.Sprintf("Uses fuzzing mode. Replaces the keyword %s in the URL, method, headers, cookies, basic auth credentials and the request body", gobusterfuzz.FuzzKeyword),
 		RunE:  runFuzz,
 	}
 
 	if err := addCommonHTTPOptions(cmdFuzz); err != nil {
 		log.Fatalf("%v", err)
 	}
-	cmdFuzz.Flags().StringP("excludestatuscodes", "b", "", "Excluded status codes. Can also handle ranges like 200,300-400,404.")
-	cmdFuzz.Flags().String("exclude-length", "", "exclude the following content lengths (completely ignores the status). You can separate multiple lengths by comma and it also supports ranges like 203-206")
+	cmdFuzz.Flags().StringP("exclude-status-codes", "b", "", "Excluded status codes. Can also handle ranges like 200,300-400,404.")
+	cmdFuzz.Flags().String("exclude-length", "", "exclude the following content lengths (completely ignores the status). You can separate multiple lengths by comma and it also supports ranges like 203-206 or open ranges like 5000-")
 	cmdFuzz.Flags().StringP("body", "B", "", "Request body")
+	cmdFuzz.Flags().String("request-file", "", "Raw http request used as base request, like the ones exported by Burp. Scheme and host are taken from the url")
+	cmdFuzz.Flags().StringArray("extra-wordlist", []string{}, "Additional wordlist for another keyword like FUZ2Z:passwords.txt, the keyword defaults to FUZ2Z, FUZ3Z, ... in the given order. Can be set multiple times")
+	cmdFuzz.Flags().StringArray("encoder", []string{}, fmt.Sprintf("Encoders applied in order to the words of a keyword like FUZ2Z:base64,urlencode, the keyword defaults to %s. Valid encoders are %s. Can be set multiple times", gobusterfuzz.FuzzKeyword, strings.Join(libgobuster.EncoderNames(), ",")))
+	cmdFuzz.Flags().String("csrf-url", "", fmt.Sprintf("Page the CSRF token is fetched from before fuzzing, the token replaces %s and %s (url encoded) in the request", gobusterfuzz.CSRFPlaceholder, gobusterfuzz.CSRFPlaceholderEncoded))
+	cmdFuzz.Flags().String("csrf-regex", "", "Regular expression extracting the CSRF token from the csrf-url page, the first group is the token")
+	cmdFuzz.Flags().String("csrf-field", "", "Name of the input or meta element holding the CSRF token on the csrf-url page")
+	cmdFuzz.Flags().Int("csrf-refresh", 0, "Fetch a new CSRF token after this many requests, 0 keeps the first token")
+	cmdFuzz.Flags().String("strategy", libgobuster.StrategyClusterbomb, fmt.Sprintf("How to combine multiple wordlists: %s tries all combinations, %s combines them line by line", libgobuster.StrategyClusterbomb, libgobuster.StrategyPitchfork))
 
 	cmdFuzz.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		configureGlobalOptions()
@@ -121,26 +223,85 @@ func init() {
 	rootCmd.AddCommand(cmdFuzz)
 }
 
-func containsFuzzKeyword(pluginopts gobusterfuzz.OptionsFuzz) bool {
-	if strings.Contains(pluginopts.URL, gobusterfuzz.FuzzKeyword) {
+// parseExtraWordlists parses the extra wordlists given as KEYWORD:file or
+// just file, in which case the keywords FUZ2Z, FUZ3Z, ... are used in order
+func parseExtraWordlists(values []string) ([]libgobuster.ExtraWordlist, error) {
+	re := regexp.MustCompile(`^(FUZ\d+Z):(.+)$`)
+	ret := make([]libgobuster.ExtraWordlist, 0, len(values))
+	keywords := libgobuster.NewSet[string]()
+	for i, v := range values {
+		w := libgobuster.ExtraWordlist{
+			Keyword:  fmt.Sprintf("FUZ%dZ", i+2),
+			Filename: v,
+		}
+		if match := re.FindStringSubmatch(v); match != nil {
+			w.Keyword = match[1]
+			w.Filename = match[2]
+		}
+		if !keywords.Add(w.Keyword) {
+			return nil, fmt.Errorf("keyword %s is used for multiple wordlists", w.Keyword)
+		}
+		ret = append(ret, w)
+	}
+	return ret, nil
+}
+
+// parseFuzzEncoders parses the encoder chains given as KEYWORD:chain or just
+// chain for the FUZZ keyword
+func parseFuzzEncoders(values []string, extraWordlists []libgobuster.ExtraWordlist, encoders map[string]libgobuster.EncoderChain) error {
+	keywords := libgobuster.NewSet[string]()
+	keywords.Add(gobusterfuzz.FuzzKeyword)
+	for _, w := range extraWordlists {
+		keywords.Add(w.Keyword)
+	}
+
+	re := regexp.MustCompile(`^(FUZZ|FUZ\d+Z):(.*)$`)
+	for _, v := range values {
+		keyword := gobusterfuzz.FuzzKeyword
+		chain := v
+		if match := re.FindStringSubmatch(v); match != nil {
+			keyword = match[1]
+			chain = match[2]
+		}
+		if !keywords.Contains(keyword) {
+			return fmt.Errorf("encoder for keyword %s without a wordlist", keyword)
+		}
+		if _, ok := encoders[keyword]; ok {
+			return fmt.Errorf("multiple encoders for keyword %s, chain them like urlencode,base64 instead", keyword)
+		}
+		c, err := libgobuster.ParseEncoderChain(chain)
+		if err != nil {
+			return fmt.Errorf("invalid value for encoder: %w", err)
+		}
+		encoders[keyword] = c
+	}
+	return nil
+}
+
+func containsFuzzKeyword(pluginopts gobusterfuzz.OptionsFuzz, keyword string) bool {
+	if strings.Contains(pluginopts.URL, keyword) {
 		return true
 	}
 
-	if strings.Contains(pluginopts.RequestBody, gobusterfuzz.FuzzKeyword) {
+	if strings.Contains(pluginopts.RequestBody, keyword) {
 		return true
 	}
 
 	for _, h := range pluginopts.Headers {
-		if strings.Contains(h.Name, gobusterfuzz.FuzzKeyword) || strings.Contains(h.Value, gobusterfuzz.FuzzKeyword) {
+		if strings.Contains(h.Name, keyword) || strings.Contains(h.Value, keyword) {
 			return true
 		}
 	}
 
-	if strings.Contains(pluginopts.Username, gobusterfuzz.FuzzKeyword) {
+	if strings.Contains(pluginopts.Username, keyword) {
+		return true
+	}
+
+	if strings.Contains(pluginopts.Password, keyword) {
 		return true
 	}
 
-	if strings.Contains(pluginopts.Password, gobusterfuzz.FuzzKeyword) {
+	if strings.Contains(pluginopts.Cookies, keyword) || strings.Contains(pluginopts.Method, keyword) {
 		return true
 	}
 
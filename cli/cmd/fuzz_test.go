@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/gobusterfuzz"
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestParseExtraWordlists(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseExtraWordlists([]string{"users.txt", "FUZ5Z:C:\\passwords.txt"})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	expected := []libgobuster.ExtraWordlist{{Keyword: "FUZ2Z", Filename: "users.txt"}, {Keyword: "FUZ5Z", Filename: "C:\\passwords.txt"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := parseExtraWordlists([]string{"users.txt", "FUZ2Z:passwords.txt"}); err == nil {
+		t.Fatal("expected an error for a duplicate keyword")
+	}
+}
+
+func TestParseFuzzEncoders(t *testing.T) {
+	t.Parallel()
+
+	extra := []libgobuster.ExtraWordlist{{Keyword: "FUZ2Z", Filename: "users.txt"}}
+
+	tt := []struct {
+		testName string
+		values   []string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"Default Keyword", []string{"urlencode"}, map[string]string{"FUZZ": "urlencode"}, false},
+		{"Keywords", []string{"FUZZ:hex", "FUZ2Z:base64,urlencode"}, map[string]string{"FUZZ": "hex", "FUZ2Z": "base64,urlencode"}, false},
+		{"Unknown Keyword", []string{"FUZ3Z:hex"}, nil, true},
+		{"Duplicate", []string{"hex", "FUZZ:base64"}, nil, true},
+		{"Unknown Encoder", []string{"rot13"}, nil, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			encoders := make(map[string]libgobuster.EncoderChain)
+			err := parseFuzzEncoders(x.values, extra, encoders)
+			if x.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			got := make(map[string]string)
+			for k, v := range encoders {
+				got[k] = v.String()
+			}
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestContainsFuzzKeyword(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		opts     gobusterfuzz.OptionsFuzz
+		expected bool
+	}{
+		{"URL", gobusterfuzz.OptionsFuzz{HTTPOptions: libgobuster.HTTPOptions{URL: "http://localhost/api/FUZZ/users"}}, true},
+		{"Cookies", gobusterfuzz.OptionsFuzz{HTTPOptions: libgobuster.HTTPOptions{URL: "http://localhost", Cookies: "role=FUZZ"}}, true},
+		{"Method", gobusterfuzz.OptionsFuzz{HTTPOptions: libgobuster.HTTPOptions{URL: "http://localhost", Method: "FUZZ"}}, true},
+		{"Missing", gobusterfuzz.OptionsFuzz{HTTPOptions: libgobuster.HTTPOptions{URL: "http://localhost", Method: "GET", Cookies: "role=admin"}}, false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if got := containsFuzzKeyword(x.opts, gobusterfuzz.FuzzKeyword); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
@@ -46,17 +46,31 @@ func parseGCSOptions() (*libgobuster.Options, *gobustergcs.OptionsGCS, error) {
 
 	pluginopts.UserAgent = httpOpts.UserAgent
 	pluginopts.Proxy = httpOpts.Proxy
+	pluginopts.Proxies = httpOpts.Proxies
+	pluginopts.ProxyRotation = httpOpts.ProxyRotation
 	pluginopts.Timeout = httpOpts.Timeout
 	pluginopts.NoTLSValidation = httpOpts.NoTLSValidation
 	pluginopts.RetryOnTimeout = httpOpts.RetryOnTimeout
 	pluginopts.RetryAttempts = httpOpts.RetryAttempts
+	pluginopts.RetryWait = httpOpts.RetryWait
 	pluginopts.TLSCertificate = httpOpts.TLSCertificate
+	pluginopts.Limits = httpOpts.Limits
+	pluginopts.TLSPolicy = httpOpts.TLSPolicy
+
+	pluginopts.Headers, err = parseHeaders(cmdGCS)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	pluginopts.MaxFilesToList, err = cmdGCS.Flags().GetInt("maxfiles")
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for maxfiles: %w", err)
 	}
 
+	if err := globalopts.Validate("gcs", pluginopts); err != nil {
+		return nil, nil, err
+	}
+
 	return globalopts, pluginopts, nil
 }
 
@@ -69,6 +83,7 @@ func init() {
 	}
 
 	addBasicHTTPOptions(cmdGCS)
+	addHeadersOption(cmdGCS)
 	cmdGCS.Flags().IntP("maxfiles", "m", 5, "max files to list when listing buckets (only shown in verbose mode)")
 
 	cmdGCS.PersistentPreRun = func(cmd *cobra.Command, args []string) {
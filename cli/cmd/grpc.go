@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OJ/gobuster/v3/cli"
+	"github.com/OJ/gobuster/v3/gobustergrpc"
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdGRPC *cobra.Command
+
+func runGRPC(cmd *cobra.Command, args []string) error {
+	globalopts, pluginopts, err := parseGRPCOptions()
+	if err != nil {
+		return fmt.Errorf("error on parsing arguments: %w", err)
+	}
+
+	plugin, err := gobustergrpc.NewGobusterGRPC(globalopts, pluginopts)
+	if err != nil {
+		return fmt.Errorf("error on creating gobustergrpc: %w", err)
+	}
+	defer plugin.Close()
+
+	log := libgobuster.NewLogger(globalopts.Debug)
+	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
+		log.Debugf("%#v", err)
+		return fmt.Errorf("error on running gobuster: %w", err)
+	}
+	return nil
+}
+
+func parseGRPCOptions() (*libgobuster.Options, *gobustergrpc.OptionsGRPC, error) {
+	globalopts, err := parseGlobalOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginOpts := gobustergrpc.NewOptionsGRPC()
+
+	pluginOpts.Command, err = cmdGRPC.Flags().GetString("plugin-cmd")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for plugin-cmd: %w", err)
+	}
+
+	pluginOpts.Args, err = cmdGRPC.Flags().GetStringArray("plugin-arg")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for plugin-arg: %w", err)
+	}
+
+	options, err := cmdGRPC.Flags().GetStringArray("plugin-option")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for plugin-option: %w", err)
+	}
+	for _, o := range options {
+		key, value, found := strings.Cut(o, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, nil, fmt.Errorf("invalid plugin-option %q, expected key=value", o)
+		}
+		pluginOpts.PluginOptions[strings.TrimSpace(key)] = value
+	}
+
+	pluginOpts.StartTimeout, err = cmdGRPC.Flags().GetDuration("plugin-timeout")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for plugin-timeout: %w", err)
+	}
+
+	if err := globalopts.Validate("grpc", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
+	return globalopts, pluginOpts, nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdGRPC = &cobra.Command{
+		Use:   "grpc",
+		Short: "Uses an out-of-process plugin speaking the gobuster gRPC protocol",
+		RunE:  runGRPC,
+	}
+
+	cmdGRPC.Flags().String("plugin-cmd", "", fmt.Sprintf("Executable of the plugin implementing the gobuster plugin protocol (version %d)", gobustergrpc.ProtocolVersion))
+	cmdGRPC.Flags().StringArray("plugin-arg", []string{}, "Argument passed to the plugin executable, can be set multiple times")
+	cmdGRPC.Flags().StringArray("plugin-option", []string{}, "Option passed to the plugin like key=value, can be set multiple times")
+	cmdGRPC.Flags().Duration("plugin-timeout", 10*time.Second, "Time the plugin has to start and configure itself")
+
+	cmdGRPC.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureGlobalOptions()
+	}
+
+	rootCmd.AddCommand(cmdGRPC)
+}
@@ -13,35 +13,119 @@ import (
 
 	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/crypto/pkcs12"
+	"golang.org/x/net/http/httpguts"
 	"golang.org/x/term"
 )
 
 func addBasicHTTPOptions(cmd *cobra.Command) {
-	cmd.Flags().StringP("useragent", "a", libgobuster.DefaultUserAgent(), "Set the User-Agent string")
+	cmd.Flags().StringP("user-agent", "a", libgobuster.DefaultUserAgent(), "Set the User-Agent string")
 	cmd.Flags().BoolP("random-agent", "", false, "Use a random User-Agent string")
 	cmd.Flags().StringP("proxy", "", "", "Proxy to use for requests [http(s)://host:port] or [socks5://host:port]")
+	cmd.Flags().String("proxy-file", "", "File with one proxy per line, the proxies are rotated for every request")
+	cmd.Flags().String("proxy-rotation", libgobuster.ProxyRoundRobin, fmt.Sprintf("How the proxies of the proxy file are rotated (%s or %s)", libgobuster.ProxyRoundRobin, libgobuster.ProxyRandom))
 	cmd.Flags().DurationP("timeout", "", 10*time.Second, "HTTP Timeout")
 	cmd.Flags().BoolP("no-tls-validation", "k", false, "Skip TLS certificate verification")
-	cmd.Flags().BoolP("retry", "", false, "Should retry on request timeout")
-	cmd.Flags().IntP("retry-attempts", "", 3, "Times to retry on request timeout")
+	cmd.Flags().Bool("tls-allow-expired", false, "Accept expired and not yet valid certificates, all other certificate problems still fail")
+	cmd.Flags().Bool("tls-allow-self-signed", false, "Accept self-signed certificates, all other certificate problems still fail")
+	cmd.Flags().Bool("tls-allow-hostname-mismatch", false, "Accept certificates issued for other names, all other certificate problems still fail")
+	cmd.Flags().BoolP("retry", "", false, "Retry requests failing with a transient error (timeout, connection reset, 502 or 503 response)")
+	cmd.Flags().IntP("retry-attempts", "", 3, "Times to retry a request failing with a transient error")
+	cmd.Flags().Duration("retry-wait", time.Second, "Wait before the first retry, it doubles with every further attempt")
+	cmd.Flags().String("max-body-size", libgobuster.FormatMemorySize(libgobuster.DefaultMaxBodySize), "Maximum size of a response body after decompression, longer bodies are truncated to protect against decompression bombs and endless responses")
+	cmd.Flags().String("max-header-size", libgobuster.FormatMemorySize(libgobuster.DefaultMaxHeaderSize), "Maximum size of the headers of a response")
+	cmd.Flags().Int("max-headers", libgobuster.DefaultMaxHeaders, "Maximum number of header fields of a response, responses with more fail")
 	// client certificates, either pem or p12
-	cmd.Flags().StringP("client-cert-pem", "", "", "public key in PEM format for optional TLS client certificates")
-	cmd.Flags().StringP("client-cert-pem-key", "", "", "private key in PEM format for optional TLS client certificates (this key needs to have no password)")
+	cmd.Flags().StringP("client-cert-pem", "", "", "public key in PEM format for optional TLS client certificates, may also contain the private key (alias --client-cert)")
+	cmd.Flags().StringP("client-cert-pem-key", "", "", "private key in PEM format for optional TLS client certificates (this key needs to have no password, alias --client-key)")
 	cmd.Flags().StringP("client-cert-p12", "", "", "a p12 file to use for options TLS client certificates")
 	cmd.Flags().StringP("client-cert-p12-password", "", "", "the password to the p12 file")
+	cmd.Flags().SetNormalizeFunc(clientCertAliases)
+}
+
+// parseTLSPolicy parses the certificate problems allowed per category
+func parseTLSPolicy(cmd *cobra.Command) (libgobuster.TLSPolicy, error) {
+	var policy libgobuster.TLSPolicy
+	var err error
+	policy.AllowExpired, err = cmd.Flags().GetBool("tls-allow-expired")
+	if err != nil {
+		return policy, fmt.Errorf("invalid value for tls-allow-expired: %w", err)
+	}
+	policy.AllowSelfSigned, err = cmd.Flags().GetBool("tls-allow-self-signed")
+	if err != nil {
+		return policy, fmt.Errorf("invalid value for tls-allow-self-signed: %w", err)
+	}
+	policy.AllowHostnameMismatch, err = cmd.Flags().GetBool("tls-allow-hostname-mismatch")
+	if err != nil {
+		return policy, fmt.Errorf("invalid value for tls-allow-hostname-mismatch: %w", err)
+	}
+	return policy, nil
+}
+
+// parseResponseLimits parses the limits protecting against hostile responses
+func parseResponseLimits(cmd *cobra.Command) (libgobuster.ResponseLimits, error) {
+	var limits libgobuster.ResponseLimits
+	for _, l := range []struct {
+		name  string
+		value *int64
+	}{
+		{"max-body-size", &limits.MaxBodySize},
+		{"max-header-size", &limits.MaxHeaderSize},
+	} {
+		s, err := cmd.Flags().GetString(l.name)
+		if err != nil {
+			return limits, fmt.Errorf("invalid value for %s: %w", l.name, err)
+		}
+		*l.value, err = libgobuster.ParseMemorySize(s)
+		if err != nil {
+			return limits, fmt.Errorf("invalid value for %s: %w", l.name, err)
+		}
+		if *l.value <= 0 {
+			return limits, fmt.Errorf("%s must be bigger than 0", l.name)
+		}
+	}
+
+	var err error
+	limits.MaxHeaders, err = cmd.Flags().GetInt("max-headers")
+	if err != nil {
+		return limits, fmt.Errorf("invalid value for max-headers: %w", err)
+	}
+	if limits.MaxHeaders <= 0 {
+		return limits, fmt.Errorf("max-headers must be bigger than 0")
+	}
+	return limits, nil
+}
+
+// clientCertAliases maps the short names --client-cert and --client-key to
+// the pem client certificate options
+func clientCertAliases(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "client-cert":
+		name = "client-cert-pem"
+	case "client-key":
+		name = "client-cert-pem-key"
+	}
+	return pflag.NormalizedName(name)
+}
+
+// addHeadersOption adds the -H option for custom headers sent with every request
+func addHeadersOption(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("headers", "H", []string{""}, "Specify HTTP headers, -H 'Header1: val1' -H 'Header2: val2'")
 }
 
 func addCommonHTTPOptions(cmd *cobra.Command) error {
 	addBasicHTTPOptions(cmd)
-	cmd.Flags().StringP("url", "u", "", "The target URL")
+	cmd.Flags().StringP("url", "u", "", "The target URL. Multiple targets can be given as a comma separated list of URLs with scheme or a file with one URL per line, they are scanned one after another")
 	cmd.Flags().StringP("cookies", "c", "", "Cookies to use for the requests")
+	cmd.Flags().String("target-overrides", "", "YAML or CSV file with the cookies, headers and credentials of single targets, they replace the options of the scan for their target")
 	cmd.Flags().StringP("username", "U", "", "Username for Basic Auth")
 	cmd.Flags().StringP("password", "P", "", "Password for Basic Auth")
 	cmd.Flags().BoolP("follow-redirect", "r", false, "Follow redirects")
-	cmd.Flags().StringArrayP("headers", "H", []string{""}, "Specify HTTP headers, -H 'Header1: val1' -H 'Header2: val2'")
+	addHeadersOption(cmd)
 	cmd.Flags().BoolP("no-canonicalize-headers", "", false, "Do not canonicalize HTTP header names. If set header names are sent as is.")
-	cmd.Flags().StringP("method", "m", "GET", "Use the following HTTP method")
+	cmd.Flags().Bool("cache-bypass", false, "Add a random query parameter and cache defeating headers to every request so cached responses of a CDN don't mask the origin")
+	cmd.Flags().StringP("method", "m", "GET", "Use the following HTTP method, e.g. HEAD for faster scans or POST and PUT to find endpoints only answering to other methods")
 
 	if err := cmd.MarkFlagRequired("url"); err != nil {
 		return fmt.Errorf("error on marking flag as required: %w", err)
@@ -54,9 +138,9 @@ func parseBasicHTTPOptions(cmd *cobra.Command) (libgobuster.BasicHTTPOptions, er
 	options := libgobuster.BasicHTTPOptions{}
 	var err error
 
-	options.UserAgent, err = cmd.Flags().GetString("useragent")
+	options.UserAgent, err = cmd.Flags().GetString("user-agent")
 	if err != nil {
-		return options, fmt.Errorf("invalid value for useragent: %w", err)
+		return options, fmt.Errorf("invalid value for user-agent: %w", err)
 	}
 	randomUA, err := cmd.Flags().GetBool("random-agent")
 	if err != nil {
@@ -75,6 +159,22 @@ func parseBasicHTTPOptions(cmd *cobra.Command) (libgobuster.BasicHTTPOptions, er
 		return options, fmt.Errorf("invalid value for proxy: %w", err)
 	}
 
+	proxyFile, err := cmd.Flags().GetString("proxy-file")
+	if err != nil {
+		return options, fmt.Errorf("invalid value for proxy-file: %w", err)
+	}
+	if proxyFile != "" {
+		options.Proxies, err = libgobuster.ReadProxyFile(proxyFile)
+		if err != nil {
+			return options, err
+		}
+	}
+
+	options.ProxyRotation, err = cmd.Flags().GetString("proxy-rotation")
+	if err != nil {
+		return options, fmt.Errorf("invalid value for proxy-rotation: %w", err)
+	}
+
 	options.Timeout, err = cmd.Flags().GetDuration("timeout")
 	if err != nil {
 		return options, fmt.Errorf("invalid value for timeout: %w", err)
@@ -90,11 +190,29 @@ func parseBasicHTTPOptions(cmd *cobra.Command) (libgobuster.BasicHTTPOptions, er
 		return options, fmt.Errorf("invalid value for retry-attempts: %w", err)
 	}
 
+	options.RetryWait, err = cmd.Flags().GetDuration("retry-wait")
+	if err != nil {
+		return options, fmt.Errorf("invalid value for retry-wait: %w", err)
+	}
+
+	options.Limits, err = parseResponseLimits(cmd)
+	if err != nil {
+		return options, err
+	}
+
 	options.NoTLSValidation, err = cmd.Flags().GetBool("no-tls-validation")
 	if err != nil {
 		return options, fmt.Errorf("invalid value for no-tls-validation: %w", err)
 	}
 
+	options.TLSPolicy, err = parseTLSPolicy(cmd)
+	if err != nil {
+		return options, err
+	}
+	if options.NoTLSValidation && options.TLSPolicy.Relaxed() {
+		return options, fmt.Errorf("the tls-allow options can not be used with no-tls-validation, which skips the verification completely")
+	}
+
 	pemFile, err := cmd.Flags().GetString("client-cert-pem")
 	if err != nil {
 		return options, fmt.Errorf("invalid value for client-cert-pem: %w", err)
@@ -116,7 +234,15 @@ func parseBasicHTTPOptions(cmd *cobra.Command) (libgobuster.BasicHTTPOptions, er
 		return options, fmt.Errorf("please supply either a pem or a p12, not both")
 	}
 
+	if pemKeyFile != "" && pemFile == "" {
+		return options, fmt.Errorf("client-cert-pem-key needs the certificate in client-cert-pem")
+	}
+
 	if pemFile != "" {
+		// without a key file the certificate file needs to contain the key too
+		if pemKeyFile == "" {
+			pemKeyFile = pemFile
+		}
 		cert, err := tls.LoadX509KeyPair(pemFile, pemKeyFile)
 		if err != nil {
 			return options, fmt.Errorf("could not load supplied pem key: %w", err)
@@ -145,6 +271,30 @@ func parseBasicHTTPOptions(cmd *cobra.Command) (libgobuster.BasicHTTPOptions, er
 	return options, nil
 }
 
+// normalizeURL adds the scheme to urls without one. The scheme is guessed
+// from the port
+func normalizeURL(url string) (string, error) {
+	if strings.HasPrefix(url, "http") {
+		return url, nil
+	}
+
+	// check to see if a port was specified
+	re := regexp.MustCompile(`^[^/]+:(\d+)`)
+	match := re.FindStringSubmatch(url)
+
+	if len(match) < 2 {
+		// no port, default to http on 80
+		return fmt.Sprintf("http://%s", url), nil
+	}
+	port, err := strconv.Atoi(match[1])
+	if err != nil || (port != 80 && port != 443) {
+		return "", fmt.Errorf("url scheme not specified")
+	} else if port == 80 {
+		return fmt.Sprintf("http://%s", url), nil
+	}
+	return fmt.Sprintf("https://%s", url), nil
+}
+
 func parseCommonHTTPOptions(cmd *cobra.Command) (libgobuster.HTTPOptions, error) {
 	options := libgobuster.HTTPOptions{}
 	var err error
@@ -154,35 +304,42 @@ func parseCommonHTTPOptions(cmd *cobra.Command) (libgobuster.HTTPOptions, error)
 		return options, err
 	}
 	options.Proxy = basic.Proxy
+	options.Proxies = basic.Proxies
+	options.ProxyRotation = basic.ProxyRotation
 	options.Timeout = basic.Timeout
 	options.UserAgent = basic.UserAgent
 	options.NoTLSValidation = basic.NoTLSValidation
 	options.RetryOnTimeout = basic.RetryOnTimeout
 	options.RetryAttempts = basic.RetryAttempts
+	options.RetryWait = basic.RetryWait
 	options.TLSCertificate = basic.TLSCertificate
+	options.Limits = basic.Limits
+	options.TLSPolicy = basic.TLSPolicy
 
-	options.URL, err = cmd.Flags().GetString("url")
+	url, err := cmd.Flags().GetString("url")
 	if err != nil {
 		return options, fmt.Errorf("invalid value for url: %w", err)
 	}
+	targets, err := parseTargets(url)
+	if err != nil {
+		return options, err
+	}
+	options.URL = targets[0]
+	if len(targets) > 1 {
+		options.Targets = targets
+	}
 
-	if !strings.HasPrefix(options.URL, "http") {
-		// check to see if a port was specified
-		re := regexp.MustCompile(`^[^/]+:(\d+)`)
-		match := re.FindStringSubmatch(options.URL)
-
-		if len(match) < 2 {
-			// no port, default to http on 80
-			options.URL = fmt.Sprintf("http://%s", options.URL)
-		} else {
-			port, err2 := strconv.Atoi(match[1])
-			if err2 != nil || (port != 80 && port != 443) {
-				return options, fmt.Errorf("url scheme not specified")
-			} else if port == 80 {
-				options.URL = fmt.Sprintf("http://%s", options.URL)
-			} else {
-				options.URL = fmt.Sprintf("https://%s", options.URL)
-			}
+	overridesFile, err := cmd.Flags().GetString("target-overrides")
+	if err != nil {
+		return options, fmt.Errorf("invalid value for target-overrides: %w", err)
+	}
+	if overridesFile != "" {
+		options.Overrides, err = libgobuster.ParseTargetOverridesFile(overridesFile)
+		if err != nil {
+			return options, fmt.Errorf("invalid value for target-overrides: %w", err)
+		}
+		if unmatched := options.Overrides.Unmatched(targets); len(unmatched) > 0 {
+			return options, fmt.Errorf("invalid value for target-overrides: the entries %s match no target", strings.Join(unmatched, ", "))
 		}
 	}
 
@@ -211,23 +368,14 @@ func parseCommonHTTPOptions(cmd *cobra.Command) (libgobuster.HTTPOptions, error)
 		return options, fmt.Errorf("invalid value for method: %w", err)
 	}
 
-	headers, err := cmd.Flags().GetStringArray("headers")
-	if err != nil {
-		return options, fmt.Errorf("invalid value for headers: %w", err)
+	// fail early instead of on every request
+	if options.Method == "" || strings.IndexFunc(options.Method, func(r rune) bool { return !httpguts.IsTokenRune(r) }) >= 0 {
+		return options, fmt.Errorf("invalid value for method: %q is not a valid HTTP method", options.Method)
 	}
 
-	for _, h := range headers {
-		keyAndValue := strings.SplitN(h, ":", 2)
-		if len(keyAndValue) != 2 {
-			return options, fmt.Errorf("invalid header format for header %q", h)
-		}
-		key := strings.TrimSpace(keyAndValue[0])
-		value := strings.TrimSpace(keyAndValue[1])
-		if len(key) == 0 {
-			return options, fmt.Errorf("invalid header format for header %q - name is empty", h)
-		}
-		header := libgobuster.HTTPHeader{Name: key, Value: value}
-		options.Headers = append(options.Headers, header)
+	options.Headers, err = parseHeaders(cmd)
+	if err != nil {
+		return options, err
 	}
 
 	noCanonHeaders, err := cmd.Flags().GetBool("no-canonicalize-headers")
@@ -236,6 +384,11 @@ func parseCommonHTTPOptions(cmd *cobra.Command) (libgobuster.HTTPOptions, error)
 	}
 	options.NoCanonicalizeHeaders = noCanonHeaders
 
+	options.CacheBypass, err = cmd.Flags().GetBool("cache-bypass")
+	if err != nil {
+		return options, fmt.Errorf("invalid value for cache-bypass: %w", err)
+	}
+
 	// Prompt for PW if not provided
 	if options.Username != "" && options.Password == "" {
 		fmt.Printf("[?] Auth Password: ")
@@ -256,3 +409,26 @@ func parseCommonHTTPOptions(cmd *cobra.Command) (libgobuster.HTTPOptions, error)
 
 	return options, nil
 }
+
+// parseHeaders parses the values of the -H option
+func parseHeaders(cmd *cobra.Command) ([]libgobuster.HTTPHeader, error) {
+	headers, err := cmd.Flags().GetStringArray("headers")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for headers: %w", err)
+	}
+
+	var ret []libgobuster.HTTPHeader
+	for _, h := range headers {
+		keyAndValue := strings.SplitN(h, ":", 2)
+		if len(keyAndValue) != 2 {
+			return nil, fmt.Errorf("invalid header format for header %q", h)
+		}
+		key := strings.TrimSpace(keyAndValue[0])
+		value := strings.TrimSpace(keyAndValue[1])
+		if len(key) == 0 {
+			return nil, fmt.Errorf("invalid header format for header %q - name is empty", h)
+		}
+		ret = append(ret, libgobuster.HTTPHeader{Name: key, Value: value})
+	}
+	return ret, nil
+}
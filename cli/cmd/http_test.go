@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+func TestParseHeaders(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName      string
+		headers       []string
+		expectedCount int
+		expectedError bool
+	}{
+		{"No headers", nil, 0, false},
+		{"Single header", []string{"X-Api-Key: abc"}, 1, false},
+		{"Multiple headers", []string{"X-Forwarded-For: 127.0.0.1", "Authorization: Bearer a:b"}, 2, false},
+		{"Missing colon", []string{"X-Api-Key"}, 0, true},
+		{"Empty name", []string{": abc"}, 0, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			cmd := &cobra.Command{}
+			addHeadersOption(cmd)
+			for _, h := range x.headers {
+				if err := cmd.Flags().Set("headers", h); err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+			}
+			headers, err := parseHeaders(cmd)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for %v", x.headers)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if len(headers) != x.expectedCount {
+				t.Fatalf("expected %d headers, got %+v", x.expectedCount, headers)
+			}
+		})
+	}
+}
+
+func TestParseCommonHTTPOptionsMethod(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		method        string
+		expectedError bool
+	}{
+		{"GET", false},
+		{"HEAD", false},
+		{"PROPFIND", false},
+		{"get", false},
+		{"", true},
+		{"GET /", true},
+		{"PO\nST", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.method, func(t *testing.T) {
+			t.Parallel()
+			cmd := &cobra.Command{}
+			if err := addCommonHTTPOptions(cmd); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := cmd.Flags().Set("url", "http://localhost"); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := cmd.Flags().Set("method", x.method); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			opts, err := parseCommonHTTPOptions(cmd)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for method %q", x.method)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if opts.Method != x.method {
+				t.Fatalf("expected method %q, got %q", x.method, opts.Method)
+			}
+		})
+	}
+}
+
+// writeClientCert writes a self signed certificate and its key to dir, once
+// in separate files and once combined in a single file
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile, combinedFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "gobuster"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	combinedFile = filepath.Join(dir, "combined.pem")
+	for file, content := range map[string][]byte{
+		certFile:     certPem,
+		keyFile:      keyPem,
+		combinedFile: append(append([]byte{}, certPem...), keyPem...),
+	} {
+		if err := os.WriteFile(file, content, 0o600); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	return certFile, keyFile, combinedFile
+}
+
+func TestParseBasicHTTPOptionsClientCert(t *testing.T) {
+	t.Parallel()
+
+	certFile, keyFile, combinedFile := writeClientCert(t, t.TempDir())
+
+	tt := []struct {
+		testName      string
+		args          []string
+		expectedCert  bool
+		expectedError bool
+	}{
+		{"No certificate", nil, false, false},
+		{"Certificate and key", []string{"--client-cert-pem", certFile, "--client-cert-pem-key", keyFile}, true, false},
+		{"Aliases", []string{"--client-cert", certFile, "--client-key", keyFile}, true, false},
+		{"Combined file", []string{"--client-cert", combinedFile}, true, false},
+		{"Certificate without key", []string{"--client-cert", certFile}, false, true},
+		{"Key without certificate", []string{"--client-key", keyFile}, false, true},
+		{"Pem and p12", []string{"--client-cert", combinedFile, "--client-cert-p12", "cert.p12"}, false, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			cmd := &cobra.Command{}
+			addBasicHTTPOptions(cmd)
+			if err := cmd.ParseFlags(x.args); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			opts, err := parseBasicHTTPOptions(cmd)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for %v", x.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if (opts.TLSCertificate != nil) != x.expectedCert {
+				t.Fatalf("expected a certificate: %t, got %v", x.expectedCert, opts.TLSCertificate)
+			}
+		})
+	}
+}
+
+func TestParseResponseLimits(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName      string
+		args          []string
+		expected      libgobuster.ResponseLimits
+		expectedError bool
+	}{
+		{"Defaults", nil, libgobuster.ResponseLimits{MaxBodySize: libgobuster.DefaultMaxBodySize, MaxHeaderSize: libgobuster.DefaultMaxHeaderSize, MaxHeaders: libgobuster.DefaultMaxHeaders}, false},
+		{"Custom", []string{"--max-body-size", "2MB", "--max-header-size", "64KiB", "--max-headers", "50"}, libgobuster.ResponseLimits{MaxBodySize: 2 << 20, MaxHeaderSize: 64 << 10, MaxHeaders: 50}, false},
+		{"Invalid size", []string{"--max-body-size", "big"}, libgobuster.ResponseLimits{}, true},
+		{"Zero size", []string{"--max-header-size", "0"}, libgobuster.ResponseLimits{}, true},
+		{"Zero headers", []string{"--max-headers", "0"}, libgobuster.ResponseLimits{}, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			cmd := &cobra.Command{}
+			addBasicHTTPOptions(cmd)
+			if err := cmd.ParseFlags(x.args); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			opts, err := parseBasicHTTPOptions(cmd)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for %v", x.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if opts.Limits != x.expected {
+				t.Fatalf("expected %+v, got %+v", x.expected, opts.Limits)
+			}
+		})
+	}
+}
+
+func TestParseTLSPolicy(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName      string
+		args          []string
+		expected      libgobuster.TLSPolicy
+		expectedError bool
+	}{
+		{"Default", nil, libgobuster.TLSPolicy{}, false},
+		{"Expired", []string{"--tls-allow-expired"}, libgobuster.TLSPolicy{AllowExpired: true}, false},
+		{"Self-signed and hostname", []string{"--tls-allow-self-signed", "--tls-allow-hostname-mismatch"}, libgobuster.TLSPolicy{AllowSelfSigned: true, AllowHostnameMismatch: true}, false},
+		{"With no-tls-validation", []string{"-k", "--tls-allow-expired"}, libgobuster.TLSPolicy{}, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			cmd := &cobra.Command{}
+			addBasicHTTPOptions(cmd)
+			if err := cmd.ParseFlags(x.args); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			opts, err := parseBasicHTTPOptions(cmd)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for %v", x.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if opts.TLSPolicy != x.expected {
+				t.Fatalf("expected %+v, got %+v", x.expected, opts.TLSPolicy)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// nolint:gochecknoglobals
+var (
+	cmdModes *cobra.Command
+	cmdFlags *cobra.Command
+)
+
+// nonModeCommands are the commands of gobuster which are not scan modes
+// nolint:gochecknoglobals
+var nonModeCommands = map[string]bool{
+	"completion": true,
+	"examples":   true,
+	"flags":      true,
+	"help":       true,
+	"modes":      true,
+	"stats":      true,
+	"verify":     true,
+	"version":    true,
+	"wordlist":   true,
+}
+
+// flagInfo describes a flag for frameworks building their forms dynamically
+type flagInfo struct {
+	Name        string `json:"name"`
+	Shorthand   string `json:"shorthand,omitempty"`
+	Type        string `json:"type"`
+	Default     string `json:"default"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// modeInfo describes a scan mode and its own flags
+type modeInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Flags       []flagInfo `json:"flags,omitempty"`
+}
+
+// capabilities is the machine readable description of the installed gobuster
+type capabilities struct {
+	Version     string     `json:"version"`
+	Modes       []modeInfo `json:"modes"`
+	GlobalFlags []flagInfo `json:"global_flags,omitempty"`
+}
+
+// scanModes returns the commands of all scan modes sorted by name
+func scanModes() []*cobra.Command {
+	var modes []*cobra.Command
+	for _, c := range rootCmd.Commands() {
+		if !nonModeCommands[c.Name()] {
+			modes = append(modes, c)
+		}
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i].Name() < modes[j].Name() })
+	return modes
+}
+
+// describeFlags returns the visible flags of the set, without the help flag
+func describeFlags(flags *pflag.FlagSet) []flagInfo {
+	var infos []flagInfo
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Deprecated != "" || f.Name == "help" {
+			return
+		}
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		infos = append(infos, flagInfo{
+			Name:        f.Name,
+			Shorthand:   f.Shorthand,
+			Type:        f.Value.Type(),
+			Default:     f.DefValue,
+			Description: f.Usage,
+			Required:    required,
+		})
+	})
+	return infos
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("could not encode json: %w", err)
+	}
+	return nil
+}
+
+func runModes(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("invalid value for json: %w", err)
+	}
+
+	c := capabilities{Version: libgobuster.VERSION}
+	for _, mode := range scanModes() {
+		c.Modes = append(c.Modes, modeInfo{Name: mode.Name(), Description: mode.Short})
+	}
+	if asJSON {
+		return printJSON(c)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, mode := range c.Modes {
+		fmt.Fprintf(w, "%s\t%s\n", mode.Name, mode.Description)
+	}
+	return w.Flush()
+}
+
+func runFlags(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return fmt.Errorf("invalid value for json: %w", err)
+	}
+
+	modes := scanModes()
+	if len(args) == 1 {
+		var found *cobra.Command
+		for _, mode := range modes {
+			if mode.Name() == args[0] {
+				found = mode
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("unknown mode %q", args[0])
+		}
+		modes = []*cobra.Command{found}
+	}
+
+	c := capabilities{
+		Version:     libgobuster.VERSION,
+		GlobalFlags: describeFlags(rootCmd.PersistentFlags()),
+	}
+	for _, mode := range modes {
+		c.Modes = append(c.Modes, modeInfo{Name: mode.Name(), Description: mode.Short, Flags: describeFlags(mode.LocalFlags())})
+	}
+	if asJSON {
+		return printJSON(c)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	printFlags := func(title string, flags []flagInfo) {
+		fmt.Fprintf(w, "%s:\n", title)
+		for _, f := range flags {
+			required := ""
+			if f.Required {
+				required = "required"
+			}
+			fmt.Fprintf(w, "  --%s\t%s\t%s\t%s\n", f.Name, f.Type, f.Default, required)
+		}
+		fmt.Fprintln(w)
+	}
+	printFlags("global", c.GlobalFlags)
+	for _, mode := range c.Modes {
+		printFlags(mode.Name, mode.Flags)
+	}
+	return w.Flush()
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdModes = &cobra.Command{
+		Use:   "modes",
+		Short: "lists the available modes",
+		Args:  cobra.NoArgs,
+		RunE:  runModes,
+	}
+	cmdModes.Flags().Bool("json", false, "Print the modes as JSON")
+
+	cmdFlags = &cobra.Command{
+		Use:   "flags [mode]",
+		Short: "lists the flags with their types and defaults of all modes or the given mode",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runFlags,
+	}
+	cmdFlags.Flags().Bool("json", false, "Print the flags as JSON")
+
+	rootCmd.AddCommand(cmdModes)
+	rootCmd.AddCommand(cmdFlags)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import "testing"
+
+func TestScanModes(t *testing.T) {
+	t.Parallel()
+
+	modes := make(map[string]bool)
+	for _, mode := range scanModes() {
+		if nonModeCommands[mode.Name()] {
+			t.Fatalf("%s is not a mode", mode.Name())
+		}
+		modes[mode.Name()] = true
+	}
+	for _, name := range []string{"dir", "dns", "fuzz", "vhost"} {
+		if !modes[name] {
+			t.Fatalf("mode %s is missing in %v", name, modes)
+		}
+	}
+}
+
+func TestDescribeFlags(t *testing.T) {
+	t.Parallel()
+
+	global := make(map[string]flagInfo)
+	for _, f := range describeFlags(rootCmd.PersistentFlags()) {
+		global[f.Name] = f
+	}
+	if f := global["threads"]; f.Type != "int" || f.Default != "10" || f.Shorthand != "t" {
+		t.Fatalf("unexpected global flag threads: %+v", f)
+	}
+
+	dir := make(map[string]flagInfo)
+	for _, f := range describeFlags(cmdDir.LocalFlags()) {
+		dir[f.Name] = f
+	}
+	if f := dir["url"]; f.Type != "string" || !f.Required {
+		t.Fatalf("unexpected dir flag url: %+v", f)
+	}
+	if _, ok := dir["help"]; ok {
+		t.Fatal("the help flag should not be described")
+	}
+	if _, ok := dir["threads"]; ok {
+		t.Fatal("global flags should not be described as dir flags")
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/OJ/gobuster/v3/cli"
+	"github.com/OJ/gobuster/v3/gobustermethods"
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdMethods *cobra.Command
+
+func runMethods(cmd *cobra.Command, args []string) error {
+	globalopts, pluginopts, err := parseMethodsOptions()
+	if err != nil {
+		return fmt.Errorf("error on parsing arguments: %w", err)
+	}
+
+	plugins, err := newTargetPlugins(pluginopts.HTTPOptions, func(url string) (*gobustermethods.GobusterMethods, error) {
+		opts := *pluginopts
+		opts.HTTPOptions = pluginopts.ForTarget(url)
+		plugin, err := gobustermethods.NewGobusterMethods(globalopts, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error on creating gobustermethods: %w", err)
+		}
+		return plugin, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log := libgobuster.NewLogger(globalopts.Debug)
+	if err := cli.GobusterTargets(mainContext, globalopts, plugins, log); err != nil {
+		log.Debugf("%#v", err)
+		return fmt.Errorf("error on running gobuster: %w", err)
+	}
+	return nil
+}
+
+func parseMethodsOptions() (*libgobuster.Options, *gobustermethods.OptionsMethods, error) {
+	globalopts, err := parseGlobalOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginOpts := gobustermethods.NewOptionsMethods()
+
+	httpOpts, err := parseCommonHTTPOptions(cmdMethods)
+	if err != nil {
+		return nil, nil, err
+	}
+	pluginOpts.Password = httpOpts.Password
+	pluginOpts.URL = httpOpts.URL
+	pluginOpts.Targets = httpOpts.Targets
+	pluginOpts.Overrides = httpOpts.Overrides
+	pluginOpts.UserAgent = httpOpts.UserAgent
+	pluginOpts.Username = httpOpts.Username
+	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
+	pluginOpts.Cookies = httpOpts.Cookies
+	pluginOpts.Timeout = httpOpts.Timeout
+	pluginOpts.FollowRedirect = httpOpts.FollowRedirect
+	pluginOpts.NoTLSValidation = httpOpts.NoTLSValidation
+	pluginOpts.Headers = httpOpts.Headers
+	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
+	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
+	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
+	pluginOpts.NoCanonicalizeHeaders = httpOpts.NoCanonicalizeHeaders
+	pluginOpts.CacheBypass = httpOpts.CacheBypass
+
+	pluginOpts.Intrusive, err = cmdMethods.Flags().GetBool("intrusive")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for intrusive: %w", err)
+	}
+
+	if err := globalopts.Validate("methods", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
+	return globalopts, pluginOpts, nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdMethods = &cobra.Command{
+		Use:   "methods",
+		Short: "Uses method probing mode. Checks paths for dangerous methods and WebDAV",
+		RunE:  runMethods,
+	}
+
+	if err := addCommonHTTPOptions(cmdMethods); err != nil {
+		log.Fatalf("%v", err)
+	}
+	// the methods are chosen by the checks
+	if err := cmdMethods.Flags().MarkHidden("method"); err != nil {
+		log.Fatalf("error on hiding flag: %v", err)
+	}
+	cmdMethods.Flags().Bool("intrusive", false, "Enable intrusive checks which try to create (PUT, MKCOL) and delete (DELETE) random resources below each path")
+
+	cmdMethods.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureGlobalOptions()
+	}
+
+	rootCmd.AddCommand(cmdMethods)
+}
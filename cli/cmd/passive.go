@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/cli"
+	"github.com/OJ/gobuster/v3/gobusterpassive"
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdPassive *cobra.Command
+
+func runPassive(cmd *cobra.Command, args []string) error {
+	globalopts, pluginopts, err := parsePassiveOptions()
+	if err != nil {
+		return fmt.Errorf("error on parsing arguments: %w", err)
+	}
+
+	plugin, err := gobusterpassive.NewGobusterPassive(globalopts, pluginopts)
+	if err != nil {
+		return fmt.Errorf("error on creating gobusterpassive: %w", err)
+	}
+
+	log := libgobuster.NewLogger(globalopts.Debug)
+	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
+		log.Debugf("%#v", err)
+		return fmt.Errorf("error on running gobuster: %w", err)
+	}
+	return nil
+}
+
+func parsePassiveOptions() (*libgobuster.Options, *gobusterpassive.OptionsPassive, error) {
+	globalopts, err := parseGlobalOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginOpts := gobusterpassive.NewOptionsPassive()
+
+	httpOpts, err := parseBasicHTTPOptions(cmdPassive)
+	if err != nil {
+		return nil, nil, err
+	}
+	pluginOpts.BasicHTTPOptions = httpOpts
+	// the responses of the source APIs are larger than the ones of a scan
+	if !cmdPassive.Flags().Changed("max-body-size") {
+		pluginOpts.Limits.MaxBodySize = gobusterpassive.DefaultMaxBodySize
+	}
+
+	sources, err := cmdPassive.Flags().GetString("sources")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for sources: %w", err)
+	}
+	for _, s := range strings.Split(sources, ",") {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			pluginOpts.Sources = append(pluginOpts.Sources, s)
+		}
+	}
+
+	pluginOpts.VirusTotalAPIKey, err = cmdPassive.Flags().GetString("virustotal-key")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for virustotal-key: %w", err)
+	}
+	if pluginOpts.VirusTotalAPIKey == "" {
+		pluginOpts.VirusTotalAPIKey = os.Getenv("VT_API_KEY")
+	}
+
+	pluginOpts.WaybackLimit, err = cmdPassive.Flags().GetInt("wayback-limit")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for wayback-limit: %w", err)
+	}
+
+	if err := globalopts.Validate("passive", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
+	return globalopts, pluginOpts, nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdPassive = &cobra.Command{
+		Use:   "passive",
+		Short: "Uses passive mode. Aggregates subdomains and urls of the domains in the wordlist from passive sources without touching the target",
+		RunE:  runPassive,
+	}
+
+	addBasicHTTPOptions(cmdPassive)
+	cmdPassive.Flags().String("sources", fmt.Sprintf("%s,%s", gobusterpassive.SourceCrtSh, gobusterpassive.SourceWayback), fmt.Sprintf("Comma separated list of sources to query (%s)", strings.Join(gobusterpassive.AllSources, ",")))
+	cmdPassive.Flags().String("virustotal-key", "", "API key for the virustotal source (can also be set via the VT_API_KEY environment variable)")
+	cmdPassive.Flags().Int("wayback-limit", 10000, "Maximum number of urls to fetch from the wayback machine per domain (0 means no limit)")
+
+	cmdPassive.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureGlobalOptions()
+	}
+
+	rootCmd.AddCommand(cmdPassive)
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
@@ -38,18 +40,39 @@ func Execute() {
 		select {
 		case <-signalChan:
 			// caught CTRL+C
-			fmt.Println("\n[!] Keyboard interrupt detected, terminating.")
+			fmt.Printf("\n[!] %s\n", libgobuster.T("Keyboard interrupt detected, terminating."))
 			cancel()
 		case <-mainContext.Done():
 		}
 	}()
 
-	if err := rootCmd.Execute(); err != nil {
-		// Leaving this in results in the same error appearing twice
-		// Once before and once after the help output. Not sure if
-		// this is going to be needed to output other errors that
-		// aren't automatically outputted.
-		// fmt.Println(err)
+	var subcommands []string
+	for _, c := range rootCmd.Commands() {
+		subcommands = append(subcommands, c.Name())
+	}
+	args, warnings, printOnly := migrateArgs(os.Args[1:], subcommands)
+	if printOnly {
+		fmt.Println(shellJoin(append([]string{"gobuster"}, args...)))
+		return
+	}
+	log := libgobuster.NewLogger(false)
+	for _, w := range warnings {
+		log.Warn(w)
+	}
+	rootCmd.SetArgs(args)
+
+	// errors are printed here so runs stopped early only exit with their
+	// code, the reason is already part of the summary
+	rootCmd.SilenceErrors = true
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
+		var stopped *libgobuster.StoppedError
+		if errors.As(err, &stopped) {
+			os.Exit(stopped.Reason.ExitCode())
+		}
+		rootCmd.PrintErrln("Error:", err.Error())
+		if cmd == rootCmd {
+			rootCmd.PrintErrf("Run '%v --help' for usage.\n", rootCmd.CommandPath())
+		}
 		os.Exit(1)
 	}
 }
@@ -61,47 +84,37 @@ func parseGlobalOptions() (*libgobuster.Options, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for threads: %w", err)
 	}
+	globalopts.Threads = threads
+	globalopts.ConnStats = libgobuster.NewConnStats()
 
-	if threads <= 0 {
-		return nil, fmt.Errorf("threads must be bigger than 0")
+	globalopts.EnrichThreads, err = rootCmd.Flags().GetInt("enrich-threads")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for enrich-threads: %w", err)
 	}
-	globalopts.Threads = threads
 
 	delay, err := rootCmd.Flags().GetDuration("delay")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for delay: %w", err)
 	}
-
-	if delay < 0 {
-		return nil, fmt.Errorf("delay must be positive")
-	}
 	globalopts.Delay = delay
 
-	globalopts.Wordlist, err = rootCmd.Flags().GetString("wordlist")
+	wordlists, err := rootCmd.Flags().GetStringArray("wordlist")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for wordlist: %w", err)
 	}
-
-	if globalopts.Wordlist == "-" {
-		// STDIN
-	} else if _, err2 := os.Stat(globalopts.Wordlist); os.IsNotExist(err2) {
-		return nil, fmt.Errorf("wordlist file %q does not exist: %w", globalopts.Wordlist, err2)
+	if len(wordlists) > 0 {
+		globalopts.Wordlist = wordlists[0]
+	}
+	if len(wordlists) > 1 {
+		globalopts.Wordlists = wordlists
 	}
 
 	offset, err := rootCmd.Flags().GetInt("wordlist-offset")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for wordlist-offset: %w", err)
 	}
-
-	if offset < 0 {
-		return nil, fmt.Errorf("wordlist-offset must be bigger or equal to 0")
-	}
 	globalopts.WordlistOffset = offset
 
-	if globalopts.Wordlist == "-" && globalopts.WordlistOffset > 0 {
-		return nil, fmt.Errorf("wordlist-offset is not supported when reading from STDIN")
-	}
-
 	globalopts.PatternFile, err = rootCmd.Flags().GetString("pattern")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for pattern: %w", err)
@@ -126,11 +139,337 @@ func parseGlobalOptions() (*libgobuster.Options, error) {
 		}
 	}
 
-	globalopts.OutputFilename, err = rootCmd.Flags().GetString("output")
+	globalopts.KnownFile, err = rootCmd.Flags().GetString("known-file")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for known-file: %w", err)
+	}
+
+	if globalopts.KnownFile != "" {
+		known, err := libgobuster.ParseKnownFile(globalopts.KnownFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read known file %q: %w", globalopts.KnownFile, err)
+		}
+		globalopts.KnownWords = known
+	}
+
+	resumeFromOutput, err := rootCmd.Flags().GetString("resume-from-output")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for resume-from-output: %w", err)
+	}
+
+	if resumeFromOutput != "" {
+		previous, err := libgobuster.ParseOutputFile(resumeFromOutput)
+		if err != nil {
+			return nil, fmt.Errorf("could not read previous output file %q: %w", resumeFromOutput, err)
+		}
+		if globalopts.KnownWords.Set == nil {
+			globalopts.KnownWords = libgobuster.NewSet[string]()
+		}
+		for entry := range previous.Set {
+			globalopts.KnownWords.Add(entry)
+		}
+	}
+
+	globalopts.RulesFile, err = rootCmd.Flags().GetString("rules-file")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for rules-file: %w", err)
+	}
+
+	if globalopts.RulesFile != "" {
+		rules, err := libgobuster.ParseRulesFile(globalopts.RulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read rules file %q: %w", globalopts.RulesFile, err)
+		}
+		globalopts.Rules = rules
+	}
+
+	scriptFile, err := rootCmd.Flags().GetString("script")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for script: %w", err)
+	}
+
+	if scriptFile != "" {
+		script, err := libgobuster.LoadScript(scriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load script %q: %w", scriptFile, err)
+		}
+		globalopts.Script = script
+	}
+
+	globalopts.RateLimit, err = rootCmd.Flags().GetInt("rate-limit")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for rate-limit: %w", err)
+	}
+
+	globalopts.RateFile, err = rootCmd.Flags().GetString("rate-file")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for rate-file: %w", err)
+	}
+
+	if globalopts.RateLimit > 0 {
+		globalopts.Throttle = libgobuster.NewThrottle(globalopts.RateLimit, globalopts.RateFile)
+	}
+
+	globalopts.SuppressFile, err = rootCmd.Flags().GetString("suppress-file")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for suppress-file: %w", err)
+	}
+
+	if globalopts.SuppressFile != "" {
+		suppressions, err := libgobuster.ParseSuppressionFile(globalopts.SuppressFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read suppress file %q: %w", globalopts.SuppressFile, err)
+		}
+		globalopts.Suppressions = suppressions
+	}
+
+	globalopts.Notify, err = rootCmd.Flags().GetBool("notify")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for notify: %w", err)
+	}
+
+	notifyFilters, err := rootCmd.Flags().GetStringArray("notify-filter")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for notify-filter: %w", err)
+	}
+
+	for _, rule := range notifyFilters {
+		f, err := libgobuster.ParseResultFilter(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for notify-filter: %w", err)
+		}
+		globalopts.NotifyFilters = append(globalopts.NotifyFilters, f)
+	}
+
+	globalopts.StopAfterFindings, err = rootCmd.Flags().GetInt("stop-after-findings")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for stop-after-findings: %w", err)
+	}
+
+	if globalopts.StopAfterFindings < 0 {
+		return nil, fmt.Errorf("stop-after-findings must be bigger or equal to 0")
+	}
+
+	stopTags, err := rootCmd.Flags().GetStringArray("stop-on-tag")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for stop-on-tag: %w", err)
+	}
+	globalopts.StopOnTags = libgobuster.NewSet[string]()
+	for _, t := range stopTags {
+		for _, x := range strings.Split(t, ",") {
+			if x = strings.TrimSpace(x); x != "" {
+				globalopts.StopOnTags.Add(x)
+			}
+		}
+	}
+
+	globalopts.MaxTime, err = rootCmd.Flags().GetDuration("max-time")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for max-time: %w", err)
+	}
+
+	if globalopts.MaxTime < 0 {
+		return nil, fmt.Errorf("max-time must be bigger or equal to 0")
+	}
+
+	globalopts.MaxErrors, err = rootCmd.Flags().GetInt("max-errors")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for max-errors: %w", err)
+	}
+
+	if globalopts.MaxErrors < 0 {
+		return nil, fmt.Errorf("max-errors must be bigger or equal to 0")
+	}
+
+	globalopts.WordPolicy, err = rootCmd.Flags().GetString("word-policy")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for word-policy: %w", err)
+	}
+
+	maxMemory, err := rootCmd.Flags().GetString("max-memory")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for max-memory: %w", err)
+	}
+
+	if maxMemory != "" {
+		globalopts.MaxMemory, err = libgobuster.ParseMemorySize(maxMemory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for max-memory: %w", err)
+		}
+		if globalopts.MaxMemory < libgobuster.MinMemoryLimit {
+			return nil, fmt.Errorf("max-memory must be at least %s", libgobuster.FormatMemorySize(libgobuster.MinMemoryLimit))
+		}
+	}
+
+	globalopts.DryRun, err = rootCmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for dry-run: %w", err)
+	}
+
+	globalopts.EstimateDuration, err = rootCmd.Flags().GetBool("estimate-duration")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for estimate-duration: %w", err)
+	}
+
+	globalopts.AssumeYes, err = rootCmd.Flags().GetBool("yes")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for yes: %w", err)
+	}
+
+	globalopts.Force, err = rootCmd.Flags().GetBool("force")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for force: %w", err)
+	}
+
+	globalopts.Cluster, err = rootCmd.Flags().GetBool("cluster")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for cluster: %w", err)
+	}
+
+	globalopts.Tree, err = rootCmd.Flags().GetBool("tree")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for tree: %w", err)
+	}
+
+	globalopts.ShowTiming, err = rootCmd.Flags().GetBool("timing")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for timing: %w", err)
+	}
+
+	globalopts.Workspace, err = rootCmd.Flags().GetString("workspace")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for workspace: %w", err)
+	}
+
+	globalopts.Report, err = rootCmd.Flags().GetString("report")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for report: %w", err)
+	}
+
+	globalopts.Database, err = rootCmd.Flags().GetString("db")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for db: %w", err)
+	}
+
+	globalopts.StateFile, err = rootCmd.Flags().GetString("resume")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for resume: %w", err)
+	}
+	if globalopts.StateFile != "" {
+		if globalopts.Wordlist == "-" {
+			return nil, fmt.Errorf("resume can not be used with a wordlist read from stdin")
+		}
+		if rootCmd.Flags().Changed("wordlist-offset") {
+			return nil, fmt.Errorf("resume can not be used with wordlist-offset, the offset is read from the state file")
+		}
+	}
+
+	globalopts.StatusFile, err = rootCmd.Flags().GetString("status-file")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for status-file: %w", err)
+	}
+
+	globalopts.Outputs, err = rootCmd.Flags().GetStringArray("output")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for output filename: %w", err)
 	}
 
+	globalopts.OutputFormat, err = rootCmd.Flags().GetString("output-format")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for output-format: %w", err)
+	}
+
+	switch globalopts.OutputFormat {
+	case libgobuster.OutputPlain, libgobuster.OutputJSON, libgobuster.OutputCSV:
+	default:
+		return nil, fmt.Errorf("invalid value for output-format: %q, valid values are %s, %s and %s", globalopts.OutputFormat, libgobuster.OutputPlain, libgobuster.OutputJSON, libgobuster.OutputCSV)
+	}
+
+	outputFilters, err := rootCmd.Flags().GetStringArray("output-filter")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for output-filter: %w", err)
+	}
+
+	for _, s := range outputFilters {
+		f, err := libgobuster.ParseOutputFilter(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for output-filter: %w", err)
+		}
+		if !libgobuster.IsOutput(f.Target, globalopts.Outputs) {
+			return nil, fmt.Errorf("output filter %q does not belong to an output, use %s or a file passed with -o", s, libgobuster.OutputStdout)
+		}
+		globalopts.OutputFilters = append(globalopts.OutputFilters, f)
+	}
+
+	outputRedactions, err := rootCmd.Flags().GetStringArray("output-redact")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for output-redact: %w", err)
+	}
+
+	for _, s := range outputRedactions {
+		r, err := libgobuster.ParseOutputRedaction(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for output-redact: %w", err)
+		}
+		if !libgobuster.IsOutput(r.Target, globalopts.Outputs) {
+			return nil, fmt.Errorf("output redaction %q does not belong to an output, use %s or a file passed with -o", s, libgobuster.OutputStdout)
+		}
+		globalopts.OutputRedactions = append(globalopts.OutputRedactions, r)
+		if r.Mode == libgobuster.RedactHash && globalopts.RedactionKey == nil {
+			globalopts.RedactionKey, err = libgobuster.NewRedactionKey()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	globalopts.PrintRedactionKey, err = rootCmd.Flags().GetBool("output-redact-print-key")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for output-redact-print-key: %w", err)
+	}
+
+	outputEncrypt, err := rootCmd.Flags().GetString("output-encrypt")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for output-encrypt: %w", err)
+	}
+
+	if outputEncrypt != "" {
+		if len(globalopts.Outputs) == 0 {
+			return nil, fmt.Errorf("output-encrypt requires an output file (-o)")
+		}
+		globalopts.OutputEncryption, err = libgobuster.ParseOutputEncryption(outputEncrypt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for output-encrypt: %w", err)
+		}
+	}
+
+	outputSign, err := rootCmd.Flags().GetString("output-sign")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for output-sign: %w", err)
+	}
+
+	if outputSign != "" {
+		if len(globalopts.Outputs) == 0 {
+			return nil, fmt.Errorf("output-sign requires an output file (-o)")
+		}
+		globalopts.OutputSigning, err = libgobuster.ParseOutputSigning(outputSign)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for output-sign: %w", err)
+		}
+	}
+
+	lang, err := rootCmd.Flags().GetString("lang")
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for lang: %w", err)
+	}
+
+	if lang == "" {
+		lang = libgobuster.DetectLocale()
+	}
+	if err := libgobuster.SetLocale(lang); err != nil {
+		return nil, fmt.Errorf("invalid value for lang: %w", err)
+	}
+
 	globalopts.Verbose, err = rootCmd.Flags().GetBool("verbose")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for verbose: %w", err)
@@ -159,6 +498,16 @@ func parseGlobalOptions() (*libgobuster.Options, error) {
 		color.NoColor = true
 	}
 
+	// the seed is set before the modes parse their options, random user
+	// agents are picked there
+	if rootCmd.Flags().Changed("seed") {
+		seed, err := rootCmd.Flags().GetInt64("seed")
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for seed: %w", err)
+		}
+		libgobuster.SetSeed(seed)
+	}
+
 	globalopts.Debug, err = rootCmd.Flags().GetBool("debug")
 	if err != nil {
 		return nil, fmt.Errorf("invalid value for debug: %w", err)
@@ -181,14 +530,50 @@ func configureGlobalOptions() {
 func init() {
 	rootCmd.PersistentFlags().DurationP("delay", "", 0, "Time each thread waits between requests (e.g. 1500ms)")
 	rootCmd.PersistentFlags().IntP("threads", "t", 10, "Number of concurrent threads")
-	rootCmd.PersistentFlags().StringP("wordlist", "w", "", "Path to the wordlist. Set to - to use STDIN.")
+	rootCmd.PersistentFlags().Int("enrich-threads", 0, "Number of threads enriching results (titles and hashes for --cluster, header assessment, cache poisoning checks) apart from the request threads, defaults to --threads")
+	rootCmd.PersistentFlags().StringArrayP("wordlist", "w", nil, "Path to the wordlist. Set to - to use STDIN. Can be used multiple times, the wordlists are merged and duplicates removed")
 	rootCmd.PersistentFlags().IntP("wordlist-offset", "", 0, "Resume from a given position in the wordlist (defaults to 0)")
-	rootCmd.PersistentFlags().StringP("output", "o", "", "Output file to write results to (defaults to stdout)")
+	rootCmd.PersistentFlags().StringArrayP("output", "o", nil, "Output file to write results to (defaults to stdout), can be used multiple times. Files ending in .json get json lines, .csv files get csv and all others plain text")
+	rootCmd.PersistentFlags().String("output-format", libgobuster.OutputPlain, "Format of the results on stdout and in output files without a .json or .csv extension (plain, json, csv). json prints one object per line, csv a header row and one row per result")
+	rootCmd.PersistentFlags().StringArray("output-filter", nil, "Only write results matching the rule to an output, e.g. 'hits.json tag=secret-file' or 'stdout status=200-299'. Can be used multiple times, a result is written if it matches one of the rules of the output")
+	rootCmd.PersistentFlags().StringArray("output-redact", nil, "Redact urls, paths and hostnames written to an output, e.g. 'stdout hash' or 'shared.txt truncate'. hash replaces them with a short HMAC-SHA256 keyed with a random key of the run, truncate keeps the first characters")
+	rootCmd.PersistentFlags().Bool("output-redact-print-key", false, "Show the random key of the hash redaction, so the hashes can be compared with the ones of known values")
+	rootCmd.PersistentFlags().String("output-encrypt", "", "Encrypt all output files to the age recipient (age:age1...), decrypt them with age -d -i key.txt")
+	rootCmd.PersistentFlags().String("output-sign", "", "ed25519 private key (PEM) to sign a manifest of every output file with, written next to it as <output>.manifest.json. Check the files with the verify command")
+	rootCmd.PersistentFlags().String("resume", "", "State file the progress and findings are checkpointed to. If it exists the run is resumed from it, it is removed once the run is finished")
+	rootCmd.PersistentFlags().String("status-file", "", "File which is rewritten every few seconds with the progress of the run as json (requests, rate, errors and findings) for external monitoring")
+	rootCmd.PersistentFlags().String("workspace", "", "Directory to store the findings of every finished run in, see the stats command")
+	rootCmd.PersistentFlags().String("db", "", "SQLite database to store every finished run with its options and findings in, so scans of the same target can be queried and compared with SQL")
+	rootCmd.PersistentFlags().String("report", "", "Write a html report with the configuration and a sortable table of all findings per status code to the file at the end of the run")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose output (errors)")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Don't print the banner and other noise")
 	rootCmd.PersistentFlags().BoolP("no-progress", "z", false, "Don't display progress")
 	rootCmd.PersistentFlags().Bool("no-error", false, "Don't display errors")
 	rootCmd.PersistentFlags().StringP("pattern", "p", "", "File containing replacement patterns")
+	rootCmd.PersistentFlags().String("known-file", "", "File containing already known entries (one per line) which will be skipped")
+	rootCmd.PersistentFlags().String("resume-from-output", "", "Output file (plain or json lines) of a previous run, the entries found there will be skipped")
+	rootCmd.PersistentFlags().String("rules-file", "", "YAML file containing rules for conditional follow up requests like retrying with additional headers (dir mode only)")
+	rootCmd.PersistentFlags().String("script", "", "Starlark script implementing on_request, on_response and on_result hooks to modify requests and classify results")
+	rootCmd.PersistentFlags().Int("rate-limit", 0, "Maximum number of requests per second and host (defaults to 0 which means no limit)")
+	rootCmd.PersistentFlags().String("rate-file", "", "File to share the rate limit with other tools scanning the same hosts at the same time")
+	rootCmd.PersistentFlags().String("suppress-file", "", "File containing false positive rules (one per line, e.g. 'status=200 size=1234 regex=^/static/') to hide from the results")
+	rootCmd.PersistentFlags().Int("stop-after-findings", 0, "Stop after the given number of findings (defaults to 0 which means no limit)")
+	rootCmd.PersistentFlags().StringArray("stop-on-tag", []string{}, "Stop as soon as a finding with the given tag is found (e.g. secret-file)")
+	rootCmd.PersistentFlags().Duration("max-time", 0, "Stop the run after the given time (e.g. 30m, defaults to 0 which means no limit)")
+	rootCmd.PersistentFlags().String("max-memory", "", "Pause the processing of new words while the memory usage is above the given size (e.g. 512MB), the garbage collector also uses it as soft limit")
+	rootCmd.PersistentFlags().Int("max-errors", 0, "Stop the run after the given number of errors (defaults to 0 which means no limit)")
+	rootCmd.PersistentFlags().String("word-policy", libgobuster.WordPolicySkip, fmt.Sprintf("What to do with words which can not be part of an url or a host name, like labels longer than 63 bytes in dns mode (%s). skip skips them with a message, encode percent encodes invalid characters of paths and truncate cuts the words", strings.Join(libgobuster.WordPolicies(), ", ")))
+	rootCmd.PersistentFlags().Bool("notify", false, "Show a desktop notification when the run is finished")
+	rootCmd.PersistentFlags().StringArray("notify-filter", nil, "Show a desktop notification for every finding matching the rule (e.g. 'tag=secret-file'), can be used multiple times")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Only print the configuration and the estimated number of requests and duration, nothing is sent to the target")
+	rootCmd.PersistentFlags().Bool("estimate-duration", false, fmt.Sprintf("Send a HEAD request to the target before the scan to measure the latency the duration is estimated with (http modes), otherwise %s per request are assumed", libgobuster.AssumedLatency))
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Do not ask for confirmation before starting scans estimated to take longer than an hour")
+	rootCmd.PersistentFlags().Bool("force", false, "Do not ask for confirmation when the configuration looks destructive or noisy")
+	rootCmd.PersistentFlags().Bool("timing", false, "Show the duration and time to first byte of every request (dir, fuzz and vhost mode only)")
+	rootCmd.PersistentFlags().Bool("cluster", false, "Group similar results (status, title and fuzzy hash of the body) at the end of the run (dir mode only)")
+	rootCmd.PersistentFlags().Bool("tree", false, "Print all findings as an indented tree of paths at the end of the run (dir mode only)")
+	rootCmd.PersistentFlags().String("lang", "", "Language of the messages and summaries (en, de, es), defaults to the language of the environment (LANG)")
+	rootCmd.PersistentFlags().Int64("seed", 0, "Seed of all random values (wildcard probes, placeholders, random user agents and proxies) to reproduce a run, the seed of a run is shown with --debug. Use --threads 1 to also get the same order")
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable color output")
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug output")
 }
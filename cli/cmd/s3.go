@@ -46,17 +46,31 @@ func parseS3Options() (*libgobuster.Options, *gobusters3.OptionsS3, error) {
 
 	pluginOpts.UserAgent = httpOpts.UserAgent
 	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
 	pluginOpts.Timeout = httpOpts.Timeout
 	pluginOpts.NoTLSValidation = httpOpts.NoTLSValidation
 	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
 	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
 	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
+
+	pluginOpts.Headers, err = parseHeaders(cmdS3)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	pluginOpts.MaxFilesToList, err = cmdS3.Flags().GetInt("maxfiles")
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for maxfiles: %w", err)
 	}
 
+	if err := globalopts.Validate("s3", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
 	return globalopts, pluginOpts, nil
 }
 
@@ -69,6 +83,7 @@ func init() {
 	}
 
 	addBasicHTTPOptions(cmdS3)
+	addHeadersOption(cmdS3)
 	cmdS3.Flags().IntP("maxfiles", "m", 5, "max files to list when listing buckets (only shown in verbose mode)")
 
 	cmdS3.PersistentPreRun = func(cmd *cobra.Command, args []string) {
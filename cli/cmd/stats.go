@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdStats *cobra.Command
+
+func runStats(cmd *cobra.Command, args []string) error {
+	workspace, err := cmd.Flags().GetString("workspace")
+	if err != nil {
+		return fmt.Errorf("invalid value for workspace: %w", err)
+	}
+	if workspace == "" {
+		return fmt.Errorf("please provide the workspace to read the runs from (--workspace)")
+	}
+
+	top, err := cmd.Flags().GetInt("top")
+	if err != nil {
+		return fmt.Errorf("invalid value for top: %w", err)
+	}
+	if top <= 0 {
+		return fmt.Errorf("top must be greater than 0")
+	}
+
+	runs, err := libgobuster.LoadRuns(workspace)
+	if err != nil {
+		return fmt.Errorf("could not load workspace: %w", err)
+	}
+	if len(runs) == 0 {
+		return fmt.Errorf("no runs found in workspace %s", workspace)
+	}
+
+	stats := libgobuster.ComputeStats(runs)
+
+	fmt.Printf("Workspace:   %s\n", workspace)
+	fmt.Printf("Runs:        %d\n", stats.Runs)
+	fmt.Printf("Findings:    %d\n", stats.Findings)
+
+	fmt.Printf("\nFindings over time:\n")
+	for _, d := range stats.Days {
+		fmt.Printf("  %s  %4d runs  %6d findings\n", d.Day, d.Runs, d.Findings)
+	}
+
+	fmt.Printf("\nTargets:\n")
+	for i, t := range stats.Targets {
+		if i >= top {
+			fmt.Printf("  ... and %d more\n", len(stats.Targets)-top)
+			break
+		}
+		fmt.Printf("  %s: %d runs, %d requests, %d findings, %d unique paths, last run %s\n",
+			t.Target, t.Runs, t.Requests, t.Findings, t.UniquePaths, t.LastRun.Format("2006-01-02 15:04"))
+	}
+
+	fmt.Printf("\nStatus codes:\n")
+	printCountStats(stats.StatusCodes, top)
+
+	fmt.Printf("\nWordlists:\n")
+	for i, w := range stats.Wordlists {
+		if i >= top {
+			fmt.Printf("  ... and %d more\n", len(stats.Wordlists)-top)
+			break
+		}
+		fmt.Printf("  %s: %.3f%% hit rate (%d findings in %d requests, %d runs)\n",
+			w.Wordlist, w.HitRate(), w.Findings, w.Requests, w.Runs)
+	}
+
+	fmt.Printf("\nMost productive words:\n")
+	for i, w := range stats.Words {
+		if i >= top {
+			fmt.Printf("  ... and %d more\n", len(stats.Words)-top)
+			break
+		}
+		fmt.Printf("  %-20s %d runs (%.1f%%), %d targets\n", w.Word, w.Runs, stats.WordHitRate(w), w.Targets)
+	}
+
+	return nil
+}
+
+func printCountStats(counts []libgobuster.CountStats, top int) {
+	for i, c := range counts {
+		if i >= top {
+			fmt.Printf("  ... and %d more\n", len(counts)-top)
+			break
+		}
+		fmt.Printf("  %-20s %d\n", c.Value, c.Count)
+	}
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdStats = &cobra.Command{
+		Use:   "stats",
+		Short: "Shows statistics of all runs stored in a workspace",
+		Args:  cobra.NoArgs,
+		RunE:  runStats,
+	}
+
+	cmdStats.Flags().Int("top", 10, "Number of entries to show per section")
+
+	rootCmd.AddCommand(cmdStats)
+}
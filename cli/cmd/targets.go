@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// parseTargets returns the targets of the url option. It is either a single
+// url, a comma separated list of urls with scheme or a file with one url
+// per line. Urls containing commas like in a query string are not split
+// unless all parts have a scheme
+func parseTargets(value string) ([]string, error) {
+	var targets []string
+	switch {
+	case !strings.Contains(value, "://") && isFile(value):
+		var err error
+		targets, err = readTargetFile(value)
+		if err != nil {
+			return nil, err
+		}
+	case isURLList(value):
+		for _, t := range strings.Split(value, ",") {
+			targets = append(targets, strings.TrimSpace(t))
+		}
+	default:
+		targets = []string{value}
+	}
+
+	for i, t := range targets {
+		u, err := normalizeURL(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target %s: %w", t, err)
+		}
+		targets[i] = u
+	}
+	return targets, nil
+}
+
+// isFile checks if the value is the path of an existing regular file
+func isFile(value string) bool {
+	info, err := os.Stat(value)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// isURLList checks if the value is a comma separated list of urls with scheme
+func isURLList(value string) bool {
+	parts := strings.Split(value, ",")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, p := range parts {
+		if !strings.Contains(strings.TrimSpace(p), "://") {
+			return false
+		}
+	}
+	return true
+}
+
+// readTargetFile reads one url per line. Empty lines and lines starting with
+// # are skipped
+func readTargetFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open target file: %w", err)
+	}
+	defer f.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read target file: %w", err)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("target file %s contains no urls", path)
+	}
+	return targets, nil
+}
+
+// newTargetPlugins creates one plugin per target of the options, newPlugin
+// is called with the url of every target
+func newTargetPlugins[T libgobuster.GobusterPlugin](opts libgobuster.HTTPOptions, newPlugin func(url string) (T, error)) ([]libgobuster.GobusterPlugin, error) {
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = []string{opts.URL}
+	}
+
+	plugins := make([]libgobuster.GobusterPlugin, len(targets))
+	for i, url := range targets {
+		plugin, err := newPlugin(url)
+		if err != nil {
+			return nil, err
+		}
+		plugins[i] = plugin
+	}
+	return plugins, nil
+}
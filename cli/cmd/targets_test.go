@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTargets(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "targets.txt")
+	if err := os.WriteFile(file, []byte("https://a.example.com\n# comment\n\nb.example.com:443\n"), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	tt := []struct {
+		testName      string
+		value         string
+		expected      []string
+		expectedError bool
+	}{
+		{"Single url", "https://example.com", []string{"https://example.com"}, false},
+		{"Scheme added", "example.com", []string{"http://example.com"}, false},
+		{"Comma separated", "https://a.example.com, http://b.example.com", []string{"https://a.example.com", "http://b.example.com"}, false},
+		{"Comma in query", "https://example.com/?ids=1,2", []string{"https://example.com/?ids=1,2"}, false},
+		{"File", file, []string{"https://a.example.com", "https://b.example.com:443"}, false},
+		{"Part without scheme", "https://a.example.com,https://b.example.com,c.example.com:8080", []string{"https://a.example.com,https://b.example.com,c.example.com:8080"}, false},
+		{"Unknown scheme", "example.com:8080", nil, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			targets, err := parseTargets(x.value)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for %s", x.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if strings.Join(targets, " ") != strings.Join(x.expected, " ") {
+				t.Fatalf("expected %v, got %v", x.expected, targets)
+			}
+		})
+	}
+}
@@ -55,6 +55,10 @@ func parseTFTPOptions() (*libgobuster.Options, *gobustertftp.OptionsTFTP, error)
 		return nil, nil, fmt.Errorf("invalid value for timeout: %w", err)
 	}
 
+	if err := globalopts.Validate("tftp"); err != nil {
+		return nil, nil, err
+	}
+
 	return globalopts, pluginOpts, nil
 }
 
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdVerify *cobra.Command
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	keyFile, err := cmd.Flags().GetString("key")
+	if err != nil {
+		return fmt.Errorf("invalid value for key: %w", err)
+	}
+
+	var publicKey ed25519.PublicKey
+	if keyFile != "" {
+		publicKey, err = libgobuster.ParsePublicKey(keyFile)
+		if err != nil {
+			return fmt.Errorf("invalid value for key: %w", err)
+		}
+	}
+
+	for _, filename := range args {
+		m, chainChecked, err := libgobuster.VerifyOutput(filename, publicKey)
+		if err != nil {
+			return fmt.Errorf("verification of %s failed: %w", filename, err)
+		}
+
+		fmt.Printf("File:       %s\n", filename)
+		fmt.Printf("Results:    %d\n", m.Results)
+		fmt.Printf("Scanned:    %s - %s\n", m.Started.Format("2006-01-02 15:04:05"), m.Finished.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Signed by:  %s\n", m.PublicKey)
+		if chainChecked {
+			fmt.Printf("Status:     signature, file and result chain verified\n")
+		} else {
+			fmt.Printf("Status:     signature and file verified, the result chain is only checked for unencrypted json files\n")
+		}
+		if publicKey == nil {
+			fmt.Printf("Warning:    no --key given, compare the signer with the expected key\n")
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdVerify = &cobra.Command{
+		Use:   "verify <output file>...",
+		Short: "Verifies output files against their signed manifests (see --output-sign)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runVerify,
+	}
+
+	cmdVerify.Flags().String("key", "", "ed25519 public key (PEM) the manifests have to be signed with")
+
+	rootCmd.AddCommand(cmdVerify)
+}
@@ -19,13 +19,21 @@ func runVhost(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error on parsing arguments: %w", err)
 	}
 
-	plugin, err := gobustervhost.NewGobusterVhost(globalopts, pluginopts)
+	plugins, err := newTargetPlugins(pluginopts.HTTPOptions, func(url string) (*gobustervhost.GobusterVhost, error) {
+		opts := *pluginopts
+		opts.HTTPOptions = pluginopts.ForTarget(url)
+		plugin, err := gobustervhost.NewGobusterVhost(globalopts, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error on creating gobustervhost: %w", err)
+		}
+		return plugin, nil
+	})
 	if err != nil {
-		return fmt.Errorf("error on creating gobustervhost: %w", err)
+		return err
 	}
 
 	log := libgobuster.NewLogger(globalopts.Debug)
-	if err := cli.Gobuster(mainContext, globalopts, plugin, log); err != nil {
+	if err := cli.GobusterTargets(mainContext, globalopts, plugins, log); err != nil {
 		log.Debugf("%#v", err)
 		return fmt.Errorf("error on running gobuster: %w", err)
 	}
@@ -46,9 +54,13 @@ func parseVhostOptions() (*libgobuster.Options, *gobustervhost.OptionsVhost, err
 	}
 	pluginOpts.Password = httpOpts.Password
 	pluginOpts.URL = httpOpts.URL
+	pluginOpts.Targets = httpOpts.Targets
+	pluginOpts.Overrides = httpOpts.Overrides
 	pluginOpts.UserAgent = httpOpts.UserAgent
 	pluginOpts.Username = httpOpts.Username
 	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
 	pluginOpts.Cookies = httpOpts.Cookies
 	pluginOpts.Timeout = httpOpts.Timeout
 	pluginOpts.FollowRedirect = httpOpts.FollowRedirect
@@ -57,8 +69,12 @@ func parseVhostOptions() (*libgobuster.Options, *gobustervhost.OptionsVhost, err
 	pluginOpts.Method = httpOpts.Method
 	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
 	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
 	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
 	pluginOpts.NoCanonicalizeHeaders = httpOpts.NoCanonicalizeHeaders
+	pluginOpts.CacheBypass = httpOpts.CacheBypass
 
 	pluginOpts.AppendDomain, err = cmdVhost.Flags().GetBool("append-domain")
 	if err != nil {
@@ -69,7 +85,7 @@ func parseVhostOptions() (*libgobuster.Options, *gobustervhost.OptionsVhost, err
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for exclude-length: %w", err)
 	}
-	ret, err := libgobuster.ParseCommaSeparatedInt(pluginOpts.ExcludeLength)
+	ret, err := libgobuster.ParseIntRanges(pluginOpts.ExcludeLength)
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid value for exclude-length: %w", err)
 	}
@@ -80,6 +96,15 @@ func parseVhostOptions() (*libgobuster.Options, *gobustervhost.OptionsVhost, err
 		return nil, nil, fmt.Errorf("invalid value for domain: %w", err)
 	}
 
+	pluginOpts.CertInfo, err = cmdVhost.Flags().GetBool("cert-info")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for cert-info: %w", err)
+	}
+
+	if err := globalopts.Validate("vhost", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
 	return globalopts, pluginOpts, nil
 }
 
@@ -94,7 +119,8 @@ func init() {
 		log.Fatalf("%v", err)
 	}
 	cmdVhost.Flags().BoolP("append-domain", "", false, "Append main domain from URL to words from wordlist. Otherwise the fully qualified domains need to be specified in the wordlist.")
-	cmdVhost.Flags().String("exclude-length", "", "exclude the following content lengths (completely ignores the status). You can separate multiple lengths by comma and it also supports ranges like 203-206")
+	cmdVhost.Flags().String("exclude-length", "", "exclude the following content lengths (completely ignores the status). You can separate multiple lengths by comma and it also supports ranges like 203-206 or open ranges like 5000-")
+	cmdVhost.Flags().Bool("cert-info", false, "Show the certificate of https targets and check the names of its SANs below the domain first")
 	cmdVhost.Flags().String("domain", "", "the domain to append when using an IP address as URL. If left empty and you specify a domain based URL the hostname from the URL is extracted")
 
 	cmdVhost.PersistentPreRun = func(cmd *cobra.Command, args []string) {
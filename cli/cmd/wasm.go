@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/OJ/gobuster/v3/cli"
+	"github.com/OJ/gobuster/v3/gobusterwasm"
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// nolint:gochecknoglobals
+var cmdWasm *cobra.Command
+
+func runWasm(cmd *cobra.Command, args []string) error {
+	globalopts, pluginopts, err := parseWasmOptions()
+	if err != nil {
+		return fmt.Errorf("error on parsing arguments: %w", err)
+	}
+
+	plugins, err := newTargetPlugins(pluginopts.HTTPOptions, func(url string) (*gobusterwasm.GobusterWasm, error) {
+		opts := *pluginopts
+		opts.HTTPOptions = pluginopts.ForTarget(url)
+		plugin, err := gobusterwasm.NewGobusterWasm(globalopts, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error on creating gobusterwasm: %w", err)
+		}
+		return plugin, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log := libgobuster.NewLogger(globalopts.Debug)
+	if err := cli.GobusterTargets(mainContext, globalopts, plugins, log); err != nil {
+		log.Debugf("%#v", err)
+		return fmt.Errorf("error on running gobuster: %w", err)
+	}
+	return nil
+}
+
+func parseWasmOptions() (*libgobuster.Options, *gobusterwasm.OptionsWasm, error) {
+	globalopts, err := parseGlobalOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pluginOpts := gobusterwasm.NewOptionsWasm()
+
+	httpOpts, err := parseCommonHTTPOptions(cmdWasm)
+	if err != nil {
+		return nil, nil, err
+	}
+	pluginOpts.Password = httpOpts.Password
+	pluginOpts.URL = httpOpts.URL
+	pluginOpts.Targets = httpOpts.Targets
+	pluginOpts.Overrides = httpOpts.Overrides
+	pluginOpts.UserAgent = httpOpts.UserAgent
+	pluginOpts.Username = httpOpts.Username
+	pluginOpts.Proxy = httpOpts.Proxy
+	pluginOpts.Proxies = httpOpts.Proxies
+	pluginOpts.ProxyRotation = httpOpts.ProxyRotation
+	pluginOpts.Cookies = httpOpts.Cookies
+	pluginOpts.Timeout = httpOpts.Timeout
+	pluginOpts.FollowRedirect = httpOpts.FollowRedirect
+	pluginOpts.NoTLSValidation = httpOpts.NoTLSValidation
+	pluginOpts.Headers = httpOpts.Headers
+	pluginOpts.Method = httpOpts.Method
+	pluginOpts.RetryOnTimeout = httpOpts.RetryOnTimeout
+	pluginOpts.RetryAttempts = httpOpts.RetryAttempts
+	pluginOpts.RetryWait = httpOpts.RetryWait
+	pluginOpts.TLSCertificate = httpOpts.TLSCertificate
+	pluginOpts.Limits = httpOpts.Limits
+	pluginOpts.TLSPolicy = httpOpts.TLSPolicy
+	pluginOpts.NoCanonicalizeHeaders = httpOpts.NoCanonicalizeHeaders
+	pluginOpts.CacheBypass = httpOpts.CacheBypass
+
+	pluginOpts.Plugin, err = cmdWasm.Flags().GetString("plugin")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for plugin: %w", err)
+	}
+
+	if err := globalopts.Validate("wasm", pluginOpts); err != nil {
+		return nil, nil, err
+	}
+
+	return globalopts, pluginOpts, nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdWasm = &cobra.Command{
+		Use:   "wasm",
+		Short: "Uses a WebAssembly plugin implementing a custom mode",
+		RunE:  runWasm,
+	}
+
+	if err := addCommonHTTPOptions(cmdWasm); err != nil {
+		log.Fatalf("%v", err)
+	}
+	cmdWasm.Flags().String("plugin", "", fmt.Sprintf("WebAssembly module implementing the gobuster plugin ABI (version %d)", gobusterwasm.ABIVersion))
+
+	cmdWasm.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		configureGlobalOptions()
+	}
+
+	rootCmd.AddCommand(cmdWasm)
+}
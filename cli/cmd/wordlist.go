@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/spf13/cobra"
+)
+
+// maxLintIssues is the maximum number of issues printed per category
+const maxLintIssues = 10
+
+// nolint:gochecknoglobals
+var cmdWordlist *cobra.Command
+
+// nolint:gochecknoglobals
+var cmdWordlistLint *cobra.Command
+
+// nolint:gochecknoglobals
+var cmdWordlistOptimize *cobra.Command
+
+func runWordlistLint(cmd *cobra.Command, args []string) error {
+	mode, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return fmt.Errorf("invalid value for mode: %w", err)
+	}
+
+	switch mode {
+	case "dir", "dns", "fuzz", "vhost", "s3", "gcs", "azure", "tftp":
+	default:
+		return fmt.Errorf("invalid mode %q", mode)
+	}
+
+	extensions, err := cmd.Flags().GetString("extensions")
+	if err != nil {
+		return fmt.Errorf("invalid value for extensions: %w", err)
+	}
+
+	ext, err := libgobuster.ParseExtensions(extensions)
+	if err != nil {
+		return fmt.Errorf("invalid value for extensions: %w", err)
+	}
+
+	patternFile, err := cmd.Flags().GetString("pattern")
+	if err != nil {
+		return fmt.Errorf("invalid value for pattern: %w", err)
+	}
+
+	patterns := 0
+	if patternFile != "" {
+		p, err := os.Open(patternFile)
+		if err != nil {
+			return fmt.Errorf("could not open pattern file %q: %w", patternFile, err)
+		}
+		defer p.Close()
+		scanner := bufio.NewScanner(p)
+		for scanner.Scan() {
+			patterns++
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("could not read pattern file %q: %w", patternFile, err)
+		}
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer f.Close()
+
+	report, err := libgobuster.AnalyzeWordlist(f, mode)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wordlist:        %s\n", args[0])
+	fmt.Printf("Lines:           %d\n", report.Lines)
+	fmt.Printf("Words:           %d (%d empty or comment lines skipped)\n", report.Words, report.Skipped)
+	fmt.Printf("Unique words:    %d\n", report.Unique)
+	// extensions are only applied in dir mode
+	perWord := 1 + patterns
+	if mode == "dir" {
+		perWord += ext.Length()
+	}
+	fmt.Printf("Est. requests:   %d\n", report.EstimatedRequests(perWord))
+
+	printLintIssues("Duplicate lines", report.Duplicates)
+	printLintIssues("Encoding problems", report.Encoding)
+	printLintIssues(fmt.Sprintf("Invalid words for %s mode", mode), report.Invalid)
+
+	return nil
+}
+
+func printLintIssues(title string, issues []libgobuster.WordlistIssue) {
+	fmt.Printf("%s: %d\n", title, len(issues))
+	for i, issue := range issues {
+		if i >= maxLintIssues {
+			fmt.Printf("  ... and %d more\n", len(issues)-maxLintIssues)
+			break
+		}
+		fmt.Printf("  line %d: %q (%s)\n", issue.Line, issue.Word, issue.Reason)
+	}
+}
+
+func runWordlistOptimize(cmd *cobra.Command, args []string) error {
+	workspace, err := cmd.Flags().GetString("workspace")
+	if err != nil {
+		return fmt.Errorf("invalid value for workspace: %w", err)
+	}
+	if workspace == "" {
+		return fmt.Errorf("please provide the workspace to read the runs from (--workspace)")
+	}
+
+	minRuns, err := cmd.Flags().GetInt("min-runs")
+	if err != nil {
+		return fmt.Errorf("invalid value for min-runs: %w", err)
+	}
+
+	outputs, err := cmd.Flags().GetStringArray("output")
+	if err != nil {
+		return fmt.Errorf("invalid value for output: %w", err)
+	}
+	if len(outputs) > 1 {
+		return fmt.Errorf("only one output file is supported")
+	}
+
+	runs, err := libgobuster.LoadRuns(workspace)
+	if err != nil {
+		return fmt.Errorf("could not load workspace: %w", err)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer f.Close()
+
+	optimized, err := libgobuster.OptimizeWordlist(f, libgobuster.ComputeStats(runs), minRuns)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if len(outputs) == 1 {
+		out, err = os.Create(outputs[0])
+		if err != nil {
+			return fmt.Errorf("error on creating output file: %w", err)
+		}
+		defer out.Close()
+	}
+
+	w := bufio.NewWriter(out)
+	for _, word := range optimized.Words {
+		if _, err := fmt.Fprintln(w, word); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// the summary goes to stderr so the wordlist can be piped
+	fmt.Fprintf(os.Stderr, "%d words written (%d productive in %d runs, %d trimmed, %d duplicates removed)\n",
+		len(optimized.Words), optimized.Productive, len(runs), optimized.Trimmed, optimized.Duplicates)
+
+	return nil
+}
+
+// nolint:gochecknoinits
+func init() {
+	cmdWordlist = &cobra.Command{
+		Use:   "wordlist",
+		Short: "Wordlist utilities",
+	}
+
+	cmdWordlistLint = &cobra.Command{
+		Use:   "lint <file>",
+		Short: "Reports duplicates, invalid words and the estimated number of requests of a wordlist",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWordlistLint,
+	}
+
+	cmdWordlistLint.Flags().String("mode", "dir", "The mode the wordlist will be used with (dir, dns, fuzz, vhost, s3, gcs, azure, tftp)")
+	cmdWordlistLint.Flags().StringP("extensions", "x", "", "File extension(s) to include in the estimation in dir mode")
+
+	cmdWordlistOptimize = &cobra.Command{
+		Use:   "optimize <file>",
+		Short: "Reorders a wordlist so words which produced findings in the runs of a workspace come first",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runWordlistOptimize,
+	}
+
+	cmdWordlistOptimize.Flags().Int("min-runs", 0, "Remove words which produced findings in less than this number of runs, 0 keeps all words")
+
+	cmdWordlist.AddCommand(cmdWordlistLint)
+	cmdWordlist.AddCommand(cmdWordlistOptimize)
+	rootCmd.AddCommand(cmdWordlist)
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchEnter calls pressed whenever enter is pressed on the terminal until
+// the context is canceled. stdin is polled with a timeout so no read is left
+// blocking at the end of the run
+func watchEnter(ctx context.Context, pressed func()) error {
+	fds := []unix.PollFd{{Fd: int32(os.Stdin.Fd()), Events: unix.POLLIN}}
+	buf := make([]byte, 256)
+	for ctx.Err() == nil {
+		n, err := unix.Poll(fds, int(stdinPollTimeout.Milliseconds()))
+		if errors.Is(err, unix.EINTR) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+		if fds[0].Revents&unix.POLLIN == 0 {
+			// hangup or error
+			return nil
+		}
+		// the terminal is in line mode so this returns the typed line
+		n, err = os.Stdin.Read(buf)
+		if err != nil || n == 0 {
+			return err
+		}
+		if bytes.IndexByte(buf[:n], '\n') >= 0 {
+			pressed()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build windows
+
+package cli
+
+import (
+	"context"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	keyEvent = 0x0001
+	vkReturn = 0x0D
+)
+
+// nolint:gochecknoglobals
+var procReadConsoleInputW = windows.NewLazySystemDLL("kernel32.dll").NewProc("ReadConsoleInputW")
+
+// inputRecord is the INPUT_RECORD of a KEY_EVENT
+type inputRecord struct {
+	eventType       uint16
+	_               uint16
+	keyDown         int32
+	repeatCount     uint16
+	virtualKeyCode  uint16
+	virtualScanCode uint16
+	char            uint16
+	controlKeyState uint32
+}
+
+// watchEnter calls pressed whenever enter is pressed on the console until the
+// context is canceled. The console is waited on with a timeout and only the
+// available input events are read, so no read is left blocking at the end of
+// the run
+func watchEnter(ctx context.Context, pressed func()) error {
+	handle := windows.Handle(os.Stdin.Fd())
+	records := make([]inputRecord, 16)
+	for ctx.Err() == nil {
+		event, err := windows.WaitForSingleObject(handle, uint32(stdinPollTimeout.Milliseconds()))
+		if err != nil {
+			return err
+		}
+		if event != windows.WAIT_OBJECT_0 {
+			continue
+		}
+		var n uint32
+		r, _, err := procReadConsoleInputW.Call(uintptr(handle), uintptr(unsafe.Pointer(&records[0])), uintptr(len(records)), uintptr(unsafe.Pointer(&n)))
+		if r == 0 {
+			return err
+		}
+		for _, record := range records[:n] {
+			if record.eventType == keyEvent && record.keyDown != 0 && record.virtualKeyCode == vkReturn {
+				pressed()
+			}
+		}
+	}
+	return nil
+}
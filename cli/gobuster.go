@@ -1,7 +1,11 @@
 package cli
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -9,54 +13,197 @@ import (
 	"time"
 
 	"github.com/OJ/gobuster/v3/libgobuster"
+	"golang.org/x/term"
 )
 
 const ruler = "==============================================================="
 const cliProgressUpdate = 500 * time.Millisecond
 
+// stdinPollTimeout is how often the targets worker checks if the run is over
+// while waiting for input
+const stdinPollTimeout = 200 * time.Millisecond
+
+// confirmDuration is the estimated duration after which a confirmation is needed
+const confirmDuration = time.Hour
+
 // resultWorker outputs the results as they come in. This needs to be a range and should not handle
 // the context so the channel always has a receiver and libgobuster will not block.
-func resultWorker(g *libgobuster.Gobuster, filename string, wg *sync.WaitGroup) {
+// Every result is also written to all outputs, each output and stdout only get the results
+// matching their filters if any are configured and redacted if configured.
+// If records is not nil all found results are collected for clustering, the tree, the notification, the workspace, the report and the database.
+// Findings are passed to notifications if it is not nil.
+func resultWorker(g *libgobuster.Gobuster, outputs libgobuster.MultiOutput, records *[]libgobuster.ResultRecord, notifications *notifier, status *libgobuster.StatusWriter, stops *stopper, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	var f *os.File
-	var err error
-	if filename != "" {
-		f, err = os.Create(filename)
-		if err != nil {
-			g.Logger.Fatalf("error on creating output file: %v", err)
-		}
-		defer f.Close()
+	if g.Opts.OutputFormat == libgobuster.OutputCSV {
+		printCSV(g, libgobuster.CSVHeader())
 	}
 
+	findings := 0
+	stopped := false
 	for r := range g.Progress.ResultChan {
+		record := libgobuster.NewResultRecord(r)
+		record.Timestamp = time.Now()
+		if libgobuster.MatchesAnyFilter(g.Opts.Suppressions, record) {
+			// known false positive
+			continue
+		}
+		keep, err := g.Opts.Script.OnResult(record)
+		if err != nil {
+			g.Logger.Error(err.Error())
+		}
+		if !keep {
+			continue
+		}
+
 		s, err := r.ResultToString()
 		if err != nil {
 			g.Logger.Fatal(err)
 		}
-		if s != "" {
-			s = strings.TrimSpace(s)
-			_, _ = fmt.Printf("%s%s\n", TERMINAL_CLEAR_LINE, s)
-			if f != nil {
-				err = writeToFile(f, s)
-				if err != nil {
-					g.Logger.Fatalf("error on writing output file: %v", err)
-				}
-			}
+		s = strings.TrimSpace(s)
+		writeResult(g, outputs, record, s)
+
+		if !record.Found {
+			continue
+		}
+		status.AddFinding()
+		if g.Opts.StateFile != "" {
+			g.AddStateResult(record, s)
 		}
+		if records != nil {
+			*records = append(*records, record)
+		}
+		if notifications != nil {
+			notifications.finding(record)
+		}
+		if stopped {
+			continue
+		}
+		findings++
+		if reason := stopReason(g.Opts, findings, record); reason != "" {
+			stopped = true
+			stops.stop(libgobuster.StopFindings, reason)
+		}
+	}
+}
+
+// writeResult prints the result to stdout and writes it to all outputs. The
+// filters and redactions of stdout and the outputs are applied
+func writeResult(g *libgobuster.Gobuster, outputs libgobuster.MultiOutput, record libgobuster.ResultRecord, s string) {
+	if s == "" {
+		return
+	}
+	stdoutFilters := libgobuster.FiltersFor(g.Opts.OutputFilters, libgobuster.OutputStdout)
+	if len(stdoutFilters) == 0 || libgobuster.MatchesAnyFilter(stdoutFilters, record) {
+		printed, line := record, s
+		if redaction := libgobuster.RedactionFor(g.Opts.OutputRedactions, libgobuster.OutputStdout); redaction != "" {
+			printed, line = libgobuster.Redact(record, s, redaction, g.Opts.RedactionKey)
+		}
+		printResult(g, printed, line)
+	}
+	if err := outputs.WriteResult(record, s); err != nil {
+		g.Logger.Fatal(err)
+	}
+}
+
+// printResult prints the result to stdout in the configured format
+func printResult(g *libgobuster.Gobuster, record libgobuster.ResultRecord, s string) {
+	switch g.Opts.OutputFormat {
+	case libgobuster.OutputJSON:
+		line, err := json.Marshal(libgobuster.NewJSONResult(record))
+		if err != nil {
+			g.Logger.Fatal(err)
+		}
+		clearProgress(g)
+		_, _ = fmt.Printf("%s\n", line)
+	case libgobuster.OutputCSV:
+		printCSV(g, libgobuster.NewCSVRecord(record))
+	default:
+		_, _ = fmt.Printf("%s%s\n", TERMINAL_CLEAR_LINE, s)
+	}
+}
+
+// printCSV prints a single csv row to stdout
+func printCSV(g *libgobuster.Gobuster, row []string) {
+	clearProgress(g)
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(row); err != nil {
+		g.Logger.Fatal(err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		g.Logger.Fatal(err)
+	}
+}
+
+// clearProgress clears the progress line on stderr, keeping the escape
+// sequences out of stdout so it can be piped
+func clearProgress(g *libgobuster.Gobuster) {
+	if !g.Opts.Quiet && !g.Opts.NoProgress {
+		_, _ = fmt.Fprint(os.Stderr, TERMINAL_CLEAR_LINE)
+	}
+}
+
+// stopReason checks if the run should be stopped early and returns the reason
+func stopReason(opts *libgobuster.Options, findings int, record libgobuster.ResultRecord) string {
+	if opts.StopAfterFindings > 0 && findings >= opts.StopAfterFindings {
+		return fmt.Sprintf(libgobuster.T("Reached %d findings"), findings)
+	}
+	for _, t := range record.Tags {
+		if opts.StopOnTags.Contains(t) {
+			return fmt.Sprintf(libgobuster.T("Found %s tagged %q"), record.Path, t)
+		}
+	}
+	return ""
+}
+
+// stopper stops the run early and records the reason. Only the first reason
+// is kept if the run is stopped multiple times
+type stopper struct {
+	g      *libgobuster.Gobuster
+	cancel context.CancelFunc
+	mutex  sync.Mutex
+	err    *libgobuster.StoppedError
+}
+
+// stop cancels the run for the reason, detail is the human readable cause
+func (s *stopper) stop(reason libgobuster.StopReason, detail string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = &libgobuster.StoppedError{Reason: reason, Detail: detail}
+	if !s.g.Opts.Quiet {
+		s.g.Logger.Infof("%s%s", TERMINAL_CLEAR_LINE, fmt.Sprintf(libgobuster.T("%s, stopping"), detail))
 	}
+	s.cancel()
+}
+
+// stopped returns why the run was stopped early or nil
+func (s *stopper) stopped() *libgobuster.StoppedError {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.err
 }
 
 // errorWorker outputs the errors as they come in. This needs to be a range and should not handle
 // the context so the channel always has a receiver and libgobuster will not block.
-func errorWorker(g *libgobuster.Gobuster, wg *sync.WaitGroup) {
+// The run is stopped once the maximum number of errors is reached.
+func errorWorker(g *libgobuster.Gobuster, status *libgobuster.StatusWriter, stops *stopper, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	errs := 0
 	for e := range g.Progress.ErrorChan {
 		if !g.Opts.Quiet && !g.Opts.NoError {
 			g.Logger.Error(e.Error())
 			g.Logger.Debugf("%#v", e)
 		}
+		status.AddError()
+		errs++
+		if g.Opts.MaxErrors > 0 && errs == g.Opts.MaxErrors {
+			stops.stop(libgobuster.StopErrorBudget, fmt.Sprintf(libgobuster.T("Reached %d errors"), errs))
+		}
 	}
 }
 
@@ -86,16 +233,56 @@ func printProgress(g *libgobuster.Gobuster) {
 		requestsIssued := g.Progress.RequestsIssued()
 		requestsExpected := g.Progress.RequestsExpected()
 		if g.Opts.Wordlist == "-" {
-			s := fmt.Sprintf("%sProgress: %d", TERMINAL_CLEAR_LINE, requestsIssued)
+			s := TERMINAL_CLEAR_LINE + fmt.Sprintf(libgobuster.T("Progress: %d"), requestsIssued)
 			_, _ = fmt.Fprint(os.Stderr, s)
 			// only print status if we already read in the wordlist
 		} else if requestsExpected > 0 {
-			s := fmt.Sprintf("%sProgress: %d / %d (%3.2f%%)", TERMINAL_CLEAR_LINE, requestsIssued, requestsExpected, float32(requestsIssued)*100.0/float32(requestsExpected))
+			s := TERMINAL_CLEAR_LINE + fmt.Sprintf(libgobuster.T("Progress: %d / %d (%3.2f%%)"), requestsIssued, requestsExpected, float32(requestsIssued)*100.0/float32(requestsExpected))
+			if eta := g.Progress.ETA(); eta > 0 {
+				s += " " + fmt.Sprintf(libgobuster.T("[%.0f req/s, ETA: %s]"), g.Progress.Rate(), eta.Round(time.Second))
+			}
+			if targets := g.Progress.Targets(); len(targets) > 1 {
+				index, name, count := g.Progress.Target()
+				s += " " + fmt.Sprintf(libgobuster.T("[target %d/%d: %s %3.2f%%]"), index+1, count, name, targetPercent(targets[index]))
+			}
 			_, _ = fmt.Fprint(os.Stderr, s)
 		}
 	}
 }
 
+// targetPercent returns the percentage of the expected requests of the
+// target which were issued
+func targetPercent(t libgobuster.TargetProgress) float32 {
+	if t.RequestsExpected <= 0 {
+		return 0
+	}
+	return float32(t.RequestsIssued) * 100.0 / float32(t.RequestsExpected)
+}
+
+// printTargets prints the progress of every target of a run over multiple
+// targets, so lagging or skipped targets can be spotted
+func printTargets(g *libgobuster.Gobuster) {
+	targets := g.Progress.Targets()
+	for i, t := range targets {
+		line := fmt.Sprintf(libgobuster.T("Target %d/%d %s: %s, %d / %d requests (%3.2f%%)"), i+1, len(targets), t.Name, t.State, t.RequestsIssued, t.RequestsExpected, targetPercent(t))
+		if rate := t.Rate(); rate > 0 {
+			line += " " + fmt.Sprintf(libgobuster.T("[%.0f req/s, %s]"), rate, t.Elapsed().Round(time.Second))
+		}
+		g.Logger.Println(line)
+	}
+}
+
+// targetsWorker prints the progress of every target whenever enter is
+// pressed. It is only started if stdin is a terminal and stops once cancel()
+// is called on the context
+func targetsWorker(ctx context.Context, g *libgobuster.Gobuster, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if err := watchEnter(ctx, func() { printTargets(g) }); err != nil {
+		g.Logger.Debugf("could not read from stdin: %v", err)
+	}
+}
+
 // progressWorker outputs the progress every tick. It will stop once cancel() is called
 // on the context
 func progressWorker(ctx context.Context, g *libgobuster.Gobuster, wg *sync.WaitGroup) {
@@ -116,33 +303,205 @@ func progressWorker(ctx context.Context, g *libgobuster.Gobuster, wg *sync.WaitG
 	}
 }
 
-func writeToFile(f *os.File, output string) error {
-	_, err := f.WriteString(fmt.Sprintf("%s\n", output))
+// printClusters prints all groups of similar results with more than one member.
+// Only the first members are shown unless verbose output is enabled
+func printClusters(g *libgobuster.Gobuster, records []libgobuster.ResultRecord) {
+	const maxMembers = 5
+
+	clusters := libgobuster.ClusterRecords(records, libgobuster.DefaultClusterDistance)
+	first := true
+	for _, c := range clusters {
+		if len(c.Members) < 2 {
+			continue
+		}
+		if first {
+			g.Logger.Println(ruler)
+			g.Logger.Println(libgobuster.T("Similar results:"))
+			first = false
+		}
+		title := ""
+		if c.Title != "" {
+			title = fmt.Sprintf(" [Title: %s]", c.Title)
+		}
+		g.Logger.Printf(libgobuster.T("%d results (Status: %d)%s"), len(c.Members), c.StatusCode, title)
+		for i, m := range c.Members {
+			if i >= maxMembers && !g.Opts.Verbose {
+				g.Logger.Printf("    "+libgobuster.T("... and %d more (use -v to show all)"), len(c.Members)-maxMembers)
+				break
+			}
+			g.Logger.Printf("    %s", m.Path)
+		}
+	}
+}
+
+// printTree prints all findings as an indented tree of paths
+func printTree(g *libgobuster.Gobuster, records []libgobuster.ResultRecord) {
+	if len(records) == 0 {
+		return
+	}
+	g.Logger.Println(ruler)
+	g.Logger.Println(libgobuster.T("Findings:"))
+	for _, line := range libgobuster.BuildPathTree(records).Lines() {
+		g.Logger.Printf("    %s", line)
+	}
+}
+
+// preview prints the estimated number of requests and duration of the run
+// and warnings about dangerous configurations. It asks for confirmation if the
+// scan is huge or dangerous and returns false if the run should not be
+// started. Nothing is sent to the target before the configuration is confirmed
+// and nothing at all on a dry run
+func preview(ctx context.Context, g *libgobuster.Gobuster) (bool, error) {
+	requests, err := g.EstimateRequests()
 	if err != nil {
-		return fmt.Errorf("[!] Unable to write to file %w", err)
+		return false, err
 	}
-	return nil
+
+	// requests is 0 if the wordlist is read from stdin
+	if requests > 0 && (!g.Opts.Quiet || g.Opts.DryRun) {
+		g.Logger.Printf(libgobuster.T("Estimated requests: %d"), requests)
+	}
+
+	warnings := g.Warnings(requests)
+	for _, w := range warnings {
+		g.Logger.Warnf("%s", w)
+	}
+
+	if !g.Opts.DryRun && len(warnings) > 0 && !g.Opts.Force {
+		ok, err := confirm(g, libgobuster.T("This configuration looks destructive or noisy (use --force to skip this check)."))
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	if requests == 0 {
+		return !g.Opts.DryRun, nil
+	}
+
+	// the latency probe sends a request to the target, so it is opt-in and
+	// never sent on a dry run. Otherwise the duration is estimated with an
+	// assumed latency
+	latency, measured := libgobuster.AssumedLatency, false
+	if g.Opts.EstimateDuration && !g.Opts.DryRun {
+		if probed, err := g.ProbeLatency(ctx); err != nil {
+			g.Logger.Warnf("could not measure the latency, assuming %s: %v", latency, err)
+		} else {
+			latency, measured = probed, true
+		}
+	}
+	duration := g.EstimateDuration(requests, latency)
+	if !g.Opts.Quiet || g.Opts.DryRun {
+		if measured {
+			g.Logger.Printf(libgobuster.T("Estimated duration: %s (%s per request)"), duration.Round(time.Second), latency.Round(time.Millisecond))
+		} else {
+			g.Logger.Printf(libgobuster.T("Estimated duration: %s (assuming %s per request)"), duration.Round(time.Second), latency.Round(time.Millisecond))
+		}
+	}
+
+	if g.Opts.DryRun {
+		return false, nil
+	}
+	if duration > confirmDuration && !g.Opts.AssumeYes {
+		return confirm(g, fmt.Sprintf(libgobuster.T("This scan will take about %s (use --yes to skip this check)."), duration.Round(time.Minute)))
+	}
+
+	return true, nil
+}
+
+// confirm asks the user to confirm on stdin and returns true if the user
+// agreed. It fails if stdin is not a terminal, so runs in scripts do not
+// silently do nothing
+func confirm(g *libgobuster.Gobuster, message string) (bool, error) {
+	// stdin is already used by the wordlist
+	if g.Opts.Wordlist == "-" {
+		return false, fmt.Errorf("%s %s", message, libgobuster.T("Can not ask for confirmation when reading the wordlist from STDIN, aborting"))
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("%s %s", message, libgobuster.T("Can not ask for confirmation as STDIN is not a terminal, aborting"))
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s ", message, libgobuster.T("Continue? [y/N]"))
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if err != nil || (answer != "y" && answer != "yes") {
+		g.Logger.Info(libgobuster.T("Aborted"))
+		return false, nil
+	}
+	return true, nil
+}
+
+// loadState resumes the run from the state file if it exists and returns
+// the findings of the previous run
+func loadState(g *libgobuster.Gobuster, plugins []libgobuster.GobusterPlugin) ([]libgobuster.StateResult, error) {
+	state := libgobuster.RunState{
+		Mode:     plugins[0].Name(),
+		Target:   targetName(plugins),
+		Wordlist: g.Opts.WordlistName(),
+		Args:     os.Args[1:],
+	}
+
+	previous, err := libgobuster.LoadState(g.Opts.StateFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read state file: %w", err)
+	}
+	if previous != nil {
+		if err := previous.Compatible(state); err != nil {
+			return nil, fmt.Errorf("can not resume from %s: %w", g.Opts.StateFile, err)
+		}
+		g.Opts.WordlistOffset = previous.Offset
+		g.Opts.TargetOffset = previous.TargetIndex
+		state.Results = previous.Results
+	}
+	g.SetState(state)
+	return state.Results, nil
+}
+
+// targetName returns the targets of the plugins joined by commas
+func targetName(plugins []libgobuster.GobusterPlugin) string {
+	return strings.Join(libgobuster.TargetNames(plugins), ",")
 }
 
 // Gobuster is the main entry point for the CLI
 func Gobuster(ctx context.Context, opts *libgobuster.Options, plugin libgobuster.GobusterPlugin, log libgobuster.Logger) error {
+	return GobusterTargets(ctx, opts, []libgobuster.GobusterPlugin{plugin}, log)
+}
+
+// GobusterTargets is the entry point for the CLI scanning multiple targets,
+// one plugin per target
+func GobusterTargets(ctx context.Context, opts *libgobuster.Options, plugins []libgobuster.GobusterPlugin, log libgobuster.Logger) error {
 	// Sanity checks
 	if opts == nil {
 		return fmt.Errorf("please provide valid options")
 	}
 
-	if plugin == nil {
+	if len(plugins) == 0 {
 		return fmt.Errorf("please provide a valid plugin")
 	}
+	for _, p := range plugins {
+		if p == nil {
+			return fmt.Errorf("please provide a valid plugin")
+		}
+	}
+	plugin := plugins[0]
 
 	ctxCancel, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	gobuster, err := libgobuster.NewGobuster(opts, plugin, log)
+	gobuster, err := libgobuster.NewGobusterTargets(opts, plugins, log)
 	if err != nil {
 		return err
 	}
 
+	log.Debugf("random seed %d", libgobuster.Seed())
+
+	var restored []libgobuster.StateResult
+	if opts.StateFile != "" {
+		restored, err = loadState(gobuster, plugins)
+		if err != nil {
+			return err
+		}
+	}
+
 	if !opts.Quiet {
 		log.Println(ruler)
 		log.Printf("Gobuster v%s\n", libgobuster.VERSION)
@@ -154,23 +513,82 @@ func Gobuster(ctx context.Context, opts *libgobuster.Options, plugin libgobuster
 		}
 		log.Println(c)
 		log.Println(ruler)
-		gobuster.Logger.Printf("Starting gobuster in %s mode", plugin.Name())
+		gobuster.Logger.Printf(libgobuster.T("Starting gobuster in %s mode"), plugin.Name())
+		if len(plugins) > 1 {
+			gobuster.Logger.Printf(libgobuster.T("Scanning %d targets one after another, the configuration is shown for the first"), len(plugins))
+		}
+		if len(opts.Wordlists) > 1 {
+			words, duplicates, err := libgobuster.CountMergedWordlists(opts.Wordlists)
+			if err != nil {
+				return err
+			}
+			gobuster.Logger.Printf(libgobuster.T("Merged %d wordlists into %d unique words, %d duplicates removed"), len(opts.Wordlists), words, duplicates)
+		}
 		if opts.WordlistOffset > 0 {
-			gobuster.Logger.Printf("Skipping the first %d elements...", opts.WordlistOffset)
+			gobuster.Logger.Printf(libgobuster.T("Skipping the first %d elements..."), opts.WordlistOffset)
+		}
+		if opts.KnownWords.Length() > 0 {
+			gobuster.Logger.Printf(libgobuster.T("Skipping %d known entries..."), opts.KnownWords.Length())
+		}
+		if len(restored) > 0 {
+			gobuster.Logger.Printf(libgobuster.T("Restored %d findings from %s"), len(restored), opts.StateFile)
 		}
 		log.Println(ruler)
 	}
 
+	if opts.PrintRedactionKey && len(opts.RedactionKey) > 0 {
+		gobuster.Logger.Printf(libgobuster.T("Redaction key: %x"), opts.RedactionKey)
+	}
+
+	proceed, err := preview(ctxCancel, gobuster)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return nil
+	}
+
+	outputs, err := libgobuster.OpenOutputs(opts)
+	if err != nil {
+		return err
+	}
+	defer outputs.Close()
+
+	var status *libgobuster.StatusWriter
+	if opts.StatusFile != "" {
+		status = libgobuster.NewStatusWriter(opts.StatusFile, plugin.Name(), targetName(plugins), gobuster.Progress, gobuster.Logger)
+		if err := status.Start(); err != nil {
+			return err
+		}
+	}
+
 	// our waitgroup for all goroutines
 	// this ensures all goroutines are finished
 	// when we call wg.Wait()
 	var wg sync.WaitGroup
 
 	wg.Add(1)
-	go resultWorker(gobuster, opts.OutputFilename, &wg)
+	var records *[]libgobuster.ResultRecord
+	if opts.Cluster || opts.Tree || opts.Notify || opts.Workspace != "" || opts.Report != "" || opts.Database != "" {
+		records = &[]libgobuster.ResultRecord{}
+	}
+	// findings of the resumed run are written again as the outputs were recreated
+	for _, r := range restored {
+		writeResult(gobuster, outputs, r.Record, r.Line)
+		if records != nil {
+			*records = append(*records, r.Record)
+		}
+		status.AddFinding()
+	}
+	var notifications *notifier
+	if len(opts.NotifyFilters) > 0 {
+		notifications = newNotifier(gobuster)
+	}
+	stops := &stopper{g: gobuster, cancel: cancel}
+	go resultWorker(gobuster, outputs, records, notifications, status, stops, &wg)
 
 	wg.Add(1)
-	go errorWorker(gobuster, &wg)
+	go errorWorker(gobuster, status, stops, &wg)
 
 	wg.Add(1)
 	go messageWorker(gobuster, &wg)
@@ -179,8 +597,23 @@ func Gobuster(ctx context.Context, opts *libgobuster.Options, plugin libgobuster
 		// if not quiet add a new workgroup entry and start the goroutine
 		wg.Add(1)
 		go progressWorker(ctxCancel, gobuster, &wg)
+
+		// the wordlist can not be read from stdin when scanning multiple targets
+		if len(plugins) > 1 && term.IsTerminal(int(os.Stdin.Fd())) {
+			gobuster.Logger.Info(libgobuster.T("Press enter to show the progress of every target"))
+			wg.Add(1)
+			go targetsWorker(ctxCancel, gobuster, &wg)
+		}
+	}
+
+	if opts.MaxTime > 0 {
+		timer := time.AfterFunc(opts.MaxTime, func() {
+			stops.stop(libgobuster.StopMaxTime, fmt.Sprintf(libgobuster.T("Reached the maximum time of %s"), opts.MaxTime))
+		})
+		defer timer.Stop()
 	}
 
+	start := time.Now()
 	err = gobuster.Run(ctxCancel)
 
 	// call cancel func so progressWorker will exit (the only goroutine in this
@@ -188,16 +621,123 @@ func Gobuster(ctx context.Context, opts *libgobuster.Options, plugin libgobuster
 	cancel()
 	// wait for all spun up goroutines to finish (all have to call wg.Done())
 	wg.Wait()
+	if notifications != nil {
+		notifications.Close()
+	}
+
+	stopped := stops.stopped()
+	if stopped == nil && ctx.Err() != nil {
+		stopped = &libgobuster.StoppedError{Reason: libgobuster.StopInterrupted, Detail: libgobuster.T("The run was canceled")}
+	}
+
+	if opts.StateFile != "" {
+		if stopped != nil {
+			gobuster.SetStopReason(stopped.Reason)
+		}
+		if gobuster.Completed() {
+			// nothing left to resume
+			if err := os.Remove(opts.StateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+				gobuster.Logger.Error(err.Error())
+			}
+		} else if err := gobuster.SaveState(); err != nil {
+			gobuster.Logger.Error(err.Error())
+		}
+	}
+
+	if status != nil {
+		state, reason := libgobuster.StatusFinished, libgobuster.StopNone
+		switch {
+		case err != nil:
+			state = libgobuster.StatusFailed
+		case stopped != nil:
+			state, reason = libgobuster.StatusStopped, stopped.Reason
+		}
+		if err := status.Finish(state, reason); err != nil {
+			gobuster.Logger.Error(err.Error())
+		}
+	}
+
+	if opts.Notify {
+		if err != nil {
+			notify(gobuster, libgobuster.T("gobuster failed"), err.Error())
+		} else if stopped != nil {
+			notify(gobuster, libgobuster.T("gobuster stopped early"), stopped.Detail)
+		} else {
+			notify(gobuster, libgobuster.T("gobuster finished"), fmt.Sprintf(libgobuster.T("%s: %d requests, %d findings"), plugin.Name(), gobuster.Progress.RequestsIssued(), len(*records)))
+		}
+	}
 
 	// Late error checking to finish all threads
 	if err != nil {
 		return err
 	}
 
+	if opts.Cluster {
+		printClusters(gobuster, *records)
+	}
+
+	if opts.Tree {
+		printTree(gobuster, *records)
+	}
+
+	if opts.Workspace != "" || opts.Report != "" || opts.Database != "" {
+		run := libgobuster.RunRecord{
+			Mode:     plugin.Name(),
+			Target:   targetName(plugins),
+			Wordlist: opts.WordlistName(),
+			Start:    start,
+			End:      time.Now(),
+			Requests: gobuster.Progress.RequestsIssued(),
+			Results:  *records,
+		}
+		if stopped != nil {
+			run.StopReason = stopped.Reason
+		}
+		if opts.Workspace != "" {
+			if err := libgobuster.SaveRun(opts.Workspace, run); err != nil {
+				return fmt.Errorf("could not save run to workspace: %w", err)
+			}
+		}
+		config, err := gobuster.GetConfigString()
+		if err != nil {
+			return fmt.Errorf("error on creating config string: %w", err)
+		}
+		if opts.Report != "" {
+			if err := libgobuster.WriteReport(opts.Report, run, config); err != nil {
+				return err
+			}
+		}
+		if opts.Database != "" {
+			if _, err := libgobuster.SaveRunDatabase(opts.Database, run, config); err != nil {
+				return fmt.Errorf("could not save run to database: %w", err)
+			}
+		}
+	}
+
 	if !opts.Quiet {
 		log.Println(ruler)
-		gobuster.Logger.Println("Finished")
+		if stopped != nil {
+			gobuster.Logger.Printf(libgobuster.T("Stopped early (%s): %s"), stopped.Reason, stopped.Detail)
+		} else {
+			gobuster.Logger.Println(libgobuster.T("Finished"))
+		}
+		if len(plugins) > 1 {
+			printTargets(gobuster)
+		}
+		if resumed := gobuster.Progress.RequestsResumed(); resumed > 0 {
+			gobuster.Logger.Printf(libgobuster.T("Requests: %d total, %d resumed from a previous run"), gobuster.Progress.RequestsIssued(), resumed)
+		}
+		if stats := opts.ConnStats; stats.Requests() > 0 {
+			gobuster.Logger.Printf(libgobuster.T("Connections: %d requests, %.1f%% reused, %d DNS lookups, %d TLS handshakes, average TTFB %s"),
+				stats.Requests(), stats.ReuseRatio()*100, stats.DNSLookups(), stats.TLSHandshakes(), stats.AverageTTFB().Round(time.Millisecond))
+		}
+		for _, problems := range opts.ConnStats.CertificateProblems() {
+			gobuster.Logger.Printf(libgobuster.T("Certificate problems of %s"), problems)
+		}
 		log.Println(ruler)
 	}
+	if stopped != nil {
+		return stopped
+	}
 	return nil
 }
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// notifyQueueSize is the number of finding notifications waiting to be shown.
+// Findings arriving while the queue is full are only counted and mentioned in
+// the next notification
+const notifyQueueSize = 16
+
+// notify shows a desktop notification. Failures are only logged as the
+// notification tool might not be installed
+func notify(g *libgobuster.Gobuster, title, message string) {
+	cmd := notifyCommand(title, message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		g.Logger.Debugf("desktop notification failed: %v %s", err, strings.TrimSpace(string(out)))
+	}
+}
+
+// notifier shows the finding notifications in its own goroutine so a slow
+// notification tool never blocks the result worker. Findings queued while a
+// notification is shown are coalesced into the next one
+type notifier struct {
+	g       *libgobuster.Gobuster
+	queue   chan string
+	mu      sync.Mutex
+	dropped int
+	done    chan struct{}
+}
+
+func newNotifier(g *libgobuster.Gobuster) *notifier {
+	n := &notifier{
+		g:     g,
+		queue: make(chan string, notifyQueueSize),
+		done:  make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// finding queues a notification if the record matches one of the notify
+// filters. The record is redacted like the results printed to stdout
+func (n *notifier) finding(record libgobuster.ResultRecord) {
+	if !libgobuster.MatchesAnyFilter(n.g.Opts.NotifyFilters, record) {
+		return
+	}
+	message := findingMessage(record)
+	if redaction := libgobuster.RedactionFor(n.g.Opts.OutputRedactions, libgobuster.OutputStdout); redaction != "" {
+		_, message = libgobuster.Redact(record, message, redaction, n.g.Opts.RedactionKey)
+	}
+	select {
+	case n.queue <- message:
+	default:
+		n.mu.Lock()
+		n.dropped++
+		n.mu.Unlock()
+	}
+}
+
+func (n *notifier) run() {
+	defer close(n.done)
+	for message := range n.queue {
+		more := n.drain()
+		if more > 0 {
+			message = fmt.Sprintf(libgobuster.T("%s and %d more"), message, more)
+		}
+		notify(n.g, libgobuster.T("gobuster finding"), message)
+	}
+	// a finding dropped while the last notification was queued
+	if dropped := n.takeDropped(); dropped > 0 {
+		notify(n.g, libgobuster.T("gobuster finding"), fmt.Sprintf(libgobuster.T("%d more findings"), dropped))
+	}
+}
+
+// drain removes all queued notifications and returns how many findings were
+// queued or dropped since the last notification
+func (n *notifier) drain() int {
+	more := 0
+	for {
+		select {
+		case _, ok := <-n.queue:
+			if !ok {
+				return more + n.takeDropped()
+			}
+			more++
+		default:
+			return more + n.takeDropped()
+		}
+	}
+}
+
+func (n *notifier) takeDropped() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	dropped := n.dropped
+	n.dropped = 0
+	return dropped
+}
+
+// Close waits until the queued notifications are shown. finding must not be
+// called afterwards
+func (n *notifier) Close() {
+	close(n.queue)
+	<-n.done
+}
+
+// findingMessage returns the text of a finding notification
+func findingMessage(record libgobuster.ResultRecord) string {
+	message := record.URL
+	if message == "" {
+		message = record.Path
+	}
+	if record.StatusCode > 0 {
+		message = fmt.Sprintf("%s (Status: %d)", message, record.StatusCode)
+	}
+	if len(record.Tags) > 0 {
+		message = fmt.Sprintf("%s [%s]", message, strings.Join(record.Tags, ", "))
+	}
+	return message
+}
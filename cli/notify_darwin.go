@@ -0,0 +1,14 @@
+//go:build darwin
+
+package cli
+
+import "os/exec"
+
+// the texts are passed as arguments so they don't need to be escaped
+func notifyCommand(title, message string) *exec.Cmd {
+	return exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+		"-e", "end run",
+		title, message)
+}
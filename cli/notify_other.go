@@ -0,0 +1,9 @@
+//go:build !windows && !darwin
+
+package cli
+
+import "os/exec"
+
+func notifyCommand(title, message string) *exec.Cmd {
+	return exec.Command("notify-send", "--app-name=gobuster", "--", title, message)
+}
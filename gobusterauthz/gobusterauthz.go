@@ -0,0 +1,417 @@
+package gobusterauthz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+const (
+	// TagProtected is added if only the authenticated request was successful
+	TagProtected = "protected"
+	// TagAnonymousOnly is added if only the anonymous request was successful
+	TagAnonymousOnly = "anonymous-only"
+	// TagContentDiffers is added if both requests were successful but the
+	// responses differ in size
+	TagContentDiffers = "content-differs"
+	// TagStatusDiffers is added for all other differences of the status code
+	TagStatusDiffers = "status-differs"
+	// TagAllowed lists the identities with a successful response if more
+	// than two identities are compared
+	TagAllowed = "allowed"
+)
+
+// identity is a set of credentials the candidates are requested with
+type identity struct {
+	name string
+	http *libgobuster.HTTPClient
+}
+
+// GobusterAuthz is the main type to implement the interface
+type GobusterAuthz struct {
+	options    *OptionsAuthz
+	globalopts *libgobuster.Options
+	identities []identity
+}
+
+// NewGobusterAuthz creates a new initialized GobusterAuthz
+func NewGobusterAuthz(globalopts *libgobuster.Options, opts *OptionsAuthz) (*GobusterAuthz, error) {
+	if globalopts == nil {
+		return nil, fmt.Errorf("please provide valid global options")
+	}
+
+	if opts == nil {
+		return nil, fmt.Errorf("please provide valid plugin options")
+	}
+
+	g := GobusterAuthz{
+		options:    opts,
+		globalopts: globalopts,
+	}
+
+	basicOptions := libgobuster.BasicHTTPOptions{
+		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
+		Timeout:         opts.Timeout,
+		UserAgent:       opts.UserAgent,
+		NoTLSValidation: opts.NoTLSValidation,
+		RetryOnTimeout:  opts.RetryOnTimeout,
+		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
+		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
+	}
+
+	// the other identities are sent without the credentials of the options
+	var baseHeaders []libgobuster.HTTPHeader
+	for _, h := range opts.Headers {
+		if !isAuthHeader(h.Name, opts.AuthHeaders) {
+			baseHeaders = append(baseHeaders, h)
+		}
+	}
+
+	newClient := func(httpOpts libgobuster.HTTPOptions) (*libgobuster.HTTPClient, error) {
+		httpOpts.BasicHTTPOptions = basicOptions
+		httpOpts.FollowRedirect = opts.FollowRedirect
+		httpOpts.NoCanonicalizeHeaders = opts.NoCanonicalizeHeaders
+		httpOpts.CacheBypass = opts.CacheBypass
+		httpOpts.Script = globalopts.Script
+		httpOpts.Throttle = globalopts.Throttle
+		httpOpts.ConnStats = globalopts.ConnStats
+		httpOpts.Method = opts.Method
+		return libgobuster.NewHTTPClient(&httpOpts)
+	}
+
+	if !opts.NoAnonymous {
+		anonymous, err := newClient(libgobuster.HTTPOptions{Headers: baseHeaders})
+		if err != nil {
+			return nil, err
+		}
+		g.identities = append(g.identities, identity{name: "anonymous", http: anonymous})
+	}
+
+	if opts.hasCredentials() {
+		authenticated, err := newClient(libgobuster.HTTPOptions{
+			Username: opts.Username,
+			Password: opts.Password,
+			Headers:  opts.Headers,
+			Cookies:  opts.Cookies,
+		})
+		if err != nil {
+			return nil, err
+		}
+		g.identities = append(g.identities, identity{name: "authenticated", http: authenticated})
+	}
+
+	for _, id := range opts.Identities {
+		headers := append(append([]libgobuster.HTTPHeader{}, baseHeaders...), id.Headers...)
+		client, err := newClient(libgobuster.HTTPOptions{Headers: headers})
+		if err != nil {
+			return nil, err
+		}
+		g.identities = append(g.identities, identity{name: id.Name, http: client})
+	}
+
+	if len(g.identities) < 2 {
+		return nil, fmt.Errorf("at least two identities are needed to compare the responses")
+	}
+
+	return &g, nil
+}
+
+// Name should return the name of the plugin
+func (a *GobusterAuthz) Name() string {
+	return "authorization comparison"
+}
+
+// Target returns the target of the run
+func (a *GobusterAuthz) Target() string {
+	return a.options.URL
+}
+
+// ProbeLatency is the latency probe implementation of gobusterauthz, it sends a
+// HEAD request to the url
+func (a *GobusterAuthz) ProbeLatency(ctx context.Context) (time.Duration, error) {
+	return a.identities[0].http.ProbeLatency(ctx, a.options.URL)
+}
+
+// PreRun is the pre run implementation of gobusterauthz
+func (a *GobusterAuthz) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	_, _, _, _, err := a.identities[0].http.Request(ctx, a.options.URL, libgobuster.RequestOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", a.options.URL, err)
+	}
+	return nil
+}
+
+// ProcessWord requests the word with every identity and reports it if the
+// responses differ
+func (a *GobusterAuthz) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	path := strings.TrimPrefix(word, "/")
+	url := strings.TrimSuffix(a.options.URL, "/") + "/" + path
+
+	responses := make([]Response, len(a.identities))
+	for i, id := range a.identities {
+		statusCode, size, err := a.request(ctx, id.http, url, progress)
+		if err != nil {
+			return err
+		}
+		// request was canceled
+		if statusCode == 0 {
+			return nil
+		}
+		responses[i] = Response{Identity: id.name, StatusCode: statusCode, Size: size}
+	}
+
+	tags := compare(responses, a.options.SizeTolerance)
+	// nothing to see if the path does not exist for anyone
+	missing := true
+	for _, r := range responses {
+		missing = missing && r.StatusCode == 404
+	}
+
+	found := len(tags) > 0
+	if found || (a.globalopts.Verbose && !missing) {
+		progress.ResultChan <- Result{
+			Verbose:   a.globalopts.Verbose,
+			Found:     found,
+			URL:       url,
+			Path:      "/" + path,
+			Responses: responses,
+			Tags:      tags,
+		}
+	}
+
+	return nil
+}
+
+// compare returns the tags describing the difference of the responses, nil
+// if they are the same. The first response is the baseline, usually the
+// anonymous one. With more than two identities the identities with a
+// successful response are added as allowed tag
+func compare(responses []Response, sizeTolerance int64) []string {
+	base := responses[0]
+	baseOK := isSuccess(base.StatusCode)
+
+	sameStatus := true
+	otherOK, otherDenied := false, false
+	minSize, maxSize := base.Size, base.Size
+	var allowed []string
+	if baseOK {
+		allowed = append(allowed, base.Identity)
+	}
+	for _, r := range responses[1:] {
+		sameStatus = sameStatus && r.StatusCode == base.StatusCode
+		if isSuccess(r.StatusCode) {
+			otherOK = true
+			allowed = append(allowed, r.Identity)
+		} else {
+			otherDenied = true
+		}
+		if r.Size < minSize {
+			minSize = r.Size
+		}
+		if r.Size > maxSize {
+			maxSize = r.Size
+		}
+	}
+
+	var tags []string
+	switch {
+	case sameStatus:
+		if !baseOK || maxSize-minSize <= sizeTolerance {
+			return nil
+		}
+		tags = []string{TagContentDiffers}
+	case !baseOK && otherOK:
+		tags = []string{TagProtected}
+	case baseOK && otherDenied:
+		tags = []string{TagAnonymousOnly}
+	default:
+		tags = []string{TagStatusDiffers}
+	}
+
+	if len(responses) > 2 && len(allowed) > 0 {
+		tags = append(tags, fmt.Sprintf("%s:%s", TagAllowed, strings.Join(allowed, "+")))
+	}
+	return tags
+}
+
+func isSuccess(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// isAuthHeader checks if the header is one of the authentication headers
+func isAuthHeader(name string, authHeaders []string) bool {
+	for _, h := range authHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *GobusterAuthz) request(ctx context.Context, client *libgobuster.HTTPClient, url string, progress *libgobuster.Progress) (int, int64, error) {
+	var statusCode int
+	var size int64
+	var err error
+	statusCode, size, _, _, err = client.Request(ctx, url, libgobuster.RequestOptions{})
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return 0, 0, err
+		}
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
+	}
+	return statusCode, size, nil
+}
+
+// Warnings returns warnings about potentially destructive or noisy configurations
+func (a *GobusterAuthz) Warnings() []string {
+	return libgobuster.HTTPWarnings(&a.options.HTTPOptions, a.globalopts)
+}
+
+// AdditionalWords returns additional words to process
+func (a *GobusterAuthz) AdditionalWords(word string) []string {
+	return []string{}
+}
+
+// GetConfigString returns the string representation of the current config
+func (a *GobusterAuthz) GetConfigString() (string, error) {
+	var buffer bytes.Buffer
+	bw := bufio.NewWriter(&buffer)
+	tw := tabwriter.NewWriter(bw, 0, 5, 3, ' ', 0)
+	o := a.options
+	if _, err := fmt.Fprintf(tw, "[+] Url:\t%s\n", o.URL); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Method:\t%s\n", o.Method); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", a.globalopts.Threads); err != nil {
+		return "", err
+	}
+
+	if a.globalopts.Delay > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Delay:\t%s\n", a.globalopts.Delay); err != nil {
+			return "", err
+		}
+	}
+
+	wordlist := "stdin (pipe)"
+	if a.globalopts.Wordlist != "-" {
+		wordlist = a.globalopts.WordlistName()
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
+		return "", err
+	}
+
+	if a.globalopts.PatternFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Patterns:\t%s (%d entries)\n", a.globalopts.PatternFile, len(a.globalopts.Patterns)); err != nil {
+			return "", err
+		}
+	}
+
+	names := make([]string, len(a.identities))
+	for i, id := range a.identities {
+		names[i] = id.name
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Identities:\t%s\n", strings.Join(names, ", ")); err != nil {
+		return "", err
+	}
+
+	if o.Username != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Auth User:\t%s\n", o.Username); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Cookies != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Cookies:\t%s\n", o.Cookies); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Auth Headers:\t%s\n", strings.Join(o.AuthHeaders, ", ")); err != nil {
+		return "", err
+	}
+
+	if o.SizeTolerance > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Size Tolerance:\t%d\n", o.SizeTolerance); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Proxy != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Proxy:\t%s\n", o.Proxy); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
+	if o.UserAgent != "" {
+		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
+			return "", err
+		}
+	}
+
+	if o.FollowRedirect {
+		if _, err := fmt.Fprintf(tw, "[+] Follow Redirect:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.CacheBypass {
+		if _, err := fmt.Fprintf(tw, "[+] Cache bypass:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if a.globalopts.Verbose {
+		if _, err := fmt.Fprintf(tw, "[+] Verbose:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
+		return "", err
+	}
+
+	if a.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", a.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	if a.globalopts.Script != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Script:\t%s\n", a.globalopts.Script); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
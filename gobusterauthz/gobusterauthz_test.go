@@ -0,0 +1,136 @@
+package gobusterauthz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName      string
+		anonymous     Response
+		authenticated Response
+		tolerance     int64
+		expected      string
+	}{
+		{"Same", Response{StatusCode: 200, Size: 10}, Response{StatusCode: 200, Size: 10}, 0, ""},
+		{"Protected", Response{StatusCode: 401}, Response{StatusCode: 200}, 0, TagProtected},
+		{"RedirectToLogin", Response{StatusCode: 302}, Response{StatusCode: 200}, 0, TagProtected},
+		{"AnonymousOnly", Response{StatusCode: 200}, Response{StatusCode: 403}, 0, TagAnonymousOnly},
+		{"ContentDiffers", Response{StatusCode: 200, Size: 10}, Response{StatusCode: 200, Size: 500}, 0, TagContentDiffers},
+		{"WithinTolerance", Response{StatusCode: 200, Size: 10}, Response{StatusCode: 200, Size: 20}, 10, ""},
+		{"StatusDiffers", Response{StatusCode: 401}, Response{StatusCode: 403}, 0, TagStatusDiffers},
+		{"Missing", Response{StatusCode: 404}, Response{StatusCode: 404}, 0, ""},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			tags := compare([]Response{x.anonymous, x.authenticated}, x.tolerance)
+			got := ""
+			if len(tags) > 0 {
+				got = tags[0]
+			}
+			if got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestCompareMatrix(t *testing.T) {
+	t.Parallel()
+
+	responses := []Response{
+		{Identity: "anonymous", StatusCode: 401},
+		{Identity: "alice", StatusCode: 200},
+		{Identity: "bob", StatusCode: 403},
+		{Identity: "admin", StatusCode: 200},
+	}
+	tags := compare(responses, 0)
+	if len(tags) != 2 || tags[0] != TagProtected || tags[1] != "allowed:alice+admin" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/public":
+		case r.URL.Path == "/admin" && r.Header.Get("Authorization") == "Bearer secret":
+		case r.URL.Path == "/admin":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	globalopts := libgobuster.NewOptions()
+	o := NewOptionsAuthz()
+	o.URL = ts.URL
+	o.Method = http.MethodGet
+	o.Timeout = 5 * time.Second
+	o.Headers = []libgobuster.HTTPHeader{{Name: "Authorization", Value: "Bearer secret"}, {Name: "X-Test", Value: "1"}}
+	a, err := NewGobusterAuthz(globalopts, o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	progress := libgobuster.NewProgress()
+	done := make(chan []Result)
+	go func() {
+		var results []Result
+		for r := range progress.ResultChan {
+			results = append(results, r.(Result))
+		}
+		done <- results
+	}()
+	for _, word := range []string{"public", "admin", "missing"} {
+		if err := a.ProcessWord(context.Background(), word, progress); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	close(progress.ResultChan)
+
+	results := <-done
+	if len(results) != 1 || results[0].Path != "/admin" || results[0].Tags[0] != TagProtected {
+		t.Fatalf("expected only the protected path to be reported, got %+v", results)
+	}
+	if results[0].Responses[0].StatusCode != 401 || results[0].Responses[1].StatusCode != 200 {
+		t.Fatalf("unexpected responses %+v", results[0].Responses)
+	}
+}
+
+func TestIdentities(t *testing.T) {
+	t.Parallel()
+
+	globalopts := libgobuster.NewOptions()
+	o := NewOptionsAuthz()
+	o.URL = "http://127.0.0.1"
+	o.Timeout = 5 * time.Second
+	o.NoAnonymous = true
+	o.Identities = []Identity{{Name: "alice"}}
+	if _, err := NewGobusterAuthz(globalopts, o); err == nil {
+		t.Fatal("expected an error with a single identity")
+	}
+
+	o.Identities = append(o.Identities, Identity{Name: "bob"})
+	a, err := NewGobusterAuthz(globalopts, o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(a.identities) != 2 || a.identities[0].name != "alice" || a.identities[1].name != "bob" {
+		t.Fatalf("unexpected identities %+v", a.identities)
+	}
+}
@@ -0,0 +1,92 @@
+package gobusterauthz
+
+import (
+	"fmt"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// Identity is a named set of headers like a session cookie of a user
+type Identity struct {
+	Name    string
+	Headers []libgobuster.HTTPHeader
+}
+
+// OptionsAuthz is the struct to hold all options for this plugin
+type OptionsAuthz struct {
+	libgobuster.HTTPOptions
+	// AuthHeaders are removed from the headers of the anonymous requests
+	AuthHeaders []string
+	// SizeTolerance is the size difference in bytes which is still treated
+	// as the same response
+	SizeTolerance int64
+	// Identities are compared in addition to the credentials of the http options
+	Identities []Identity
+	// NoAnonymous disables the anonymous requests
+	NoAnonymous bool
+}
+
+// NewOptionsAuthz returns a new initialized OptionsAuthz
+func NewOptionsAuthz() *OptionsAuthz {
+	return &OptionsAuthz{
+		AuthHeaders: []string{"Authorization", "Cookie"},
+	}
+}
+
+// ValidationProblems returns all problems of the authz options
+func (opt *OptionsAuthz) ValidationProblems() []libgobuster.ValidationProblem {
+	problems := opt.HTTPOptions.ValidationProblems()
+
+	identities := len(opt.Identities)
+	if !opt.NoAnonymous {
+		identities++
+	}
+	if opt.hasCredentials() {
+		identities++
+	}
+	if identities < 2 {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "identity",
+			Problem:    "at least two identities are needed to compare the responses",
+			Suggestion: "please provide credentials with username and password, cookies, an authentication header or identity",
+		})
+	}
+
+	seen := libgobuster.NewSet[string]()
+	seen.Add("anonymous")
+	seen.Add("authenticated")
+	for _, id := range opt.Identities {
+		if !seen.Add(id.Name) {
+			problems = append(problems, libgobuster.ValidationProblem{
+				Option:     "identity",
+				Problem:    fmt.Sprintf("identity name %q is used more than once", id.Name),
+				Suggestion: "use unique names, anonymous and authenticated are reserved",
+			})
+		}
+	}
+
+	if opt.SizeTolerance < 0 {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "size-tolerance",
+			Problem:    "size-tolerance must not be negative",
+			Suggestion: "use 0 to treat every size difference as a different response",
+		})
+	}
+
+	return problems
+}
+
+// hasCredentials checks if credentials are set in the http options
+func (opt *OptionsAuthz) hasCredentials() bool {
+	return opt.Username != "" || opt.Cookies != "" || opt.hasAuthHeader()
+}
+
+// hasAuthHeader checks if one of the headers is an authentication header
+func (opt *OptionsAuthz) hasAuthHeader() bool {
+	for _, h := range opt.Headers {
+		if isAuthHeader(h.Name, opt.AuthHeaders) {
+			return true
+		}
+	}
+	return false
+}
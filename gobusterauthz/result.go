@@ -0,0 +1,86 @@
+package gobusterauthz
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/fatih/color"
+)
+
+var (
+	yellow = color.New(color.FgYellow).FprintfFunc()
+	green  = color.New(color.FgGreen).FprintfFunc()
+	red    = color.New(color.FgRed).FprintfFunc()
+)
+
+// Response is the response of a single identity
+type Response struct {
+	Identity   string
+	StatusCode int
+	Size       int64
+}
+
+// Result represents a single result
+type Result struct {
+	Verbose   bool
+	Found     bool
+	URL       string
+	Path      string
+	Responses []Response
+	Tags      []string
+}
+
+// ResultToString converts the Result to it's textual representation
+func (r Result) ResultToString() (string, error) {
+	buf := &bytes.Buffer{}
+
+	c := green
+
+	// Prefix if we're in verbose mode
+	if r.Verbose {
+		if r.Found {
+			c(buf, "Found: ")
+		} else {
+			c = yellow
+			c(buf, "Missed: ")
+		}
+	} else if r.Found {
+		c(buf, "Found: ")
+	}
+
+	c(buf, "%s", r.URL)
+	for _, x := range r.Responses {
+		c(buf, " (%s: %d [Size: %d])", x.Identity, x.StatusCode, x.Size)
+	}
+
+	if len(r.Tags) > 0 {
+		red(buf, " [%s]", strings.Join(r.Tags, ","))
+	}
+
+	c(buf, "\n")
+
+	s := buf.String()
+	return s, nil
+}
+
+// ResultToRecord converts the Result to it's mode independent representation.
+// The status code and size are the ones of the last identity
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	record := libgobuster.ResultRecord{
+		URL:   r.URL,
+		Path:  r.Path,
+		Found: r.Found,
+		Tags:  append([]string(nil), r.Tags...),
+	}
+	if len(r.Responses) > 0 {
+		last := r.Responses[len(r.Responses)-1]
+		record.StatusCode = last.StatusCode
+		record.Size = last.Size
+	}
+	for _, x := range r.Responses {
+		record.Tags = append(record.Tags, fmt.Sprintf("%s:%d", x.Identity, x.StatusCode))
+	}
+	return record
+}
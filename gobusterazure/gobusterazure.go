@@ -0,0 +1,344 @@
+package gobusterazure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// containerSeparator separates the account from the word in the words of a
+// container pass. It can not be part of an account name
+const containerSeparator = "/"
+
+// GobusterAzure is the main type to implement the interface
+type GobusterAzure struct {
+	options         *OptionsAzure
+	globalopts      *libgobuster.Options
+	http            *libgobuster.HTTPClient
+	accountRegex    *regexp.Regexp
+	containerRegex  *regexp.Regexp
+	containersMutex sync.Mutex
+	containerPasses map[string]struct{}
+	// accountURL returns the blob endpoint of an account, changed by the
+	// tests
+	accountURL func(account string) string
+}
+
+// NewGobusterAzure creates a new initialized GobusterAzure
+func NewGobusterAzure(globalopts *libgobuster.Options, opts *OptionsAzure) (*GobusterAzure, error) {
+	if globalopts == nil {
+		return nil, fmt.Errorf("please provide valid global options")
+	}
+
+	if opts == nil {
+		return nil, fmt.Errorf("please provide valid plugin options")
+	}
+
+	g := GobusterAzure{
+		options:         opts,
+		globalopts:      globalopts,
+		containerPasses: make(map[string]struct{}),
+		accountURL: func(account string) string {
+			return fmt.Sprintf("https://%s.blob.core.windows.net", account)
+		},
+	}
+
+	basicOptions := libgobuster.BasicHTTPOptions{
+		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
+		Timeout:         opts.Timeout,
+		UserAgent:       opts.UserAgent,
+		NoTLSValidation: opts.NoTLSValidation,
+		RetryOnTimeout:  opts.RetryOnTimeout,
+		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
+		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
+	}
+
+	httpOpts := libgobuster.HTTPOptions{
+		BasicHTTPOptions: basicOptions,
+		Headers:          opts.Headers,
+		Throttle:         globalopts.Throttle,
+		ConnStats:        globalopts.ConnStats,
+	}
+
+	h, err := libgobuster.NewHTTPClient(&httpOpts)
+	if err != nil {
+		return nil, err
+	}
+	g.http = h
+	// https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules#microsoftstorage
+	g.accountRegex = regexp.MustCompile(`^[a-z0-9]{3,24}$`)
+	g.containerRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]{1,61}[a-z0-9]$`)
+
+	for _, account := range opts.Accounts {
+		if !g.isValidAccountName(account) {
+			return nil, fmt.Errorf("invalid storage account name %q", account)
+		}
+	}
+
+	return &g, nil
+}
+
+// Name should return the name of the plugin
+func (s *GobusterAzure) Name() string {
+	return "Azure blob enumeration"
+}
+
+// PreRun is the pre run implementation of GobusterAzure
+func (s *GobusterAzure) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	// the containers of a missing account would all fail
+	for _, account := range s.options.Accounts {
+		exists, _, err := s.accountExists(ctx, account)
+		if err != nil {
+			return fmt.Errorf("could not check storage account %s: %w", account, err)
+		}
+		if !exists && ctx.Err() == nil {
+			return fmt.Errorf("storage account %s does not exist", account)
+		}
+	}
+	return nil
+}
+
+// ProcessWord is the process implementation of GobusterAzure
+func (s *GobusterAzure) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	if len(s.options.Accounts) > 0 {
+		for _, account := range s.options.Accounts {
+			if err := s.processContainer(ctx, account, word, progress); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if account, container, ok := s.splitContainer(word); ok {
+		return s.processContainer(ctx, account, container, progress)
+	}
+	return s.processAccount(ctx, word, progress)
+}
+
+// splitContainer returns the account and container of a word of a container
+// pass
+func (s *GobusterAzure) splitContainer(word string) (string, string, bool) {
+	account, container, ok := strings.Cut(word, containerSeparator)
+	if !ok {
+		return "", "", false
+	}
+	s.containersMutex.Lock()
+	defer s.containersMutex.Unlock()
+	if _, ok := s.containerPasses[account]; !ok {
+		return "", "", false
+	}
+	return account, container, true
+}
+
+// accountExists checks if the storage account exists. Missing accounts have
+// no DNS record, every response of the blob service carries a request id
+func (s *GobusterAzure) accountExists(ctx context.Context, account string) (bool, int, error) {
+	statusCode, _, header, _, err := s.http.Request(ctx, s.accountURL(account)+"/?comp=list&maxresults=1", libgobuster.RequestOptions{})
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+	return statusCode != 0 && header.Get("x-ms-request-id") != "", statusCode, nil
+}
+
+func (s *GobusterAzure) processAccount(ctx context.Context, account string, progress *libgobuster.Progress) error {
+	// only check for valid account names
+	if !s.isValidAccountName(account) {
+		return nil
+	}
+
+	exists, statusCode, err := s.accountExists(ctx, account)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	if s.options.Containers {
+		s.containersMutex.Lock()
+		if _, ok := s.containerPasses[account]; !ok {
+			s.containerPasses[account] = struct{}{}
+			progress.QueueRecursion(account + containerSeparator)
+		}
+		s.containersMutex.Unlock()
+	}
+
+	progress.ResultChan <- Result{
+		Found:      true,
+		Account:    account,
+		URL:        s.accountURL(account) + "/",
+		StatusCode: statusCode,
+	}
+	return nil
+}
+
+func (s *GobusterAzure) processContainer(ctx context.Context, account, container string, progress *libgobuster.Progress) error {
+	// only check for valid container names
+	if !s.isValidContainerName(container) {
+		return nil
+	}
+
+	containerURL := fmt.Sprintf("%s/%s?restype=container&comp=list&maxresults=%d", s.accountURL(account), container, s.options.MaxFilesToList)
+	statusCode, _, _, body, err := s.http.Request(ctx, containerURL, libgobuster.RequestOptions{ReturnBody: true})
+	if err != nil {
+		return err
+	}
+
+	// anonymous requests for private and missing containers both fail
+	// with 404, only listable containers answer
+	if statusCode != http.StatusOK {
+		return nil
+	}
+
+	extraStr := ""
+	if s.globalopts.Verbose {
+		listing := AzureListing{}
+		if err := xml.Unmarshal(body, &listing); err != nil {
+			return fmt.Errorf("could not parse result xml: %w", err)
+		}
+		extraStr = "Blob Listing enabled: "
+		for _, x := range listing.Blobs {
+			extraStr += fmt.Sprintf("%s (%db), ", x.Name, x.Properties.ContentLength)
+		}
+		extraStr = strings.TrimRight(extraStr, ", ")
+	}
+
+	progress.ResultChan <- Result{
+		Found:      true,
+		Account:    account,
+		Container:  container,
+		URL:        containerURL,
+		Status:     extraStr,
+		StatusCode: statusCode,
+	}
+	return nil
+}
+
+func (s *GobusterAzure) AdditionalWords(word string) []string {
+	return []string{}
+}
+
+// GetConfigString returns the string representation of the current config
+func (s *GobusterAzure) GetConfigString() (string, error) {
+	var buffer bytes.Buffer
+	bw := bufio.NewWriter(&buffer)
+	tw := tabwriter.NewWriter(bw, 0, 5, 3, ' ', 0)
+	o := s.options
+
+	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", s.globalopts.Threads); err != nil {
+		return "", err
+	}
+
+	if s.globalopts.Delay > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Delay:\t%s\n", s.globalopts.Delay); err != nil {
+			return "", err
+		}
+	}
+
+	if s.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", s.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	wordlist := "stdin (pipe)"
+	if s.globalopts.Wordlist != "-" {
+		wordlist = s.globalopts.WordlistName()
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
+		return "", err
+	}
+
+	if s.globalopts.PatternFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Patterns:\t%s (%d entries)\n", s.globalopts.PatternFile, len(s.globalopts.Patterns)); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Accounts) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Accounts:\t%s\n", strings.Join(o.Accounts, ",")); err != nil {
+			return "", err
+		}
+	} else if o.Containers {
+		if _, err := fmt.Fprintf(tw, "[+] Containers of found accounts:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Proxy != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Proxy:\t%s\n", o.Proxy); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
+	if o.UserAgent != "" {
+		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
+		return "", err
+	}
+
+	if s.globalopts.Verbose {
+		if _, err := fmt.Fprintf(tw, "[+] Verbose:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Maximum files to list:\t%d\n", o.MaxFilesToList); err != nil {
+		return "", err
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
+
+// https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules#microsoftstorage
+func (s *GobusterAzure) isValidAccountName(accountName string) bool {
+	return s.accountRegex.MatchString(accountName)
+}
+
+// https://learn.microsoft.com/en-us/rest/api/storageservices/naming-and-referencing-containers--blobs--and-metadata
+func (s *GobusterAzure) isValidContainerName(containerName string) bool {
+	switch containerName {
+	case "$root", "$web", "$logs":
+		// special containers of the account
+		return true
+	}
+	return s.containerRegex.MatchString(containerName) && !strings.Contains(containerName, "--")
+}
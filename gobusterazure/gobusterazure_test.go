@@ -0,0 +1,155 @@
+package gobusterazure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/OJ/gobuster/v3/libgobuster/plugintest"
+)
+
+// azureServer serves the blob endpoints of the accounts below /<account>.
+// Missing accounts are answered without the request id of the blob service
+func azureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account, container, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if account != "examplecorp" && account != "backupcorp" && account != "backup" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("x-ms-request-id", "1")
+		switch {
+		case account == "examplecorp" && container == "public" && r.URL.Query().Get("comp") == "list":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs><Blob><Name>backup.zip</Name><Properties><Content-Length>42</Content-Length></Properties></Blob></Blobs></EnumerationResults>`)
+		case container == "":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><Error><Code>InvalidQueryParameterValue</Code></Error>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><Error><Code>ResourceNotFound</Code></Error>`)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestAzure(t *testing.T) {
+	t.Parallel()
+	ts := azureServer(t)
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte("examplecorp\nbackupcorp\nmissingcorp\npublic\nprivate\nInvalid\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	tt := []struct {
+		testName   string
+		accounts   []string
+		containers bool
+		verbose    bool
+		expected   []string
+	}{
+		{"Accounts", nil, false, false, []string{"backupcorp [account]", "examplecorp [account]"}},
+		{"Containers of found accounts", nil, true, false, []string{"backupcorp [account]", "examplecorp [account]", "examplecorp/public [listable]"}},
+		{"Known account", []string{"examplecorp"}, false, true, []string{"examplecorp/public [listable] Blob Listing enabled: backup.zip (42b)"}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			globalopts := libgobuster.NewOptions()
+			globalopts.Threads = 2
+			globalopts.Wordlist = wordlist
+			globalopts.Quiet = true
+			globalopts.Verbose = x.verbose
+			globalopts.CollectResults = true
+
+			o := NewOptionsAzure()
+			o.Timeout = 5 * time.Second
+			o.MaxFilesToList = 5
+			o.Accounts = x.accounts
+			o.Containers = x.containers
+			a, err := NewGobusterAzure(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			a.accountURL = func(account string) string {
+				return fmt.Sprintf("%s/%s", ts.URL, account)
+			}
+
+			g, err := libgobuster.NewGobuster(globalopts, a, libgobuster.NewLogger(false))
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := g.Run(context.Background()); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			var got []string
+			for _, r := range g.CollectedResults() {
+				record := libgobuster.NewResultRecord(r)
+				got = append(got, strings.TrimSpace(fmt.Sprintf("%s [%s] %s", record.Path, record.Tags[0], r.(Result).Status)))
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	ts := azureServer(t)
+
+	plugintest.Run(t, func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin {
+		o := NewOptionsAzure()
+		o.Timeout = 5 * time.Second
+		o.Containers = true
+		a, err := NewGobusterAzure(globalopts, o)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		a.accountURL = func(account string) string {
+			return fmt.Sprintf("%s/%s", ts.URL, account)
+		}
+		return a
+	})
+}
+
+func TestMissingAccount(t *testing.T) {
+	t.Parallel()
+	ts := azureServer(t)
+
+	o := NewOptionsAzure()
+	o.Timeout = 5 * time.Second
+	o.Accounts = []string{"missingcorp"}
+	a, err := NewGobusterAzure(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	a.accountURL = func(account string) string {
+		return fmt.Sprintf("%s/%s", ts.URL, account)
+	}
+	if err := a.PreRun(context.Background(), libgobuster.NewProgress()); err == nil {
+		t.Fatal("expected an error for a missing account")
+	}
+
+	o.Accounts = []string{"Invalid"}
+	if _, err := NewGobusterAzure(libgobuster.NewOptions(), o); err == nil {
+		t.Fatal("expected an error for an invalid account name")
+	}
+}
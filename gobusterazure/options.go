@@ -0,0 +1,25 @@
+package gobusterazure
+
+import (
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// OptionsAzure is the struct to hold all options for this plugin
+type OptionsAzure struct {
+	libgobuster.BasicHTTPOptions
+	// Headers are sent with every request
+	Headers []libgobuster.HTTPHeader
+	// Accounts are known storage accounts, the words are used as container
+	// names below each of them. Without accounts the words are account
+	// names
+	Accounts []string
+	// Containers brute forces the containers of found accounts with the
+	// same wordlist
+	Containers     bool
+	MaxFilesToList int
+}
+
+// NewOptionsAzure returns a new initialized OptionsAzure
+func NewOptionsAzure() *OptionsAzure {
+	return &OptionsAzure{}
+}
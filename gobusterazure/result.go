@@ -0,0 +1,63 @@
+package gobusterazure
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/fatih/color"
+)
+
+var (
+	green  = color.New(color.FgGreen).FprintfFunc()
+	yellow = color.New(color.FgYellow).FprintfFunc()
+)
+
+// Result represents a single result
+type Result struct {
+	Found   bool
+	Account string
+	// Container is empty for the result of an account
+	Container string
+	// URL is the requested url
+	URL        string
+	Status     string
+	StatusCode int
+}
+
+// ResultToString converts the Result to it's textual representation
+func (r Result) ResultToString() (string, error) {
+	buf := &bytes.Buffer{}
+
+	c := yellow
+	kind := "account"
+	if r.Container != "" {
+		c = green
+		kind = "listable"
+	}
+
+	c(buf, "%s [%s]", r.URL, kind)
+	if r.Status != "" {
+		c(buf, " [%s]", r.Status)
+	}
+	c(buf, "\n")
+
+	str := buf.String()
+	return str, nil
+}
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	record := libgobuster.ResultRecord{
+		URL:        r.URL,
+		Path:       r.Account,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Tags:       []string{"account"},
+	}
+	if r.Container != "" {
+		record.Path = fmt.Sprintf("%s/%s", r.Account, r.Container)
+		record.Tags = []string{"listable"}
+	}
+	return record
+}
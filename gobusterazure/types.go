@@ -0,0 +1,23 @@
+package gobusterazure
+
+import "encoding/xml"
+
+// AzureError represents a returned error from Azure
+type AzureError struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// AzureListing contains only a subset of returned properties
+type AzureListing struct {
+	XMLName    xml.Name `xml:"EnumerationResults"`
+	NextMarker string   `xml:"NextMarker"`
+	Blobs      []struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			LastModified  string `xml:"Last-Modified"`
+			ContentLength int64  `xml:"Content-Length"`
+		} `xml:"Properties"`
+	} `xml:"Blobs>Blob"`
+}
@@ -0,0 +1,36 @@
+package gobusterdir
+
+import (
+	"context"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// cachePoisoningBuster is added to the probe requests so the probe itself
+// never poisons the cached response other users get
+const cachePoisoningBuster = "gbcp={{rand}}"
+
+// checkCachePoisoning requests the url again with canaries in the unkeyed
+// headers and returns the tags of the reflected ones if the response is cacheable
+func (d *GobusterDir) checkCachePoisoning(ctx context.Context, url string) ([]string, error) {
+	probe := libgobuster.NewCachePoisoningProbe()
+
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+
+	headers := make([]libgobuster.HTTPHeader, 0, len(d.options.Headers)+len(probe.Headers))
+	headers = append(headers, d.options.Headers...)
+	headers = append(headers, probe.Headers...)
+
+	_, _, header, body, err := d.http.Request(ctx, url+separator+cachePoisoningBuster, libgobuster.RequestOptions{
+		ReturnBody:      true,
+		ModifiedHeaders: headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return probe.Assess(header, body), nil
+}
@@ -0,0 +1,44 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestCheckCachePoisoning(t *testing.T) {
+	t.Parallel()
+
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		fmt.Fprintf(w, `<link href="https://%s/style.css">`, r.Header.Get("X-Forwarded-Host"))
+	}))
+	defer ts.Close()
+
+	o := NewOptionsDir()
+	o.URL = ts.URL + "/"
+	o.Timeout = 5 * time.Second
+	d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	tags, err := d.checkCachePoisoning(context.Background(), ts.URL+"/index.html")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(tags) != 1 || tags[0] != "cache-poisoning:X-Forwarded-Host" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	// the probe must not hit the cached response of other users
+	if len(query) != len("gbcp=")+16 {
+		t.Fatalf("expected a cache buster, got %q", query)
+	}
+}
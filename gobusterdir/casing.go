@@ -0,0 +1,75 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// DetectCaseInsensitive checks if the server ignores the case of paths. IIS
+// servers are detected by the Server header. Otherwise the path of the target
+// url is requested with swapped case and compared to the original response
+func (d *GobusterDir) DetectCaseInsensitive(ctx context.Context) error {
+	target := d.options.URL
+	if !strings.HasSuffix(target, "/") {
+		target = fmt.Sprintf("%s/", target)
+	}
+
+	statusCode, size, header, _, err := d.http.Request(ctx, target, libgobuster.RequestOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", target, err)
+	}
+
+	if server := header.Get("Server"); strings.Contains(strings.ToLower(server), "microsoft-iis") {
+		d.options.CaseInsensitive = true
+		d.options.CaseDetection = fmt.Sprintf("detected by Server: %s", server)
+		return nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", target, err)
+	}
+	swapped := swapCase(u.Path)
+	if swapped == u.Path || statusCode == 404 {
+		d.options.CaseDetection = "not detected"
+		return nil
+	}
+	u.Path = swapped
+
+	swappedStatus, swappedSize, _, _, err := d.http.Request(ctx, u.String(), libgobuster.RequestOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", u.String(), err)
+	}
+	if swappedStatus == statusCode && swappedSize == size {
+		d.options.CaseInsensitive = true
+		d.options.CaseDetection = fmt.Sprintf("detected by requesting %s", swapped)
+		return nil
+	}
+
+	d.options.CaseDetection = "not detected"
+	return nil
+}
+
+// swapCase returns the string with upper case letters converted to lower
+// case and the other way round
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+		return unicode.ToUpper(r)
+	}, s)
+}
+
+// markCaseVariant records the lower case version of the path and returns
+// false if a case variant of it was already processed
+func (d *GobusterDir) markCaseVariant(path string) bool {
+	d.caseMutex.Lock()
+	defer d.caseMutex.Unlock()
+	return d.caseSeen.Add(strings.ToLower(path))
+}
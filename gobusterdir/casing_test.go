@@ -0,0 +1,77 @@
+package gobusterdir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestMarkCaseVariant(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewGobusterDir(libgobuster.NewOptions(), NewOptionsDir())
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !d.markCaseVariant("Admin") {
+		t.Fatal("expected the first variant to be processed")
+	}
+	if d.markCaseVariant("ADMIN") {
+		t.Fatal("expected other case variants to be skipped")
+	}
+}
+
+func TestDetectCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		path     string
+		handler  http.HandlerFunc
+		expected bool
+	}{
+		{"IIS", "/", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Server", "Microsoft-IIS/10.0")
+		}, true},
+		{"SwappedPath", "/App/", func(w http.ResponseWriter, r *http.Request) {
+			if strings.ToLower(r.URL.Path) != "/app/" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}, true},
+		{"CaseSensitive", "/App/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/App/" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}, false},
+		{"RootOnly", "/", func(w http.ResponseWriter, r *http.Request) {}, false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			ts := httptest.NewServer(x.handler)
+			defer ts.Close()
+
+			o := NewOptionsDir()
+			o.URL = ts.URL + x.path
+			o.Timeout = 5 * time.Second
+			d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := d.DetectCaseInsensitive(context.Background()); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if o.CaseInsensitive != x.expected {
+				t.Fatalf("expected case insensitive %t, got %t (%s)", x.expected, o.CaseInsensitive, o.CaseDetection)
+			}
+		})
+	}
+}
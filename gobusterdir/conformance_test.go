@@ -0,0 +1,39 @@
+package gobusterdir
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/OJ/gobuster/v3/libgobuster/plugintest"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "/index.html":
+			w.WriteHeader(http.StatusOK)
+		case "/admin":
+			http.Redirect(w, r, "/admin/", http.StatusMovedPermanently)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	plugintest.Run(t, func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin {
+		o := NewOptionsDir()
+		o.URL = ts.URL
+		o.Timeout = 5 * time.Second
+		o.StatusCodesBlacklistParsed.Add(404)
+		d, err := NewGobusterDir(globalopts, o)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		return d
+	})
+}
@@ -0,0 +1,108 @@
+package gobusterdir
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// crawlEntry holds the crawl state of a single path
+type crawlEntry struct {
+	depth     int
+	processed bool
+	// source is the javascript file the path was extracted from
+	source string
+}
+
+// crawler keeps track of all paths seen by the brute forcing and the
+// crawling so every path is only requested once
+type crawler struct {
+	mutex    sync.Mutex
+	maxDepth int
+	maxPages int
+	pages    int
+	entries  map[string]*crawlEntry
+}
+
+func newCrawler(maxDepth, maxPages int) *crawler {
+	return &crawler{
+		maxDepth: maxDepth,
+		maxPages: maxPages,
+		entries:  make(map[string]*crawlEntry),
+	}
+}
+
+// markProcessed marks the path as processed and returns its depth and
+// source. It returns false if the path was already processed before
+func (c *crawler) markProcessed(path string) (int, string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.entries[path]
+	if !ok {
+		// paths from the wordlist
+		c.entries[path] = &crawlEntry{processed: true}
+		return 0, "", true
+	}
+	if e.processed {
+		return e.depth, e.source, false
+	}
+	e.processed = true
+	return e.depth, e.source, true
+}
+
+// add records the paths found on a page with the given depth and source and
+// returns the new ones which are within the depth and page limits
+func (c *crawler) add(paths []string, depth int, source string) []string {
+	if depth > c.maxDepth {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var ret []string
+	for _, p := range paths {
+		if c.maxPages > 0 && c.pages >= c.maxPages {
+			break
+		}
+		if _, ok := c.entries[p]; ok {
+			continue
+		}
+		c.entries[p] = &crawlEntry{depth: depth, source: source}
+		c.pages++
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// crawl extracts all links below the target url from the page and queues
+// the new ones
+func (d *GobusterDir) crawl(pageURL string, depth int, header http.Header, body []byte, progress *libgobuster.Progress) {
+	if len(body) == 0 {
+		return
+	}
+	if ct := header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return
+	}
+
+	base, err := url.Parse(d.options.URL)
+	if err != nil {
+		return
+	}
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	var paths []string
+	for _, link := range libgobuster.ExtractLinks(body) {
+		if p, ok := libgobuster.ScopedPath(base, page, link); ok {
+			paths = append(paths, p)
+		}
+	}
+	progress.QueueWords(d.crawler.add(paths, depth+1, "")...)
+}
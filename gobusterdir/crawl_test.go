@@ -0,0 +1,35 @@
+package gobusterdir
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCrawler(t *testing.T) {
+	t.Parallel()
+
+	c := newCrawler(2, 3)
+
+	if _, _, ok := c.markProcessed("admin"); !ok {
+		t.Fatal("expected the first processing of a path to succeed")
+	}
+	if _, _, ok := c.markProcessed("admin"); ok {
+		t.Fatal("expected a path to be processed only once")
+	}
+
+	// admin is already known and the page limit is reached after 3 new paths
+	got := c.add([]string{"admin", "login", "users", "static/app.js", "logout"}, 1, "")
+	expected := []string{"login", "users", "static/app.js"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	depth, _, ok := c.markProcessed("login")
+	if !ok || depth != 1 {
+		t.Fatalf("expected login to be processed with depth 1, got %d (%t)", depth, ok)
+	}
+
+	if got := newCrawler(2, 0).add([]string{"deep"}, 3, ""); len(got) != 0 {
+		t.Fatalf("expected no paths beyond the maximum depth, got %v", got)
+	}
+}
@@ -0,0 +1,45 @@
+package gobusterdir
+
+import (
+	"context"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// needsEnrichment checks if a result needs work done by the enrichment pool
+func (d *GobusterDir) needsEnrichment(found bool) bool {
+	return d.globalopts.Cluster || found && (d.options.AssessHeaders || d.options.CheckCachePoisoning)
+}
+
+// Enrich adds the title and fuzzy hash for clustering, the findings of the
+// header assessment and the cache poisoning check to a result. It is called
+// by the enrichment pool instead of the workers
+func (d *GobusterDir) Enrich(ctx context.Context, r libgobuster.Result) (libgobuster.Result, error) {
+	result, ok := r.(Result)
+	if !ok {
+		return r, nil
+	}
+	body := result.body
+	// don't keep the body in collected results
+	result.body = nil
+
+	if d.globalopts.Cluster {
+		result.Title = libgobuster.ExtractTitle(body)
+		result.Simhash = libgobuster.Simhash(body)
+	}
+	if !result.Found {
+		return result, nil
+	}
+
+	if d.options.AssessHeaders {
+		result.Tags = append(result.Tags, libgobuster.AssessResponse(result.fullURL, result.Header, body)...)
+	}
+	if d.options.CheckCachePoisoning {
+		cacheTags, err := d.checkCachePoisoning(ctx, result.fullURL)
+		if err != nil {
+			return result, err
+		}
+		result.Tags = append(result.Tags, cacheTags...)
+	}
+	return result, nil
+}
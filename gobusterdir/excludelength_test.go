@@ -0,0 +1,77 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestExcludeLength(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	// the parallel subtests run after this function returned
+	t.Cleanup(ts.Close)
+
+	var tt = []struct {
+		testName      string
+		excludeLength string
+		verbose       bool
+		expected      map[string]bool
+	}{
+		{"Single length", "4", false, map[string]bool{"/abcdef": true, "/abcdefghij": true}},
+		{"Range", "4-7", false, map[string]bool{"/abcdefghij": true}},
+		{"Open range", "7-", false, map[string]bool{"/abc": true}},
+		{"Verbose", "4-7", true, map[string]bool{"/abc": false, "/abcdef": false, "/abcdefghij": true}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			globalopts := libgobuster.NewOptions()
+			globalopts.Verbose = x.verbose
+			o := NewOptionsDir()
+			o.URL = ts.URL + "/"
+			o.Timeout = 5 * time.Second
+			o.StatusCodesBlacklistParsed.Add(404)
+			var err error
+			o.ExcludeLengthParsed, err = libgobuster.ParseIntRanges(x.excludeLength)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			d, err := NewGobusterDir(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			progress := libgobuster.NewProgress()
+			done := make(chan map[string]bool)
+			go func() {
+				results := make(map[string]bool)
+				for r := range progress.ResultChan {
+					res := r.(Result)
+					results["/"+res.Path] = res.Found
+				}
+				done <- results
+			}()
+			for _, word := range []string{"abc", "abcdef", "abcdefghij"} {
+				if err := d.ProcessWord(context.Background(), word, progress); err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+			}
+			close(progress.ResultChan)
+
+			if results := <-done; fmt.Sprint(results) != fmt.Sprint(x.expected) {
+				t.Fatalf("Expected %v but got %v", x.expected, results)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package gobusterdir
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestExtensionVariants(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName      string
+		exclude       []string
+		only          bool
+		word          string
+		expectedWords []string
+		expectedSkip  bool
+	}{
+		{"Extensions", nil, false, "admin", []string{"admin.php", "admin.png"}, false},
+		{"Extensions only", nil, true, "admin", []string{"admin.php", "admin.png"}, true},
+		{"Excluded extension", []string{"png"}, false, "admin", []string{"admin.php"}, false},
+		{"Word with excluded extension", []string{"png"}, false, "logo.PNG", nil, false},
+		{"Word with excluded extension and extensions only", []string{"png"}, true, "logo.png", nil, false},
+		{"Word with other extension", []string{"png"}, true, "index.html", []string{"index.html.php"}, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptionsDir()
+			o.ExtensionsParsed.AddRange([]string{"php", "png"})
+			o.ExcludeExtensionsParsed.AddRange(x.exclude)
+			o.ExtensionsOnly = x.only
+			d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			words := d.AdditionalWords(x.word)
+			sort.Strings(words)
+			if strings.Join(words, ",") != strings.Join(x.expectedWords, ",") {
+				t.Fatalf("expected %v, got %v", x.expectedWords, words)
+			}
+			if skip := d.SkipWord(x.word); skip != x.expectedSkip {
+				t.Fatalf("expected skip %t, got %t", x.expectedSkip, skip)
+			}
+		})
+	}
+}
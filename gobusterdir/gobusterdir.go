@@ -4,15 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
-	"net"
 	"net/http"
+	"path"
 	"strings"
+	"sync"
 	"text/tabwriter"
-	"unicode/utf8"
+	"time"
 
 	"github.com/OJ/gobuster/v3/libgobuster"
-	"github.com/google/uuid"
 )
 
 // nolint:gochecknoglobals
@@ -35,9 +36,17 @@ func (e *ErrWildcard) Error() string {
 
 // GobusterDir is the main type to implement the interface
 type GobusterDir struct {
+	crawler    *crawler
+	recursion  *recursion
 	options    *OptionsDir
 	globalopts *libgobuster.Options
 	http       *libgobuster.HTTPClient
+	// caseSeen holds the lower case paths if the target is case insensitive
+	caseMutex sync.Mutex
+	caseSeen  libgobuster.Set[string]
+	// soft404 is the response for non existing paths if the server reports
+	// them as found, nil otherwise
+	soft404 *fingerprint
 }
 
 // NewGobusterDir creates a new initialized GobusterDir
@@ -53,16 +62,30 @@ func NewGobusterDir(globalopts *libgobuster.Options, opts *OptionsDir) (*Gobuste
 	g := GobusterDir{
 		options:    opts,
 		globalopts: globalopts,
+		caseSeen:   libgobuster.NewSet[string](),
+	}
+
+	if opts.Crawl || opts.ExtractJS || len(globalopts.Rules) > 0 {
+		g.crawler = newCrawler(opts.CrawlDepth, opts.CrawlMaxPages)
+	}
+
+	if opts.Depth > 0 {
+		g.recursion = newRecursion(opts.Depth)
 	}
 
 	basicOptions := libgobuster.BasicHTTPOptions{
 		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
 		Timeout:         opts.Timeout,
 		UserAgent:       opts.UserAgent,
 		NoTLSValidation: opts.NoTLSValidation,
 		RetryOnTimeout:  opts.RetryOnTimeout,
 		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
 		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
 	}
 
 	httpOpts := libgobuster.HTTPOptions{
@@ -72,7 +95,11 @@ func NewGobusterDir(globalopts *libgobuster.Options, opts *OptionsDir) (*Gobuste
 		Password:              opts.Password,
 		Headers:               opts.Headers,
 		NoCanonicalizeHeaders: opts.NoCanonicalizeHeaders,
+		CacheBypass:           opts.CacheBypass,
 		Cookies:               opts.Cookies,
+		Script:                globalopts.Script,
+		Throttle:              globalopts.Throttle,
+		ConnStats:             globalopts.ConnStats,
 		Method:                opts.Method,
 	}
 
@@ -90,6 +117,17 @@ func (d *GobusterDir) Name() string {
 	return "directory enumeration"
 }
 
+// Target returns the target of the run
+func (d *GobusterDir) Target() string {
+	return d.options.URL
+}
+
+// ProbeLatency is the latency probe implementation of gobusterdir, it sends a
+// HEAD request to the url
+func (d *GobusterDir) ProbeLatency(ctx context.Context) (time.Duration, error) {
+	return d.http.ProbeLatency(ctx, d.options.URL)
+}
+
 // PreRun is the pre run implementation of gobusterdir
 func (d *GobusterDir) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
 	// add trailing slash
@@ -97,40 +135,33 @@ func (d *GobusterDir) PreRun(ctx context.Context, progress *libgobuster.Progress
 		d.options.URL = fmt.Sprintf("%s/", d.options.URL)
 	}
 
-	_, _, _, _, err := d.http.Request(ctx, d.options.URL, libgobuster.RequestOptions{})
+	var state tls.ConnectionState
+	_, _, _, _, err := d.http.Request(ctx, d.options.URL, libgobuster.RequestOptions{TLS: &state})
 	if err != nil {
 		return fmt.Errorf("unable to connect to %s: %w", d.options.URL, err)
 	}
 
-	guid := uuid.New()
-	url := fmt.Sprintf("%s%s", d.options.URL, guid)
-	if d.options.UseSlash {
-		url = fmt.Sprintf("%s/", url)
-	}
-
-	wildcardResp, wildcardLength, _, _, err := d.http.Request(ctx, url, libgobuster.RequestOptions{})
-	if err != nil {
-		return err
+	if d.options.CertInfo {
+		if info, ok := libgobuster.CertificateFromState(state); ok {
+			libgobuster.ReportCertificate(progress, d.options.URL, info)
+		}
 	}
 
-	if d.options.ExcludeLengthParsed.Contains(int(wildcardLength)) {
-		// we are done and ignore the request as the length is excluded
-		return nil
+	if len(d.options.TechPaths) > 0 {
+		progress.QueueWords(d.options.TechPaths...)
 	}
 
-	if d.options.StatusCodesBlacklistParsed.Length() > 0 {
-		if !d.options.StatusCodesBlacklistParsed.Contains(wildcardResp) {
-			return &ErrWildcard{url: url, statusCode: wildcardResp, length: wildcardLength}
-		}
-	} else if d.options.StatusCodesParsed.Length() > 0 {
-		if d.options.StatusCodesParsed.Contains(wildcardResp) {
-			return &ErrWildcard{url: url, statusCode: wildcardResp, length: wildcardLength}
+	if d.options.WaybackSeed {
+		if err := d.seedFromWayback(ctx, progress); err != nil {
+			// not fatal, the wordlist is still processed
+			progress.MessageChan <- libgobuster.Message{
+				Level:   libgobuster.LevelError,
+				Message: fmt.Sprintf("could not seed paths from the wayback machine: %v", err),
+			}
 		}
-	} else {
-		return fmt.Errorf("StatusCodes and StatusCodesBlacklist are both not set which should not happen")
 	}
 
-	return nil
+	return d.detectSoft404(ctx, progress)
 }
 
 func getBackupFilenames(word string) []string {
@@ -148,6 +179,11 @@ func getBackupFilenames(word string) []string {
 	return ret
 }
 
+// Warnings returns warnings about potentially destructive or noisy configurations
+func (d *GobusterDir) Warnings() []string {
+	return libgobuster.HTTPWarnings(&d.options.HTTPOptions, d.globalopts)
+}
+
 func (d *GobusterDir) AdditionalWords(word string) []string {
 	var words []string
 	// build list of urls to check
@@ -157,7 +193,13 @@ func (d *GobusterDir) AdditionalWords(word string) []string {
 	if d.options.DiscoverBackup {
 		words = append(words, getBackupFilenames(word)...)
 	}
+	if d.hasExcludedExtension(word) {
+		return words
+	}
 	for ext := range d.options.ExtensionsParsed.Set {
+		if d.options.ExcludeExtensionsParsed.Contains(strings.ToLower(ext)) {
+			continue
+		}
 		filename := fmt.Sprintf("%s.%s", word, ext)
 		words = append(words, filename)
 		if d.options.DiscoverBackup {
@@ -167,72 +209,134 @@ func (d *GobusterDir) AdditionalWords(word string) []string {
 	return words
 }
 
+// WordKind returns the kind of the words for the word policy. Words passed
+// through encoders are not checked, the encoders decide about their url
+func (d *GobusterDir) WordKind() libgobuster.WordKind {
+	if len(d.options.Encoder.Names) > 0 {
+		return libgobuster.WordKindAny
+	}
+	return libgobuster.WordKindPath
+}
+
+// SkipWord skips the bare word if only the extension variants are tried.
+// Words ending in an excluded extension get no variants and are kept
+func (d *GobusterDir) SkipWord(word string) bool {
+	return d.options.ExtensionsOnly && !d.hasExcludedExtension(word)
+}
+
+// hasExcludedExtension checks if the word ends in an excluded extension
+func (d *GobusterDir) hasExcludedExtension(word string) bool {
+	if d.options.ExcludeExtensionsParsed.Length() == 0 {
+		return false
+	}
+	ext := path.Ext(word)
+	return ext != "" && d.options.ExcludeExtensionsParsed.Contains(strings.ToLower(strings.TrimPrefix(ext, ".")))
+}
+
+// entityURL returns the url of the entity below the base url and the entity
+// without its leading slash. The base url and the entity are separated by
+// exactly one slash
+func entityURL(base, entity string) (string, string) {
+	// prevent double slashes by removing leading /
+	entity = strings.TrimPrefix(entity, "/")
+	if strings.HasSuffix(base, "/") {
+		return base + entity, entity
+	}
+	return base + "/" + entity, entity
+}
+
 // ProcessWord is the process implementation of gobusterdir
 func (d *GobusterDir) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
 	suffix := ""
 	if d.options.UseSlash {
 		suffix = "/"
 	}
-	entity := fmt.Sprintf("%s%s", word, suffix)
+	prefix := ""
+	if d.recursion != nil {
+		// the directory of a recursion pass must not be encoded
+		prefix, word = d.recursion.split(word)
+	}
+	// concatenation needs a single allocation, this runs for every word
+	url, entity := entityURL(d.options.URL, prefix+d.options.Encoder.Encode(word)+suffix)
 
-	// make sure the url ends with a slash
-	if !strings.HasSuffix(d.options.URL, "/") {
-		d.options.URL = fmt.Sprintf("%s/", d.options.URL)
+	if d.options.CaseInsensitive && !d.markCaseVariant(entity) {
+		return nil
 	}
-	// prevent double slashes by removing leading /
-	if strings.HasPrefix(entity, "/") {
-		// get size of first rune and trim it
-		_, i := utf8.DecodeRuneInString(entity)
-		entity = entity[i:]
+
+	depth := 0
+	source := ""
+	if d.crawler != nil {
+		var ok bool
+		// shared dedupe of brute forced, crawled and extracted paths
+		if depth, source, ok = d.crawler.markProcessed(entity); !ok {
+			return nil
+		}
 	}
-	url := fmt.Sprintf("%s%s", d.options.URL, entity)
 
-	tries := 1
-	if d.options.RetryOnTimeout && d.options.RetryAttempts > 0 {
-		// add it so it will be the overall max requests
-		tries += d.options.RetryAttempts
+	var timing libgobuster.RequestTiming
+	requestOptions := libgobuster.RequestOptions{
+		Timing: &timing,
+		// the body is needed to cluster similar responses, for crawling and
+		// for extracting javascript endpoints
+		ReturnBody: d.globalopts.Cluster || d.options.Crawl || d.options.ExtractJS,
+	}
+	if d.options.AssessHeaders {
+		// send an origin to detect reflecting CORS configurations and
+		// keep the body for the directory listing check
+		requestOptions.ReturnBody = true
+		requestOptions.ModifiedHeaders = append([]libgobuster.HTTPHeader{{Name: "Origin", Value: libgobuster.AssessOrigin}}, d.options.Headers...)
+	}
+	if d.soft404 != nil {
+		// the body is compared to the response for non existing paths
+		requestOptions.ReturnBody = true
 	}
 
 	var statusCode int
 	var size int64
 	var header http.Header
-	for i := 1; i <= tries; i++ {
-		var err error
-		statusCode, size, header, _, err = d.http.Request(ctx, url, libgobuster.RequestOptions{})
-		if err != nil {
-			// check if it's a timeout and if we should try again and try again
-			// otherwise the timeout error is raised
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && i != tries {
-				continue
-			} else if strings.Contains(err.Error(), "invalid control character in URL") {
-				// put error in error chan so it's printed out and ignore it
-				// so gobuster will not quit
-				progress.ErrorChan <- err
-				continue
-			} else {
-				return err
-			}
+	var body []byte
+	var err error
+	statusCode, size, header, body, err = d.http.Request(ctx, url, requestOptions)
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return err
 		}
-		break
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
 	}
 
 	if statusCode != 0 {
-		resultStatus := false
-
-		if d.options.StatusCodesBlacklistParsed.Length() > 0 {
-			if !d.options.StatusCodesBlacklistParsed.Contains(statusCode) {
-				resultStatus = true
-			}
-		} else if d.options.StatusCodesParsed.Length() > 0 {
-			if d.options.StatusCodesParsed.Contains(statusCode) {
-				resultStatus = true
-			}
-		} else {
-			return fmt.Errorf("StatusCodes and StatusCodesBlacklist are both not set which should not happen")
+		resultStatus, err := d.isFound(statusCode)
+		if err != nil {
+			return err
+		}
+		if resultStatus && d.options.ExcludeLengthParsed.Contains(int(size)) {
+			resultStatus = false
+		}
+		if resultStatus && d.isSoft404(entity, statusCode, body) {
+			resultStatus = false
 		}
 
-		if (resultStatus && !d.options.ExcludeLengthParsed.Contains(int(size))) || d.globalopts.Verbose {
-			progress.ResultChan <- Result{
+		if resultStatus || d.globalopts.Verbose {
+			var tags []string
+			if resultStatus {
+				tags = append(getTags(entity), sourceTags(source)...)
+				// crawling and recursion queue words, so they can't be
+				// moved to the enrichment
+				if d.options.Crawl {
+					d.crawl(url, depth, header, body, progress)
+				}
+				if d.options.ExtractJS {
+					d.extractJS(url, entity, depth, header, body, progress)
+				}
+				if d.recursion != nil {
+					if dir, ok := directoryOf(url, entity, statusCode, header); ok {
+						d.recursion.add(prefix, dir, progress)
+					}
+				}
+			}
+			result := Result{
 				URL:        d.options.URL,
 				Path:       entity,
 				Verbose:    d.globalopts.Verbose,
@@ -243,6 +347,29 @@ func (d *GobusterDir) ProcessWord(ctx context.Context, word string, progress *li
 				Header:     header,
 				StatusCode: statusCode,
 				Size:       size,
+				Tags:       tags,
+				Timing:     timing,
+				ShowTiming: d.globalopts.ShowTiming,
+			}
+			if d.needsEnrichment(resultStatus) {
+				result.fullURL = url
+				result.body = body
+				progress.EnrichChan <- result
+			} else {
+				progress.ResultChan <- result
+			}
+		}
+
+		if d.options.SlashDiff && !d.options.UseSlash {
+			if err := d.checkSlashDiff(ctx, url, entity, statusCode, header, progress); err != nil {
+				return err
+			}
+		}
+
+		if len(d.globalopts.Rules) > 0 {
+			record := libgobuster.ResultRecord{URL: url, Path: fmt.Sprintf("/%s", entity), Found: resultStatus, StatusCode: statusCode, Size: size}
+			if err := d.applyRules(ctx, url, entity, depth, record, header, progress); err != nil {
+				return err
 			}
 		}
 	}
@@ -250,6 +377,18 @@ func (d *GobusterDir) ProcessWord(ctx context.Context, word string, progress *li
 	return nil
 }
 
+// isFound checks if the status code is a positive result
+func (d *GobusterDir) isFound(statusCode int) (bool, error) {
+	switch {
+	case d.options.StatusCodesBlacklistParsed.Length() > 0:
+		return !d.options.StatusCodesBlacklistParsed.Contains(statusCode), nil
+	case d.options.StatusCodesParsed.Length() > 0:
+		return d.options.StatusCodesParsed.Contains(statusCode), nil
+	default:
+		return false, fmt.Errorf("StatusCodes and StatusCodesBlacklist are both not set which should not happen")
+	}
+}
+
 // GetConfigString returns the string representation of the current config
 func (d *GobusterDir) GetConfigString() (string, error) {
 	var buffer bytes.Buffer
@@ -276,7 +415,7 @@ func (d *GobusterDir) GetConfigString() (string, error) {
 
 	wordlist := "stdin (pipe)"
 	if d.globalopts.Wordlist != "-" {
-		wordlist = d.globalopts.Wordlist
+		wordlist = d.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
@@ -299,7 +438,7 @@ func (d *GobusterDir) GetConfigString() (string, error) {
 	}
 
 	if len(o.ExcludeLength) > 0 {
-		if _, err := fmt.Fprintf(tw, "[+] Exclude Length:\t%s\n", d.options.ExcludeLengthParsed.Stringify()); err != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Exclude Length:\t%s\n", d.options.ExcludeLengthParsed.String()); err != nil {
 			return "", err
 		}
 	}
@@ -310,6 +449,12 @@ func (d *GobusterDir) GetConfigString() (string, error) {
 		}
 	}
 
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
 	if o.Cookies != "" {
 		if _, err := fmt.Fprintf(tw, "[+] Cookies:\t%s\n", o.Cookies); err != nil {
 			return "", err
@@ -334,12 +479,44 @@ func (d *GobusterDir) GetConfigString() (string, error) {
 		}
 	}
 
-	if o.Extensions != "" || o.ExtensionsFile != "" {
+	if o.Extensions != "" || o.ExtensionsFile != "" || o.Tech != "" {
 		if _, err := fmt.Fprintf(tw, "[+] Extensions:\t%s\n", o.ExtensionsParsed.Stringify()); err != nil {
 			return "", err
 		}
 	}
 
+	if o.ExcludeExtensionsParsed.Length() > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Excluded extensions:\t%s\n", o.ExcludeExtensionsParsed.Stringify()); err != nil {
+			return "", err
+		}
+	}
+
+	if o.ExtensionsOnly {
+		if _, err := fmt.Fprintf(tw, "[+] Extensions only:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Tech != "" {
+		tech := fmt.Sprintf("%s (%d candidate paths)", o.Tech, len(o.TechPaths))
+		if o.TechDetection != "" {
+			tech = fmt.Sprintf("%s (%s, %d candidate paths)", o.Tech, o.TechDetection, len(o.TechPaths))
+		}
+		if _, err := fmt.Fprintf(tw, "[+] Tech:\t%s\n", tech); err != nil {
+			return "", err
+		}
+	}
+
+	if o.CaseInsensitive || o.CaseDetection != "" {
+		caseInsensitive := fmt.Sprintf("%t", o.CaseInsensitive)
+		if o.CaseDetection != "" {
+			caseInsensitive = fmt.Sprintf("%t (%s)", o.CaseInsensitive, o.CaseDetection)
+		}
+		if _, err := fmt.Fprintf(tw, "[+] Case insensitive:\t%s\n", caseInsensitive); err != nil {
+			return "", err
+		}
+	}
+
 	if o.ExtensionsFile != "" {
 		if _, err := fmt.Fprintf(tw, "[+] Extensions file:\t%s\n", o.ExtensionsFile); err != nil {
 			return "", err
@@ -358,6 +535,66 @@ func (d *GobusterDir) GetConfigString() (string, error) {
 		}
 	}
 
+	if o.CacheBypass {
+		if _, err := fmt.Fprintf(tw, "[+] Cache bypass:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Crawl {
+		if _, err := fmt.Fprintf(tw, "[+] Crawl:\tdepth %d, max %d pages\n", o.CrawlDepth, o.CrawlMaxPages); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Depth > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Recursion depth:\t%d\n", o.Depth); err != nil {
+			return "", err
+		}
+	}
+
+	if d.globalopts.RulesFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Rules:\t%s (%d rules)\n", d.globalopts.RulesFile, len(d.globalopts.Rules)); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Encoder.Names) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Encoders:\t%s\n", o.Encoder); err != nil {
+			return "", err
+		}
+	}
+
+	if o.ExtractJS {
+		if _, err := fmt.Fprintf(tw, "[+] Extract JS:\tdepth %d, max %d pages\n", o.CrawlDepth, o.CrawlMaxPages); err != nil {
+			return "", err
+		}
+	}
+
+	if o.SlashDiff {
+		if _, err := fmt.Fprintf(tw, "[+] Slash differential:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.WaybackSeed {
+		if _, err := fmt.Fprintf(tw, "[+] Wayback seed:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.AssessHeaders {
+		if _, err := fmt.Fprintf(tw, "[+] Assess Headers:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.CheckCachePoisoning {
+		if _, err := fmt.Fprintf(tw, "[+] Cache poisoning check:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
 	if o.Expanded {
 		if _, err := fmt.Fprintf(tw, "[+] Expanded:\ttrue\n"); err != nil {
 			return "", err
@@ -380,6 +617,18 @@ func (d *GobusterDir) GetConfigString() (string, error) {
 		return "", err
 	}
 
+	if d.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", d.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	if d.globalopts.Script != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Script:\t%s\n", d.globalopts.Script); err != nil {
+			return "", err
+		}
+	}
+
 	if err := tw.Flush(); err != nil {
 		return "", fmt.Errorf("error on tostring: %w", err)
 	}
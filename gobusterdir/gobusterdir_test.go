@@ -0,0 +1,57 @@
+package gobusterdir
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEntityURL(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		base     string
+		entity   string
+		expected string
+	}{
+		{"http://localhost/", "admin", "http://localhost/admin"},
+		{"http://localhost", "admin", "http://localhost/admin"},
+		{"http://localhost/", "/admin/", "http://localhost/admin/"},
+		{"http://localhost/app/", "/ä", "http://localhost/app/ä"},
+		{"http://localhost/", "", "http://localhost/"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.expected, func(t *testing.T) {
+			t.Parallel()
+			url, entity := entityURL(x.base, x.entity)
+			if url != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, url)
+			}
+			if entity != strings.TrimPrefix(x.entity, "/") {
+				t.Fatalf("unexpected entity %s", entity)
+			}
+		})
+	}
+}
+
+func FuzzEntityURL(f *testing.F) {
+	f.Add("http://localhost/", "admin")
+	f.Add("http://localhost", "/admin/")
+	f.Add("", "//")
+	f.Add("http://[::1]:8080/a b/", "\x00%zz")
+
+	f.Fuzz(func(t *testing.T, base, word string) {
+		url, entity := entityURL(base, word)
+		if strings.HasPrefix(word, "/") && entity != word[1:] || !strings.HasPrefix(word, "/") && entity != word {
+			t.Fatalf("entity %q of word %q", entity, word)
+		}
+		if !strings.HasPrefix(url, base) || !strings.HasSuffix(url, "/"+entity) {
+			t.Fatalf("url %q does not join %q and %q", url, base, entity)
+		}
+		// only a single slash is added between the base url and the word
+		if len(url) > len(base)+len(entity)+1 {
+			t.Fatalf("url %q is longer than %q and %q", url, base, entity)
+		}
+	})
+}
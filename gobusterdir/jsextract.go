@@ -0,0 +1,70 @@
+package gobusterdir
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// TagJSEndpoint is added to all results found through javascript endpoint extraction
+const TagJSEndpoint = "js-endpoint"
+
+// sourceTags returns the tags of a path extracted from the given javascript source
+func sourceTags(source string) []string {
+	if source == "" {
+		return nil
+	}
+	return []string{TagJSEndpoint, fmt.Sprintf("source:%s", source)}
+}
+
+// isJavascript checks if the response is a javascript file
+func isJavascript(entity string, header http.Header) bool {
+	if ct := header.Get("Content-Type"); ct != "" {
+		return strings.Contains(ct, "javascript") || strings.Contains(ct, "ecmascript")
+	}
+	ext := path.Ext(entity)
+	return ext == ".js" || ext == ".mjs"
+}
+
+// extractJS extracts the endpoints from a found javascript file or the
+// inline scripts of a html page and queues the new ones, tagged with the
+// file they were found in
+func (d *GobusterDir) extractJS(pageURL, entity string, depth int, header http.Header, body []byte, progress *libgobuster.Progress) {
+	if len(body) == 0 {
+		return
+	}
+
+	base, err := url.Parse(d.options.URL)
+	if err != nil {
+		return
+	}
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	var endpoints []string
+	switch {
+	case isJavascript(entity, header):
+		endpoints = libgobuster.ExtractEndpoints(body)
+		// relative paths in bundles are relative to the document loading
+		// them and not to the script itself
+		page = base
+	case strings.Contains(header.Get("Content-Type"), "html"):
+		endpoints = libgobuster.ExtractInlineScriptEndpoints(body)
+	default:
+		return
+	}
+
+	var paths []string
+	for _, e := range endpoints {
+		if p, ok := libgobuster.ScopedPath(base, page, e); ok {
+			paths = append(paths, p)
+		}
+	}
+	progress.QueueWords(d.crawler.add(paths, depth+1, entity)...)
+}
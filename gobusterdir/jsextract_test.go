@@ -0,0 +1,57 @@
+package gobusterdir
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestIsJavascript(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName    string
+		entity      string
+		contentType string
+		expected    bool
+	}{
+		{"Extension", "static/app.js", "", true},
+		{"Module", "app.mjs", "", true},
+		{"Content-Type", "bundle", "application/javascript; charset=utf-8", true},
+		{"Content-Type wins", "app.js", "text/html", false},
+		{"Other", "index.php", "", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			header := http.Header{}
+			if x.contentType != "" {
+				header.Set("Content-Type", x.contentType)
+			}
+			if got := isJavascript(x.entity, header); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestCrawlerSource(t *testing.T) {
+	t.Parallel()
+
+	c := newCrawler(2, 0)
+	c.add([]string{"api/v1/users"}, 1, "static/app.js")
+
+	_, source, ok := c.markProcessed("api/v1/users")
+	if !ok || source != "static/app.js" {
+		t.Fatalf("expected the source static/app.js, got %q (%t)", source, ok)
+	}
+	expected := []string{TagJSEndpoint, "source:static/app.js"}
+	if got := sourceTags(source); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	if got := sourceTags(""); got != nil {
+		t.Fatalf("expected no tags for wordlist entries, got %v", got)
+	}
+}
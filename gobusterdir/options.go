@@ -1,26 +1,58 @@
 package gobusterdir
 
 import (
+	"fmt"
+
 	"github.com/OJ/gobuster/v3/libgobuster"
 )
 
 // OptionsDir is the struct to hold all options for this plugin
 type OptionsDir struct {
 	libgobuster.HTTPOptions
-	Extensions                 string
-	ExtensionsParsed           libgobuster.Set[string]
-	ExtensionsFile             string
+	Extensions       string
+	ExtensionsParsed libgobuster.Set[string]
+	ExtensionsFile   string
+	// ExcludeExtensionsParsed are never appended, words already ending in
+	// one of them are tried as they are
+	ExcludeExtensions       string
+	ExcludeExtensionsParsed libgobuster.Set[string]
+	// ExtensionsOnly only tries the extension variants of a word
+	ExtensionsOnly             bool
 	StatusCodes                string
 	StatusCodesParsed          libgobuster.Set[int]
 	StatusCodesBlacklist       string
 	StatusCodesBlacklistParsed libgobuster.Set[int]
 	UseSlash                   bool
+	SlashDiff                  bool
 	HideLength                 bool
 	Expanded                   bool
 	NoStatus                   bool
 	DiscoverBackup             bool
 	ExcludeLength              string
-	ExcludeLengthParsed        libgobuster.Set[int]
+	ExcludeLengthParsed        libgobuster.IntRanges
+	AssessHeaders              bool
+	CheckCachePoisoning        bool
+	WaybackSeed                bool
+	Crawl                      bool
+	ExtractJS                  bool
+	Encoder                    libgobuster.EncoderChain
+	CrawlDepth                 int
+	CrawlMaxPages              int
+	// CertInfo reports the certificate of https targets
+	CertInfo bool
+	// Depth is the maximum recursion depth into found directories, 0
+	// disables the recursion
+	Depth int
+	// Tech is the name of the applied TechPreset or TechAuto
+	Tech string
+	// TechDetection is the result of the detection if Tech is TechAuto
+	TechDetection string
+	TechPaths     []string
+	// CaseInsensitive skips case variants of already processed paths
+	CaseInsensitive bool
+	DetectCase      bool
+	// CaseDetection is the result of the detection if DetectCase is set
+	CaseDetection string
 }
 
 // NewOptionsDir returns a new initialized OptionsDir
@@ -29,6 +61,45 @@ func NewOptionsDir() *OptionsDir {
 		StatusCodesParsed:          libgobuster.NewSet[int](),
 		StatusCodesBlacklistParsed: libgobuster.NewSet[int](),
 		ExtensionsParsed:           libgobuster.NewSet[string](),
-		ExcludeLengthParsed:        libgobuster.NewSet[int](),
+		ExcludeExtensionsParsed:    libgobuster.NewSet[string](),
+	}
+}
+
+// ValidationProblems returns all problems of the dir options
+func (opt *OptionsDir) ValidationProblems() []libgobuster.ValidationProblem {
+	problems := opt.HTTPOptions.ValidationProblems()
+
+	if opt.StatusCodes != "" && opt.StatusCodesBlacklist != "" {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "status-codes",
+			Problem:    fmt.Sprintf("status-codes (%q) and status-codes-blacklist (%q) are both set", opt.StatusCodes, opt.StatusCodesBlacklist),
+			Suggestion: "please set only one. status-codes-blacklist is set by default so you might want to disable it by supplying an empty string",
+		})
+	}
+
+	if opt.StatusCodes == "" && opt.StatusCodesBlacklist == "" {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "status-codes",
+			Problem:    "status-codes and status-codes-blacklist are both not set",
+			Suggestion: "please set one",
+		})
+	}
+
+	if opt.ExtensionsOnly && opt.Extensions == "" && opt.ExtensionsFile == "" && opt.Tech == "" {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "extensions-only",
+			Problem:    "extensions-only is set without extensions",
+			Suggestion: "set the extensions with -x, -X or --tech",
+		})
 	}
+
+	if opt.SlashDiff && opt.UseSlash {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "slash-diff",
+			Problem:    "slash-diff and add-slash are both set",
+			Suggestion: "remove add-slash, slash-diff already requests every word with and without a trailing slash",
+		})
+	}
+
+	return problems
 }
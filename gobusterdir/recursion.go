@@ -0,0 +1,86 @@
+package gobusterdir
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// recursion keeps track of the found directories which get another pass
+// over the wordlist
+type recursion struct {
+	mutex    sync.Mutex
+	maxDepth int
+	// dirs maps the queued directories to their depth
+	dirs map[string]int
+}
+
+func newRecursion(maxDepth int) *recursion {
+	return &recursion{
+		maxDepth: maxDepth,
+		dirs:     make(map[string]int),
+	}
+}
+
+// split returns the longest queued directory the word starts with and the
+// remaining word of the wordlist
+func (r *recursion) split(word string) (string, string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	prefix := ""
+	for dir := range r.dirs {
+		if len(dir) > len(prefix) && strings.HasPrefix(word, dir) {
+			prefix = dir
+		}
+	}
+	return prefix, strings.TrimPrefix(word, prefix)
+}
+
+// add queues the directory found in the pass of prefix if it is within the
+// depth limit and was not queued before
+func (r *recursion) add(prefix, dir string, progress *libgobuster.Progress) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	depth := r.dirs[prefix] + 1
+	if depth > r.maxDepth {
+		return
+	}
+	if _, ok := r.dirs[dir]; ok {
+		return
+	}
+	r.dirs[dir] = depth
+	progress.QueueRecursion(dir)
+}
+
+// directoryOf returns the directory of a found entity. Entities ending with
+// a slash are directories, others only if they redirect to the entity with
+// a trailing slash
+func directoryOf(fullURL, entity string, statusCode int, header http.Header) (string, bool) {
+	if strings.HasSuffix(entity, "/") {
+		return entity, true
+	}
+
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return "", false
+	}
+
+	base, err := url.Parse(fullURL)
+	if err != nil {
+		return "", false
+	}
+	location, err := base.Parse(header.Get("Location"))
+	if err != nil {
+		return "", false
+	}
+	if location.Path != base.Path+"/" {
+		return "", false
+	}
+	return entity + "/", true
+}
@@ -0,0 +1,104 @@
+package gobusterdir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestDirectoryOf(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		entity   string
+		status   int
+		location string
+		expected string
+	}{
+		{"Slash", "admin/", 403, "", "admin/"},
+		{"Redirect", "admin", 301, "/base/admin/", "admin/"},
+		{"RelativeRedirect", "admin", 302, "admin/", "admin/"},
+		{"AbsoluteRedirect", "admin", 308, "https://example.com/base/admin/", "admin/"},
+		{"OtherRedirect", "admin", 301, "/login", ""},
+		{"File", "admin", 200, "", ""},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			header := http.Header{}
+			if x.location != "" {
+				header.Set("Location", x.location)
+			}
+			dir, _ := directoryOf("http://example.com/base/"+x.entity, x.entity, x.status, header)
+			if dir != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, dir)
+			}
+		})
+	}
+}
+
+func TestRecursion(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "/admin/secret":
+			w.WriteHeader(http.StatusOK)
+		case "/admin", "/admin/admin":
+			http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte("admin\nsecret\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	globalopts := libgobuster.NewOptions()
+	globalopts.Threads = 2
+	globalopts.Wordlist = wordlist
+	globalopts.CollectResults = true
+
+	o := NewOptionsDir()
+	o.URL = ts.URL
+	o.Timeout = 5 * time.Second
+	o.StatusCodesBlacklistParsed.Add(404)
+	o.Depth = 1
+	d, err := NewGobusterDir(globalopts, o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	g, err := libgobuster.NewGobuster(globalopts, d, libgobuster.NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	var got []string
+	for _, r := range g.CollectedResults() {
+		got = append(got, strings.TrimPrefix(libgobuster.NewResultRecord(r).Path, "/"))
+	}
+	sort.Strings(got)
+	// admin/admin is found in the first recursion but not brute forced again
+	expected := []string{"admin", "admin/admin", "admin/secret"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
@@ -29,6 +31,14 @@ type Result struct {
 	Header     http.Header
 	StatusCode int
 	Size       int64
+	Tags       []string
+	Title      string
+	Simhash    uint64
+	Timing     libgobuster.RequestTiming
+	ShowTiming bool
+	// fullURL and body are only kept until the result is enriched
+	fullURL string
+	body    []byte
 }
 
 // ResultToString converts the Result to it's textual representation
@@ -82,11 +92,21 @@ func (r Result) ResultToString() (string, error) {
 		}
 	}
 
+	if r.ShowTiming {
+		if _, err := fmt.Fprintf(buf, " [%s]", r.Timing); err != nil {
+			return "", err
+		}
+	}
+
 	location := r.Header.Get("Location")
 	if location != "" {
 		blue(buf, " [--> %s]", location)
 	}
 
+	if len(r.Tags) > 0 {
+		red(buf, " [%s]", strings.Join(r.Tags, ","))
+	}
+
 	if _, err := fmt.Fprintf(buf, "\n"); err != nil {
 		return "", err
 	}
@@ -95,3 +115,19 @@ func (r Result) ResultToString() (string, error) {
 
 	return s, nil
 }
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	return libgobuster.ResultRecord{
+		URL:        fmt.Sprintf("%s%s", r.URL, r.Path),
+		Path:       fmt.Sprintf("/%s", r.Path),
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Size:       r.Size,
+		Tags:       r.Tags,
+		Redirect:   r.Header.Get("Location"),
+		Title:      r.Title,
+		Simhash:    r.Simhash,
+		Timing:     r.Timing,
+	}
+}
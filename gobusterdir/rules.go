@@ -0,0 +1,91 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// applyRules executes the actions of all rules matching the response. Retried
+// requests are reported with a tag of the rule and are not checked against the
+// rules again, additional requests are queued and deduplicated like crawled paths
+func (d *GobusterDir) applyRules(ctx context.Context, fullURL, entity string, depth int, record libgobuster.ResultRecord, header http.Header, progress *libgobuster.Progress) error {
+	for _, r := range libgobuster.MatchingRules(d.globalopts.Rules, record, header) {
+		if headers := r.Headers(); len(headers) > 0 {
+			if err := d.retryWithHeaders(ctx, fullURL, entity, r.Name, headers, progress); err != nil {
+				return err
+			}
+		}
+
+		paths := r.Requests(entity)
+		if location := header.Get("Location"); r.Then.FollowLocation && location != "" {
+			if p, ok := d.scopedLocation(fullURL, location); ok {
+				paths = append(paths, p)
+			}
+		}
+		progress.QueueWords(d.crawler.add(paths, depth+1, "")...)
+	}
+	return nil
+}
+
+// scopedLocation returns the path of the location relative to the target url
+// if it is within the target url
+func (d *GobusterDir) scopedLocation(fullURL, location string) (string, bool) {
+	base, err := url.Parse(d.options.URL)
+	if err != nil {
+		return "", false
+	}
+	page, err := url.Parse(fullURL)
+	if err != nil {
+		return "", false
+	}
+	return libgobuster.ScopedPath(base, page, location)
+}
+
+// retryWithHeaders requests the url again with the additional headers and
+// reports the response tagged with the name of the rule
+func (d *GobusterDir) retryWithHeaders(ctx context.Context, fullURL, entity, rule string, headers []libgobuster.HTTPHeader, progress *libgobuster.Progress) error {
+	var timing libgobuster.RequestTiming
+	requestOptions := libgobuster.RequestOptions{
+		Timing:          &timing,
+		ModifiedHeaders: append(append([]libgobuster.HTTPHeader{}, d.options.Headers...), headers...),
+	}
+	statusCode, size, header, _, err := d.http.Request(ctx, fullURL, requestOptions)
+	if err != nil {
+		return err
+	}
+	if statusCode == 0 {
+		return nil
+	}
+
+	found, err := d.isFound(statusCode)
+	if err != nil {
+		return err
+	}
+	if found && d.options.ExcludeLengthParsed.Contains(int(size)) {
+		found = false
+	}
+	if !found && !d.globalopts.Verbose {
+		return nil
+	}
+
+	progress.ResultChan <- Result{
+		URL:        d.options.URL,
+		Path:       entity,
+		Verbose:    d.globalopts.Verbose,
+		Expanded:   d.options.Expanded,
+		NoStatus:   d.options.NoStatus,
+		HideLength: d.options.HideLength,
+		Found:      found,
+		Header:     header,
+		StatusCode: statusCode,
+		Size:       size,
+		Tags:       []string{fmt.Sprintf("rule:%s", rule)},
+		Timing:     timing,
+		ShowTiming: d.globalopts.ShowTiming,
+	}
+	return nil
+}
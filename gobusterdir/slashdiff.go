@@ -0,0 +1,67 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// TagSlashDiff is added to results which answer differently with a trailing slash
+const TagSlashDiff = "slash-diff"
+
+// checkSlashDiff requests the entity with a trailing slash and reports it if
+// the response differs from the one without the slash. Servers hiding
+// directories behind uniform errors often still answer differently for them
+// (like 404 without and 403 with the slash)
+func (d *GobusterDir) checkSlashDiff(ctx context.Context, url, entity string, statusCode int, header http.Header, progress *libgobuster.Progress) error {
+	if strings.HasSuffix(entity, "/") {
+		return nil
+	}
+
+	var timing libgobuster.RequestTiming
+	slashStatus, slashSize, slashHeader, _, err := d.http.Request(ctx, url+"/", libgobuster.RequestOptions{Timing: &timing})
+	if err != nil {
+		// ignore context canceled errors
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	if !isSlashDiff(entity, statusCode, header, slashStatus) || d.options.ExcludeLengthParsed.Contains(int(slashSize)) {
+		return nil
+	}
+
+	progress.ResultChan <- Result{
+		URL:        d.options.URL,
+		Path:       entity + "/",
+		Verbose:    d.globalopts.Verbose,
+		Expanded:   d.options.Expanded,
+		NoStatus:   d.options.NoStatus,
+		HideLength: d.options.HideLength,
+		Found:      true,
+		Header:     slashHeader,
+		StatusCode: slashStatus,
+		Size:       slashSize,
+		Tags:       []string{TagSlashDiff, fmt.Sprintf("without-slash:%d", statusCode)},
+		Timing:     timing,
+		ShowTiming: d.globalopts.ShowTiming,
+	}
+	return nil
+}
+
+// isSlashDiff checks if the status codes with and without trailing slash
+// differ meaningfully. The usual redirect to the slash version is already
+// reported as a normal result
+func isSlashDiff(entity string, statusCode int, header http.Header, slashStatus int) bool {
+	if statusCode == slashStatus {
+		return false
+	}
+	if statusCode >= 300 && statusCode < 400 && strings.HasSuffix(header.Get("Location"), entity+"/") {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package gobusterdir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestIsSlashDiff(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName    string
+		status      int
+		location    string
+		slashStatus int
+		expected    bool
+	}{
+		{"Hidden", 404, "", 403, true},
+		{"Same", 404, "", 404, false},
+		{"DirectoryRedirect", 301, "/admin/", 200, false},
+		{"OtherRedirect", 302, "/login", 403, true},
+		{"File", 200, "", 404, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			header := http.Header{}
+			if x.location != "" {
+				header.Set("Location", x.location)
+			}
+			if got := isSlashDiff("admin", x.status, header, x.slashStatus); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestSlashDiff(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hidden/" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	o := NewOptionsDir()
+	o.URL = ts.URL + "/"
+	o.Timeout = 5 * time.Second
+	o.SlashDiff = true
+	o.StatusCodesBlacklistParsed.Add(404)
+	o.StatusCodesBlacklistParsed.Add(403)
+	d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	progress := libgobuster.NewProgress()
+	done := make(chan []Result)
+	go func() {
+		var results []Result
+		for r := range progress.ResultChan {
+			results = append(results, r.(Result))
+		}
+		done <- results
+	}()
+	for _, word := range []string{"missing", "hidden"} {
+		if err := d.ProcessWord(context.Background(), word, progress); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	close(progress.ResultChan)
+
+	results := <-done
+	if len(results) != 1 || results[0].Path != "hidden/" || results[0].StatusCode != 403 || results[0].Tags[0] != TagSlashDiff {
+		t.Fatalf("expected only the hidden directory to be reported, got %+v", results)
+	}
+}
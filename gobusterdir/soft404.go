@@ -0,0 +1,94 @@
+package gobusterdir
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// soft404Probes is the number of random paths requested to fingerprint the
+// response of the server for non existing paths
+const soft404Probes = 3
+
+// fingerprint identifies the response of the server for a non existing path.
+// The requested path is removed from the body before hashing, so error pages
+// reflecting the path still match
+type fingerprint struct {
+	statusCode int
+	length     int
+	hash       [sha256.Size]byte
+}
+
+// newFingerprint returns the fingerprint of the response for the path
+func newFingerprint(path string, statusCode int, body []byte) fingerprint {
+	normalized := bytes.ReplaceAll(body, []byte(path), nil)
+	if escaped := url.PathEscape(path); escaped != path {
+		normalized = bytes.ReplaceAll(normalized, []byte(escaped), nil)
+	}
+	return fingerprint{
+		statusCode: statusCode,
+		length:     len(normalized),
+		hash:       sha256.Sum256(normalized),
+	}
+}
+
+// detectSoft404 requests random non existing paths. If the server reports
+// them as found with the same response every time, results matching this
+// response are filtered. If the responses differ the wildcard can not be
+// filtered and ErrWildcard is returned
+func (d *GobusterDir) detectSoft404(ctx context.Context, progress *libgobuster.Progress) error {
+	var first fingerprint
+	for i := 0; i < soft404Probes; i++ {
+		guid := libgobuster.RandomUUID()
+		url := fmt.Sprintf("%s%s", d.options.URL, guid)
+		if d.options.UseSlash {
+			url = fmt.Sprintf("%s/", url)
+		}
+
+		statusCode, length, _, body, err := d.http.Request(ctx, url, libgobuster.RequestOptions{ReturnBody: true})
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			if d.options.ExcludeLengthParsed.Contains(int(length)) {
+				// we are done and ignore the request as the length is excluded
+				return nil
+			}
+			found, err := d.isFound(statusCode)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return nil
+			}
+			first = newFingerprint(guid, statusCode, body)
+			continue
+		}
+
+		if newFingerprint(guid, statusCode, body) != first {
+			return &ErrWildcard{url: url, statusCode: statusCode, length: length}
+		}
+	}
+
+	d.soft404 = &first
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelInfo,
+		Message: fmt.Sprintf("The server answers non existing paths with status %d (Length: %d), matching results are filtered", first.statusCode, first.length),
+	}
+	return nil
+}
+
+// isSoft404 checks if the response matches the response for non existing paths
+func (d *GobusterDir) isSoft404(entity string, statusCode int, body []byte) bool {
+	if d.soft404 == nil || statusCode != d.soft404.statusCode {
+		return false
+	}
+	// the probes were requested without the trailing slash in the path
+	return newFingerprint(strings.TrimSuffix(entity, "/"), statusCode, body) == *d.soft404
+}
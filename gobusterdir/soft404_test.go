@@ -0,0 +1,93 @@
+package gobusterdir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/google/uuid"
+)
+
+func TestSoft404(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			fmt.Fprint(w, "admin panel")
+			return
+		}
+		// every other path is answered with 200 and reflected
+		fmt.Fprintf(w, "The page %s was not found", r.URL.Path)
+	}))
+	defer ts.Close()
+
+	o := NewOptionsDir()
+	o.URL = ts.URL + "/"
+	o.Timeout = 5 * time.Second
+	o.StatusCodesBlacklistParsed.Add(404)
+	d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	progress := libgobuster.NewProgress()
+	go func() {
+		for range progress.MessageChan {
+		}
+	}()
+	if err := d.detectSoft404(context.Background(), progress); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if d.soft404 == nil || d.soft404.statusCode != http.StatusOK {
+		t.Fatalf("expected a soft 404 fingerprint, got %+v", d.soft404)
+	}
+
+	done := make(chan []Result)
+	go func() {
+		var results []Result
+		for r := range progress.ResultChan {
+			results = append(results, r.(Result))
+		}
+		done <- results
+	}()
+	for _, word := range []string{"missing", "admin", "other"} {
+		if err := d.ProcessWord(context.Background(), word, progress); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	close(progress.ResultChan)
+
+	results := <-done
+	if len(results) != 1 || results[0].Path != "admin" {
+		t.Fatalf("expected only admin to be found, got %+v", results)
+	}
+}
+
+func TestSoft404Varying(t *testing.T) {
+	t.Parallel()
+
+	// the responses for non existing paths differ, so they can't be filtered
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, uuid.New().String())
+	}))
+	defer ts.Close()
+
+	o := NewOptionsDir()
+	o.URL = ts.URL + "/"
+	o.Timeout = 5 * time.Second
+	o.StatusCodesBlacklistParsed.Add(404)
+	d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	var wErr *ErrWildcard
+	if err := d.detectSoft404(context.Background(), libgobuster.NewProgress()); !errors.As(err, &wErr) {
+		t.Fatalf("expected a wildcard error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package gobusterdir
+
+import (
+	"path"
+	"strings"
+)
+
+// TagSecretFile is added to results which look like files containing secrets
+const TagSecretFile = "secret-file"
+
+// nolint:gochecknoglobals
+var (
+	secretFilenames = []string{
+		".env", ".htpasswd", ".netrc", ".npmrc", ".pgpass", ".bash_history",
+		"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", "credentials", "credentials.json",
+		"web.config", "wp-config.php", "database.yml", "secrets.yml", "settings.py",
+	}
+	secretPathSuffixes = []string{".git/config", ".git/head", ".aws/credentials", ".ssh/authorized_keys"}
+	secretExtensions   = []string{".sql", ".sqlite", ".db", ".key", ".pem", ".p12", ".pfx", ".kdbx", ".bak", ".old", ".swp"}
+)
+
+// getTags returns the tags for a found path
+func getTags(p string) []string {
+	var tags []string
+	if isSecretFile(p) {
+		tags = append(tags, TagSecretFile)
+	}
+	return tags
+}
+
+func isSecretFile(p string) bool {
+	lower := strings.ToLower(strings.TrimSuffix(p, "/"))
+	base := path.Base(lower)
+	for _, s := range secretFilenames {
+		if base == s {
+			return true
+		}
+	}
+	for _, s := range secretPathSuffixes {
+		if strings.HasSuffix(lower, s) {
+			return true
+		}
+	}
+	for _, s := range secretExtensions {
+		if strings.HasSuffix(base, s) {
+			return true
+		}
+	}
+	return false
+}
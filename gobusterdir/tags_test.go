@@ -0,0 +1,32 @@
+package gobusterdir
+
+import "testing"
+
+func TestIsSecretFile(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		path     string
+		expected bool
+	}{
+		{".env", true},
+		{"app/.ENV", true},
+		{".git/config", true},
+		{"backup.sql", true},
+		{"index.php.bak", true},
+		{"admin", false},
+		{"config", false},
+		{"test.php", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.path, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isSecretFile(x.path); got != x.expected {
+				t.Fatalf("isSecretFile(%q) = %t, expected %t", x.path, got, x.expected)
+			}
+		})
+	}
+}
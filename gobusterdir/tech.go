@@ -0,0 +1,169 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// TechAuto detects the technology of the target and applies the matching preset
+const TechAuto = "auto"
+
+// TechPreset holds the extensions and candidate paths typical for a
+// language or framework
+type TechPreset struct {
+	Name       string
+	Extensions []string
+	// Paths are checked before the wordlist
+	Paths []string
+	// CaseInsensitive is set for stacks which usually run on servers
+	// ignoring the case of paths
+	CaseInsensitive bool
+}
+
+// nolint:gochecknoglobals
+var techPresets = map[string]TechPreset{
+	"php": {
+		Name:       "php",
+		Extensions: []string{"php", "phtml", "php5", "inc"},
+		Paths: []string{
+			"phpinfo.php", "info.php", "config.php", "composer.json", "composer.lock",
+			".user.ini", "php.ini", "vendor/", "wp-config.php.bak", "server-status",
+		},
+	},
+	"aspnet": {
+		Name:       "aspnet",
+		Extensions: []string{"aspx", "asp", "ashx", "asmx", "axd", "config"},
+		Paths: []string{
+			"web.config", "web.config.bak", "global.asax", "trace.axd", "elmah.axd",
+			"bin/", "App_Data/", "App_Code/", "aspnet_client/", "_vti_bin/",
+		},
+		CaseInsensitive: true,
+	},
+	"java": {
+		Name:       "java",
+		Extensions: []string{"jsp", "jspx", "do", "action", "jsf"},
+		Paths: []string{
+			"WEB-INF/", "WEB-INF/web.xml", "META-INF/", "META-INF/MANIFEST.MF",
+			"manager/html", "host-manager/html", "jmx-console/", "actuator", "actuator/env", "actuator/health",
+		},
+	},
+	"node": {
+		Name:       "node",
+		Extensions: []string{"js", "json"},
+		Paths: []string{
+			"package.json", "package-lock.json", "yarn.lock", ".npmrc", ".env",
+			"node_modules/", "server.js", "app.js", "config.json", "graphql",
+		},
+	},
+}
+
+// TechPresetNames returns the names of all presets
+func TechPresetNames() []string {
+	names := make([]string, 0, len(techPresets))
+	for name := range techPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTechPreset returns the preset with the given name
+func GetTechPreset(name string) (TechPreset, error) {
+	p, ok := techPresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return TechPreset{}, fmt.Errorf("unknown tech %q, valid values are %s", name, strings.Join(TechPresetNames(), ", "))
+	}
+	return p, nil
+}
+
+// techSignature maps a response header or cookie to the technology it reveals
+type techSignature struct {
+	tech   string
+	header string
+	// contains is matched case insensitive, empty matches every value
+	contains string
+	cookie   string
+}
+
+// nolint:gochecknoglobals
+var techSignatures = []techSignature{
+	{tech: "aspnet", header: "X-AspNet-Version"},
+	{tech: "aspnet", header: "X-AspNetMvc-Version"},
+	{tech: "aspnet", header: "X-Powered-By", contains: "asp.net"},
+	{tech: "aspnet", header: "Server", contains: "microsoft-iis"},
+	{tech: "aspnet", cookie: "ASP.NET_SessionId"},
+	{tech: "aspnet", cookie: ".ASPXAUTH"},
+	{tech: "php", header: "X-Powered-By", contains: "php"},
+	{tech: "php", cookie: "PHPSESSID"},
+	{tech: "java", header: "X-Powered-By", contains: "servlet"},
+	{tech: "java", header: "X-Powered-By", contains: "jsp"},
+	{tech: "java", header: "Server", contains: "tomcat"},
+	{tech: "java", header: "Server", contains: "jetty"},
+	{tech: "java", cookie: "JSESSIONID"},
+	{tech: "node", header: "X-Powered-By", contains: "express"},
+	{tech: "node", header: "X-Powered-By", contains: "next.js"},
+	{tech: "node", cookie: "connect.sid"},
+}
+
+// DetectTech returns the preset matching the headers of a response and the
+// reason for the detection. It returns false if no technology was detected
+func DetectTech(header http.Header) (TechPreset, string, bool) {
+	cookies := (&http.Response{Header: header}).Cookies()
+	for _, s := range techSignatures {
+		if s.cookie != "" {
+			for _, c := range cookies {
+				if strings.EqualFold(c.Name, s.cookie) {
+					return techPresets[s.tech], fmt.Sprintf("%s cookie", c.Name), true
+				}
+			}
+			continue
+		}
+		value := header.Get(s.header)
+		if value == "" || !strings.Contains(strings.ToLower(value), s.contains) {
+			continue
+		}
+		return techPresets[s.tech], fmt.Sprintf("%s: %s", s.header, value), true
+	}
+	return TechPreset{}, "", false
+}
+
+// DetectTech requests the target url and applies the preset of the detected
+// technology. Extensions given by the user are not extended
+func (d *GobusterDir) DetectTech(ctx context.Context) error {
+	url := d.options.URL
+	if !strings.HasSuffix(url, "/") {
+		url = fmt.Sprintf("%s/", url)
+	}
+
+	_, _, header, _, err := d.http.Request(ctx, url, libgobuster.RequestOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", url, err)
+	}
+
+	preset, reason, ok := DetectTech(header)
+	if !ok {
+		d.options.TechDetection = "nothing detected"
+		return nil
+	}
+	if d.options.Extensions != "" || d.options.ExtensionsFile != "" {
+		preset.Extensions = nil
+	}
+	d.options.ApplyTechPreset(preset)
+	d.options.TechDetection = fmt.Sprintf("detected by %s", reason)
+	return nil
+}
+
+// ApplyTechPreset adds the extensions and paths of the preset to the options
+func (opt *OptionsDir) ApplyTechPreset(p TechPreset) {
+	opt.Tech = p.Name
+	opt.ExtensionsParsed.AddRange(p.Extensions)
+	opt.TechPaths = append(opt.TechPaths, p.Paths...)
+	if p.CaseInsensitive {
+		opt.CaseInsensitive = true
+	}
+}
@@ -0,0 +1,124 @@
+package gobusterdir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestGetTechPreset(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name            string
+		extension       string
+		path            string
+		caseInsensitive bool
+		wantErr         bool
+	}{
+		{"php", "php", "phpinfo.php", false, false},
+		{"ASPNET", "aspx", "web.config", true, false},
+		{"java", "jsp", "WEB-INF/", false, false},
+		{"node", "js", "package.json", false, false},
+		{"ruby", "", "", false, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.name, func(t *testing.T) {
+			t.Parallel()
+
+			preset, err := GetTechPreset(x.name)
+			if x.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			o := NewOptionsDir()
+			o.ApplyTechPreset(preset)
+			if !o.ExtensionsParsed.Contains(x.extension) {
+				t.Fatalf("expected extension %q in %v", x.extension, o.ExtensionsParsed.Stringify())
+			}
+			found := false
+			for _, p := range o.TechPaths {
+				found = found || p == x.path
+			}
+			if !found {
+				t.Fatalf("expected path %q in %v", x.path, o.TechPaths)
+			}
+			if o.CaseInsensitive != x.caseInsensitive {
+				t.Fatalf("expected case insensitive %t", x.caseInsensitive)
+			}
+		})
+	}
+}
+
+func TestDetectTech(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		header   http.Header
+		expected string
+	}{
+		{"AspNetHeader", http.Header{"X-Aspnet-Version": {"4.0.30319"}}, "aspnet"},
+		{"IIS", http.Header{"Server": {"Microsoft-IIS/10.0"}}, "aspnet"},
+		{"PHPPoweredBy", http.Header{"X-Powered-By": {"PHP/8.1.2"}}, "php"},
+		{"JavaCookie", http.Header{"Set-Cookie": {"JSESSIONID=abc; Path=/"}}, "java"},
+		{"Express", http.Header{"X-Powered-By": {"Express"}}, "node"},
+		{"Nothing", http.Header{"Server": {"nginx"}}, ""},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			preset, reason, ok := DetectTech(x.header)
+			if ok != (x.expected != "") {
+				t.Fatalf("expected detection %t, got %t (%s)", x.expected != "", ok, reason)
+			}
+			if preset.Name != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, preset.Name)
+			}
+		})
+	}
+}
+
+func TestDetectTechRequest(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Powered-By", "PHP/8.1.2")
+	}))
+	defer ts.Close()
+
+	o := NewOptionsDir()
+	o.URL = ts.URL
+	o.Timeout = 5 * time.Second
+	o.Extensions = "txt"
+	o.ExtensionsParsed.Add("txt")
+	d, err := NewGobusterDir(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := d.DetectTech(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if o.Tech != "php" || len(o.TechPaths) == 0 {
+		t.Fatalf("expected the php preset to be applied, got %q", o.Tech)
+	}
+	// extensions given by the user override the preset
+	if o.ExtensionsParsed.Contains("php") {
+		t.Fatalf("expected the extensions to be kept, got %s", o.ExtensionsParsed.Stringify())
+	}
+}
@@ -0,0 +1,77 @@
+package gobusterdir
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// waybackSeedLimit is the maximum number of archived urls fetched for seeding
+const waybackSeedLimit = 10000
+
+// seedFromWayback fetches the archived urls of the target and queues their
+// paths so they are checked before the wordlist
+func (d *GobusterDir) seedFromWayback(ctx context.Context, progress *libgobuster.Progress) error {
+	target, err := url.Parse(d.options.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", d.options.URL, err)
+	}
+
+	// use a separate client so cookies, headers and credentials of the
+	// target are not sent to the wayback machine
+	client, err := libgobuster.NewHTTPClient(&libgobuster.HTTPOptions{
+		BasicHTTPOptions: libgobuster.BasicHTTPOptions{
+			Proxy:         d.options.Proxy,
+			Proxies:       d.options.Proxies,
+			ProxyRotation: d.options.ProxyRotation,
+			Timeout:       d.options.Timeout,
+			UserAgent:     d.options.UserAgent,
+		},
+		FollowRedirect: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	urls, err := libgobuster.FetchWaybackURLs(ctx, client, libgobuster.WaybackCDXURL, target.Host+target.Path, false, waybackSeedLimit)
+	if err != nil {
+		return err
+	}
+
+	paths := waybackPaths(urls, target)
+	progress.QueueWords(paths...)
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelInfo,
+		Message: fmt.Sprintf("Seeded %d paths from the wayback machine", len(paths)),
+	}
+	return nil
+}
+
+// waybackPaths extracts the unique paths below the target from archived urls.
+// The paths are returned relative to the target url
+func waybackPaths(urls []string, target *url.URL) []string {
+	base := strings.TrimSuffix(target.EscapedPath(), "/") + "/"
+	seen := libgobuster.NewSet[string]()
+	var ret []string
+	for _, x := range urls {
+		u, err := url.Parse(x)
+		if err != nil || !strings.EqualFold(u.Hostname(), target.Hostname()) {
+			continue
+		}
+		p := u.EscapedPath()
+		if !strings.HasPrefix(p, base) {
+			continue
+		}
+		p = strings.TrimPrefix(p, base)
+		if p == "" {
+			continue
+		}
+		if seen.Add(p) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
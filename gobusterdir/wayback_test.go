@@ -0,0 +1,31 @@
+package gobusterdir
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestWaybackPaths(t *testing.T) {
+	t.Parallel()
+
+	urls := []string{
+		"http://example.com/",
+		"http://example.com/app/admin",
+		"https://example.com:443/app/admin?x=1",
+		"http://example.com/app/api/v1/users",
+		"http://example.com/other",
+		"http://www.example.com/app/login",
+		"http://example.com/app/file%20name.txt",
+	}
+
+	target, err := url.Parse("http://example.com/app/")
+	if err != nil {
+		t.Fatalf("could not parse url: %v", err)
+	}
+
+	expected := []string{"admin", "api/v1/users", "file%20name.txt"}
+	if got := waybackPaths(urls, target); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,100 @@
+package gobusterdns
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// levelSeparator separates the parent name from the word in the words of a
+// deeper level. It can not be part of a host name
+const levelSeparator = "/"
+
+// level is a name below the domain whose labels are brute forced
+type level struct {
+	// depth is the number of found names above the level, 0 for the domain
+	depth int
+	// wildcardIps are the addresses of the wildcard record of the level,
+	// shared by all words brute forced below it
+	wildcardIps libgobuster.Set[netip.Addr]
+}
+
+// levels keeps track of the found names which get another pass over the
+// wordlist
+type levels struct {
+	mutex    sync.Mutex
+	maxDepth int
+	// names maps the queued names, relative to the domain, to their level
+	names map[string]*level
+}
+
+func newLevels(maxDepth int) *levels {
+	return &levels{
+		maxDepth: maxDepth,
+		names:    make(map[string]*level),
+	}
+}
+
+// split returns the queued name and level the word is brute forced below
+// and the remaining word of the wordlist. Words of the domain return an
+// empty name and root
+func (l *levels) split(word string, root *level) (string, *level, string) {
+	i := strings.Index(word, levelSeparator)
+	if i < 0 {
+		return "", root, word
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	lvl, ok := l.names[word[:i]]
+	if !ok || lvl == nil {
+		return "", root, word
+	}
+	return word[:i], lvl, word[i+len(levelSeparator):]
+}
+
+// reserve marks the name as queued if it is within the depth limit and was
+// not queued before
+func (l *levels) reserve(name string, depth int) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if depth > l.maxDepth {
+		return false
+	}
+	if _, ok := l.names[name]; ok {
+		return false
+	}
+	l.names[name] = nil
+	return true
+}
+
+// set stores the calibrated level of a reserved name
+func (l *levels) set(name string, lvl *level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.names[name] = lvl
+}
+
+// addLevel queues another pass over the wordlist below the found name. The
+// wildcard record of the name is detected once before, so every word of the
+// pass is compared with the same addresses
+func (d *GobusterDNS) addLevel(ctx context.Context, name string, depth int, progress *libgobuster.Progress) error {
+	if !d.levels.reserve(name, depth) {
+		return nil
+	}
+	wildcardIps, err := d.detectWildcard(ctx, name+"."+d.domain, progress)
+	if err != nil {
+		// ignore context canceled errors
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	d.levels.set(name, &level{depth: depth, wildcardIps: wildcardIps})
+	progress.QueueRecursion(name + levelSeparator)
+	return nil
+}
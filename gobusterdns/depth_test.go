@@ -0,0 +1,151 @@
+package gobusterdns
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// levelResolver answers the names of records and every name below the
+// names of wildcards
+type levelResolver struct {
+	records   map[string]netip.Addr
+	wildcards map[string]netip.Addr
+}
+
+func (r *levelResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	host = strings.TrimSuffix(host, ".")
+	if ip, ok := r.records[host]; ok {
+		return []netip.Addr{ip}, nil
+	}
+	for name, ip := range r.wildcards {
+		if strings.HasSuffix(host, "."+name) {
+			return []netip.Addr{ip}, nil
+		}
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r *levelResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	return host, nil
+}
+
+func TestLevelsSplit(t *testing.T) {
+	t.Parallel()
+
+	l := newLevels(2)
+	root := &level{}
+	dev := &level{depth: 1}
+	if !l.reserve("dev", 1) {
+		t.Fatal("expected dev to be reserved")
+	}
+	if l.reserve("dev", 1) {
+		t.Fatal("expected dev to be reserved only once")
+	}
+	if l.reserve("api.dev", 3) {
+		t.Fatal("expected a name below the maximum depth to be skipped")
+	}
+	// reserved names are not split before their level is calibrated
+	if name, _, word := l.split("dev/www", root); name != "" || word != "dev/www" {
+		t.Fatalf("unexpected split %q %q", name, word)
+	}
+	l.set("dev", dev)
+
+	tt := []struct {
+		testName string
+		word     string
+		name     string
+		level    *level
+		rest     string
+	}{
+		{"Domain", "www", "", root, "www"},
+		{"Level", "dev/www", "dev", dev, "www"},
+		{"Unknown level", "test/www", "", root, "test/www"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			name, lvl, rest := l.split(x.word, root)
+			if name != x.name || lvl != x.level || rest != x.rest {
+				t.Fatalf("expected %q %q, got %q %q", x.name, x.rest, name, rest)
+			}
+		})
+	}
+}
+
+func TestDepth(t *testing.T) {
+	t.Parallel()
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte("dev\napi\nwww\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		depth    int
+		expected []string
+	}{
+		{"Disabled", 0, []string{"dev.example.com", "www.example.com"}},
+		// the names below dev are compared with its own wildcard record
+		{"One level", 1, []string{"api.dev.example.com", "dev.example.com", "www.example.com"}},
+		{"Two levels", 2, []string{"api.api.dev.example.com", "api.dev.example.com", "dev.example.com", "www.example.com"}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			globalopts := libgobuster.NewOptions()
+			globalopts.Threads = 2
+			globalopts.Wordlist = wordlist
+			globalopts.Quiet = true
+			globalopts.CollectResults = true
+
+			o := NewOptionsDNS()
+			o.Domain = "example.com"
+			o.Depth = x.depth
+			d, err := NewGobusterDNS(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			d.resolver = &levelResolver{
+				records: map[string]netip.Addr{
+					"dev.example.com":         netip.MustParseAddr("198.51.100.1"),
+					"www.example.com":         netip.MustParseAddr("198.51.100.2"),
+					"api.dev.example.com":     netip.MustParseAddr("198.51.100.3"),
+					"api.api.dev.example.com": netip.MustParseAddr("198.51.100.4"),
+				},
+				wildcards: map[string]netip.Addr{"dev.example.com": netip.MustParseAddr("192.0.2.1")},
+			}
+
+			g, err := libgobuster.NewGobuster(globalopts, d, libgobuster.NewLogger(false))
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := g.Run(context.Background()); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			var got []string
+			for _, r := range g.CollectedResults() {
+				got = append(got, libgobuster.NewResultRecord(r).Path)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package gobusterdns
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// newCustomDialer returns a dial function for the go resolver. If server is
+// empty the nameserver chosen by the resolver is used
+func newCustomDialer(server string, opts *OptionsDNS) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		d := net.Dialer{}
+		if server != "" {
+			address = server
+			if !strings.Contains(address, ":") {
+				port := 53
+				if opts.Protocol == "tls" {
+					port = 853
+				}
+				address = fmt.Sprintf("%s:%d", address, port)
+			}
+		}
+
+		if opts.Protocol == "tls" {
+			// the resolver uses the framing of tcp for all connections
+			// which are no PacketConn
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			td := tls.Dialer{NetDialer: &d, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+			conn, err := td.DialContext(ctx, "tcp", address)
+			if err != nil {
+				return nil, err
+			}
+			if opts.EDNS0Size > 0 {
+				return &edns0StreamConn{Conn: conn, size: opts.EDNS0Size}, nil
+			}
+			return conn, nil
+		}
+
+		if opts.Protocol == "tcp" {
+			network = "tcp"
+		} else if strings.HasPrefix(network, "tcp") {
+			// the go resolver retries over tcp if the answer was truncated
+			if opts.NoTCPFallback {
+				return nil, fmt.Errorf("DNS answer was truncated and TCP fallback is disabled")
+			}
+		} else {
+			network = "udp"
+		}
+
+		conn, err := d.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.EDNS0Size > 0 {
+			// the resolver checks for a PacketConn to decide on the framing
+			// so keep the concrete type
+			if udp, ok := conn.(*net.UDPConn); ok {
+				return &edns0PacketConn{UDPConn: udp, size: opts.EDNS0Size}, nil
+			}
+			return &edns0StreamConn{Conn: conn, size: opts.EDNS0Size}, nil
+		}
+		return conn, nil
+	}
+}
+
+// edns0PacketConn rewrites the EDNS0 buffer size of all udp queries
+type edns0PacketConn struct {
+	*net.UDPConn
+	size uint16
+}
+
+func (c *edns0PacketConn) Write(b []byte) (int, error) {
+	msg, err := setEDNS0Size(b, c.size)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := c.UDPConn.Write(msg); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// edns0StreamConn rewrites the EDNS0 buffer size of all tcp queries. Queries
+// are prefixed with the two byte length of the message
+type edns0StreamConn struct {
+	net.Conn
+	size uint16
+}
+
+func (c *edns0StreamConn) Write(b []byte) (int, error) {
+	if len(b) < 2 {
+		return c.Conn.Write(b)
+	}
+	msg, err := setEDNS0Size(b[2:], c.size)
+	if err != nil {
+		return 0, err
+	}
+	out := make([]byte, 2, len(msg)+2)
+	out[0] = byte(len(msg) >> 8)
+	out[1] = byte(len(msg))
+	if _, err := c.Conn.Write(append(out, msg...)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// setEDNS0Size sets the UDP payload size of the OPT record in the query and
+// adds an OPT record if none is present
+func setEDNS0Size(b []byte, size uint16) ([]byte, error) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(b); err != nil {
+		return nil, fmt.Errorf("could not parse DNS query: %w", err)
+	}
+
+	found := false
+	for i := range msg.Additionals {
+		if msg.Additionals[i].Header.Type == dnsmessage.TypeOPT {
+			// the class of the OPT record holds the payload size
+			msg.Additionals[i].Header.Class = dnsmessage.Class(size)
+			found = true
+		}
+	}
+
+	if !found {
+		var rh dnsmessage.ResourceHeader
+		if err := rh.SetEDNS0(int(size), dnsmessage.RCodeSuccess, false); err != nil {
+			return nil, fmt.Errorf("could not create EDNS0 record: %w", err)
+		}
+		msg.Additionals = append(msg.Additionals, dnsmessage.Resource{Header: rh, Body: &dnsmessage.OPTResource{}})
+	}
+
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("could not pack DNS query: %w", err)
+	}
+	return packed, nil
+}
@@ -0,0 +1,70 @@
+package gobusterdns
+
+import (
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func buildQuery(t *testing.T, edns bool) []byte {
+	t.Helper()
+
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 1, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName("www.example.com."),
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	if edns {
+		var rh dnsmessage.ResourceHeader
+		if err := rh.SetEDNS0(1232, dnsmessage.RCodeSuccess, false); err != nil {
+			t.Fatalf("could not set EDNS0: %v", err)
+		}
+		msg.Additionals = append(msg.Additionals, dnsmessage.Resource{Header: rh, Body: &dnsmessage.OPTResource{}})
+	}
+	b, err := msg.Pack()
+	if err != nil {
+		t.Fatalf("could not pack query: %v", err)
+	}
+	return b
+}
+
+func TestSetEDNS0Size(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		edns     bool
+	}{
+		{"Existing OPT record", true},
+		{"Missing OPT record", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := setEDNS0Size(buildQuery(t, x.edns), 4096)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+
+			var msg dnsmessage.Message
+			if err := msg.Unpack(b); err != nil {
+				t.Fatalf("could not unpack query: %v", err)
+			}
+			if len(msg.Additionals) != 1 {
+				t.Fatalf("expected one additional record, got %d", len(msg.Additionals))
+			}
+			if msg.Additionals[0].Header.Class != 4096 {
+				t.Fatalf("expected a buffer size of 4096, got %d", msg.Additionals[0].Header.Class)
+			}
+			if len(msg.Questions) != 1 || msg.Questions[0].Name.String() != "www.example.com." {
+				t.Fatalf("question was modified: %v", msg.Questions)
+			}
+		})
+	}
+}
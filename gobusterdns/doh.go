@@ -0,0 +1,147 @@
+package gobusterdns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dohContentType is the media type of DNS messages sent over https
+const dohContentType = "application/dns-message"
+
+// dohResolver sends the queries as DNS over HTTPS (RFC 8484) POST requests,
+// so it works from networks blocking or tampering with plain DNS. The
+// requests share the connections of the http client
+type dohResolver struct {
+	url      string
+	client   *http.Client
+	attempts int
+	ednsSize uint16
+}
+
+// dohURL returns the url of the DNS over HTTPS server, servers without a
+// scheme use the common /dns-query path
+func dohURL(server string) string {
+	if strings.Contains(server, "://") {
+		return server
+	}
+	return fmt.Sprintf("https://%s/dns-query", server)
+}
+
+// newDoHResolver returns a resolver sending the queries to server, either a
+// url or a host name
+func newDoHResolver(server string, opts *OptionsDNS) *dohResolver {
+	r := dohResolver{
+		url: dohURL(server),
+		client: &http.Client{
+			Timeout: opts.Timeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 100,
+			},
+		},
+		attempts: opts.Attempts,
+		ednsSize: opts.EDNS0Size,
+	}
+	if r.ednsSize == 0 {
+		r.ednsSize = defaultEDNS0Size
+	}
+	return &r
+}
+
+// LookupNetIP returns the A and AAAA records of host. network is ignored,
+// both record types are always queried
+func (r *dohResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	return lookupNetIP(ctx, r, host)
+}
+
+// LookupCNAME follows the CNAME records of host and returns the canonical
+// name. It returns host if there is no CNAME record
+func (r *dohResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	return lookupCNAME(ctx, r, host)
+}
+
+// exchange sends the query for host and returns the answer. The query is
+// sent again if the request failed or the server failed
+func (r *dohResolver) exchange(ctx context.Context, host string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	question, err := newQuestion(host, qtype)
+	if err != nil {
+		return nil, err
+	}
+	// the id is 0 so the answers can be cached by http caches
+	query, err := packQuery(0, question, r.ednsSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < r.attempts; attempt++ {
+		msg, err := r.post(ctx, query, question)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		switch msg.RCode {
+		case dnsmessage.RCodeSuccess:
+			return msg, nil
+		case dnsmessage.RCodeNameError:
+			return nil, r.notFound(host)
+		case dnsmessage.RCodeServerFailure:
+			lastErr = &net.DNSError{Err: "server misbehaving", Name: host, Server: r.url, IsTemporary: true}
+			continue
+		default:
+			return nil, &net.DNSError{Err: fmt.Sprintf("unexpected answer %s", msg.RCode), Name: host, Server: r.url}
+		}
+	}
+	return nil, lastErr
+}
+
+// post sends the query once and returns the answer
+func (r *dohResolver) post(ctx context.Context, query []byte, question dnsmessage.Question) (*dnsmessage.Message, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dohContentType)
+	req.Header.Set("Accept", dohContentType)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDNSMessageSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &net.DNSError{Err: fmt.Sprintf("unexpected status %s", resp.Status), Name: question.Name.String(), Server: r.url, IsTemporary: resp.StatusCode >= 500}
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(b); err != nil {
+		return nil, fmt.Errorf("could not parse DNS answer: %w", err)
+	}
+	if !isAnswer(&msg, question) {
+		return nil, fmt.Errorf("DNS answer does not match the query")
+	}
+	return &msg, nil
+}
+
+// notFound returns the error of the go resolver for names without records
+func (r *dohResolver) notFound(host string) error {
+	return &net.DNSError{Err: "no such host", Name: host, Server: r.url, IsNotFound: true}
+}
@@ -0,0 +1,163 @@
+package gobusterdns
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dohServer answers DNS over HTTPS queries. The first query for
+// flaky.example.com. is answered with SERVFAIL
+func dohServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var mutex sync.Mutex
+	seen := make(map[string]bool)
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/dns-query" || r.Header.Get("Content-Type") != dohContentType {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		var query dnsmessage.Message
+		if err := query.Unpack(b); err != nil || len(query.Questions) != 1 {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+		q := query.Questions[0]
+		name := q.Name.String()
+
+		mutex.Lock()
+		first := !seen[name+q.Type.String()]
+		seen[name+q.Type.String()] = true
+		mutex.Unlock()
+
+		answer := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+			Questions: query.Questions,
+		}
+		switch {
+		case name == "www.example.com." && q.Type == dnsmessage.TypeA:
+			answer.Answers = append(answer.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+			})
+		case name == "www.example.com.":
+		case name == "alias.example.com.":
+			target := dnsmessage.MustNewName("www.example.com.")
+			answer.Answers = append(answer.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.CNAMEResource{CNAME: target},
+			})
+		case name == "flaky.example.com." && first:
+			answer.RCode = dnsmessage.RCodeServerFailure
+		case name == "flaky.example.com." && q.Type == dnsmessage.TypeA:
+			answer.Answers = append(answer.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 3}},
+			})
+		case name == "flaky.example.com.":
+		default:
+			answer.RCode = dnsmessage.RCodeNameError
+		}
+		packed, err := answer.Pack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", dohContentType)
+		_, _ = w.Write(packed)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func newTestDoHResolver(t *testing.T) *dohResolver {
+	t.Helper()
+
+	ts := dohServer(t)
+	opts := NewOptionsDNS()
+	opts.Timeout = 3 * time.Second
+	r := newDoHResolver(ts.Listener.Addr().String(), opts)
+	// trust the certificate of the test server
+	r.client = ts.Client()
+	return r
+}
+
+func TestDoHResolver(t *testing.T) {
+	t.Parallel()
+	r := newTestDoHResolver(t)
+
+	tt := []struct {
+		host     string
+		expected string
+		notFound bool
+	}{
+		{"www.example.com", "192.0.2.1", false},
+		{"flaky.example.com.", "192.0.2.3", false},
+		{"missing.example.com.", "", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.host, func(t *testing.T) {
+			t.Parallel()
+			ips, err := r.LookupNetIP(context.Background(), "ip", x.host)
+			if x.notFound {
+				var dnsErr *net.DNSError
+				if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+					t.Fatalf("expected a not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if len(ips) != 1 || ips[0].String() != x.expected {
+				t.Fatalf("expected %s, got %v", x.expected, ips)
+			}
+		})
+	}
+}
+
+func TestDoHResolverLookupCNAME(t *testing.T) {
+	t.Parallel()
+	r := newTestDoHResolver(t)
+
+	cname, err := r.LookupCNAME(context.Background(), "alias.example.com")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if cname != "www.example.com." {
+		t.Fatalf("expected www.example.com., got %s", cname)
+	}
+}
+
+func TestDoHURL(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		server   string
+		expected string
+	}{
+		{"dns.google", "https://dns.google/dns-query"},
+		{"1.1.1.1:443", "https://1.1.1.1:443/dns-query"},
+		{"https://cloudflare-dns.com/dns-query", "https://cloudflare-dns.com/dns-query"},
+	}
+	for _, x := range tt {
+		if u := dohURL(x.server); u != x.expected {
+			t.Fatalf("expected %s for %s, got %s", x.expected, x.server, u)
+		}
+	}
+}
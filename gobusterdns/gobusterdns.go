@@ -12,36 +12,33 @@ import (
 	"time"
 
 	"github.com/OJ/gobuster/v3/libgobuster"
-	"github.com/google/uuid"
 )
 
-// ErrWildcard is returned if a wildcard response is found
-type ErrWildcard struct {
-	wildcardIps libgobuster.Set[netip.Addr]
-}
-
-// Error is the implementation of the error interface
-func (e *ErrWildcard) Error() string {
-	return fmt.Sprintf("the DNS Server returned the same IP for every domain. IP address(es) returned: %s", e.wildcardIps.Stringify())
-}
+// certificateTimeout is the timeout to fetch the certificate of the domain
+const certificateTimeout = 5 * time.Second
 
 // GobusterDNS is the main type to implement the interface
 type GobusterDNS struct {
-	resolver    *net.Resolver
-	globalopts  *libgobuster.Options
-	options     *OptionsDNS
-	isWildcard  bool
+	resolver   resolver
+	globalopts *libgobuster.Options
+	options    *OptionsDNS
+	// wildcardIps are the addresses of the wildcard record of the zone,
+	// empty if there is none
 	wildcardIps libgobuster.Set[netip.Addr]
-}
-
-func newCustomDialer(server string) func(ctx context.Context, network, address string) (net.Conn, error) {
-	return func(ctx context.Context, network, address string) (net.Conn, error) {
-		d := net.Dialer{}
-		if !strings.Contains(server, ":") {
-			server = fmt.Sprintf("%s:53", server)
-		}
-		return d.DialContext(ctx, "udp", server)
-	}
+	// domain is the punycode encoded (ACE) form of the domain
+	domain string
+	// http is only set if found names are probed over http
+	http *libgobuster.HTTPClient
+	// probePool bounds the number of concurrent http probes
+	probePool chan struct{}
+	// portPool bounds the number of concurrent port connections
+	portPool chan struct{}
+	// levels is only set if found names are brute forced too
+	levels *levels
+	// walked holds the names of the NSEC chain relative to the domain,
+	// walkComplete is set if the chain returned to the apex
+	walked       map[string]struct{}
+	walkComplete bool
 }
 
 // NewGobusterDNS creates a new initialized GobusterDNS
@@ -54,12 +51,60 @@ func NewGobusterDNS(globalopts *libgobuster.Options, opts *OptionsDNS) (*Gobuste
 		return nil, fmt.Errorf("please provide valid plugin options")
 	}
 
-	resolver := net.DefaultResolver
-	if opts.Resolver != "" {
-		resolver = &net.Resolver{
-			PreferGo: true,
-			Dial:     newCustomDialer(opts.Resolver),
+	servers := opts.Servers()
+	if len(servers) == 0 && opts.Encrypted() {
+		return nil, fmt.Errorf("the %s protocol needs a resolver", opts.Protocol)
+	}
+	if len(servers) == 0 && opts.UDPResolver() {
+		if server := systemNameserver(); server != "" {
+			servers = []string{server}
+		}
+	}
+	var resolvers []resolver
+	switch {
+	case opts.Protocol == "https":
+		if opts.Attempts <= 0 {
+			return nil, fmt.Errorf("attempts must be greater than 0")
+		}
+		for _, server := range servers {
+			resolvers = append(resolvers, newDoHResolver(server, opts))
+		}
+	case opts.UDPResolver() && len(servers) > 0:
+		if opts.Sockets <= 0 {
+			return nil, fmt.Errorf("sockets must be greater than 0")
+		}
+		if opts.Attempts <= 0 {
+			return nil, fmt.Errorf("attempts must be greater than 0")
+		}
+		for _, server := range servers {
+			resolvers = append(resolvers, newUDPResolver(server, opts))
 		}
+	case len(servers) > 0:
+		for _, server := range servers {
+			resolvers = append(resolvers, &net.Resolver{
+				PreferGo: true,
+				Dial:     newCustomDialer(server, opts),
+			})
+		}
+	case opts.CustomTransport():
+		resolvers = append(resolvers, &net.Resolver{
+			PreferGo: true,
+			Dial:     newCustomDialer("", opts),
+		})
+	}
+
+	var resolver resolver = net.DefaultResolver
+	switch len(resolvers) {
+	case 0:
+	case 1:
+		resolver = resolvers[0]
+	default:
+		resolver = newRotatingResolver(resolvers, opts.ResolverSelection)
+	}
+
+	domain, err := libgobuster.HostToASCII(opts.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid domain %q: %w", opts.Domain, err)
 	}
 
 	g := GobusterDNS{
@@ -67,6 +112,43 @@ func NewGobusterDNS(globalopts *libgobuster.Options, opts *OptionsDNS) (*Gobuste
 		globalopts:  globalopts,
 		wildcardIps: libgobuster.NewSet[netip.Addr](),
 		resolver:    resolver,
+		domain:      domain,
+		walked:      make(map[string]struct{}),
+	}
+
+	if opts.ProbeHTTP {
+		if opts.ProbeThreads <= 0 {
+			return nil, fmt.Errorf("probe threads must be greater than 0")
+		}
+		httpOpts := libgobuster.HTTPOptions{
+			BasicHTTPOptions: libgobuster.BasicHTTPOptions{
+				Timeout: opts.ProbeTimeout,
+				// certificates of subdomains often do not match, the probe
+				// is only interested in the response
+				NoTLSValidation: true,
+			},
+			Throttle:  globalopts.Throttle,
+			ConnStats: globalopts.ConnStats,
+		}
+		g.http, err = libgobuster.NewHTTPClient(&httpOpts)
+		if err != nil {
+			return nil, err
+		}
+		g.probePool = make(chan struct{}, opts.ProbeThreads)
+	}
+
+	if opts.TopPorts > 0 {
+		if opts.TopPorts > MaxTopPorts() {
+			return nil, fmt.Errorf("top ports must not be greater than %d", MaxTopPorts())
+		}
+		if opts.PortThreads <= 0 {
+			return nil, fmt.Errorf("port threads must be greater than 0")
+		}
+		g.portPool = make(chan struct{}, opts.PortThreads)
+	}
+
+	if opts.Depth > 0 {
+		g.levels = newLevels(opts.Depth)
 	}
 	return &g, nil
 }
@@ -76,22 +158,44 @@ func (d *GobusterDNS) Name() string {
 	return "DNS enumeration"
 }
 
+// Target returns the target of the run
+func (d *GobusterDNS) Target() string {
+	return d.options.Domain
+}
+
 // PreRun is the pre run implementation of gobusterdns
 func (d *GobusterDNS) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
-	// Resolve a subdomain that probably shouldn't exist
-	guid := uuid.New()
-	wildcardIps, err := d.dnsLookup(ctx, fmt.Sprintf("%s.%s", guid, d.options.Domain))
-	if err == nil {
-		d.isWildcard = true
-		d.wildcardIps.AddRange(wildcardIps)
-		if !d.options.WildcardForced {
-			return &ErrWildcard{wildcardIps: d.wildcardIps}
+	if r, ok := d.resolver.(startResolver); ok {
+		if err := r.start(ctx); err != nil {
+			return err
+		}
+	}
+
+	wildcardIps, err := d.detectWildcard(ctx, d.domain, progress)
+	if err != nil {
+		return err
+	}
+	d.wildcardIps = wildcardIps
+
+	if d.options.Walk {
+		if err := d.walkZone(ctx, progress); err != nil {
+			return err
+		}
+	}
+
+	if d.options.CertInfo {
+		if err := d.seedFromCertificate(ctx, progress); err != nil {
+			// not fatal, the wordlist is still processed
+			progress.MessageChan <- libgobuster.Message{
+				Level:   libgobuster.LevelError,
+				Message: fmt.Sprintf("could not fetch the certificate: %v", err),
+			}
 		}
 	}
 
 	if !d.globalopts.Quiet {
 		// Provide a warning if the base domain doesn't resolve (in case of typo)
-		_, err = d.dnsLookup(ctx, d.options.Domain)
+		_, err := d.dnsLookup(ctx, d.domain)
 		if err != nil {
 			// Not an error, just a warning. Eg. `yp.to` doesn't resolve, but `cr.yp.to` does!
 			progress.MessageChan <- libgobuster.Message{
@@ -108,22 +212,79 @@ func (d *GobusterDNS) PreRun(ctx context.Context, progress *libgobuster.Progress
 	return nil
 }
 
+// seedFromCertificate reports the certificate served for the domain on port
+// 443 and queues the names of its SANs below the domain
+func (d *GobusterDNS) seedFromCertificate(ctx context.Context, progress *libgobuster.Progress) error {
+	address := net.JoinHostPort(strings.TrimSuffix(d.domain, "."), "443")
+	info, err := libgobuster.FetchCertificate(ctx, address, certificateTimeout)
+	if err != nil {
+		return err
+	}
+	libgobuster.ReportCertificate(progress, address, info)
+
+	names := info.NamesBelow(d.domain)
+	progress.QueueWords(names...)
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelInfo,
+		Message: fmt.Sprintf("Queued %d subdomains from the certificate", len(names)),
+	}
+	return nil
+}
+
 // ProcessWord is the process implementation of gobusterdns
 func (d *GobusterDNS) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
-	subdomain := fmt.Sprintf("%s.%s", word, d.options.Domain)
+	// words of a deeper level are brute forced below their parent name
+	parent := ""
+	level := &level{wildcardIps: d.wildcardIps}
+	if d.levels != nil {
+		parent, level, word = d.levels.split(word, level)
+	}
+	walked := false
+	if parent == "" {
+		_, walked = d.walked[word]
+	}
+	// internationalized names need to be punycode encoded for the query,
+	// ASCII names like _dmarc or walked names are queried as they are
+	ace, err := libgobuster.HostToASCII(word)
+	if err != nil {
+		return fmt.Errorf("invalid internationalized domain name %q: %w", word, err)
+	}
+	if parent != "" {
+		ace = fmt.Sprintf("%s.%s", ace, parent)
+	}
+	subdomain := fmt.Sprintf("%s.%s", ace, d.domain)
 	if !d.options.NoFQDN && !strings.HasSuffix(subdomain, ".") {
 		// add a . to indicate this is the full domain and we do not want to traverse the search domains on the system
 		subdomain = fmt.Sprintf("%s.", subdomain)
 	}
+	// the queries of all subdomains share the budget of the domain
+	if err := d.globalopts.Throttle.Wait(ctx, d.domain); err != nil {
+		// ignore context canceled errors
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
 	ips, err := d.dnsLookup(ctx, subdomain)
 	if err == nil {
-		if !d.isWildcard || !d.wildcardIps.ContainsAny(ips) {
+		// walked names exist, even if they share an address with the
+		// wildcard record
+		wildcard := !walked && level.wildcardIps.ContainsAny(ips)
+		if !wildcard && d.levels != nil {
+			if err := d.addLevel(ctx, ace, level.depth+1, progress); err != nil {
+				return err
+			}
+		}
+		if !wildcard || d.options.ShowWildcard {
 			result := Result{
 				Subdomain: subdomain,
 				Found:     true,
 				ShowIPs:   d.options.ShowIPs,
 				ShowCNAME: d.options.ShowCNAME,
 				NoFQDN:    d.options.NoFQDN,
+				Unicode:   unicodeName(subdomain),
+				Wildcard:  wildcard,
+				Walked:    walked,
 			}
 			if d.options.ShowIPs {
 				result.IPs = ips
@@ -133,19 +294,51 @@ func (d *GobusterDNS) ProcessWord(ctx context.Context, word string, progress *li
 					result.CNAME = cname
 				}
 			}
+			if d.options.ProbeHTTP {
+				result.Web = d.probeWeb(ctx, strings.TrimSuffix(subdomain, "."))
+			}
+			if d.options.TopPorts > 0 {
+				result.ShowPorts = true
+				result.OpenPorts = d.scanPorts(ctx, strings.TrimSuffix(subdomain, "."), topPorts[:d.options.TopPorts])
+			}
 			progress.ResultChan <- result
 		}
+	} else if walked {
+		// names of the NSEC chain exist even without addresses
+		progress.ResultChan <- Result{
+			Subdomain: subdomain,
+			Found:     true,
+			NoFQDN:    d.options.NoFQDN,
+			Unicode:   unicodeName(subdomain),
+			Walked:    true,
+		}
 	} else if d.globalopts.Verbose {
 		progress.ResultChan <- Result{
 			Subdomain: subdomain,
 			Found:     false,
 			ShowIPs:   d.options.ShowIPs,
 			ShowCNAME: d.options.ShowCNAME,
+			Unicode:   unicodeName(subdomain),
 		}
 	}
 	return nil
 }
 
+// unicodeName returns the unicode form of a punycode encoded name or an
+// empty string if the name does not contain internationalized labels
+func unicodeName(name string) string {
+	u, err := libgobuster.HostToUnicode(strings.TrimSuffix(name, "."))
+	if err != nil || u == strings.TrimSuffix(name, ".") {
+		return ""
+	}
+	return u
+}
+
+// WordKind returns the kind of the words for the word policy
+func (d *GobusterDNS) WordKind() libgobuster.WordKind {
+	return libgobuster.WordKindHost
+}
+
 func (d *GobusterDNS) AdditionalWords(word string) []string {
 	return []string{}
 }
@@ -161,6 +354,12 @@ func (d *GobusterDNS) GetConfigString() (string, error) {
 		return "", err
 	}
 
+	if d.domain != o.Domain {
+		if _, err := fmt.Fprintf(tw, "[+] Domain (ACE):\t%s\n", d.domain); err != nil {
+			return "", err
+		}
+	}
+
 	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", d.globalopts.Threads); err != nil {
 		return "", err
 	}
@@ -171,10 +370,65 @@ func (d *GobusterDNS) GetConfigString() (string, error) {
 		}
 	}
 
-	if o.Resolver != "" {
-		if _, err := fmt.Fprintf(tw, "[+] Resolver:\t%s\n", o.Resolver); err != nil {
+	if d.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", d.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	switch servers := o.Servers(); {
+	case len(servers) == 1:
+		if _, err := fmt.Fprintf(tw, "[+] Resolver:\t%s\n", servers[0]); err != nil {
+			return "", err
+		}
+	case len(servers) > 1 && o.ResolverSelection == SelectionLatency:
+		if _, err := fmt.Fprintf(tw, "[+] Resolvers:\t%d servers, selected by response time\n", len(servers)); err != nil {
+			return "", err
+		}
+	case len(servers) > 1:
+		if _, err := fmt.Fprintf(tw, "[+] Resolvers:\t%d servers, rotated per query\n", len(servers)); err != nil {
+			return "", err
+		}
+	}
+
+	switch o.Protocol {
+	case "tcp":
+		if _, err := fmt.Fprintf(tw, "[+] Protocol:\ttcp\n"); err != nil {
+			return "", err
+		}
+	case "tls":
+		if _, err := fmt.Fprintf(tw, "[+] Protocol:\tDNS over TLS\n"); err != nil {
+			return "", err
+		}
+	case "https":
+		if _, err := fmt.Fprintf(tw, "[+] Protocol:\tDNS over HTTPS\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.EDNS0Size > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] EDNS0 buffer size:\t%d\n", o.EDNS0Size); err != nil {
+			return "", err
+		}
+	}
+
+	if o.NoTCPFallback {
+		if _, err := fmt.Fprintf(tw, "[+] TCP fallback:\tdisabled\n"); err != nil {
+			return "", err
+		}
+	}
+
+	switch r := d.resolver.(type) {
+	case *udpResolver:
+		if _, err := fmt.Fprintf(tw, "[+] UDP resolver:\t%s, %d sockets, %d attempts\n", r.server, r.sockets, r.attempts); err != nil {
 			return "", err
 		}
+	case *rotatingResolver:
+		if udp := r.udpResolvers(); len(udp) > 0 {
+			if _, err := fmt.Fprintf(tw, "[+] UDP resolver:\t%d servers, %d sockets each, %d attempts\n", len(udp), udp[0].sockets, udp[0].attempts); err != nil {
+				return "", err
+			}
+		}
 	}
 
 	if o.ShowCNAME {
@@ -189,8 +443,38 @@ func (d *GobusterDNS) GetConfigString() (string, error) {
 		}
 	}
 
-	if o.WildcardForced {
-		if _, err := fmt.Fprintf(tw, "[+] Wildcard forced:\ttrue\n"); err != nil {
+	if o.ProbeHTTP {
+		if _, err := fmt.Fprintf(tw, "[+] HTTP probe:\t%d threads, timeout %s\n", o.ProbeThreads, o.ProbeTimeout); err != nil {
+			return "", err
+		}
+	}
+
+	if o.CertInfo {
+		if _, err := fmt.Fprintf(tw, "[+] Certificate names:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.TopPorts > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Port scan:\ttop %d tcp ports, %d threads, timeout %s\n", o.TopPorts, o.PortThreads, o.PortTimeout); err != nil {
+			return "", err
+		}
+	}
+
+	if o.ShowWildcard {
+		if _, err := fmt.Fprintf(tw, "[+] Show wildcard:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Depth > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Depth:\t%d\n", o.Depth); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Walk {
+		if _, err := fmt.Fprintf(tw, "[+] NSEC walk:\ttrue\n"); err != nil {
 			return "", err
 		}
 	}
@@ -201,7 +485,7 @@ func (d *GobusterDNS) GetConfigString() (string, error) {
 
 	wordlist := "stdin (pipe)"
 	if d.globalopts.Wordlist != "-" {
-		wordlist = d.globalopts.Wordlist
+		wordlist = d.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
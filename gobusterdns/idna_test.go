@@ -0,0 +1,98 @@
+package gobusterdns
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestUnderscoreAndInternationalizedNames(t *testing.T) {
+	t.Parallel()
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte("_dmarc\n_sip._tcp\na_b\n_domainkey\nbücher\nwww\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	tt := []struct {
+		testName string
+		domain   string
+		records  []string
+		expected []string
+	}{
+		{"Domain", "example.com", []string{"_dmarc.example.com", "_sip._tcp.example.com", "a_b.example.com", "xn--bcher-kva.example.com", "www.example.com"},
+			[]string{"_dmarc.example.com", "_sip._tcp.example.com", "a_b.example.com", "www.example.com", "xn--bcher-kva.example.com"}},
+		{"Underscore domain", "_msdcs.corp.local", []string{"_dmarc._msdcs.corp.local", "www._msdcs.corp.local"},
+			[]string{"_dmarc._msdcs.corp.local", "www._msdcs.corp.local"}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			globalopts := libgobuster.NewOptions()
+			globalopts.Threads = 2
+			globalopts.Wordlist = wordlist
+			globalopts.Quiet = true
+			globalopts.CollectResults = true
+
+			o := NewOptionsDNS()
+			o.Domain = x.domain
+			d, err := NewGobusterDNS(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			records := make(map[string]netip.Addr)
+			for _, name := range x.records {
+				records[name] = netip.MustParseAddr("198.51.100.1")
+			}
+			d.resolver = &levelResolver{records: records}
+
+			g, err := libgobuster.NewGobuster(globalopts, d, libgobuster.NewLogger(false))
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := g.Run(context.Background()); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if errs := g.CollectedErrors(); len(errs) > 0 {
+				t.Fatalf("expected no errors, got %v", errs)
+			}
+
+			var got []string
+			for _, r := range g.CollectedResults() {
+				got = append(got, libgobuster.NewResultRecord(r).Path)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnicodeName(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		expected string
+	}{
+		{"www.example.com.", ""},
+		{"xn--bcher-kva.example.com.", "bücher.example.com"},
+		{"_dmarc.xn--bcher-kva.example.com.", "_dmarc.bücher.example.com"},
+	}
+
+	for _, x := range tt {
+		if got := unicodeName(x.name); got != x.expected {
+			t.Fatalf("expected %q for %q, got %q", x.expected, x.name, got)
+		}
+	}
+}
@@ -6,16 +6,98 @@ import (
 
 // OptionsDNS holds all options for the dns plugin
 type OptionsDNS struct {
-	Domain         string
-	ShowIPs        bool
-	ShowCNAME      bool
-	WildcardForced bool
-	Resolver       string
-	NoFQDN         bool
-	Timeout        time.Duration
+	Domain    string
+	ShowIPs   bool
+	ShowCNAME bool
+	Resolver  string
+	NoFQDN    bool
+	Timeout   time.Duration
+	// ShowWildcard reports names resolving to the addresses of a wildcard
+	// record marked as wildcard instead of filtering them
+	ShowWildcard bool
+	// Depth is the number of levels below found names which are brute
+	// forced with the wordlist too, 0 only brute forces the domain
+	Depth int
+	// Walk follows the NSEC chain of the zone before the wordlist, the
+	// wordlist is skipped if the whole chain was walked
+	Walk bool
+	// Resolvers are the DNS servers of a resolvers file, every lookup is
+	// sent to the next one. Resolver is used first if both are set
+	Resolvers []string
+	// ResolverSelection is SelectionRoundRobin or SelectionLatency and
+	// decides which of the resolvers gets a query
+	ResolverSelection string
+	// Protocol is udp, tcp, tls for DNS over TLS or https for DNS over
+	// HTTPS
+	Protocol string
+	// EDNS0Size overrides the EDNS0 buffer size of the queries if set
+	EDNS0Size uint16
+	// NoTCPFallback disables the retry over tcp for truncated answers
+	NoTCPFallback bool
+	// RawResolver sends raw queries over UDP instead of using the resolver of
+	// the go standard library. Without resolvers it only asks the first
+	// nameserver of /etc/resolv.conf and does not read /etc/hosts
+	RawResolver bool
+	// Sockets is the number of UDP sockets the queries are spread over
+	Sockets int
+	// Attempts is the number of times a query is sent before it fails
+	Attempts int
+	// ProbeHTTP requests every found name over https and http
+	ProbeHTTP bool
+	// ProbeThreads is the number of concurrent http probes
+	ProbeThreads int
+	// ProbeTimeout is the timeout of a single http probe
+	ProbeTimeout time.Duration
+	// CertInfo reports the certificate of the domain and checks the names
+	// of its SANs
+	CertInfo bool
+	// TopPorts is the number of common tcp ports connected to on every
+	// found name, 0 disables the port scan
+	TopPorts int
+	// PortThreads is the number of concurrent port connections
+	PortThreads int
+	// PortTimeout is the timeout of a single port connection
+	PortTimeout time.Duration
+}
+
+// Servers returns the custom DNS servers the lookups are spread over, empty
+// if the system resolver is used
+func (o *OptionsDNS) Servers() []string {
+	var servers []string
+	if o.Resolver != "" {
+		servers = append(servers, o.Resolver)
+	}
+	return append(servers, o.Resolvers...)
+}
+
+// CustomTransport returns true if the queries need a custom transport
+func (o *OptionsDNS) CustomTransport() bool {
+	return o.Protocol == "tcp" || o.Protocol == "tls" || o.EDNS0Size > 0 || o.NoTCPFallback
+}
+
+// UDPResolver returns true if the queries are sent by the UDP resolver. It
+// has to be enabled with RawResolver, the go resolver is needed for tcp, tls
+// and the search domains of the system
+func (o *OptionsDNS) UDPResolver() bool {
+	return o.RawResolver && o.Protocol == "udp" && !o.NoFQDN
+}
+
+// Encrypted returns true if the queries are sent over DNS over TLS or DNS
+// over HTTPS, both need a resolver
+func (o *OptionsDNS) Encrypted() bool {
+	return o.Protocol == "tls" || o.Protocol == "https"
 }
 
 // NewOptionsDNS returns a new initialized OptionsDNS
 func NewOptionsDNS() *OptionsDNS {
-	return &OptionsDNS{}
+	return &OptionsDNS{
+		Protocol:          "udp",
+		ResolverSelection: SelectionRoundRobin,
+		Sockets:           4,
+		Attempts:          3,
+		ProbeThreads:      10,
+		ProbeTimeout:      5 * time.Second,
+		PortThreads:       50,
+		PortTimeout:       time.Second,
+	}
 }
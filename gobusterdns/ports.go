@@ -0,0 +1,60 @@
+package gobusterdns
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// topPorts are the most common open tcp ports, most common first
+// nolint:gochecknoglobals
+var topPorts = []int{
+	80, 23, 443, 21, 22, 25, 3389, 110, 445, 139,
+	143, 53, 135, 3306, 8080, 1723, 111, 995, 993, 5900,
+	1025, 587, 8888, 199, 1720, 465, 548, 113, 81, 6001,
+	10000, 514, 5060, 179, 1026, 2000, 8443, 8000, 32768, 554,
+	26, 1433, 49152, 2001, 515, 8008, 49154, 1027, 5666, 646,
+}
+
+// MaxTopPorts is the highest number of ports which can be scanned
+func MaxTopPorts() int {
+	return len(topPorts)
+}
+
+// scanPorts connects to the ports of the host and returns the open ones
+// in ascending order. The number of concurrent connections is bounded by the
+// size of the port pool
+func (d *GobusterDNS) scanPorts(ctx context.Context, host string, ports []int) []int {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var open []int
+
+	dialer := net.Dialer{Timeout: d.options.PortTimeout}
+	for _, port := range ports {
+		select {
+		case d.portPool <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		}
+		wg.Add(1)
+		go func(port int) {
+			defer wg.Done()
+			defer func() { <-d.portPool }()
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+			if err != nil {
+				return
+			}
+			conn.Close()
+			mu.Lock()
+			open = append(open, port)
+			mu.Unlock()
+		}(port)
+	}
+	wg.Wait()
+
+	sort.Ints(open)
+	return open
+}
@@ -0,0 +1,56 @@
+package gobusterdns
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestScanPorts(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	defer l.Close()
+	openPort := l.Addr().(*net.TCPAddr).Port
+
+	// a port which was just released is most likely closed
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	opts := NewOptionsDNS()
+	opts.Domain = "example.com"
+	opts.TopPorts = 10
+	opts.PortThreads = 1
+	d, err := NewGobusterDNS(libgobuster.NewOptions(), opts)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	open := d.scanPorts(context.Background(), "127.0.0.1", []int{closedPort, openPort})
+	if !reflect.DeepEqual(open, []int{openPort}) {
+		t.Fatalf("expected only port %d to be open, got %v", openPort, open)
+	}
+
+	r := Result{Found: true, Subdomain: "www.example.com.", ShowPorts: true, OpenPorts: []int{22, 443}}
+	s, err := r.ResultToString()
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !strings.Contains(s, "www.example.com [ports: 22,443]") {
+		t.Fatalf("ports are missing in result %q", s)
+	}
+	if tags := libgobuster.NewResultRecord(r).Tags; !reflect.DeepEqual(tags, []string{"port:22", "port:443"}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+}
@@ -0,0 +1,56 @@
+package gobusterdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// probeSchemes are requested in this order for every found name
+// nolint:gochecknoglobals
+var probeSchemes = []string{"https", "http"}
+
+// WebProbe is the response summary of a web server on a found name
+type WebProbe struct {
+	URL        string
+	StatusCode int
+	Title      string
+}
+
+// String returns the scheme, the status and the title of the response
+func (p WebProbe) String() string {
+	scheme, _, _ := strings.Cut(p.URL, "://")
+	if p.Title == "" {
+		return fmt.Sprintf("%s: %d", scheme, p.StatusCode)
+	}
+	return fmt.Sprintf("%s: %d %q", scheme, p.StatusCode, p.Title)
+}
+
+// probeWeb requests the root of the host over https and http. Only schemes
+// which answered are returned. The number of concurrent probes is bounded
+// by the size of the probe pool
+func (d *GobusterDNS) probeWeb(ctx context.Context, host string) []WebProbe {
+	select {
+	case d.probePool <- struct{}{}:
+	case <-ctx.Done():
+		return nil
+	}
+	defer func() { <-d.probePool }()
+
+	var probes []WebProbe
+	for _, scheme := range probeSchemes {
+		url := fmt.Sprintf("%s://%s/", scheme, host)
+		status, _, _, body, err := d.http.Request(ctx, url, libgobuster.RequestOptions{ReturnBody: true})
+		if err != nil {
+			continue
+		}
+		probes = append(probes, WebProbe{
+			URL:        url,
+			StatusCode: status,
+			Title:      libgobuster.ExtractTitle(body),
+		})
+	}
+	return probes
+}
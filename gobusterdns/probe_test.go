@@ -0,0 +1,56 @@
+package gobusterdns
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestProbeWeb(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><head><title> Intranet </title></head></html>")
+	}))
+	defer ts.Close()
+
+	opts := NewOptionsDNS()
+	opts.Domain = "example.com"
+	opts.ProbeHTTP = true
+	opts.ProbeThreads = 1
+	opts.ProbeTimeout = 2 * time.Second
+	d, err := NewGobusterDNS(libgobuster.NewOptions(), opts)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	// the test server only speaks http, so the https probe is not included
+	host := strings.TrimPrefix(ts.URL, "http://")
+	probes := d.probeWeb(context.Background(), host)
+	if len(probes) != 1 {
+		t.Fatalf("expected 1 probe, got %+v", probes)
+	}
+	want := WebProbe{URL: ts.URL + "/", StatusCode: http.StatusOK, Title: "Intranet"}
+	if probes[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, probes[0])
+	}
+
+	r := Result{Found: true, Subdomain: "www.example.com.", Web: probes}
+	s, err := r.ResultToString()
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !strings.Contains(s, `www.example.com [http: 200 "Intranet"]`) {
+		t.Fatalf("probe is missing in result %q", s)
+	}
+	record := libgobuster.NewResultRecord(r)
+	if record.StatusCode != http.StatusOK || record.Title != "Intranet" {
+		t.Fatalf("probe is missing in record %+v", record)
+	}
+}
@@ -0,0 +1,492 @@
+package gobusterdns
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// defaultEDNS0Size is the buffer size advertised by the udp resolver, the
+// same as the go resolver uses
+const defaultEDNS0Size = 1232
+
+// maxDNSMessageSize is the biggest possible DNS message
+const maxDNSMessageSize = 65535
+
+// resolver looks up the names of the dns mode. It is implemented by
+// net.Resolver and udpResolver
+type resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
+
+// udpResolver sends raw DNS queries over a fixed set of UDP sockets. Many
+// queries are in flight on every socket at the same time, answers are
+// matched to the queries by their id. Queries timing out or answered with
+// SERVFAIL are sent again on the next socket
+type udpResolver struct {
+	server string
+	// attemptTimeout is the time to wait for an answer before a query is
+	// sent again
+	attemptTimeout time.Duration
+	attempts       int
+	ednsSize       uint16
+	noTCPFallback  bool
+	sockets        int
+
+	conns []*udpConn
+	next  atomic.Uint32
+}
+
+// newUDPResolver returns a resolver sending the queries to server. Use start
+// to open the sockets
+func newUDPResolver(server string, opts *OptionsDNS) *udpResolver {
+	if !strings.Contains(server, ":") {
+		server = fmt.Sprintf("%s:53", server)
+	}
+	r := udpResolver{
+		server:         server,
+		attempts:       opts.Attempts,
+		attemptTimeout: opts.Timeout / time.Duration(opts.Attempts),
+		ednsSize:       opts.EDNS0Size,
+		noTCPFallback:  opts.NoTCPFallback,
+		sockets:        opts.Sockets,
+	}
+	if r.ednsSize == 0 {
+		r.ednsSize = defaultEDNS0Size
+	}
+	return &r
+}
+
+// start opens the sockets, they are closed once ctx is done
+func (r *udpResolver) start(ctx context.Context) error {
+	for i := 0; i < r.sockets; i++ {
+		conn, err := net.Dial("udp", r.server)
+		if err != nil {
+			for _, c := range r.conns {
+				c.conn.Close()
+			}
+			return fmt.Errorf("could not open socket to %s: %w", r.server, err)
+		}
+		c := newUDPConn(conn)
+		r.conns = append(r.conns, c)
+		go c.read()
+	}
+
+	go func() {
+		<-ctx.Done()
+		for _, c := range r.conns {
+			c.conn.Close()
+		}
+	}()
+	return nil
+}
+
+// LookupNetIP returns the A and AAAA records of host. network is ignored,
+// both record types are always queried
+func (r *udpResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	return lookupNetIP(ctx, r, host)
+}
+
+// LookupCNAME follows the CNAME records of host and returns the canonical
+// name. It returns host if there is no CNAME record
+func (r *udpResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	return lookupCNAME(ctx, r, host)
+}
+
+// exchanger sends single DNS queries. The lookups of the resolvers sending
+// raw queries are built on top of it
+type exchanger interface {
+	exchange(ctx context.Context, host string, qtype dnsmessage.Type) (*dnsmessage.Message, error)
+	notFound(host string) error
+}
+
+// lookupNetIP queries the A and AAAA records of host at the same time
+func lookupNetIP(ctx context.Context, r exchanger, host string) ([]netip.Addr, error) {
+	type answer struct {
+		ips []netip.Addr
+		err error
+	}
+	answers := make(chan answer, 2)
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		go func(qtype dnsmessage.Type) {
+			msg, err := r.exchange(ctx, host, qtype)
+			if err != nil {
+				answers <- answer{err: err}
+				return
+			}
+			var ips []netip.Addr
+			for _, rr := range msg.Answers {
+				switch body := rr.Body.(type) {
+				case *dnsmessage.AResource:
+					ips = append(ips, netip.AddrFrom4(body.A))
+				case *dnsmessage.AAAAResource:
+					ips = append(ips, netip.AddrFrom16(body.AAAA))
+				}
+			}
+			answers <- answer{ips: ips}
+		}(qtype)
+	}
+
+	var ips []netip.Addr
+	var err error
+	for i := 0; i < 2; i++ {
+		a := <-answers
+		ips = append(ips, a.ips...)
+		if a.err != nil && err == nil {
+			err = a.err
+		}
+	}
+	if len(ips) > 0 {
+		return ips, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, r.notFound(host)
+}
+
+// lookupCNAME follows the CNAME records in the answer of the A query of
+// host
+func lookupCNAME(ctx context.Context, r exchanger, host string) (string, error) {
+	msg, err := r.exchange(ctx, host, dnsmessage.TypeA)
+	if err != nil {
+		return "", err
+	}
+	name := host
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	// the records of a chain are not necessarily in order
+	for i := 0; i < len(msg.Answers); i++ {
+		for _, rr := range msg.Answers {
+			cname, ok := rr.Body.(*dnsmessage.CNAMEResource)
+			if ok && strings.EqualFold(rr.Header.Name.String(), name) {
+				name = cname.CNAME.String()
+				break
+			}
+		}
+	}
+	return name, nil
+}
+
+// exchange sends the query for host and returns the answer. The query is
+// sent again if it timed out or the server failed
+func (r *udpResolver) exchange(ctx context.Context, host string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	if len(r.conns) == 0 {
+		return nil, fmt.Errorf("resolver was not started")
+	}
+	question, err := newQuestion(host, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < r.attempts; attempt++ {
+		c := r.conns[int(r.next.Add(1))%len(r.conns)]
+		msg, err := r.exchangeUDP(ctx, c, question)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		if msg.Truncated {
+			if r.noTCPFallback {
+				return nil, fmt.Errorf("DNS answer was truncated and TCP fallback is disabled")
+			}
+			if msg, err = r.exchangeTCP(ctx, question); err != nil {
+				return nil, err
+			}
+		}
+
+		switch msg.RCode {
+		case dnsmessage.RCodeSuccess:
+			return msg, nil
+		case dnsmessage.RCodeNameError:
+			return nil, r.notFound(host)
+		case dnsmessage.RCodeServerFailure:
+			lastErr = &net.DNSError{Err: "server misbehaving", Name: host, Server: r.server, IsTemporary: true}
+			continue
+		default:
+			return nil, &net.DNSError{Err: fmt.Sprintf("unexpected answer %s", msg.RCode), Name: host, Server: r.server}
+		}
+	}
+	return nil, lastErr
+}
+
+// exchangeUDP sends the question once over the socket and waits for the
+// answer
+func (r *udpResolver) exchangeUDP(ctx context.Context, c *udpConn, question dnsmessage.Question) (*dnsmessage.Message, error) {
+	id, answers, err := c.register()
+	if err != nil {
+		return nil, err
+	}
+	defer c.unregister(id)
+
+	query, err := packQuery(id, question, r.ednsSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(r.attemptTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case b, ok := <-answers:
+			if !ok {
+				return nil, c.error()
+			}
+			var msg dnsmessage.Message
+			if err := msg.Unpack(b); err != nil || !isAnswer(&msg, question) {
+				// ignore spoofed or broken answers and wait for the real one
+				continue
+			}
+			return &msg, nil
+		case <-timer.C:
+			return nil, &net.DNSError{Err: "i/o timeout", Name: question.Name.String(), Server: r.server, IsTimeout: true}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// exchangeTCP sends the question over a new tcp connection, this is used if
+// the UDP answer was truncated
+func (r *udpResolver) exchangeTCP(ctx context.Context, question dnsmessage.Question) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	id := randomID()
+	query, err := packQuery(id, question, r.ednsSize)
+	if err != nil {
+		return nil, err
+	}
+	// messages over tcp are prefixed with their two byte length
+	out := make([]byte, 2, len(query)+2)
+	binary.BigEndian.PutUint16(out, uint16(len(query)))
+	if _, err := conn.Write(append(out, query...)); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return nil, err
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(b); err != nil {
+		return nil, fmt.Errorf("could not parse DNS answer: %w", err)
+	}
+	if msg.ID != id || !isAnswer(&msg, question) {
+		return nil, fmt.Errorf("DNS answer does not match the query")
+	}
+	return &msg, nil
+}
+
+// newQuestion returns the question for the records of host
+func newQuestion(host string, qtype dnsmessage.Type) (dnsmessage.Question, error) {
+	fqdn := host
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn += "."
+	}
+	name, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return dnsmessage.Question{}, &net.DNSError{Err: err.Error(), Name: host}
+	}
+	return dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET}, nil
+}
+
+// packQuery returns the query for the question with an EDNS0 record
+// advertising the buffer size
+func packQuery(id uint16, question dnsmessage.Question, ednsSize uint16) ([]byte, error) {
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ID: id, RecursionDesired: true})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(question); err != nil {
+		return nil, err
+	}
+	if err := b.StartAdditionals(); err != nil {
+		return nil, err
+	}
+	var rh dnsmessage.ResourceHeader
+	if err := rh.SetEDNS0(int(ednsSize), dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	if err := b.OPTResource(rh, dnsmessage.OPTResource{}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// notFound returns the error of the go resolver for names without records
+func (r *udpResolver) notFound(host string) error {
+	return &net.DNSError{Err: "no such host", Name: host, Server: r.server, IsNotFound: true}
+}
+
+// isAnswer checks if the message answers the question
+func isAnswer(msg *dnsmessage.Message, question dnsmessage.Question) bool {
+	if !msg.Response || len(msg.Questions) != 1 {
+		return false
+	}
+	q := msg.Questions[0]
+	return q.Type == question.Type && q.Class == question.Class && strings.EqualFold(q.Name.String(), question.Name.String())
+}
+
+// udpConn is a connected UDP socket with the queries waiting for an answer
+type udpConn struct {
+	conn  net.Conn
+	mutex sync.Mutex
+	// pending maps the ids of the queries in flight to the channel their
+	// answer is sent to
+	pending map[uint16]chan []byte
+	id      uint16
+	// err is set once the socket is closed
+	err error
+}
+
+func newUDPConn(conn net.Conn) *udpConn {
+	return &udpConn{
+		conn:    conn,
+		pending: make(map[uint16]chan []byte),
+		// a random start makes guessing the ids harder
+		id: randomID(),
+	}
+}
+
+// register reserves an id for a query and returns the channel its answer is
+// sent to
+func (c *udpConn) register() (uint16, chan []byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return 0, nil, c.err
+	}
+	if len(c.pending) > 0xffff {
+		return 0, nil, fmt.Errorf("too many DNS queries in flight")
+	}
+	for {
+		c.id++
+		if _, ok := c.pending[c.id]; !ok {
+			break
+		}
+	}
+	// buffered so the reader never blocks, later answers for the same id
+	// are dropped
+	answers := make(chan []byte, 4)
+	c.pending[c.id] = answers
+	return c.id, answers, nil
+}
+
+// unregister releases the id of a query
+func (c *udpConn) unregister(id uint16) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.pending, id)
+}
+
+// error returns the error the socket was closed with
+func (c *udpConn) error() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.err
+}
+
+// read passes the answers to the waiting queries until the socket is closed
+func (c *udpConn) read() {
+	buf := make([]byte, maxDNSMessageSize)
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				// port unreachable answers are reported on reads of
+				// connected sockets, the queries will time out
+				continue
+			}
+			c.mutex.Lock()
+			c.err = err
+			for id, answers := range c.pending {
+				close(answers)
+				delete(c.pending, id)
+			}
+			c.mutex.Unlock()
+			return
+		}
+		if n < 2 {
+			continue
+		}
+
+		id := binary.BigEndian.Uint16(buf)
+		c.mutex.Lock()
+		answers, ok := c.pending[id]
+		if ok {
+			select {
+			case answers <- append([]byte(nil), buf[:n]...):
+			default:
+			}
+		}
+		c.mutex.Unlock()
+	}
+}
+
+// randomID returns a random query id
+func randomID() uint16 {
+	var b [2]byte
+	// the id only makes spoofing harder, a zero id still works
+	_, _ = rand.Read(b[:])
+	return binary.BigEndian.Uint16(b[:])
+}
+
+// systemNameserver returns the first nameserver of /etc/resolv.conf or an
+// empty string if there is none
+func systemNameserver() string {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			if _, err := netip.ParseAddr(fields[1]); err == nil {
+				return net.JoinHostPort(fields[1], "53")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,212 @@
+package gobusterdns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsServer answers queries over UDP like a recursive resolver would. The
+// first query for drop.example.com. is dropped and the first query for
+// flaky.example.com. is answered with SERVFAIL
+func dnsServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	var mutex sync.Mutex
+	seen := make(map[string]bool)
+	go func() {
+		buf := make([]byte, maxDNSMessageSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+				continue
+			}
+			q := query.Questions[0]
+			name := q.Name.String()
+
+			mutex.Lock()
+			first := !seen[name+q.Type.String()]
+			seen[name+q.Type.String()] = true
+			mutex.Unlock()
+
+			answer := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+				Questions: query.Questions,
+			}
+			switch name {
+			case "www.example.com.":
+				if q.Type == dnsmessage.TypeA {
+					answer.Answers = append(answer.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+						Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+					})
+				} else if q.Type == dnsmessage.TypeAAAA {
+					answer.Answers = append(answer.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeAAAA, Class: dnsmessage.ClassINET},
+						Body:   &dnsmessage.AAAAResource{AAAA: netip.MustParseAddr("2001:db8::1").As16()},
+					})
+				}
+			case "alias.example.com.":
+				// the chain is returned out of order
+				answer.Answers = append(answer.Answers,
+					dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName("cdn.example.net."), Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET},
+						Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("edge.example.net.")},
+					},
+					dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET},
+						Body:   &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("cdn.example.net.")},
+					},
+				)
+			case "drop.example.com.":
+				if first {
+					continue
+				}
+				answer.Answers = append(answer.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+					Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}},
+				})
+			case "flaky.example.com.":
+				if first {
+					answer.RCode = dnsmessage.RCodeServerFailure
+					break
+				}
+				answer.Answers = append(answer.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+					Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 3}},
+				})
+			case "big.example.com.":
+				answer.Truncated = true
+			default:
+				answer.RCode = dnsmessage.RCodeNameError
+			}
+
+			b, err := answer.Pack()
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(b, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func newTestResolver(t *testing.T) *udpResolver {
+	t.Helper()
+
+	opts := NewOptionsDNS()
+	opts.Timeout = 3 * time.Second
+	opts.NoTCPFallback = true
+	r := newUDPResolver(dnsServer(t), opts)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := r.start(ctx); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	return r
+}
+
+func TestUDPResolverLookupNetIP(t *testing.T) {
+	t.Parallel()
+	r := newTestResolver(t)
+
+	tt := []struct {
+		host     string
+		expected []string
+		notFound bool
+	}{
+		{"www.example.com.", []string{"192.0.2.1", "2001:db8::1"}, false},
+		{"www.example.com", []string{"192.0.2.1", "2001:db8::1"}, false},
+		{"drop.example.com.", []string{"192.0.2.2"}, false},
+		{"flaky.example.com.", []string{"192.0.2.3"}, false},
+		{"missing.example.com.", nil, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.host, func(t *testing.T) {
+			t.Parallel()
+			ips, err := r.LookupNetIP(context.Background(), "ip", x.host)
+			if x.notFound {
+				var dnsErr *net.DNSError
+				if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+					t.Fatalf("expected a not found error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			found := make(map[string]bool)
+			for _, ip := range ips {
+				found[ip.String()] = true
+			}
+			if len(found) != len(x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, ips)
+			}
+			for _, ip := range x.expected {
+				if !found[ip] {
+					t.Fatalf("expected %v, got %v", x.expected, ips)
+				}
+			}
+		})
+	}
+}
+
+func TestUDPResolverLookupCNAME(t *testing.T) {
+	t.Parallel()
+	r := newTestResolver(t)
+
+	cname, err := r.LookupCNAME(context.Background(), "alias.example.com.")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if cname != "edge.example.net." {
+		t.Fatalf("expected edge.example.net., got %s", cname)
+	}
+
+	cname, err = r.LookupCNAME(context.Background(), "www.example.com")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if cname != "www.example.com." {
+		t.Fatalf("expected www.example.com., got %s", cname)
+	}
+}
+
+func TestUDPResolverTruncated(t *testing.T) {
+	t.Parallel()
+	r := newTestResolver(t)
+
+	if _, err := r.LookupNetIP(context.Background(), "ip", "big.example.com."); err == nil {
+		t.Fatal("expected an error for a truncated answer without tcp fallback")
+	}
+}
+
+func TestUDPResolverNotStarted(t *testing.T) {
+	t.Parallel()
+
+	r := newUDPResolver("127.0.0.1", NewOptionsDNS())
+	if r.server != "127.0.0.1:53" {
+		t.Fatalf("expected the default port, got %s", r.server)
+	}
+	if _, err := r.LookupNetIP(context.Background(), "ip", "www.example.com."); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -2,9 +2,12 @@ package gobusterdns
 
 import (
 	"bytes"
+	"fmt"
 	"net/netip"
+	"strconv"
 	"strings"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
@@ -22,6 +25,19 @@ type Result struct {
 	NoFQDN    bool
 	IPs       []netip.Addr
 	CNAME     string
+	// Unicode holds the unicode form of internationalized subdomains
+	Unicode string
+	// Web holds the responses of the http probe
+	Web []WebProbe
+	// ShowPorts is set if the ports of the name were scanned
+	ShowPorts bool
+	// OpenPorts holds the open tcp ports of the port scan
+	OpenPorts []int
+	// Wildcard is set if the name resolved to an address of the wildcard
+	// record of the zone
+	Wildcard bool
+	// Walked is set if the name was found in the NSEC chain of the zone
+	Walked bool
 }
 
 // ResultToString converts the Result to it's textual representation
@@ -40,18 +56,69 @@ func (r Result) ResultToString() (string, error) {
 		c(buf, "Missed: ")
 	}
 
+	if r.Unicode != "" {
+		r.Subdomain = fmt.Sprintf("%s (%s)", r.Unicode, r.Subdomain)
+	}
+
 	if r.ShowIPs && r.Found {
 		ips := make([]string, len(r.IPs))
 		for i := range r.IPs {
 			ips[i] = r.IPs[i].String()
 		}
-		c(buf, "%s [%s]\n", r.Subdomain, strings.Join(ips, ","))
+		c(buf, "%s [%s]", r.Subdomain, strings.Join(ips, ","))
 	} else if r.ShowCNAME && r.Found && r.CNAME != "" {
-		c(buf, "%s [%s]\n", r.Subdomain, r.CNAME)
+		c(buf, "%s [%s]", r.Subdomain, r.CNAME)
 	} else {
-		c(buf, "%s\n", r.Subdomain)
+		c(buf, "%s", r.Subdomain)
+	}
+
+	if r.Wildcard {
+		c(buf, " [wildcard]")
+	}
+	if r.Walked {
+		c(buf, " [nsec]")
+	}
+	for _, w := range r.Web {
+		c(buf, " [%s]", w)
+	}
+	if r.ShowPorts {
+		if len(r.OpenPorts) == 0 {
+			c(buf, " [ports: none]")
+		} else {
+			ports := make([]string, len(r.OpenPorts))
+			for i, p := range r.OpenPorts {
+				ports[i] = strconv.Itoa(p)
+			}
+			c(buf, " [ports: %s]", strings.Join(ports, ","))
+		}
 	}
+	c(buf, "\n")
 
 	s := buf.String()
 	return s, nil
 }
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	record := libgobuster.ResultRecord{
+		Path:  strings.TrimSuffix(r.Subdomain, "."),
+		Found: r.Found,
+	}
+	if r.Wildcard {
+		record.Tags = append(record.Tags, "wildcard")
+	}
+	if r.Walked {
+		record.Tags = append(record.Tags, "nsec")
+	}
+	// open ports are tagged, so they can be filtered with tag=port or tag=port:22
+	for _, p := range r.OpenPorts {
+		record.Tags = append(record.Tags, fmt.Sprintf("port:%d", p))
+	}
+	// the first responding scheme is used for filters and outputs
+	if len(r.Web) > 0 {
+		record.URL = r.Web[0].URL
+		record.StatusCode = r.Web[0].StatusCode
+		record.Title = r.Web[0].Title
+	}
+	return record
+}
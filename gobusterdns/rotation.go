@@ -0,0 +1,168 @@
+package gobusterdns
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+const (
+	// SelectionRoundRobin sends every query to the next resolver
+	SelectionRoundRobin = "round-robin"
+	// SelectionLatency sends every query to the faster of two random
+	// resolvers, measured by their recent response times
+	SelectionLatency = "latency"
+)
+
+const (
+	// latencyWeight is the weight of the newest response time in the
+	// moving average of a resolver, as a fraction 1/latencyWeight
+	latencyWeight = 8
+	// latencyProbe sends every latencyProbe-th query to a random resolver,
+	// so slow resolvers are measured again once they recovered
+	latencyProbe = 32
+)
+
+// startResolver is implemented by resolvers which open their sockets before
+// the run
+type startResolver interface {
+	start(ctx context.Context) error
+}
+
+// rotatingResolver sends every lookup to the next of its resolvers, so the
+// queries are spread over multiple DNS servers and none of them rate limits
+// the scan on its own. With the latency selection slow servers get fewer
+// queries
+type rotatingResolver struct {
+	resolvers []resolver
+	next      atomic.Uint32
+	byLatency bool
+	// latencies are the moving averages of the response times of the
+	// resolvers in nanoseconds, 0 until the first response
+	latencies []atomic.Int64
+}
+
+func newRotatingResolver(resolvers []resolver, selection string) *rotatingResolver {
+	return &rotatingResolver{
+		resolvers: resolvers,
+		byLatency: selection == SelectionLatency,
+		latencies: make([]atomic.Int64, len(resolvers)),
+	}
+}
+
+// pick returns the index of the resolver of the next lookup. The latency
+// selection compares two random resolvers instead of always using the
+// fastest one, so the load is still spread over the fast ones. Unmeasured
+// resolvers are tried first
+func (r *rotatingResolver) pick() int {
+	n := r.next.Add(1) - 1
+	if !r.byLatency || len(r.resolvers) < 2 {
+		return int(n % uint32(len(r.resolvers)))
+	}
+	if n%latencyProbe == latencyProbe-1 {
+		return libgobuster.RandomIntn(len(r.resolvers))
+	}
+	a := libgobuster.RandomIntn(len(r.resolvers))
+	b := libgobuster.RandomIntn(len(r.resolvers) - 1)
+	if b >= a {
+		b++
+	}
+	if r.latencies[b].Load() < r.latencies[a].Load() {
+		return b
+	}
+	return a
+}
+
+// observe adds the response time of a lookup to the moving average of the
+// resolver. Failed lookups count too, so timing out resolvers are avoided
+func (r *rotatingResolver) observe(ctx context.Context, i int, start time.Time) {
+	if !r.byLatency || ctx.Err() != nil {
+		return
+	}
+	sample := int64(time.Since(start))
+	for {
+		old := r.latencies[i].Load()
+		avg := sample
+		if old != 0 {
+			avg = old + (sample-old)/latencyWeight
+		}
+		// 0 marks unmeasured resolvers
+		if avg <= 0 {
+			avg = 1
+		}
+		if r.latencies[i].CompareAndSwap(old, avg) {
+			return
+		}
+	}
+}
+
+// start starts all resolvers which need to be started
+func (r *rotatingResolver) start(ctx context.Context) error {
+	for _, res := range r.resolvers {
+		if s, ok := res.(startResolver); ok {
+			if err := s.start(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LookupNetIP looks up host on the next resolver
+func (r *rotatingResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	i := r.pick()
+	defer r.observe(ctx, i, time.Now())
+	return r.resolvers[i].LookupNetIP(ctx, network, host)
+}
+
+// LookupCNAME looks up the CNAME of host on the next resolver
+func (r *rotatingResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	i := r.pick()
+	defer r.observe(ctx, i, time.Now())
+	return r.resolvers[i].LookupCNAME(ctx, host)
+}
+
+// udpResolvers returns the UDP resolvers among the resolvers
+func (r *rotatingResolver) udpResolvers() []*udpResolver {
+	var udp []*udpResolver
+	for _, res := range r.resolvers {
+		if u, ok := res.(*udpResolver); ok {
+			udp = append(udp, u)
+		}
+	}
+	return udp
+}
+
+// ReadResolverFile reads one DNS server per line in the format of the
+// resolver option. Empty lines and lines starting with # are skipped
+func ReadResolverFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open resolvers file: %w", err)
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		servers = append(servers, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read resolvers file: %w", err)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("resolvers file %s contains no servers", path)
+	}
+	return servers, nil
+}
@@ -0,0 +1,173 @@
+package gobusterdns
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// countingResolver counts the lookups it received
+type countingResolver struct {
+	mu      sync.Mutex
+	lookups int
+}
+
+func (r *countingResolver) LookupNetIP(context.Context, string, string) ([]netip.Addr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	return nil, nil
+}
+
+func (r *countingResolver) LookupCNAME(context.Context, string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	return "", nil
+}
+
+func TestRotatingResolver(t *testing.T) {
+	t.Parallel()
+
+	counters := []*countingResolver{{}, {}, {}}
+	var resolvers []resolver
+	for _, c := range counters {
+		resolvers = append(resolvers, c)
+	}
+	r := newRotatingResolver(resolvers, SelectionRoundRobin)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				_, _ = r.LookupNetIP(context.Background(), "ip", "www.example.com.")
+			} else {
+				_, _ = r.LookupCNAME(context.Background(), "www.example.com.")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, c := range counters {
+		if c.lookups != 10 {
+			t.Fatalf("expected 10 lookups on resolver %d, got %d", i, c.lookups)
+		}
+	}
+}
+
+// slowResolver answers every lookup after a delay
+type slowResolver struct {
+	countingResolver
+	delay time.Duration
+}
+
+func (r *slowResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	time.Sleep(r.delay)
+	return r.countingResolver.LookupNetIP(ctx, network, host)
+}
+
+func TestLatencySelection(t *testing.T) {
+	t.Parallel()
+
+	fast := []*slowResolver{{}, {}}
+	slow := &slowResolver{delay: 20 * time.Millisecond}
+	r := newRotatingResolver([]resolver{fast[0], slow, fast[1]}, SelectionLatency)
+	for i := 0; i < 200; i++ {
+		_, _ = r.LookupNetIP(context.Background(), "ip", "www.example.com.")
+	}
+
+	// the slow resolver only gets the random probes and the queries before
+	// it was measured
+	if slow.lookups > 20 {
+		t.Fatalf("expected the slow resolver to get few lookups, got %d", slow.lookups)
+	}
+	for i, f := range fast {
+		if f.lookups < 50 {
+			t.Fatalf("expected the load to be spread over the fast resolvers, resolver %d got %d", i, f.lookups)
+		}
+	}
+}
+
+func TestNewGobusterDNSResolvers(t *testing.T) {
+	t.Parallel()
+
+	opts := NewOptionsDNS()
+	opts.Domain = "example.com"
+	opts.Timeout = 3 * time.Second
+	opts.NoTCPFallback = true
+	opts.RawResolver = true
+	opts.Resolver = dnsServer(t)
+	opts.Resolvers = []string{dnsServer(t)}
+	d, err := NewGobusterDNS(libgobuster.NewOptions(), opts)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	r, ok := d.resolver.(*rotatingResolver)
+	if !ok || len(r.udpResolvers()) != 2 {
+		t.Fatalf("expected a rotating resolver over two UDP resolvers, got %T", d.resolver)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := r.start(ctx); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	// both servers answer, so every lookup succeeds whichever is used
+	for i := 0; i < 4; i++ {
+		ips, err := d.dnsLookup(ctx, "www.example.com.")
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if len(ips) != 2 {
+			t.Fatalf("expected 2 addresses, got %v", ips)
+		}
+	}
+}
+
+func TestReadResolverFile(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+		expected []string
+		wantErr  bool
+	}{
+		{"Servers", "1.1.1.1\n8.8.8.8:53\n", []string{"1.1.1.1", "8.8.8.8:53"}, false},
+		{"Comments and empty lines", "# public\n\n 1.1.1.1 \n#8.8.8.8\n", []string{"1.1.1.1"}, false},
+		{"Empty", "# nothing\n\n", nil, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "resolvers.txt")
+			if err := os.WriteFile(path, []byte(x.content), 0o600); err != nil {
+				t.Fatalf("could not write file: %v", err)
+			}
+			servers, err := ReadResolverFile(path)
+			if x.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", servers)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if strings.Join(servers, ",") != strings.Join(x.expected, ",") {
+				t.Fatalf("expected %v, got %v", x.expected, servers)
+			}
+		})
+	}
+}
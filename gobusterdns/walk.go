@@ -0,0 +1,189 @@
+package gobusterdns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// record types dnsmessage has no constants for
+const (
+	typeNSEC       dnsmessage.Type = 47
+	typeNSEC3PARAM dnsmessage.Type = 51
+)
+
+// maxWalkNames stops walking chains which never return to the apex
+const maxWalkNames = 100000
+
+// walkExchanger returns the resolver sending the raw queries of the zone
+// walk, nil if only the go resolver is used
+func (d *GobusterDNS) walkExchanger() exchanger {
+	if r, ok := d.resolver.(*rotatingResolver); ok {
+		for _, res := range r.resolvers {
+			if ex, ok := res.(exchanger); ok {
+				return ex
+			}
+		}
+		return nil
+	}
+	ex, _ := d.resolver.(exchanger)
+	return ex
+}
+
+// walkZone follows the NSEC chain of a zone signed with NSEC from the apex
+// until it returns to it. The walked names are queued and reported even if
+// they have no addresses. If the chain is complete the wordlist is skipped,
+// zones signed with NSEC3 or not signed at all fall back to the wordlist
+func (d *GobusterDNS) walkZone(ctx context.Context, progress *libgobuster.Progress) error {
+	ex := d.walkExchanger()
+	if ex == nil {
+		return fmt.Errorf("walking the zone needs the udp or https resolver")
+	}
+
+	apex := strings.ToLower(fqdn(d.domain))
+	names, complete, err := walkNSEC(ctx, ex, apex)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("could not walk the zone %s: %w", d.domain, err)
+	}
+
+	if len(names) == 0 && !complete {
+		message := fmt.Sprintf("%s is not signed with NSEC, falling back to the wordlist", d.domain)
+		if msg, err := ex.exchange(ctx, apex, typeNSEC3PARAM); err == nil && hasRecord(msg, apex, typeNSEC3PARAM) {
+			message = fmt.Sprintf("%s is signed with NSEC3, its names can not be walked, falling back to the wordlist", d.domain)
+		}
+		progress.MessageChan <- libgobuster.Message{
+			Level:   libgobuster.LevelInfo,
+			Message: message,
+		}
+		return nil
+	}
+
+	var words []string
+	for _, name := range names {
+		word := strings.TrimSuffix(strings.TrimSuffix(name, apex), ".")
+		// the owner of a wildcard record is no name of its own
+		if word == "" || strings.HasPrefix(word, "*.") || word == "*" {
+			continue
+		}
+		d.walked[word] = struct{}{}
+		words = append(words, word)
+	}
+	d.walkComplete = complete
+	progress.QueueWords(words...)
+
+	message := fmt.Sprintf("Walked %d names of the NSEC chain of %s, the chain is incomplete and the wordlist is used too", len(words), d.domain)
+	if complete {
+		message = fmt.Sprintf("Walked %d names of the NSEC chain of %s, the wordlist is skipped", len(words), d.domain)
+	}
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelInfo,
+		Message: message,
+	}
+	return nil
+}
+
+// SkipWord skips the words of the wordlist once the whole zone was walked
+// and the words which were walked already
+func (d *GobusterDNS) SkipWord(word string) bool {
+	if d.walkComplete {
+		return true
+	}
+	_, ok := d.walked[word]
+	return ok
+}
+
+// walkNSEC queries the NSEC record of every name starting at the apex and
+// returns the next names of the records until the chain returns to the
+// apex. complete is false if the chain broke before
+func walkNSEC(ctx context.Context, ex exchanger, apex string) (names []string, complete bool, err error) {
+	seen := libgobuster.NewSet[string]()
+	current := apex
+	for len(names) < maxWalkNames {
+		msg, err := ex.exchange(ctx, current, typeNSEC)
+		if err != nil {
+			if current == apex {
+				return nil, false, err
+			}
+			// a name of the chain may be delegated to a server failing it
+			return names, false, nil
+		}
+		next, ok := nsecNext(msg, current)
+		if !ok {
+			return names, false, nil
+		}
+		if strings.EqualFold(next, apex) {
+			return names, true, nil
+		}
+		// names outside the zone or seen before break the chain, like
+		// the chain of a delegated zone
+		if !strings.HasSuffix(next, "."+apex) || !seen.Add(next) {
+			return names, false, nil
+		}
+		names = append(names, next)
+		current = next
+	}
+	return names, false, nil
+}
+
+// nsecNext returns the lowercased next domain name of the NSEC record of
+// the name in the answer
+func nsecNext(msg *dnsmessage.Message, name string) (string, bool) {
+	for _, rr := range msg.Answers {
+		if rr.Header.Type != typeNSEC || !strings.EqualFold(rr.Header.Name.String(), name) {
+			continue
+		}
+		body, ok := rr.Body.(*dnsmessage.UnknownResource)
+		if !ok {
+			continue
+		}
+		// the next domain name is not compressed, the type bitmaps follow
+		return parseWireName(body.Data)
+	}
+	return "", false
+}
+
+// parseWireName parses an uncompressed name in wire format
+func parseWireName(b []byte) (string, bool) {
+	var labels []string
+	for i := 0; i < len(b); {
+		length := int(b[i])
+		i++
+		if length == 0 {
+			if len(labels) == 0 {
+				return "", false
+			}
+			return strings.ToLower(strings.Join(labels, ".") + "."), true
+		}
+		// compression pointers are not allowed in NSEC records
+		if length > 63 || i+length > len(b) {
+			return "", false
+		}
+		labels = append(labels, string(b[i:i+length]))
+		i += length
+	}
+	return "", false
+}
+
+// hasRecord checks if the answer contains a record of the type for name
+func hasRecord(msg *dnsmessage.Message, name string, qtype dnsmessage.Type) bool {
+	for _, rr := range msg.Answers {
+		if rr.Header.Type == qtype && strings.EqualFold(rr.Header.Name.String(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// fqdn returns the name with a trailing dot
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
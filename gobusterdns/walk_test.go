@@ -0,0 +1,185 @@
+package gobusterdns
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// zoneResolver answers the raw queries for a signed zone
+type zoneResolver struct {
+	// nsec maps the owners of the NSEC records to their next name
+	nsec  map[string]string
+	nsec3 bool
+	addrs map[string]netip.Addr
+}
+
+func (r *zoneResolver) exchange(_ context.Context, host string, qtype dnsmessage.Type) (*dnsmessage.Message, error) {
+	host = strings.ToLower(host)
+	name := dnsmessage.MustNewName(host)
+	header := dnsmessage.ResourceHeader{Name: name, Type: qtype, Class: dnsmessage.ClassINET}
+	msg := dnsmessage.Message{Header: dnsmessage.Header{Response: true}}
+	switch qtype {
+	case typeNSEC:
+		if next, ok := r.nsec[host]; ok {
+			// the type bitmap of A and NSEC follows the next name
+			data := append(wireName(next), 0, 6, 0x40, 0, 0, 0, 0, 0x01)
+			msg.Answers = append(msg.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.UnknownResource{Type: typeNSEC, Data: data}})
+		}
+	case typeNSEC3PARAM:
+		if r.nsec3 && host == "example.com." {
+			msg.Answers = append(msg.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.UnknownResource{Type: typeNSEC3PARAM, Data: []byte{1, 0, 0, 0, 0}}})
+		}
+	case dnsmessage.TypeA:
+		if ip, ok := r.addrs[host]; ok {
+			msg.Answers = append(msg.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: ip.As4()}})
+		}
+	}
+	return &msg, nil
+}
+
+func (r *zoneResolver) notFound(host string) error {
+	return &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r *zoneResolver) LookupNetIP(ctx context.Context, _, host string) ([]netip.Addr, error) {
+	return lookupNetIP(ctx, r, host)
+}
+
+func (r *zoneResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	return lookupCNAME(ctx, r, host)
+}
+
+// wireName returns the uncompressed wire format of a name
+func wireName(name string) []byte {
+	var b []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+func TestParseWireName(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		data     []byte
+		expected string
+		ok       bool
+	}{
+		{"Name", wireName("WWW.example.com."), "www.example.com.", true},
+		{"Bitmap", append(wireName("a.example.com."), 0, 1, 0x40), "a.example.com.", true},
+		{"Root", []byte{0}, "", false},
+		{"Truncated", []byte{3, 'w', 'w'}, "", false},
+		{"Pointer", []byte{0xc0, 12}, "", false},
+		{"Empty", nil, "", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			name, ok := parseWireName(x.data)
+			if name != x.expected || ok != x.ok {
+				t.Fatalf("expected %q %t, got %q %t", x.expected, x.ok, name, ok)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte("www\nftp\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	chain := map[string]string{
+		"example.com.":        "_dmarc.example.com.",
+		"_dmarc.example.com.": "*.example.com.",
+		"*.example.com.":      "mail.example.com.",
+		"mail.example.com.":   "www.example.com.",
+		"www.example.com.":    "example.com.",
+	}
+	broken := map[string]string{}
+	for owner, next := range chain {
+		if owner != "www.example.com." {
+			broken[owner] = next
+		}
+	}
+
+	tt := []struct {
+		testName string
+		nsec     map[string]string
+		nsec3    bool
+		expected []string
+	}{
+		// ftp is not part of the zone, the wordlist is not used
+		{"Complete", chain, false, []string{"_dmarc.example.com [nsec]", "mail.example.com [nsec]", "www.example.com [nsec]"}},
+		{"Incomplete", broken, false, []string{"_dmarc.example.com [nsec]", "ftp.example.com", "mail.example.com [nsec]", "www.example.com [nsec]"}},
+		{"NSEC3", nil, true, []string{"ftp.example.com", "www.example.com"}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			globalopts := libgobuster.NewOptions()
+			globalopts.Threads = 2
+			globalopts.Wordlist = wordlist
+			globalopts.Quiet = true
+			globalopts.CollectResults = true
+
+			o := NewOptionsDNS()
+			o.Domain = "example.com"
+			o.Walk = true
+			d, err := NewGobusterDNS(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			d.resolver = &zoneResolver{
+				nsec:  x.nsec,
+				nsec3: x.nsec3,
+				addrs: map[string]netip.Addr{
+					"www.example.com.":  netip.MustParseAddr("198.51.100.1"),
+					"mail.example.com.": netip.MustParseAddr("198.51.100.2"),
+					"ftp.example.com.":  netip.MustParseAddr("198.51.100.3"),
+				},
+			}
+
+			g, err := libgobuster.NewGobuster(globalopts, d, libgobuster.NewLogger(false))
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := g.Run(context.Background()); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			var got []string
+			for _, r := range g.CollectedResults() {
+				name := libgobuster.NewResultRecord(r).Path
+				if r.(Result).Walked {
+					name += " [nsec]"
+				}
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package gobusterdns
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// wildcardProbes is the number of random labels resolved to collect the
+// addresses of a wildcard record. Wildcards answered from a pool of
+// addresses return a different subset on every query
+const wildcardProbes = 5
+
+// detectWildcard resolves random labels below the name. If any of them
+// resolves the name has a wildcard record and names below it resolving to
+// one of its addresses are filtered. The addresses are returned
+func (d *GobusterDNS) detectWildcard(ctx context.Context, name string, progress *libgobuster.Progress) (libgobuster.Set[netip.Addr], error) {
+	wildcardIps := libgobuster.NewSet[netip.Addr]()
+	for i := 0; i < wildcardProbes; i++ {
+		ips, err := d.dnsLookup(ctx, fmt.Sprintf("%s.%s", libgobuster.RandomUUID(), name))
+		if ctx.Err() != nil {
+			return wildcardIps, ctx.Err()
+		}
+		if err == nil {
+			wildcardIps.AddRange(ips)
+		}
+	}
+	if wildcardIps.Length() == 0 {
+		return wildcardIps, nil
+	}
+
+	prefix := "Wildcard DNS found."
+	if name != d.domain {
+		prefix = fmt.Sprintf("Wildcard DNS found for %s.", name)
+	}
+	message := fmt.Sprintf("%s IP address(es): %s, names resolving to them are filtered", prefix, wildcardIps.Stringify())
+	if d.options.ShowWildcard {
+		message = fmt.Sprintf("%s IP address(es): %s, names resolving to them are marked", prefix, wildcardIps.Stringify())
+	}
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelInfo,
+		Message: message,
+	}
+	return wildcardIps, nil
+}
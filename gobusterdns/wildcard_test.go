@@ -0,0 +1,115 @@
+package gobusterdns
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// wildcardResolver answers every name with one address of the wildcard pool
+// in turn, except the names of records
+type wildcardResolver struct {
+	mu      sync.Mutex
+	pool    []netip.Addr
+	next    int
+	records map[string]netip.Addr
+}
+
+func (r *wildcardResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ip, ok := r.records[strings.TrimSuffix(host, ".")]; ok {
+		return []netip.Addr{ip}, nil
+	}
+	if len(r.pool) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	ip := r.pool[r.next%len(r.pool)]
+	r.next++
+	return []netip.Addr{ip}, nil
+}
+
+func (r *wildcardResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	return host, nil
+}
+
+func TestWildcard(t *testing.T) {
+	t.Parallel()
+
+	pool := []netip.Addr{netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("192.0.2.2")}
+	tt := []struct {
+		testName     string
+		pool         []netip.Addr
+		showWildcard bool
+		expected     string
+	}{
+		{"No wildcard", nil, false, "www.example.com,missing.example.com"},
+		{"Filtered", pool, false, "www.example.com"},
+		{"Shown", pool, true, "www.example.com,missing.example.com [wildcard],other.example.com [wildcard]"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			opts := NewOptionsDNS()
+			opts.Domain = "example.com"
+			opts.ShowWildcard = x.showWildcard
+			globalopts := libgobuster.NewOptions()
+			globalopts.Quiet = true
+			d, err := NewGobusterDNS(globalopts, opts)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			records := map[string]netip.Addr{"www.example.com": netip.MustParseAddr("198.51.100.1")}
+			if x.pool == nil {
+				// without a wildcard the second word is a record
+				records["missing.example.com"] = netip.MustParseAddr("198.51.100.2")
+			}
+			d.resolver = &wildcardResolver{pool: x.pool, records: records}
+
+			progress := libgobuster.NewProgress()
+			done := make(chan []string)
+			go func() {
+				var results []string
+				for r := range progress.ResultChan {
+					res := r.(Result)
+					name := strings.TrimSuffix(res.Subdomain, ".")
+					if res.Wildcard {
+						name += " [wildcard]"
+					}
+					results = append(results, name)
+				}
+				done <- results
+			}()
+			go func() {
+				for range progress.MessageChan {
+				}
+			}()
+
+			if err := d.PreRun(context.Background(), progress); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if x.pool != nil && d.wildcardIps.Length() != len(x.pool) {
+				t.Fatalf("expected the probes to find all %d wildcard addresses, got %s", len(x.pool), d.wildcardIps.Stringify())
+			}
+			for _, word := range []string{"www", "missing", "other"} {
+				if err := d.ProcessWord(context.Background(), word, progress); err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+			}
+			close(progress.ResultChan)
+			close(progress.MessageChan)
+
+			if results := strings.Join(<-done, ","); results != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, results)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package gobusterfuzz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"golang.org/x/net/html"
+)
+
+const (
+	// CSRFPlaceholder is replaced with the CSRF token of the form page
+	CSRFPlaceholder = "{{csrf}}"
+	// CSRFPlaceholderEncoded is replaced with the url encoded CSRF token,
+	// for tokens sent in form bodies and query strings
+	CSRFPlaceholderEncoded = "{{csrf_urlencoded}}"
+)
+
+// csrfSource fetches the CSRF token from a form page. A token and the
+// cookies set with it are used for refresh requests before the page is
+// fetched again, forever if refresh is 0
+type csrfSource struct {
+	http    *libgobuster.HTTPClient
+	url     string
+	pattern *regexp.Regexp
+	field   string
+	refresh int
+	// baseCookies are the cookies of the options
+	baseCookies string
+
+	mutex   sync.Mutex
+	token   string
+	cookies []*http.Cookie
+	uses    int
+}
+
+func newCSRFSource(client *libgobuster.HTTPClient, opts *OptionsFuzz) (*csrfSource, error) {
+	s := csrfSource{
+		http:    client,
+		url:     opts.CSRFURL,
+		field:   opts.CSRFField,
+		refresh: opts.CSRFRefresh,
+
+		baseCookies: opts.Cookies,
+	}
+	if opts.CSRFPattern != "" {
+		var err error
+		s.pattern, err = compileCSRFPattern(opts.CSRFPattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// compileCSRFPattern compiles the pattern, its first group is the token
+func compileCSRFPattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSRF pattern: %w", err)
+	}
+	if re.NumSubexp() < 1 {
+		return nil, fmt.Errorf("the CSRF pattern %q needs a group matching the token", pattern)
+	}
+	return re, nil
+}
+
+// load fetches the first token, so a page without a token fails the run
+// before the first word
+func (s *csrfSource) load(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.fetch(ctx)
+}
+
+// get returns the current token and its cookies, the token is fetched
+// again once it was used for refresh requests
+func (s *csrfSource) get(ctx context.Context) (string, []*http.Cookie, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.token == "" || (s.refresh > 0 && s.uses >= s.refresh) {
+		if err := s.fetch(ctx); err != nil {
+			return "", nil, err
+		}
+	}
+	s.uses++
+	return s.token, s.cookies, nil
+}
+
+// fetch requests the form page and extracts the token. The cookies of the
+// previous token are sent along, so the session stays the same
+func (s *csrfSource) fetch(ctx context.Context) error {
+	opts := libgobuster.RequestOptions{Method: http.MethodGet, ReturnBody: true}
+	if len(s.cookies) > 0 {
+		opts.UpdatedCookies = mergeCookies(s.baseCookies, s.cookies)
+	}
+	status, _, header, body, err := s.http.Request(ctx, s.url, opts)
+	if err != nil {
+		return fmt.Errorf("could not fetch the CSRF token from %s: %w", s.url, err)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	token := s.extract(body)
+	if token == "" {
+		return fmt.Errorf("no CSRF token found on %s (status %d)", s.url, status)
+	}
+	s.token = token
+	s.uses = 0
+	for _, c := range (&http.Response{Header: header}).Cookies() {
+		s.setCookie(c)
+	}
+	return nil
+}
+
+// setCookie stores the cookie, replacing a cookie with the same name
+func (s *csrfSource) setCookie(cookie *http.Cookie) {
+	for i, c := range s.cookies {
+		if c.Name == cookie.Name {
+			s.cookies[i] = cookie
+			return
+		}
+	}
+	s.cookies = append(s.cookies, cookie)
+}
+
+// extract returns the token of the page, empty if there is none
+func (s *csrfSource) extract(body []byte) string {
+	if s.pattern != nil {
+		if match := s.pattern.FindSubmatch(body); match != nil {
+			return string(match[1])
+		}
+		return ""
+	}
+	return fieldValue(body, s.field)
+}
+
+// fieldValue returns the value of the input or the content of the meta
+// element named name, the two places forms and frameworks put their tokens
+func fieldValue(body []byte, name string) string {
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			var valueAttr string
+			switch t.Data {
+			case "input":
+				valueAttr = "value"
+			case "meta":
+				valueAttr = "content"
+			default:
+				continue
+			}
+			var matches bool
+			var value string
+			for _, a := range t.Attr {
+				switch a.Key {
+				case "name":
+					matches = a.Val == name
+				case valueAttr:
+					value = a.Val
+				}
+			}
+			if matches && value != "" {
+				return value
+			}
+		}
+	}
+}
+
+// mergeCookies adds the cookies to the cookie header, cookies with the same
+// name are replaced
+func mergeCookies(header string, cookies []*http.Cookie) string {
+	set := libgobuster.NewSet[string]()
+	for _, c := range cookies {
+		set.Add(c.Name)
+	}
+	var parts []string
+	for _, part := range strings.Split(header, ";") {
+		part = strings.TrimSpace(part)
+		name, _, _ := strings.Cut(part, "=")
+		if part == "" || set.Contains(name) {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	for _, c := range cookies {
+		parts = append(parts, fmt.Sprintf("%s=%s", c.Name, c.Value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// csrfReplacements returns the replacements of the placeholders for the token
+func csrfReplacements(token string) []string {
+	return []string{CSRFPlaceholderEncoded, url.QueryEscape(token), CSRFPlaceholder, token}
+}
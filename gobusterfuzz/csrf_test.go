@@ -0,0 +1,203 @@
+package gobusterfuzz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestFieldValue(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		body     string
+		expected string
+	}{
+		{"Input", `<form><input type="hidden" name="csrf_token" value="abc"><input name="user"></form>`, "abc"},
+		{"Self closing input", `<input value="abc" name="csrf_token"/>`, "abc"},
+		{"Meta", `<head><meta name="csrf_token" content="abc"></head>`, "abc"},
+		{"Other name", `<input name="token" value="abc">`, ""},
+		{"Empty value", `<input name="csrf_token" value=""><input name="csrf_token" value="abc">`, "abc"},
+		{"No token", `<html><body>login</body></html>`, ""},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if v := fieldValue([]byte(x.body), "csrf_token"); v != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, v)
+			}
+		})
+	}
+}
+
+func TestCompileCSRFPattern(t *testing.T) {
+	t.Parallel()
+
+	if _, err := compileCSRFPattern(`value="([^"]+)"`); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if _, err := compileCSRFPattern(`value="[^"]+"`); err == nil {
+		t.Fatal("expected an error for a pattern without a group")
+	}
+	if _, err := compileCSRFPattern(`value="(`); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+}
+
+func TestMergeCookies(t *testing.T) {
+	t.Parallel()
+
+	cookies := []*http.Cookie{{Name: "session", Value: "new"}}
+	if c := mergeCookies("lang=en; session=old", cookies); c != "lang=en; session=new" {
+		t.Fatalf("unexpected cookies %q", c)
+	}
+	if c := mergeCookies("", cookies); c != "session=new" {
+		t.Fatalf("unexpected cookies %q", c)
+	}
+}
+
+// csrfServer issues a new token with every form page and accepts a login
+// only with the latest token and the session cookie it was issued for
+func csrfServer(t *testing.T) (*httptest.Server, func() int) {
+	t.Helper()
+
+	var mutex sync.Mutex
+	var forms int
+	var token, session string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.URL.Path {
+		case "/form":
+			forms++
+			if c, err := r.Cookie("session"); err == nil {
+				session = c.Value
+			} else {
+				session = fmt.Sprintf("s%d", forms)
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: session})
+			}
+			token = fmt.Sprintf("t+%d/=", forms)
+			fmt.Fprintf(w, `<form method="post"><input type="hidden" name="csrf_token" value="%s"></form>`, token)
+		case "/login":
+			c, err := r.Cookie("session")
+			if err != nil || c.Value != session || r.PostFormValue("csrf_token") != token {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if r.PostFormValue("user") != "admin" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return forms
+	}
+}
+
+func TestCSRFProcessWord(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		pattern  string
+		field    string
+		refresh  int
+		forms    int
+	}{
+		{"Field", "", "csrf_token", 0, 1},
+		{"Regex with refresh", `name="csrf_token" value="([^"]+)"`, "", 2, 2},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			ts, forms := csrfServer(t)
+
+			globalopts := libgobuster.NewOptions()
+			o := NewOptionsFuzz()
+			o.URL = ts.URL + "/login"
+			o.Method = http.MethodPost
+			o.Timeout = 5 * time.Second
+			o.Headers = []libgobuster.HTTPHeader{{Name: "Content-Type", Value: "application/x-www-form-urlencoded"}}
+			o.RequestBody = "user=FUZZ&csrf_token=" + CSRFPlaceholderEncoded
+			o.Cookies = "lang=en"
+			o.CSRFURL = ts.URL + "/form"
+			o.CSRFPattern = x.pattern
+			o.CSRFField = x.field
+			o.CSRFRefresh = x.refresh
+			f, err := NewGobusterFuzz(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			progress := libgobuster.NewProgress()
+			done := make(chan []Result)
+			go func() {
+				var results []Result
+				for r := range progress.ResultChan {
+					results = append(results, r.(Result))
+				}
+				done <- results
+			}()
+			if err := f.PreRun(context.Background(), progress); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			for _, word := range []string{"guest", "admin", "root"} {
+				if err := f.ProcessWord(context.Background(), word, progress); err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+			}
+			close(progress.ResultChan)
+
+			results := <-done
+			if len(results) != 3 {
+				t.Fatalf("expected 3 results, got %+v", results)
+			}
+			for _, r := range results {
+				expected := http.StatusUnauthorized
+				if r.Word == "admin" {
+					expected = http.StatusOK
+				}
+				if r.StatusCode != expected {
+					t.Fatalf("expected status %d for %s, got %d", expected, r.Word, r.StatusCode)
+				}
+			}
+			if n := forms(); n != x.forms {
+				t.Fatalf("expected the form to be fetched %d times, got %d", x.forms, n)
+			}
+		})
+	}
+}
+
+func TestCSRFMissingToken(t *testing.T) {
+	t.Parallel()
+	ts, _ := csrfServer(t)
+
+	o := NewOptionsFuzz()
+	o.URL = ts.URL + "/login"
+	o.Timeout = 5 * time.Second
+	o.CSRFURL = ts.URL + "/missing"
+	o.CSRFField = "csrf_token"
+	f, err := NewGobusterFuzz(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := f.PreRun(context.Background(), libgobuster.NewProgress()); err == nil {
+		t.Fatal("expected an error for a page without a token")
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"net"
+	"net/http"
 	"strings"
 	"text/tabwriter"
 
@@ -30,6 +30,7 @@ type GobusterFuzz struct {
 	options    *OptionsFuzz
 	globalopts *libgobuster.Options
 	http       *libgobuster.HTTPClient
+	csrf       *csrfSource
 }
 
 // NewGobusterFuzz creates a new initialized GobusterFuzz
@@ -49,12 +50,17 @@ func NewGobusterFuzz(globalopts *libgobuster.Options, opts *OptionsFuzz) (*Gobus
 
 	basicOptions := libgobuster.BasicHTTPOptions{
 		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
 		Timeout:         opts.Timeout,
 		UserAgent:       opts.UserAgent,
 		NoTLSValidation: opts.NoTLSValidation,
 		RetryOnTimeout:  opts.RetryOnTimeout,
 		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
 		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
 	}
 
 	httpOpts := libgobuster.HTTPOptions{
@@ -64,7 +70,11 @@ func NewGobusterFuzz(globalopts *libgobuster.Options, opts *OptionsFuzz) (*Gobus
 		Password:              opts.Password,
 		Headers:               opts.Headers,
 		NoCanonicalizeHeaders: opts.NoCanonicalizeHeaders,
+		CacheBypass:           opts.CacheBypass,
 		Cookies:               opts.Cookies,
+		Script:                globalopts.Script,
+		Throttle:              globalopts.Throttle,
+		ConnStats:             globalopts.ConnStats,
 		Method:                opts.Method,
 	}
 
@@ -73,6 +83,13 @@ func NewGobusterFuzz(globalopts *libgobuster.Options, opts *OptionsFuzz) (*Gobus
 		return nil, err
 	}
 	g.http = h
+
+	if opts.CSRFURL != "" {
+		g.csrf, err = newCSRFSource(h, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &g, nil
 }
 
@@ -81,65 +98,98 @@ func (d *GobusterFuzz) Name() string {
 	return "fuzzing"
 }
 
+// Target returns the target of the run
+func (d *GobusterFuzz) Target() string {
+	return d.options.URL
+}
+
 // PreRun is the pre run implementation of gobusterfuzz
 func (d *GobusterFuzz) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	if d.csrf != nil {
+		return d.csrf.load(ctx)
+	}
 	return nil
 }
 
 // ProcessWord is the process implementation of gobusterfuzz
 func (d *GobusterFuzz) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
-	url := strings.ReplaceAll(d.options.URL, FuzzKeyword, word)
+	// words combined from multiple wordlists contain one part per keyword
+	parts := libgobuster.SplitWord(word)
+	replacements := []string{FuzzKeyword, d.options.Encoders[FuzzKeyword].Encode(parts[0])}
+	for i, w := range d.globalopts.ExtraWordlists {
+		if i+1 < len(parts) {
+			replacements = append(replacements, w.Keyword, d.options.Encoders[w.Keyword].Encode(parts[i+1]))
+		}
+	}
+	var csrfCookies []*http.Cookie
+	if d.csrf != nil {
+		token, cookies, err := d.csrf.get(ctx)
+		if err != nil {
+			return err
+		}
+		replacements = append(replacements, csrfReplacements(token)...)
+		csrfCookies = cookies
+	}
+	replacer := strings.NewReplacer(replacements...)
+	word = strings.Join(parts, ",")
+
+	url := replacer.Replace(d.options.URL)
 
 	requestOptions := libgobuster.RequestOptions{}
 
+	if method := replacer.Replace(d.options.Method); method != d.options.Method {
+		requestOptions.Method = method
+	}
+
+	cookies := replacer.Replace(d.options.Cookies)
+	// the token is only valid with the session cookies it was issued for
+	if len(csrfCookies) > 0 {
+		cookies = mergeCookies(cookies, csrfCookies)
+	}
+	if cookies != d.options.Cookies {
+		requestOptions.UpdatedCookies = cookies
+	}
+
 	if len(d.options.Headers) > 0 {
 		requestOptions.ModifiedHeaders = make([]libgobuster.HTTPHeader, len(d.options.Headers))
 		for i := range d.options.Headers {
 			requestOptions.ModifiedHeaders[i] = libgobuster.HTTPHeader{
-				Name:  strings.ReplaceAll(d.options.Headers[i].Name, FuzzKeyword, word),
-				Value: strings.ReplaceAll(d.options.Headers[i].Value, FuzzKeyword, word),
+				Name:  replacer.Replace(d.options.Headers[i].Name),
+				Value: replacer.Replace(d.options.Headers[i].Value),
+			}
+			// the host can not be set as normal header
+			if strings.EqualFold(requestOptions.ModifiedHeaders[i].Name, "Host") {
+				requestOptions.Host = requestOptions.ModifiedHeaders[i].Value
 			}
 		}
 	}
 
 	if d.options.RequestBody != "" {
-		data := strings.ReplaceAll(d.options.RequestBody, FuzzKeyword, word)
+		data := replacer.Replace(d.options.RequestBody)
 		buffer := strings.NewReader(data)
 		requestOptions.Body = buffer
 	}
 
 	// fuzzing of basic auth
-	if strings.Contains(d.options.Username, FuzzKeyword) || strings.Contains(d.options.Password, FuzzKeyword) {
-		requestOptions.UpdatedBasicAuthUsername = strings.ReplaceAll(d.options.Username, FuzzKeyword, word)
-		requestOptions.UpdatedBasicAuthPassword = strings.ReplaceAll(d.options.Password, FuzzKeyword, word)
+	if username, password := replacer.Replace(d.options.Username), replacer.Replace(d.options.Password); username != d.options.Username || password != d.options.Password {
+		requestOptions.UpdatedBasicAuthUsername = username
+		requestOptions.UpdatedBasicAuthPassword = password
 	}
 
-	tries := 1
-	if d.options.RetryOnTimeout && d.options.RetryAttempts > 0 {
-		// add it so it will be the overall max requests
-		tries += d.options.RetryAttempts
-	}
+	var timing libgobuster.RequestTiming
+	requestOptions.Timing = &timing
 
 	var statusCode int
 	var size int64
-	for i := 1; i <= tries; i++ {
-		var err error
-		statusCode, size, _, _, err = d.http.Request(ctx, url, requestOptions)
-		if err != nil {
-			// check if it's a timeout and if we should try again and try again
-			// otherwise the timeout error is raised
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && i != tries {
-				continue
-			} else if strings.Contains(err.Error(), "invalid control character in URL") {
-				// put error in error chan so it's printed out and ignore it
-				// so gobuster will not quit
-				progress.ErrorChan <- err
-				continue
-			} else {
-				return err
-			}
+	var err error
+	statusCode, size, _, _, err = d.http.Request(ctx, url, requestOptions)
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return err
 		}
-		break
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
 	}
 
 	if statusCode != 0 {
@@ -163,12 +213,19 @@ func (d *GobusterFuzz) ProcessWord(ctx context.Context, word string, progress *l
 				StatusCode: statusCode,
 				Size:       size,
 				Word:       word,
+				Timing:     timing,
+				ShowTiming: d.globalopts.ShowTiming,
 			}
 		}
 	}
 	return nil
 }
 
+// Warnings returns warnings about potentially destructive or noisy configurations
+func (d *GobusterFuzz) Warnings() []string {
+	return libgobuster.HTTPWarnings(&d.options.HTTPOptions, d.globalopts)
+}
+
 func (d *GobusterFuzz) AdditionalWords(word string) []string {
 	return []string{}
 }
@@ -187,6 +244,12 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 		return "", err
 	}
 
+	if o.RequestFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Request file:\t%s\n", o.RequestFile); err != nil {
+			return "", err
+		}
+	}
+
 	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", d.globalopts.Threads); err != nil {
 		return "", err
 	}
@@ -199,18 +262,52 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 
 	wordlist := "stdin (pipe)"
 	if d.globalopts.Wordlist != "-" {
-		wordlist = d.globalopts.Wordlist
+		wordlist = d.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
 	}
 
+	for _, w := range d.globalopts.ExtraWordlists {
+		if _, err := fmt.Fprintf(tw, "[+] Wordlist %s:\t%s\n", w.Keyword, w.Filename); err != nil {
+			return "", err
+		}
+	}
+
+	if len(d.globalopts.ExtraWordlists) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Strategy:\t%s\n", d.globalopts.Strategy); err != nil {
+			return "", err
+		}
+	}
+
+	for _, keyword := range append([]string{FuzzKeyword}, extraKeywords(d.globalopts)...) {
+		if c, ok := o.Encoders[keyword]; ok {
+			if _, err := fmt.Fprintf(tw, "[+] Encoders %s:\t%s\n", keyword, c); err != nil {
+				return "", err
+			}
+		}
+	}
+
 	if d.globalopts.PatternFile != "" {
 		if _, err := fmt.Fprintf(tw, "[+] Patterns:\t%s (%d entries)\n", d.globalopts.PatternFile, len(d.globalopts.Patterns)); err != nil {
 			return "", err
 		}
 	}
 
+	if o.CSRFURL != "" {
+		source := fmt.Sprintf("field %s", o.CSRFField)
+		if o.CSRFPattern != "" {
+			source = fmt.Sprintf("regex %s", o.CSRFPattern)
+		}
+		refresh := "never refreshed"
+		if o.CSRFRefresh > 0 {
+			refresh = fmt.Sprintf("refreshed every %d requests", o.CSRFRefresh)
+		}
+		if _, err := fmt.Fprintf(tw, "[+] CSRF token:\t%s of %s, %s\n", source, o.CSRFURL, refresh); err != nil {
+			return "", err
+		}
+	}
+
 	if o.ExcludedStatusCodesParsed.Length() > 0 {
 		if _, err := fmt.Fprintf(tw, "[+] Excluded Status codes:\t%s\n", o.ExcludedStatusCodesParsed.Stringify()); err != nil {
 			return "", err
@@ -218,7 +315,7 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 	}
 
 	if len(o.ExcludeLength) > 0 {
-		if _, err := fmt.Fprintf(tw, "[+] Exclude Length:\t%s\n", d.options.ExcludeLengthParsed.Stringify()); err != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Exclude Length:\t%s\n", d.options.ExcludeLengthParsed.String()); err != nil {
 			return "", err
 		}
 	}
@@ -229,6 +326,12 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 		}
 	}
 
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
 	if o.Cookies != "" {
 		if _, err := fmt.Fprintf(tw, "[+] Cookies:\t%s\n", o.Cookies); err != nil {
 			return "", err
@@ -253,6 +356,12 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 		}
 	}
 
+	if o.CacheBypass {
+		if _, err := fmt.Fprintf(tw, "[+] Cache bypass:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
 	if d.globalopts.Verbose {
 		if _, err := fmt.Fprintf(tw, "[+] Verbose:\ttrue\n"); err != nil {
 			return "", err
@@ -263,6 +372,18 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 		return "", err
 	}
 
+	if d.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", d.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	if d.globalopts.Script != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Script:\t%s\n", d.globalopts.Script); err != nil {
+			return "", err
+		}
+	}
+
 	if err := tw.Flush(); err != nil {
 		return "", fmt.Errorf("error on tostring: %w", err)
 	}
@@ -273,3 +394,12 @@ func (d *GobusterFuzz) GetConfigString() (string, error) {
 
 	return strings.TrimSpace(buffer.String()), nil
 }
+
+// extraKeywords returns the keywords of all extra wordlists
+func extraKeywords(globalopts *libgobuster.Options) []string {
+	ret := make([]string, 0, len(globalopts.ExtraWordlists))
+	for _, w := range globalopts.ExtraWordlists {
+		ret = append(ret, w.Keyword)
+	}
+	return ret
+}
@@ -1,6 +1,8 @@
 package gobusterfuzz
 
 import (
+	"fmt"
+
 	"github.com/OJ/gobuster/v3/libgobuster"
 )
 
@@ -10,14 +12,69 @@ type OptionsFuzz struct {
 	ExcludedStatusCodes       string
 	ExcludedStatusCodesParsed libgobuster.Set[int]
 	ExcludeLength             string
-	ExcludeLengthParsed       libgobuster.Set[int]
+	ExcludeLengthParsed       libgobuster.IntRanges
 	RequestBody               string
+	// RequestFile is the raw request the method, url, headers and body
+	// were loaded from
+	RequestFile string
+	// Encoders maps the keywords to the encoders applied to their words
+	Encoders map[string]libgobuster.EncoderChain
+	// CSRFURL is the form page the CSRF token is fetched from
+	CSRFURL string
+	// CSRFPattern is a regular expression, its first group is the token
+	CSRFPattern string
+	// CSRFField is the name of the input or meta element holding the token
+	CSRFField string
+	// CSRFRefresh is the number of requests a token is used for, 0 uses
+	// the first token for all requests
+	CSRFRefresh int
 }
 
 // NewOptionsFuzz returns a new initialized OptionsFuzz
 func NewOptionsFuzz() *OptionsFuzz {
 	return &OptionsFuzz{
 		ExcludedStatusCodesParsed: libgobuster.NewSet[int](),
-		ExcludeLengthParsed:       libgobuster.NewSet[int](),
+		Encoders:                  make(map[string]libgobuster.EncoderChain),
+	}
+}
+
+// ValidationProblems returns all problems of the fuzz options
+func (opt *OptionsFuzz) ValidationProblems() []libgobuster.ValidationProblem {
+	problems := opt.HTTPOptions.ValidationProblems()
+
+	if opt.CSRFURL == "" && (opt.CSRFPattern != "" || opt.CSRFField != "") {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "csrf-url",
+			Problem:    "csrf-regex or csrf-field is set without csrf-url",
+			Suggestion: "set the page containing the token with --csrf-url",
+		})
+	}
+
+	if opt.CSRFURL != "" && (opt.CSRFPattern == "") == (opt.CSRFField == "") {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "csrf-url",
+			Problem:    "csrf-url needs either csrf-regex or csrf-field",
+			Suggestion: "set exactly one of --csrf-regex and --csrf-field",
+		})
+	}
+
+	if opt.CSRFPattern != "" {
+		if _, err := compileCSRFPattern(opt.CSRFPattern); err != nil {
+			problems = append(problems, libgobuster.ValidationProblem{
+				Option:     "csrf-regex",
+				Problem:    err.Error(),
+				Suggestion: "use a regular expression with a group around the token like name=\"token\" value=\"([^\"]+)\"",
+			})
+		}
 	}
+
+	if opt.CSRFRefresh < 0 {
+		problems = append(problems, libgobuster.ValidationProblem{
+			Option:     "csrf-refresh",
+			Problem:    fmt.Sprintf("csrf-refresh (%d) is negative", opt.CSRFRefresh),
+			Suggestion: "use 0 to keep the first token or the number of requests per token",
+		})
+	}
+
+	return problems
 }
@@ -3,6 +3,7 @@ package gobusterfuzz
 import (
 	"bytes"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
@@ -19,6 +20,8 @@ type Result struct {
 	Path       string
 	StatusCode int
 	Size       int64
+	Timing     libgobuster.RequestTiming
+	ShowTiming bool
 }
 
 // ResultToString converts the Result to it's textual representation
@@ -39,9 +42,25 @@ func (r Result) ResultToString() (string, error) {
 		c(buf, "Found: ")
 	}
 
-	c(buf, "[Status=%d] [Length=%d] [Word=%s] %s", r.StatusCode, r.Size, r.Word, r.Path)
+	c(buf, "[Status=%d] [Length=%d] ", r.StatusCode, r.Size)
+	if r.ShowTiming {
+		c(buf, "[Time=%dms] [TTFB=%dms] ", r.Timing.Duration.Milliseconds(), r.Timing.TTFB.Milliseconds())
+	}
+	c(buf, "[Word=%s] %s", r.Word, r.Path)
 	c(buf, "\n")
 
 	s := buf.String()
 	return s, nil
 }
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	return libgobuster.ResultRecord{
+		URL:        r.Path,
+		Path:       r.Path,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Size:       r.Size,
+		Timing:     r.Timing,
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
 	"regexp"
 	"strings"
@@ -21,6 +20,8 @@ type GobusterGCS struct {
 	globalopts  *libgobuster.Options
 	http        *libgobuster.HTTPClient
 	bucketRegex *regexp.Regexp
+	// baseURL is the json api endpoint, changed by the tests
+	baseURL string
 }
 
 // NewGobusterGCS creates a new initialized GobusterGCS
@@ -36,20 +37,29 @@ func NewGobusterGCS(globalopts *libgobuster.Options, opts *OptionsGCS) (*Gobuste
 	g := GobusterGCS{
 		options:    opts,
 		globalopts: globalopts,
+		baseURL:    "https://storage.googleapis.com/storage/v1",
 	}
 
 	basicOptions := libgobuster.BasicHTTPOptions{
 		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
 		Timeout:         opts.Timeout,
 		UserAgent:       opts.UserAgent,
 		NoTLSValidation: opts.NoTLSValidation,
 		RetryOnTimeout:  opts.RetryOnTimeout,
 		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
 		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
 	}
 
 	httpOpts := libgobuster.HTTPOptions{
 		BasicHTTPOptions: basicOptions,
+		Headers:          opts.Headers,
+		Throttle:         globalopts.Throttle,
+		ConnStats:        globalopts.ConnStats,
 		// needed so we can list bucket contents
 		FollowRedirect: true,
 	}
@@ -70,65 +80,49 @@ func (s *GobusterGCS) Name() string {
 	return "GCS bucket enumeration"
 }
 
-// PreRun is the pre run implementation of GobusterS3
+// PreRun is the pre run implementation of GobusterGCS
 func (s *GobusterGCS) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
 	return nil
 }
 
-// ProcessWord is the process implementation of GobusterS3
+// ProcessWord is the process implementation of GobusterGCS
 func (s *GobusterGCS) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
 	// only check for valid bucket names
 	if !s.isValidBucketName(word) {
 		return nil
 	}
 
-	bucketURL := fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o?maxResults=%d", word, s.options.MaxFilesToList)
-
-	tries := 1
-	if s.options.RetryOnTimeout && s.options.RetryAttempts > 0 {
-		// add it so it will be the overall max requests
-		tries += s.options.RetryAttempts
-	}
+	bucketURL := fmt.Sprintf("%s/b/%s/o?maxResults=%d", s.baseURL, word, s.options.MaxFilesToList)
 
 	var statusCode int
 	var body []byte
-	for i := 1; i <= tries; i++ {
-		var err error
-		statusCode, _, _, body, err = s.http.Request(ctx, bucketURL, libgobuster.RequestOptions{ReturnBody: true})
-		if err != nil {
-			// check if it's a timeout and if we should try again and try again
-			// otherwise the timeout error is raised
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && i != tries {
-				continue
-			} else if strings.Contains(err.Error(), "invalid control character in URL") {
-				// put error in error chan so it's printed out and ignore it
-				// so gobuster will not quit
-				progress.ErrorChan <- err
-				continue
-			} else {
-				return err
-			}
+	var err error
+	statusCode, _, _, body, err = s.http.Request(ctx, bucketURL, libgobuster.RequestOptions{ReturnBody: true})
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return err
 		}
-		break
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
 	}
 
 	if statusCode == 0 || body == nil {
 		return nil
 	}
 
-	// looks like 401, 403, and 404 are the only negative status codes
-	found := false
+	// 404 is returned for missing buckets and 400 for invalid names. A
+	// private bucket exists too, anonymous requests get a 401 and
+	// authenticated ones without access a 403
+	found := true
+	listable := false
 	switch statusCode {
-	case http.StatusUnauthorized,
-		http.StatusForbidden,
+	case http.StatusBadRequest,
 		http.StatusNotFound:
 		found = false
 	case http.StatusOK:
 		// listing enabled
-		found = true
-	default:
-		// default to found as we use negative status codes
-		found = true
+		listable = true
 	}
 
 	// nothing found, bail out
@@ -173,8 +167,10 @@ func (s *GobusterGCS) ProcessWord(ctx context.Context, word string, progress *li
 
 	progress.ResultChan <- Result{
 		Found:      found,
+		Listable:   listable,
 		BucketName: word,
 		Status:     extraStr,
+		StatusCode: statusCode,
 	}
 
 	return nil
@@ -201,9 +197,15 @@ func (s *GobusterGCS) GetConfigString() (string, error) {
 		}
 	}
 
+	if s.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", s.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
 	wordlist := "stdin (pipe)"
 	if s.globalopts.Wordlist != "-" {
-		wordlist = s.globalopts.Wordlist
+		wordlist = s.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
@@ -221,6 +223,12 @@ func (s *GobusterGCS) GetConfigString() (string, error) {
 		}
 	}
 
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
 	if o.UserAgent != "" {
 		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
 			return "", err
@@ -252,7 +260,7 @@ func (s *GobusterGCS) GetConfigString() (string, error) {
 	return strings.TrimSpace(buffer.String()), nil
 }
 
-// https://docs.aws.amazon.com/AmazonS3/latest/dev/BucketRestrictions.html
+// https://cloud.google.com/storage/docs/buckets#naming
 func (s *GobusterGCS) isValidBucketName(bucketName string) bool {
 	if len(bucketName) > 222 || !s.bucketRegex.MatchString(bucketName) {
 		return false
@@ -0,0 +1,113 @@
+package gobustergcs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/OJ/gobuster/v3/libgobuster/plugintest"
+)
+
+// gcsServer serves the object listing of the storage api
+func gcsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/b/public-bucket/o", "/b/backup/o":
+			fmt.Fprint(w, `{"kind":"storage#objects","items":[{"name":"backup.zip","size":"42"}]}`)
+		case "/b/private-bucket/o":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":401,"message":"Anonymous caller does not have storage.objects.list access"}}`)
+		case "/b/denied-bucket/o":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":{"code":403,"message":"caller does not have storage.objects.list access"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":404,"message":"The specified bucket does not exist."}}`)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	ts := gcsServer(t)
+
+	plugintest.Run(t, func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin {
+		o := NewOptionsGCS()
+		o.Timeout = 5 * time.Second
+		o.MaxFilesToList = 5
+		g, err := NewGobusterGCS(globalopts, o)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		g.baseURL = ts.URL
+		return g
+	})
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+	ts := gcsServer(t)
+
+	tt := []struct {
+		testName string
+		verbose  bool
+		expected string
+	}{
+		{"Default", false, "public-bucket listable,private-bucket private,denied-bucket private"},
+		{"Verbose", true, "public-bucket listable Bucket Listing enabled: backup.zip (42b)," +
+			"private-bucket private Error: Anonymous caller does not have storage.objects.list access (401)," +
+			"denied-bucket private Error: caller does not have storage.objects.list access (403)"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			globalopts := libgobuster.NewOptions()
+			globalopts.Verbose = x.verbose
+			o := NewOptionsGCS()
+			o.Timeout = 5 * time.Second
+			o.MaxFilesToList = 5
+			g, err := NewGobusterGCS(globalopts, o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			g.baseURL = ts.URL
+
+			progress := libgobuster.NewProgress()
+			done := make(chan []string)
+			go func() {
+				var results []string
+				for r := range progress.ResultChan {
+					res := r.(Result)
+					access := "private"
+					if res.Listable {
+						access = "listable"
+					}
+					results = append(results, strings.TrimSpace(fmt.Sprintf("%s %s %s", res.BucketName, access, res.Status)))
+				}
+				done <- results
+			}()
+			for _, word := range []string{"public-bucket", "private-bucket", "denied-bucket", "missing-bucket", "-invalid"} {
+				if err := g.ProcessWord(context.Background(), word, progress); err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+			}
+			close(progress.ResultChan)
+
+			if results := strings.Join(<-done, ","); results != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, results)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 // OptionsGCS is the struct to hold all options for this plugin
 type OptionsGCS struct {
 	libgobuster.BasicHTTPOptions
+	// Headers are sent with every request
+	Headers        []libgobuster.HTTPHeader
 	MaxFilesToList int
 }
 
@@ -2,28 +2,40 @@ package gobustergcs
 
 import (
 	"bytes"
+	"fmt"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
 var (
-	green = color.New(color.FgGreen).FprintfFunc()
+	green  = color.New(color.FgGreen).FprintfFunc()
+	yellow = color.New(color.FgYellow).FprintfFunc()
 )
 
 // Result represents a single result
 type Result struct {
-	Found      bool
+	Found bool
+	// Listable is set if the objects of the bucket can be listed
+	// anonymously, otherwise the bucket exists but is private
+	Listable   bool
 	BucketName string
 	Status     string
+	StatusCode int
 }
 
 // ResultToString converts the Result to it's textual representation
 func (r Result) ResultToString() (string, error) {
 	buf := &bytes.Buffer{}
 
-	c := green
+	c := yellow
+	access := "private"
+	if r.Listable {
+		c = green
+		access = "listable"
+	}
 
-	c(buf, "https://storage.googleapis.com/storage/v1/b/%s/o", r.BucketName)
+	c(buf, "https://storage.googleapis.com/storage/v1/b/%s/o [%s]", r.BucketName, access)
 
 	if r.Status != "" {
 		c(buf, " [%s]", r.Status)
@@ -33,3 +45,18 @@ func (r Result) ResultToString() (string, error) {
 	str := buf.String()
 	return str, nil
 }
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	access := "private"
+	if r.Listable {
+		access = "listable"
+	}
+	return libgobuster.ResultRecord{
+		URL:        fmt.Sprintf("https://storage.googleapis.com/storage/v1/b/%s/o", r.BucketName),
+		Path:       r.BucketName,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Tags:       []string{access},
+	}
+}
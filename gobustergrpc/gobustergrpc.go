@@ -0,0 +1,193 @@
+package gobustergrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/OJ/gobuster/v3/gobustergrpc/pluginpb"
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// GobusterGRPC is the main type to implement the interface
+type GobusterGRPC struct {
+	options    *OptionsGRPC
+	globalopts *libgobuster.Options
+	plugin     *pluginProcess
+	// name and config are returned by the plugin in Configure
+	name   string
+	config []*pluginpb.ConfigEntry
+}
+
+// NewGobusterGRPC starts and configures the plugin. Close needs to be called
+// to stop the plugin again
+func NewGobusterGRPC(globalopts *libgobuster.Options, opts *OptionsGRPC) (*GobusterGRPC, error) {
+	if globalopts == nil {
+		return nil, fmt.Errorf("please provide valid global options")
+	}
+
+	if opts == nil {
+		return nil, fmt.Errorf("please provide valid plugin options")
+	}
+
+	g := GobusterGRPC{
+		options:    opts,
+		globalopts: globalopts,
+	}
+
+	p, err := startPlugin(opts)
+	if err != nil {
+		return nil, err
+	}
+	g.plugin = p
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.StartTimeout)
+	defer cancel()
+	resp, err := p.client.Configure(ctx, &pluginpb.ConfigureRequest{
+		ProtocolVersion: ProtocolVersion,
+		Options:         opts.PluginOptions,
+		Threads:         uint32(globalopts.Threads),
+	})
+	if err != nil {
+		_ = p.close()
+		return nil, fmt.Errorf("could not configure plugin: %w", err)
+	}
+	g.name = resp.GetName()
+	g.config = resp.GetConfig()
+
+	return &g, nil
+}
+
+// Close stops the plugin
+func (g *GobusterGRPC) Close() error {
+	return g.plugin.close()
+}
+
+// Name should return the name of the plugin
+func (g *GobusterGRPC) Name() string {
+	if g.name != "" {
+		return g.name
+	}
+	return fmt.Sprintf("grpc plugin %s", filepath.Base(g.options.Command))
+}
+
+// PreRun is the pre run implementation of gobustergrpc
+func (g *GobusterGRPC) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	return nil
+}
+
+// ProcessWord passes the word to the plugin and reports its results
+func (g *GobusterGRPC) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	resp, err := g.plugin.client.ProcessWord(ctx, &pluginpb.ProcessWordRequest{Word: word})
+	if err != nil {
+		// ignore context canceled errors
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("plugin failed on %q: %w", word, err)
+	}
+
+	for _, r := range resp.GetResults() {
+		if !r.GetFound() && !g.globalopts.Verbose {
+			continue
+		}
+		path := r.GetPath()
+		if path == "" {
+			path = word
+		}
+		progress.ResultChan <- Result{
+			Verbose:    g.globalopts.Verbose,
+			Found:      r.GetFound(),
+			Path:       path,
+			StatusCode: int(r.GetStatus()),
+			Size:       r.GetSize(),
+			Message:    r.GetMessage(),
+			Tags:       r.GetTags(),
+		}
+	}
+
+	if resp.GetError() != "" {
+		return fmt.Errorf("plugin error on %q: %s", word, resp.GetError())
+	}
+	return nil
+}
+
+// AdditionalWords returns additional words to process
+func (g *GobusterGRPC) AdditionalWords(word string) []string {
+	return []string{}
+}
+
+// GetConfigString returns the string representation of the current config
+func (g *GobusterGRPC) GetConfigString() (string, error) {
+	var buffer bytes.Buffer
+	bw := bufio.NewWriter(&buffer)
+	tw := tabwriter.NewWriter(bw, 0, 5, 3, ' ', 0)
+	o := g.options
+
+	command := strings.Join(append([]string{o.Command}, o.Args...), " ")
+	if _, err := fmt.Fprintf(tw, "[+] Plugin:\t%s (protocol version %d)\n", command, ProtocolVersion); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", g.globalopts.Threads); err != nil {
+		return "", err
+	}
+
+	if g.globalopts.Delay > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Delay:\t%s\n", g.globalopts.Delay); err != nil {
+			return "", err
+		}
+	}
+
+	wordlist := "stdin (pipe)"
+	if g.globalopts.Wordlist != "-" {
+		wordlist = g.globalopts.WordlistName()
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
+		return "", err
+	}
+
+	if g.globalopts.PatternFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Patterns:\t%s (%d entries)\n", g.globalopts.PatternFile, len(g.globalopts.Patterns)); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.PluginOptions) > 0 {
+		keys := make([]string, 0, len(o.PluginOptions))
+		for k := range o.PluginOptions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if _, err := fmt.Fprintf(tw, "[+] Plugin Options:\t%s\n", strings.Join(keys, ", ")); err != nil {
+			return "", err
+		}
+	}
+
+	for _, c := range g.config {
+		if _, err := fmt.Fprintf(tw, "[+] %s:\t%s\n", c.GetKey(), c.GetValue()); err != nil {
+			return "", err
+		}
+	}
+
+	if g.globalopts.Verbose {
+		if _, err := fmt.Fprintf(tw, "[+] Verbose:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
@@ -0,0 +1,120 @@
+package gobustergrpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/gobustergrpc/pluginpb"
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// testPluginEnv makes the test binary serve the test plugin
+const testPluginEnv = "GOBUSTER_TEST_PLUGIN"
+
+type testPlugin struct {
+	pluginpb.UnimplementedPluginServer
+	prefix string
+}
+
+func (p *testPlugin) Configure(ctx context.Context, req *pluginpb.ConfigureRequest) (*pluginpb.ConfigureResponse, error) {
+	p.prefix = req.GetOptions()["prefix"]
+	return &pluginpb.ConfigureResponse{
+		Name:   "test",
+		Config: []*pluginpb.ConfigEntry{{Key: "Prefix", Value: p.prefix}},
+	}, nil
+}
+
+func (p *testPlugin) ProcessWord(ctx context.Context, req *pluginpb.ProcessWordRequest) (*pluginpb.ProcessWordResponse, error) {
+	return &pluginpb.ProcessWordResponse{
+		Results: []*pluginpb.Result{{Path: p.prefix + req.GetWord(), Found: true, Status: 200, Tags: []string{"custom"}}},
+	}, nil
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testPluginEnv) != "" {
+		if err := Serve(&testPlugin{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestPlugin(t *testing.T) {
+	t.Setenv(testPluginEnv, "1")
+
+	globalopts := libgobuster.NewOptions()
+	globalopts.Threads = 1
+	opts := NewOptionsGRPC()
+	opts.Command = os.Args[0]
+	opts.PluginOptions["prefix"] = "/api/"
+	opts.StartTimeout = 10 * time.Second
+
+	plugin, err := NewGobusterGRPC(globalopts, opts)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	defer plugin.Close()
+
+	if plugin.Name() != "test" {
+		t.Fatalf("expected the name of the plugin, got %q", plugin.Name())
+	}
+
+	progress := libgobuster.NewProgress()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- plugin.ProcessWord(context.Background(), "admin", progress)
+	}()
+
+	r, ok := (<-progress.ResultChan).(Result)
+	if !ok {
+		t.Fatal("expected a result of the grpc plugin")
+	}
+	if r.Path != "/api/admin" || r.StatusCode != 200 || !r.Found {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		line     string
+		expected string
+		wantErr  bool
+	}{
+		{"TCP", "1|tcp|127.0.0.1:1234|grpc\n", "127.0.0.1:1234", false},
+		{"Unix", "1|unix|/tmp/plugin.sock|grpc", "unix:/tmp/plugin.sock", false},
+		{"Version", "2|tcp|127.0.0.1:1234|grpc", "", true},
+		{"Protocol", "1|tcp|127.0.0.1:1234|netrpc", "", true},
+		{"Invalid", "hello", "", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			target, err := parseHandshake(x.line)
+			if x.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if target != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, target)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package gobustergrpc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OJ/gobuster/v3/gobustergrpc/pluginpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// ProtocolVersion is the version of the plugin protocol. It is part of
+	// the handshake and is increased on incompatible changes
+	ProtocolVersion = 1
+	// MagicCookieKey and MagicCookieValue are set in the environment of the
+	// plugin so it can tell it was started by gobuster
+	MagicCookieKey   = "GOBUSTER_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "f1f1a9d1c5e2d1b8b7a5bc6e9e4e7f30"
+)
+
+// pluginProcess is a running plugin and the connection to it
+type pluginProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	conn   *grpc.ClientConn
+	client pluginpb.PluginClient
+}
+
+// startPlugin starts the plugin and connects to the address of the handshake.
+// The handshake is a single line on stdout like "1|tcp|127.0.0.1:1234|grpc"
+// containing the protocol version, the network, the address and the protocol
+func startPlugin(opts *OptionsGRPC) (*pluginProcess, error) {
+	cmd := exec.Command(opts.Command, opts.Args...) // #nosec G204
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", MagicCookieKey, MagicCookieValue))
+	cmd.Stderr = os.Stderr
+
+	// the plugin exits once stdin is closed so it does not outlive gobuster
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start plugin: %w", err)
+	}
+
+	p := pluginProcess{cmd: cmd, stdin: stdin}
+
+	lineChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(stdout)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			errChan <- fmt.Errorf("plugin exited before the handshake: %w", err)
+			return
+		}
+		lineChan <- line
+		// plugins should not write to stdout but it must not block them
+		_, _ = io.Copy(io.Discard, reader)
+	}()
+
+	var line string
+	select {
+	case line = <-lineChan:
+	case err := <-errChan:
+		_ = p.close()
+		return nil, err
+	case <-time.After(opts.StartTimeout):
+		_ = p.close()
+		return nil, fmt.Errorf("plugin did not complete the handshake within %s", opts.StartTimeout)
+	}
+
+	target, err := parseHandshake(line)
+	if err != nil {
+		_ = p.close()
+		return nil, err
+	}
+
+	p.conn, err = grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		_ = p.close()
+		return nil, fmt.Errorf("could not connect to plugin: %w", err)
+	}
+	p.client = pluginpb.NewPluginClient(p.conn)
+	return &p, nil
+}
+
+// parseHandshake checks the handshake line and returns the grpc target
+func parseHandshake(line string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 4 {
+		return "", fmt.Errorf("invalid handshake %q, expected version|network|address|grpc", strings.TrimSpace(line))
+	}
+
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid protocol version %q in handshake", parts[0])
+	}
+	if version != ProtocolVersion {
+		return "", fmt.Errorf("plugin speaks protocol version %d but gobuster supports version %d", version, ProtocolVersion)
+	}
+
+	if parts[3] != "grpc" {
+		return "", fmt.Errorf("unsupported plugin protocol %q", parts[3])
+	}
+
+	switch parts[1] {
+	case "tcp":
+		return parts[2], nil
+	case "unix":
+		return fmt.Sprintf("unix:%s", parts[2]), nil
+	default:
+		return "", fmt.Errorf("unsupported network %q in handshake", parts[1])
+	}
+}
+
+// close closes the connection and stdin so the plugin can exit on its own. It
+// is killed if it is still running after a few seconds
+func (p *pluginProcess) close() error {
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+	_ = p.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		p.kill()
+		return <-done
+	}
+}
+
+func (p *pluginProcess) kill() {
+	if p.cmd.Process != nil {
+		_ = p.cmd.Process.Kill()
+	}
+}
+
+// Serve is used by plugins written in Go to serve the implementation. It
+// prints the handshake and returns once gobuster closes stdin
+func Serve(impl pluginpb.PluginServer) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return fmt.Errorf("this binary is a gobuster plugin and is not meant to be executed directly, use gobuster grpc --plugin-cmd %s", os.Args[0])
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	pluginpb.RegisterPluginServer(server, impl)
+
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		server.Stop()
+	}()
+
+	if _, err := fmt.Printf("%d|tcp|%s|grpc\n", ProtocolVersion, lis.Addr()); err != nil {
+		return err
+	}
+
+	return server.Serve(lis)
+}
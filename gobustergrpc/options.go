@@ -0,0 +1,45 @@
+package gobustergrpc
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// OptionsGRPC is the struct to hold all options for this plugin
+type OptionsGRPC struct {
+	// Command is the executable of the plugin
+	Command string
+	// Args are passed to the executable
+	Args []string
+	// PluginOptions are passed to the plugin in Configure
+	PluginOptions map[string]string
+	// StartTimeout is the time the plugin has to complete the handshake
+	StartTimeout time.Duration
+}
+
+// NewOptionsGRPC returns a new initialized OptionsGRPC
+func NewOptionsGRPC() *OptionsGRPC {
+	return &OptionsGRPC{
+		PluginOptions: make(map[string]string),
+	}
+}
+
+// ValidationProblems returns all problems of the grpc options
+func (opt *OptionsGRPC) ValidationProblems() []libgobuster.ValidationProblem {
+	var problems []libgobuster.ValidationProblem
+
+	if opt.Command == "" {
+		problems = append(problems, libgobuster.ValidationProblem{Option: "plugin-cmd", Problem: "is required", Suggestion: "use --plugin-cmd ./my-plugin"})
+	} else if _, err := exec.LookPath(opt.Command); err != nil {
+		problems = append(problems, libgobuster.ValidationProblem{Option: "plugin-cmd", Problem: fmt.Sprintf("%q can not be executed: %v", opt.Command, err), Suggestion: "check the path and the permissions"})
+	}
+
+	if opt.StartTimeout <= 0 {
+		problems = append(problems, libgobuster.ValidationProblem{Option: "plugin-timeout", Problem: "must be bigger than 0", Suggestion: "e.g. 10s"})
+	}
+
+	return problems
+}
@@ -0,0 +1,5 @@
+// Package pluginpb contains the protocol spoken between gobuster and
+// out-of-process plugins.
+package pluginpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative plugin.proto
@@ -0,0 +1,407 @@
+package gobustermethods
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+const (
+	// TagWebDAV is added if the path answers PROPFIND requests
+	TagWebDAV = "webdav"
+	// TagDangerousMethods is added if the server allows methods which modify resources
+	TagDangerousMethods = "dangerous-methods"
+	// TagWritable is added if a file could be created below the path
+	TagWritable = "writable"
+	// TagDeletable is added if a created resource could be deleted again
+	TagDeletable = "deletable"
+	// TagMkcol is added if a collection could be created below the path
+	TagMkcol = "mkcol"
+	// TagIntrusive is added to all findings of the intrusive checks
+	TagIntrusive = "intrusive"
+)
+
+// nolint:gochecknoglobals
+var dangerousMethods = []string{"PUT", "DELETE", "MKCOL", "MOVE", "COPY", "PROPPATCH", "PATCH"}
+
+// GobusterMethods is the main type to implement the interface
+type GobusterMethods struct {
+	options    *OptionsMethods
+	globalopts *libgobuster.Options
+	http       *libgobuster.HTTPClient
+}
+
+// NewGobusterMethods creates a new initialized GobusterMethods
+func NewGobusterMethods(globalopts *libgobuster.Options, opts *OptionsMethods) (*GobusterMethods, error) {
+	if globalopts == nil {
+		return nil, fmt.Errorf("please provide valid global options")
+	}
+
+	if opts == nil {
+		return nil, fmt.Errorf("please provide valid plugin options")
+	}
+
+	g := GobusterMethods{
+		options:    opts,
+		globalopts: globalopts,
+	}
+
+	basicOptions := libgobuster.BasicHTTPOptions{
+		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
+		Timeout:         opts.Timeout,
+		UserAgent:       opts.UserAgent,
+		NoTLSValidation: opts.NoTLSValidation,
+		RetryOnTimeout:  opts.RetryOnTimeout,
+		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
+		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
+	}
+
+	httpOpts := libgobuster.HTTPOptions{
+		BasicHTTPOptions:      basicOptions,
+		FollowRedirect:        opts.FollowRedirect,
+		Username:              opts.Username,
+		Password:              opts.Password,
+		Headers:               opts.Headers,
+		NoCanonicalizeHeaders: opts.NoCanonicalizeHeaders,
+		CacheBypass:           opts.CacheBypass,
+		Cookies:               opts.Cookies,
+		Script:                globalopts.Script,
+		Throttle:              globalopts.Throttle,
+		ConnStats:             globalopts.ConnStats,
+	}
+
+	h, err := libgobuster.NewHTTPClient(&httpOpts)
+	if err != nil {
+		return nil, err
+	}
+	g.http = h
+	return &g, nil
+}
+
+// Name should return the name of the plugin
+func (m *GobusterMethods) Name() string {
+	return "method probing"
+}
+
+// Target returns the target of the run
+func (m *GobusterMethods) Target() string {
+	return m.options.URL
+}
+
+// ProbeLatency is the latency probe implementation of gobustermethods, it sends a
+// HEAD request to the url
+func (m *GobusterMethods) ProbeLatency(ctx context.Context) (time.Duration, error) {
+	return m.http.ProbeLatency(ctx, m.options.URL)
+}
+
+// PreRun is the pre run implementation of gobustermethods
+func (m *GobusterMethods) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	return nil
+}
+
+// ProcessWord is the process implementation of gobustermethods
+func (m *GobusterMethods) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	path := strings.TrimPrefix(word, "/")
+	url := strings.TrimSuffix(m.options.URL, "/") + "/" + path
+
+	var tags []string
+
+	statusCode, header, err := m.request(ctx, url, libgobuster.RequestOptions{Method: http.MethodOptions}, progress)
+	if err != nil {
+		return err
+	}
+	// request was canceled
+	if statusCode == 0 {
+		return nil
+	}
+
+	var allow []string
+	for _, x := range strings.Split(header.Get("Allow")+","+header.Get("Public"), ",") {
+		if x = strings.ToUpper(strings.TrimSpace(x)); x != "" && !containsString(allow, x) {
+			allow = append(allow, x)
+		}
+	}
+	for _, x := range allow {
+		if containsString(dangerousMethods, x) {
+			tags = append(tags, TagDangerousMethods)
+			break
+		}
+	}
+
+	propfind := libgobuster.RequestOptions{
+		Method:          "PROPFIND",
+		ModifiedHeaders: m.headersWith(libgobuster.HTTPHeader{Name: "Depth", Value: "0"}),
+	}
+	propfindStatus, _, err := m.request(ctx, url, propfind, progress)
+	if err != nil {
+		return err
+	}
+	if propfindStatus == http.StatusMultiStatus || header.Get("DAV") != "" {
+		tags = append(tags, TagWebDAV)
+	}
+
+	if m.options.Intrusive {
+		intrusiveTags, err := m.intrusiveChecks(ctx, url, progress)
+		if err != nil {
+			return err
+		}
+		tags = append(tags, intrusiveTags...)
+	}
+
+	found := len(tags) > 0
+	if found || m.globalopts.Verbose {
+		progress.ResultChan <- Result{
+			Verbose:    m.globalopts.Verbose,
+			Found:      found,
+			URL:        url,
+			Path:       "/" + path,
+			StatusCode: statusCode,
+			Allow:      allow,
+			Tags:       tags,
+		}
+	}
+
+	return nil
+}
+
+// intrusiveChecks tries to create a random file and a random collection below
+// the url and deletes them again. Existing resources are never modified
+func (m *GobusterMethods) intrusiveChecks(ctx context.Context, url string, progress *libgobuster.Progress) ([]string, error) {
+	var tags []string
+	base := strings.TrimSuffix(url, "/")
+
+	fileURL := fmt.Sprintf("%s/gobuster-%s.txt", base, libgobuster.RandomUUID())
+	putOptions := libgobuster.RequestOptions{
+		Method: http.MethodPut,
+		Body:   strings.NewReader("gobuster write test"),
+	}
+	statusCode, _, err := m.request(ctx, fileURL, putOptions, progress)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode == http.StatusOK || statusCode == http.StatusCreated || statusCode == http.StatusNoContent {
+		tags = append(tags, TagWritable)
+		deleted, err := m.delete(ctx, fileURL, progress)
+		if err != nil {
+			return nil, err
+		}
+		if deleted {
+			tags = append(tags, TagDeletable)
+		}
+	}
+
+	collectionURL := fmt.Sprintf("%s/gobuster-%s/", base, libgobuster.RandomUUID())
+	statusCode, _, err = m.request(ctx, collectionURL, libgobuster.RequestOptions{Method: "MKCOL"}, progress)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode == http.StatusCreated {
+		tags = append(tags, TagMkcol)
+		deleted, err := m.delete(ctx, collectionURL, progress)
+		if err != nil {
+			return nil, err
+		}
+		if deleted && !containsString(tags, TagDeletable) {
+			tags = append(tags, TagDeletable)
+		}
+	}
+
+	if len(tags) > 0 {
+		tags = append(tags, TagIntrusive)
+	}
+	return tags, nil
+}
+
+// delete removes a resource created by the intrusive checks
+func (m *GobusterMethods) delete(ctx context.Context, url string, progress *libgobuster.Progress) (bool, error) {
+	statusCode, _, err := m.request(ctx, url, libgobuster.RequestOptions{Method: http.MethodDelete}, progress)
+	if err != nil {
+		return false, err
+	}
+	switch statusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+		return true, nil
+	case 0:
+		return false, nil
+	}
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelError,
+		Message: fmt.Sprintf("could not delete %s (Status: %d), please remove it manually", url, statusCode),
+	}
+	return false, nil
+}
+
+func (m *GobusterMethods) request(ctx context.Context, url string, requestOptions libgobuster.RequestOptions, progress *libgobuster.Progress) (int, http.Header, error) {
+	var statusCode int
+	var header http.Header
+	var err error
+	statusCode, _, header, _, err = m.http.Request(ctx, url, requestOptions)
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return 0, nil, err
+		}
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
+	}
+	return statusCode, header, nil
+}
+
+// headersWith returns the configured headers with an additional header
+func (m *GobusterMethods) headersWith(h libgobuster.HTTPHeader) []libgobuster.HTTPHeader {
+	headers := make([]libgobuster.HTTPHeader, 0, len(m.options.Headers)+1)
+	headers = append(headers, m.options.Headers...)
+	return append(headers, h)
+}
+
+func containsString(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns warnings about potentially destructive or noisy configurations
+func (m *GobusterMethods) Warnings() []string {
+	warnings := libgobuster.HTTPWarnings(&m.options.HTTPOptions, m.globalopts)
+	if m.options.Intrusive {
+		warnings = append(warnings, "intrusive checks are enabled, PUT, MKCOL and DELETE requests will be sent to the target")
+	}
+	return warnings
+}
+
+// AdditionalWords returns additional words to process
+func (m *GobusterMethods) AdditionalWords(word string) []string {
+	return []string{}
+}
+
+// GetConfigString returns the string representation of the current config
+func (m *GobusterMethods) GetConfigString() (string, error) {
+	var buffer bytes.Buffer
+	bw := bufio.NewWriter(&buffer)
+	tw := tabwriter.NewWriter(bw, 0, 5, 3, ' ', 0)
+	o := m.options
+	if _, err := fmt.Fprintf(tw, "[+] Url:\t%s\n", o.URL); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", m.globalopts.Threads); err != nil {
+		return "", err
+	}
+
+	if m.globalopts.Delay > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Delay:\t%s\n", m.globalopts.Delay); err != nil {
+			return "", err
+		}
+	}
+
+	wordlist := "stdin (pipe)"
+	if m.globalopts.Wordlist != "-" {
+		wordlist = m.globalopts.WordlistName()
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
+		return "", err
+	}
+
+	if m.globalopts.PatternFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Patterns:\t%s (%d entries)\n", m.globalopts.PatternFile, len(m.globalopts.Patterns)); err != nil {
+			return "", err
+		}
+	}
+
+	checks := "OPTIONS, PROPFIND"
+	if o.Intrusive {
+		checks += ", PUT, MKCOL, DELETE (intrusive)"
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Checks:\t%s\n", checks); err != nil {
+		return "", err
+	}
+
+	if o.Proxy != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Proxy:\t%s\n", o.Proxy); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Cookies != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Cookies:\t%s\n", o.Cookies); err != nil {
+			return "", err
+		}
+	}
+
+	if o.UserAgent != "" {
+		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Username != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Auth User:\t%s\n", o.Username); err != nil {
+			return "", err
+		}
+	}
+
+	if o.FollowRedirect {
+		if _, err := fmt.Fprintf(tw, "[+] Follow Redirect:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.CacheBypass {
+		if _, err := fmt.Fprintf(tw, "[+] Cache bypass:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if m.globalopts.Verbose {
+		if _, err := fmt.Fprintf(tw, "[+] Verbose:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
+		return "", err
+	}
+
+	if m.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", m.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	if m.globalopts.Script != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Script:\t%s\n", m.globalopts.Script); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
@@ -0,0 +1,167 @@
+package gobustermethods
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// davServer is a minimal WebDAV server below /dav/ recording all requests.
+// Resources it did not create itself can not be deleted if denyDelete is set
+type davServer struct {
+	mutex      sync.Mutex
+	requests   []string
+	resources  map[string]bool
+	denyDelete bool
+}
+
+func (s *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// the random names of the intrusive checks are replaced to compare them
+	path := r.URL.Path
+	if i := strings.Index(path, "gobuster-"); i >= 0 {
+		path = path[:i] + "gobuster-*"
+		if strings.HasSuffix(r.URL.Path, ".txt") {
+			path += ".txt"
+		} else if strings.HasSuffix(r.URL.Path, "/") {
+			path += "/"
+		}
+	}
+	s.requests = append(s.requests, r.Method+" "+path)
+
+	if !strings.HasPrefix(r.URL.Path, "/dav/") {
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		if r.Method != http.MethodOptions {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS, PUT, DELETE, MKCOL, PROPFIND")
+		w.Header().Set("DAV", "1")
+	case "PROPFIND":
+		w.WriteHeader(http.StatusMultiStatus)
+	case http.MethodPut, "MKCOL":
+		s.resources[r.URL.Path] = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		switch {
+		case !s.resources[r.URL.Path]:
+			w.WriteHeader(http.StatusNotFound)
+		case s.denyDelete:
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			delete(s.resources, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName   string
+		word       string
+		intrusive  bool
+		denyDelete bool
+		requests   []string
+		tags       []string
+		remaining  int
+		messages   int
+	}{
+		{"Not found", "static", false, false, []string{"OPTIONS /static", "PROPFIND /static"}, nil, 0, 0},
+		{"WebDAV", "dav/", false, false, []string{"OPTIONS /dav/", "PROPFIND /dav/"}, []string{TagDangerousMethods, TagWebDAV}, 0, 0},
+		{"Intrusive", "dav/", true, false, []string{
+			"OPTIONS /dav/", "PROPFIND /dav/",
+			"PUT /dav/gobuster-*.txt", "DELETE /dav/gobuster-*.txt",
+			"MKCOL /dav/gobuster-*/", "DELETE /dav/gobuster-*/",
+		}, []string{TagDangerousMethods, TagWebDAV, TagWritable, TagDeletable, TagMkcol, TagIntrusive}, 0, 0},
+		{"Intrusive not writable", "static", true, false, []string{
+			"OPTIONS /static", "PROPFIND /static", "PUT /static/gobuster-*.txt", "MKCOL /static/gobuster-*/",
+		}, nil, 0, 0},
+		{"Cleanup failed", "dav/", true, true, []string{
+			"OPTIONS /dav/", "PROPFIND /dav/",
+			"PUT /dav/gobuster-*.txt", "DELETE /dav/gobuster-*.txt",
+			"MKCOL /dav/gobuster-*/", "DELETE /dav/gobuster-*/",
+		}, []string{TagDangerousMethods, TagWebDAV, TagWritable, TagMkcol, TagIntrusive}, 2, 2},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			dav := &davServer{resources: make(map[string]bool), denyDelete: x.denyDelete}
+			ts := httptest.NewServer(dav)
+			defer ts.Close()
+
+			o := NewOptionsMethods()
+			o.URL = ts.URL
+			o.Timeout = 5 * time.Second
+			o.Intrusive = x.intrusive
+			m, err := NewGobusterMethods(libgobuster.NewOptions(), o)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			progress := libgobuster.NewProgress()
+			results := make(chan []libgobuster.Result)
+			go func() {
+				var r []libgobuster.Result
+				for result := range progress.ResultChan {
+					r = append(r, result)
+				}
+				results <- r
+			}()
+			messages := make(chan int)
+			go func() {
+				n := 0
+				for range progress.MessageChan {
+					n++
+				}
+				messages <- n
+			}()
+
+			if err := m.ProcessWord(context.Background(), x.word, progress); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			close(progress.ResultChan)
+			close(progress.MessageChan)
+
+			dav.mutex.Lock()
+			defer dav.mutex.Unlock()
+			if !reflect.DeepEqual(dav.requests, x.requests) {
+				t.Fatalf("expected the requests %v, got %v", x.requests, dav.requests)
+			}
+			// everything created by the intrusive checks is removed again
+			if len(dav.resources) != x.remaining {
+				t.Fatalf("expected %d remaining resources, got %v", x.remaining, dav.resources)
+			}
+			if n := <-messages; n != x.messages {
+				t.Fatalf("expected %d messages about failed cleanups, got %d", x.messages, n)
+			}
+
+			var tags []string
+			for _, r := range <-results {
+				tags = append(tags, r.(Result).Tags...)
+			}
+			if !reflect.DeepEqual(tags, x.tags) {
+				t.Fatalf("expected the tags %v, got %v", x.tags, tags)
+			}
+		})
+	}
+}
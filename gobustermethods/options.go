@@ -0,0 +1,17 @@
+package gobustermethods
+
+import (
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// OptionsMethods is the struct to hold all options for this plugin
+type OptionsMethods struct {
+	libgobuster.HTTPOptions
+	// Intrusive enables the checks which try to create and delete resources
+	Intrusive bool
+}
+
+// NewOptionsMethods returns a new initialized OptionsMethods
+func NewOptionsMethods() *OptionsMethods {
+	return &OptionsMethods{}
+}
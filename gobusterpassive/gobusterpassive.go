@@ -0,0 +1,194 @@
+package gobusterpassive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// GobusterPassive is the main type to implement the interface
+type GobusterPassive struct {
+	options    *OptionsPassive
+	globalopts *libgobuster.Options
+	http       *libgobuster.HTTPClient
+	seenMutex  sync.Mutex
+	seen       libgobuster.Set[string]
+	// the endpoints of the sources, changed by the tests
+	crtShURL      string
+	waybackURL    string
+	virusTotalURL string
+}
+
+// NewGobusterPassive creates a new initialized GobusterPassive
+func NewGobusterPassive(globalopts *libgobuster.Options, opts *OptionsPassive) (*GobusterPassive, error) {
+	if globalopts == nil {
+		return nil, fmt.Errorf("please provide valid global options")
+	}
+
+	if opts == nil {
+		return nil, fmt.Errorf("please provide valid plugin options")
+	}
+
+	if len(opts.Sources) == 0 {
+		return nil, fmt.Errorf("please provide at least one source")
+	}
+
+	for _, s := range opts.Sources {
+		valid := false
+		for _, x := range AllSources {
+			if s == x {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown source %q, valid sources are %s", s, strings.Join(AllSources, ","))
+		}
+		if s == SourceVirusTotal && opts.VirusTotalAPIKey == "" {
+			return nil, fmt.Errorf("the %s source needs an API key", SourceVirusTotal)
+		}
+	}
+
+	g := GobusterPassive{
+		options:       opts,
+		globalopts:    globalopts,
+		seen:          libgobuster.NewSet[string](),
+		crtShURL:      "https://crt.sh/",
+		waybackURL:    libgobuster.WaybackCDXURL,
+		virusTotalURL: "https://www.virustotal.com/api/v3",
+	}
+
+	httpOpts := libgobuster.HTTPOptions{
+		BasicHTTPOptions: opts.BasicHTTPOptions,
+		FollowRedirect:   true,
+		ConnStats:        globalopts.ConnStats,
+	}
+	if httpOpts.Limits.MaxBodySize <= 0 {
+		httpOpts.Limits.MaxBodySize = DefaultMaxBodySize
+	}
+
+	h, err := libgobuster.NewHTTPClient(&httpOpts)
+	if err != nil {
+		return nil, err
+	}
+	g.http = h
+	return &g, nil
+}
+
+// Name should return the name of the plugin
+func (p *GobusterPassive) Name() string {
+	return "passive sources aggregation"
+}
+
+// PreRun is the pre run implementation of gobusterpassive
+func (p *GobusterPassive) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	return nil
+}
+
+// ProcessWord is the process implementation of gobusterpassive. Every word
+// is a domain which is looked up in all sources
+func (p *GobusterPassive) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	domain := strings.ToLower(strings.TrimSuffix(word, "."))
+
+	for _, source := range p.options.Sources {
+		values, err := p.query(ctx, source, domain)
+		if err != nil {
+			// one failing source should not stop the others
+			progress.ErrorChan <- fmt.Errorf("%s: %w", source, err)
+			continue
+		}
+
+		for _, v := range values {
+			if !p.markSeen(v.value) {
+				continue
+			}
+			progress.ResultChan <- Result{
+				Domain: domain,
+				Source: source,
+				Value:  v.value,
+				IsURL:  v.isURL,
+			}
+		}
+	}
+	return nil
+}
+
+// markSeen returns false if the value was already returned by another source
+func (p *GobusterPassive) markSeen(v string) bool {
+	p.seenMutex.Lock()
+	defer p.seenMutex.Unlock()
+	return p.seen.Add(v)
+}
+
+// AdditionalWords returns additional words to process
+func (p *GobusterPassive) AdditionalWords(word string) []string {
+	return []string{}
+}
+
+// GetConfigString returns the string representation of the current config
+func (p *GobusterPassive) GetConfigString() (string, error) {
+	var buffer bytes.Buffer
+	bw := bufio.NewWriter(&buffer)
+	tw := tabwriter.NewWriter(bw, 0, 5, 3, ' ', 0)
+	o := p.options
+
+	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", p.globalopts.Threads); err != nil {
+		return "", err
+	}
+
+	wordlist := "stdin (pipe)"
+	if p.globalopts.Wordlist != "-" {
+		wordlist = p.globalopts.WordlistName()
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Domains:\t%s\n", wordlist); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Sources:\t%s\n", strings.Join(o.Sources, ",")); err != nil {
+		return "", err
+	}
+
+	if o.WaybackLimit > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Wayback limit:\t%d\n", o.WaybackLimit); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Proxy != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Proxy:\t%s\n", o.Proxy); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
+	if o.UserAgent != "" {
+		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
+		return "", err
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
@@ -0,0 +1,197 @@
+package gobusterpassive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/OJ/gobuster/v3/libgobuster/plugintest"
+)
+
+// sourcesServer serves the APIs of crt.sh below /crtsh, the wayback machine
+// below /cdx and virustotal below /vt for every queried domain. Virustotal
+// only answers requests with the API key "key" and splits its answer over
+// two pages
+func sourcesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/crtsh":
+			domain := strings.TrimPrefix(q.Get("q"), "%.")
+			if q.Get("output") != "json" || domain == q.Get("q") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `[{"name_value":"www.%[1]s\n*.dev.%[1]s"},{"name_value":"other.org"},{"name_value":"WWW.%[1]s"}]`, domain)
+		case r.URL.Path == "/cdx":
+			if q.Get("matchType") != "domain" || q.Get("fl") != "original" || q.Get("output") != "json" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			domain := q.Get("url")
+			fmt.Fprintf(w, `[["original"],["https://%[1]s/a"],["https://%[1]s/a"],["https://www.%[1]s/b"]]`, domain)
+		case strings.HasPrefix(r.URL.Path, "/vt/domains/"):
+			if r.Header.Get("x-apikey") != "key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			domain := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/vt/domains/"), "/subdomains")
+			if q.Get("cursor") == "" {
+				fmt.Fprintf(w, `{"data":[{"id":"mail.%[1]s"},{"id":"evil.org"}],"links":{"next":"http://%[2]s%[3]s?limit=40&cursor=2"}}`, domain, r.Host, r.URL.Path)
+				return
+			}
+			fmt.Fprintf(w, `{"data":[{"id":"VPN.%[1]s"},{"id":"www.%[1]s"}],"links":{}}`, domain)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// newTestPassive returns a plugin querying the sources of the server
+func newTestPassive(t *testing.T, globalopts *libgobuster.Options, o *OptionsPassive, ts *httptest.Server) *GobusterPassive {
+	t.Helper()
+
+	p, err := NewGobusterPassive(globalopts, o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	p.crtShURL = ts.URL + "/crtsh"
+	p.waybackURL = ts.URL + "/cdx"
+	p.virusTotalURL = ts.URL + "/vt"
+	return p
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	ts := sourcesServer(t)
+
+	plugintest.Run(t, func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin {
+		o := NewOptionsPassive()
+		o.Timeout = 5 * time.Second
+		o.Sources = AllSources
+		o.VirusTotalAPIKey = "key"
+		return newTestPassive(t, globalopts, o, ts)
+	})
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+	ts := sourcesServer(t)
+
+	tt := []struct {
+		testName    string
+		sources     []string
+		apiKey      string
+		maxBodySize int64
+		expected    string
+		errors      []string
+	}{
+		{"crt.sh", []string{SourceCrtSh}, "", 0, "crtsh www.example.com,crtsh dev.example.com", nil},
+		{"Wayback", []string{SourceWayback}, "", 0, "wayback https://example.com/a,wayback https://www.example.com/b", nil},
+		{"Virustotal", []string{SourceVirusTotal}, "key", 0, "virustotal mail.example.com,virustotal vpn.example.com,virustotal www.example.com", nil},
+		{"All sources", AllSources, "key", 0, "crtsh www.example.com,crtsh dev.example.com," +
+			"wayback https://example.com/a,wayback https://www.example.com/b," +
+			"virustotal mail.example.com,virustotal vpn.example.com", nil},
+		{"Wrong API key", AllSources, "wrong", 0, "crtsh www.example.com,crtsh dev.example.com," +
+			"wayback https://example.com/a,wayback https://www.example.com/b", []string{"virustotal: ", "/vt/domains/example.com/subdomains?limit=40 returned status 401"}},
+		{"Truncated responses", []string{SourceCrtSh, SourceWayback}, "", 32, "", []string{
+			"crtsh: response of", "is larger than the maximum body size of 32B",
+			"wayback: could not query the wayback machine: response of",
+		}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOptionsPassive()
+			o.Timeout = 5 * time.Second
+			o.Sources = x.sources
+			o.VirusTotalAPIKey = x.apiKey
+			o.Limits.MaxBodySize = x.maxBodySize
+			p := newTestPassive(t, libgobuster.NewOptions(), o, ts)
+
+			progress := libgobuster.NewProgress()
+			done := make(chan []string)
+			go func() {
+				var results []string
+				for r := range progress.ResultChan {
+					res := r.(Result)
+					if res.Domain != "example.com" {
+						t.Errorf("expected the domain example.com, got %s", res.Domain)
+					}
+					results = append(results, fmt.Sprintf("%s %s", res.Source, res.Value))
+				}
+				done <- results
+			}()
+			errs := make(chan []string)
+			go func() {
+				var e []string
+				for err := range progress.ErrorChan {
+					e = append(e, err.Error())
+				}
+				errs <- e
+			}()
+
+			if err := p.ProcessWord(context.Background(), "Example.com.", progress); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			close(progress.ResultChan)
+			close(progress.ErrorChan)
+
+			if results := strings.Join(<-done, ","); results != x.expected {
+				t.Fatalf("expected %s, got %s", x.expected, results)
+			}
+			got := strings.Join(<-errs, "\n")
+			if (got == "") != (len(x.errors) == 0) {
+				t.Fatalf("expected the errors %v, got %q", x.errors, got)
+			}
+			for _, e := range x.errors {
+				if !strings.Contains(got, e) {
+					t.Fatalf("expected an error containing %q, got %q", e, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	// a response larger than the default of the other modes is read whole
+	big := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[")
+		for i := 0; i < 200000; i++ {
+			fmt.Fprintf(w, `{"name_value":"host%d.example.com","issuer":"%s"},`, i, strings.Repeat("x", 40))
+		}
+		fmt.Fprint(w, `{"name_value":"last.example.com"}]`)
+	}))
+	t.Cleanup(big.Close)
+
+	o := NewOptionsPassive()
+	o.Timeout = 10 * time.Second
+	o.Sources = []string{SourceCrtSh}
+	p, err := NewGobusterPassive(libgobuster.NewOptions(), o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	p.crtShURL = big.URL
+
+	values, err := p.queryCrtSh(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(values) != 200001 || values[len(values)-1].value != "last.example.com" {
+		t.Fatalf("expected 200001 names ending with last.example.com, got %d", len(values))
+	}
+}
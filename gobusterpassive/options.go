@@ -0,0 +1,26 @@
+package gobusterpassive
+
+import (
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// OptionsPassive is the struct to hold all options for this plugin
+type OptionsPassive struct {
+	libgobuster.BasicHTTPOptions
+	// Sources holds the names of the enabled sources
+	Sources []string
+	// VirusTotalAPIKey is needed for the virustotal source
+	VirusTotalAPIKey string
+	// WaybackLimit is the maximum number of urls fetched from the wayback machine
+	WaybackLimit int
+}
+
+// DefaultMaxBodySize is the maximum body size of the source APIs. Their
+// responses for large domains are far larger than the default of the other
+// modes, a truncated response can not be parsed at all
+const DefaultMaxBodySize = 512 * 1024 * 1024
+
+// NewOptionsPassive returns a new initialized OptionsPassive
+func NewOptionsPassive() *OptionsPassive {
+	return &OptionsPassive{}
+}
@@ -0,0 +1,50 @@
+package gobusterpassive
+
+import (
+	"bytes"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/fatih/color"
+)
+
+var (
+	green = color.New(color.FgGreen).FprintfFunc()
+	blue  = color.New(color.FgBlue).FprintfFunc()
+)
+
+// Result represents a single result
+type Result struct {
+	// Domain is the domain which was queried
+	Domain string
+	// Source is the name of the source which returned the value
+	Source string
+	// Value is a subdomain or an url
+	Value string
+	// IsURL is true if the value is an url instead of a subdomain
+	IsURL bool
+}
+
+// ResultToString converts the Result to it's textual representation
+func (r Result) ResultToString() (string, error) {
+	buf := &bytes.Buffer{}
+
+	green(buf, "%s", r.Value)
+	blue(buf, " [%s]", r.Source)
+	green(buf, "\n")
+
+	s := buf.String()
+	return s, nil
+}
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	record := libgobuster.ResultRecord{
+		Path:  r.Value,
+		Found: true,
+		Tags:  []string{r.Source},
+	}
+	if r.IsURL {
+		record.URL = r.Value
+	}
+	return record
+}
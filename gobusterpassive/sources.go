@@ -0,0 +1,144 @@
+package gobusterpassive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+const (
+	// SourceCrtSh queries the certificate transparency logs via crt.sh
+	SourceCrtSh = "crtsh"
+	// SourceWayback queries the archived urls of the wayback machine
+	SourceWayback = "wayback"
+	// SourceVirusTotal queries the virustotal API, needs an API key
+	SourceVirusTotal = "virustotal"
+)
+
+// virusTotalMaxPages is the maximum number of result pages fetched from virustotal
+const virusTotalMaxPages = 10
+
+// AllSources contains the names of all supported sources
+// nolint:gochecknoglobals
+var AllSources = []string{SourceCrtSh, SourceWayback, SourceVirusTotal}
+
+// value is a single entry returned by a source
+type value struct {
+	value string
+	isURL bool
+}
+
+func (p *GobusterPassive) query(ctx context.Context, source, domain string) ([]value, error) {
+	switch source {
+	case SourceCrtSh:
+		return p.queryCrtSh(ctx, domain)
+	case SourceWayback:
+		return p.queryWayback(ctx, domain)
+	case SourceVirusTotal:
+		return p.queryVirusTotal(ctx, domain)
+	}
+	return nil, fmt.Errorf("unknown source %q", source)
+}
+
+func (p *GobusterPassive) queryCrtSh(ctx context.Context, domain string) ([]value, error) {
+	u := fmt.Sprintf("%s?q=%s&output=json", p.crtShURL, url.QueryEscape("%."+domain))
+	body, err := p.get(ctx, u, nil)
+	if err != nil || body == nil {
+		return nil, err
+	}
+
+	var entries []struct {
+		NameValue string `json:"name_value"`
+	}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("could not parse crt.sh response: %w", err)
+	}
+
+	var ret []value
+	for _, e := range entries {
+		// one entry can contain multiple names separated by newlines
+		for _, name := range strings.Split(e.NameValue, "\n") {
+			name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "*."))
+			if isSubdomain(name, domain) {
+				ret = append(ret, value{value: name})
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (p *GobusterPassive) queryWayback(ctx context.Context, domain string) ([]value, error) {
+	urls, err := libgobuster.FetchWaybackURLs(ctx, p.http, p.waybackURL, domain, true, p.options.WaybackLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]value, len(urls))
+	for i, u := range urls {
+		ret[i] = value{value: u, isURL: true}
+	}
+	return ret, nil
+}
+
+func (p *GobusterPassive) queryVirusTotal(ctx context.Context, domain string) ([]value, error) {
+	headers := []libgobuster.HTTPHeader{{Name: "x-apikey", Value: p.options.VirusTotalAPIKey}}
+	next := fmt.Sprintf("%s/domains/%s/subdomains?limit=40", p.virusTotalURL, url.PathEscape(domain))
+
+	var ret []value
+	for i := 0; i < virusTotalMaxPages && next != ""; i++ {
+		body, err := p.get(ctx, next, headers)
+		if err != nil || body == nil {
+			return ret, err
+		}
+
+		var resp struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return ret, fmt.Errorf("could not parse virustotal response: %w", err)
+		}
+		for _, d := range resp.Data {
+			if name := strings.ToLower(d.ID); isSubdomain(name, domain) {
+				ret = append(ret, value{value: name})
+			}
+		}
+		next = resp.Links.Next
+	}
+	return ret, nil
+}
+
+// get requests the url and returns the body. A nil body is returned if the
+// request was canceled, bodies larger than the maximum body size fail
+func (p *GobusterPassive) get(ctx context.Context, u string, headers []libgobuster.HTTPHeader) ([]byte, error) {
+	statusCode, _, _, body, err := p.http.Request(ctx, u, libgobuster.RequestOptions{
+		Method:          http.MethodGet,
+		ReturnBody:      true,
+		ModifiedHeaders: headers,
+		RejectTruncated: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if statusCode == 0 {
+		return nil, nil
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", u, statusCode)
+	}
+	return body, nil
+}
+
+// isSubdomain checks if name is the domain itself or one of its subdomains
+func isSubdomain(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
-	"net"
 	"net/http"
 	"regexp"
 	"strings"
@@ -40,16 +39,24 @@ func NewGobusterS3(globalopts *libgobuster.Options, opts *OptionsS3) (*GobusterS
 
 	basicOptions := libgobuster.BasicHTTPOptions{
 		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
 		Timeout:         opts.Timeout,
 		UserAgent:       opts.UserAgent,
 		NoTLSValidation: opts.NoTLSValidation,
 		RetryOnTimeout:  opts.RetryOnTimeout,
 		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
 		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
 	}
 
 	httpOpts := libgobuster.HTTPOptions{
 		BasicHTTPOptions: basicOptions,
+		Headers:          opts.Headers,
+		Throttle:         globalopts.Throttle,
+		ConnStats:        globalopts.ConnStats,
 		// needed so we can list bucket contents
 		FollowRedirect: true,
 	}
@@ -83,32 +90,17 @@ func (s *GobusterS3) ProcessWord(ctx context.Context, word string, progress *lib
 
 	bucketURL := fmt.Sprintf("https://%s.s3.amazonaws.com/?max-keys=%d", word, s.options.MaxFilesToList)
 
-	tries := 1
-	if s.options.RetryOnTimeout && s.options.RetryAttempts > 0 {
-		// add it so it will be the overall max requests
-		tries += s.options.RetryAttempts
-	}
-
 	var statusCode int
 	var body []byte
-	for i := 1; i <= tries; i++ {
-		var err error
-		statusCode, _, _, body, err = s.http.Request(ctx, bucketURL, libgobuster.RequestOptions{ReturnBody: true})
-		if err != nil {
-			// check if it's a timeout and if we should try again and try again
-			// otherwise the timeout error is raised
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && i != tries {
-				continue
-			} else if strings.Contains(err.Error(), "invalid control character in URL") {
-				// put error in error chan so it's printed out and ignore it
-				// so gobuster will not quit
-				progress.ErrorChan <- err
-				continue
-			} else {
-				return err
-			}
+	var err error
+	statusCode, _, _, body, err = s.http.Request(ctx, bucketURL, libgobuster.RequestOptions{ReturnBody: true})
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return err
 		}
-		break
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
 	}
 
 	if statusCode == 0 || body == nil {
@@ -166,6 +158,7 @@ func (s *GobusterS3) ProcessWord(ctx context.Context, word string, progress *lib
 		Found:      found,
 		BucketName: word,
 		Status:     extraStr,
+		StatusCode: statusCode,
 	}
 
 	return nil
@@ -192,9 +185,15 @@ func (s *GobusterS3) GetConfigString() (string, error) {
 		}
 	}
 
+	if s.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", s.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
 	wordlist := "stdin (pipe)"
 	if s.globalopts.Wordlist != "-" {
-		wordlist = s.globalopts.Wordlist
+		wordlist = s.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
@@ -212,6 +211,12 @@ func (s *GobusterS3) GetConfigString() (string, error) {
 		}
 	}
 
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
 	if o.UserAgent != "" {
 		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
 			return "", err
@@ -7,6 +7,8 @@ import (
 // OptionsS3 is the struct to hold all options for this plugin
 type OptionsS3 struct {
 	libgobuster.BasicHTTPOptions
+	// Headers are sent with every request
+	Headers        []libgobuster.HTTPHeader
 	MaxFilesToList int
 }
 
@@ -2,7 +2,9 @@ package gobusters3
 
 import (
 	"bytes"
+	"fmt"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
@@ -15,6 +17,7 @@ type Result struct {
 	Found      bool
 	BucketName string
 	Status     string
+	StatusCode int
 }
 
 // ResultToString converts the Result to it's textual representation
@@ -33,3 +36,13 @@ func (r Result) ResultToString() (string, error) {
 	str := buf.String()
 	return str, nil
 }
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	return libgobuster.ResultRecord{
+		URL:        fmt.Sprintf("http://%s.s3.amazonaws.com/", r.BucketName),
+		Path:       r.BucketName,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"text/tabwriter"
@@ -13,6 +14,24 @@ import (
 	"github.com/pin/tftp/v3"
 )
 
+// blockSize is the size of the data packets of a transfer without the
+// blksize option, shorter packets end the transfer
+const blockSize = 512
+
+// nolint:gochecknoglobals
+var errProbed = errors.New("file probed")
+
+// dataProbe receives the first data packet of a transfer and stops it, so
+// the whole file is not downloaded
+type dataProbe struct {
+	n int64
+}
+
+func (p *dataProbe) Write(b []byte) (int, error) {
+	p.n += int64(len(b))
+	return len(b), errProbed
+}
+
 // GobusterTFTP is the main type to implement the interface
 type GobusterTFTP struct {
 	globalopts *libgobuster.Options
@@ -41,6 +60,11 @@ func (d *GobusterTFTP) Name() string {
 	return "TFTP enumeration"
 }
 
+// Target returns the target of the run
+func (d *GobusterTFTP) Target() string {
+	return d.options.Server
+}
+
 // PreRun is the pre run implementation of gobustertftp
 func (d *GobusterTFTP) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
 	_, err := tftp.NewClient(d.options.Server)
@@ -52,32 +76,54 @@ func (d *GobusterTFTP) PreRun(ctx context.Context, progress *libgobuster.Progres
 
 // ProcessWord is the process implementation of gobustertftp
 func (d *GobusterTFTP) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	if err := d.globalopts.Throttle.Wait(ctx, d.options.Server); err != nil {
+		// ignore context canceled errors
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
 	c, err := tftp.NewClient(d.options.Server)
 	if err != nil {
 		return err
 	}
 	c.SetTimeout(d.options.Timeout)
+	// servers supporting the tsize option report the size of the file
+	c.RequestTSize(true)
 	wt, err := c.Receive(word, "octet")
-	if err != nil {
-		// file not found
-		if d.globalopts.Verbose {
-			progress.ResultChan <- Result{
-				Filename:     word,
-				Found:        false,
-				ErrorMessage: err.Error(),
+	if err == nil {
+		// the options are gone once the transfer started
+		size, sized := wt.(tftp.IncomingTransfer).Size()
+		// only files returning data are found, servers may send an error
+		// after acknowledging the options. Reading the first packet also
+		// ends the transfer, so the server stops sending it
+		probe := &dataProbe{}
+		if _, err = wt.WriteTo(probe); errors.Is(err, errProbed) {
+			result := Result{
+				Filename: word,
+				Found:    true,
+			}
+			// servers not knowing the size echo the requested tsize of 0
+			if sized && size > 0 {
+				result.Size = size
+			} else if probe.n < blockSize {
+				// the first packet was the whole file
+				result.Size = probe.n
 			}
+			progress.ResultChan <- result
+			return nil
 		}
-
-		return nil
 	}
-	result := Result{
-		Filename: word,
-		Found:    true,
-	}
-	if n, ok := wt.(tftp.IncomingTransfer).Size(); ok {
-		result.Size = n
+
+	// file not found
+	if d.globalopts.Verbose {
+		progress.ResultChan <- Result{
+			Filename:     word,
+			Found:        false,
+			ErrorMessage: err.Error(),
+		}
 	}
-	progress.ResultChan <- result
 	return nil
 }
 
@@ -106,13 +152,19 @@ func (d *GobusterTFTP) GetConfigString() (string, error) {
 		}
 	}
 
+	if d.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", d.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
 	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
 		return "", err
 	}
 
 	wordlist := "stdin (pipe)"
 	if d.globalopts.Wordlist != "-" {
-		wordlist = d.globalopts.Wordlist
+		wordlist = d.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
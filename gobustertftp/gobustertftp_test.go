@@ -0,0 +1,105 @@
+package gobustertftp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/OJ/gobuster/v3/libgobuster/plugintest"
+
+	"github.com/pin/tftp/v3"
+)
+
+// tftpServer serves the files on a local udp port, only the files in sized
+// report their size with the tsize option
+func tftpServer(t *testing.T, files map[string][]byte, sized map[string]bool) string {
+	t.Helper()
+
+	s := tftp.NewServer(func(filename string, rf io.ReaderFrom) error {
+		content, ok := files[filename]
+		if !ok {
+			return os.ErrNotExist
+		}
+		if sized[filename] {
+			rf.(tftp.OutgoingTransfer).SetSize(int64(len(content)))
+		}
+		// the server sends the size of seekable readers on its own
+		_, err := rf.ReadFrom(io.MultiReader(bytes.NewReader(content)))
+		return err
+	}, nil)
+	s.SetTimeout(time.Second)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	go s.Serve(conn) // nolint:errcheck
+	t.Cleanup(s.Shutdown)
+	return conn.LocalAddr().String()
+}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	server := tftpServer(t, map[string][]byte{"backup": []byte("backup")}, nil)
+
+	plugintest.Run(t, func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin {
+		o := NewOptionsTFTP()
+		o.Server = server
+		o.Timeout = 2 * time.Second
+		d, err := NewGobusterTFTP(globalopts, o)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		return d
+	})
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+
+	server := tftpServer(t, map[string][]byte{
+		"small.txt":  []byte("hello"),
+		"large.bin":  bytes.Repeat([]byte("a"), 3*blockSize),
+		"sized.bin":  bytes.Repeat([]byte("b"), 3*blockSize),
+		"empty.conf": {},
+	}, map[string]bool{"sized.bin": true})
+
+	globalopts := libgobuster.NewOptions()
+	o := NewOptionsTFTP()
+	o.Server = server
+	o.Timeout = 2 * time.Second
+	d, err := NewGobusterTFTP(globalopts, o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	progress := libgobuster.NewProgress()
+	done := make(chan []string)
+	go func() {
+		var results []string
+		for r := range progress.ResultChan {
+			res := r.(Result)
+			results = append(results, fmt.Sprintf("%s %t %d", res.Filename, res.Found, res.Size))
+		}
+		done <- results
+	}()
+	for _, word := range []string{"small.txt", "large.bin", "sized.bin", "empty.conf", "missing.txt"} {
+		if err := d.ProcessWord(context.Background(), word, progress); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	close(progress.ResultChan)
+
+	// the size of large files is only known from the tsize option
+	expected := "small.txt true 5,large.bin true 0,sized.bin true 1536,empty.conf true 0"
+	if results := strings.Join(<-done, ","); results != expected {
+		t.Fatalf("expected %s, got %s", expected, results)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
@@ -44,3 +45,12 @@ func (r Result) ResultToString() (string, error) {
 	s := buf.String()
 	return s, nil
 }
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	return libgobuster.ResultRecord{
+		Path:  r.Filename,
+		Found: r.Found,
+		Size:  r.Size,
+	}
+}
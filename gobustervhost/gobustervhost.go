@@ -4,25 +4,47 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/OJ/gobuster/v3/libgobuster"
-	"github.com/google/uuid"
 )
 
+// baseline is the response for a vhost. Pages reflecting the Host header
+// match the baseline if they differ only in the reflected host
+type baseline struct {
+	host       string
+	statusCode int
+	body       []byte
+}
+
+// matches checks if the response for the host equals the baseline
+func (b baseline) matches(host string, statusCode int, body []byte) bool {
+	if statusCode != b.statusCode {
+		return false
+	}
+	if bytes.Equal(body, b.body) {
+		return true
+	}
+	return b.host != "" && bytes.Equal(body, bytes.ReplaceAll(b.body, []byte(b.host), []byte(host)))
+}
+
 // GobusterVhost is the main type to implement the interface
 type GobusterVhost struct {
-	options      *OptionsVhost
-	globalopts   *libgobuster.Options
-	http         *libgobuster.HTTPClient
-	domain       string
-	normalBody   []byte
-	abnormalBody []byte
+	options    *OptionsVhost
+	globalopts *libgobuster.Options
+	http       *libgobuster.HTTPClient
+	domain     string
+	// normal is the response of the default vhost
+	normal baseline
+	// abnormal is the response of a non existing vhost
+	abnormal baseline
 }
 
 // NewGobusterVhost creates a new initialized GobusterDir
@@ -42,12 +64,17 @@ func NewGobusterVhost(globalopts *libgobuster.Options, opts *OptionsVhost) (*Gob
 
 	basicOptions := libgobuster.BasicHTTPOptions{
 		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
 		Timeout:         opts.Timeout,
 		UserAgent:       opts.UserAgent,
 		NoTLSValidation: opts.NoTLSValidation,
 		RetryOnTimeout:  opts.RetryOnTimeout,
 		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
 		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
 	}
 
 	httpOpts := libgobuster.HTTPOptions{
@@ -57,7 +84,11 @@ func NewGobusterVhost(globalopts *libgobuster.Options, opts *OptionsVhost) (*Gob
 		Password:              opts.Password,
 		Headers:               opts.Headers,
 		NoCanonicalizeHeaders: opts.NoCanonicalizeHeaders,
+		CacheBypass:           opts.CacheBypass,
 		Cookies:               opts.Cookies,
+		Script:                globalopts.Script,
+		Throttle:              globalopts.Throttle,
+		ConnStats:             globalopts.ConnStats,
 		Method:                opts.Method,
 	}
 
@@ -74,6 +105,21 @@ func (v *GobusterVhost) Name() string {
 	return "VHOST enumeration"
 }
 
+// Target returns the target of the run
+func (v *GobusterVhost) Target() string {
+	// the hostname is appended to the words with --append-domain
+	if u, err := url.Parse(v.options.URL); err == nil {
+		return u.Hostname()
+	}
+	return v.options.URL
+}
+
+// ProbeLatency is the latency probe implementation of gobustervhost, it sends a
+// HEAD request to the url
+func (v *GobusterVhost) ProbeLatency(ctx context.Context) (time.Duration, error) {
+	return v.http.ProbeLatency(ctx, v.options.URL)
+}
+
 // PreRun is the pre run implementation of gobusterdir
 func (v *GobusterVhost) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
 	// add trailing slash
@@ -92,22 +138,51 @@ func (v *GobusterVhost) PreRun(ctx context.Context, progress *libgobuster.Progre
 	}
 
 	// request default vhost for normalBody
-	_, _, _, body, err := v.http.Request(ctx, v.options.URL, libgobuster.RequestOptions{ReturnBody: true})
+	var state tls.ConnectionState
+	statusCode, _, _, body, err := v.http.Request(ctx, v.options.URL, libgobuster.RequestOptions{ReturnBody: true, TLS: &state})
 	if err != nil {
 		return fmt.Errorf("unable to connect to %s: %w", v.options.URL, err)
 	}
-	v.normalBody = body
+	v.normal = baseline{host: urlParsed.Host, statusCode: statusCode, body: body}
+
+	if v.options.CertInfo {
+		if info, ok := libgobuster.CertificateFromState(state); ok {
+			libgobuster.ReportCertificate(progress, v.options.URL, info)
+			names := v.certificateNames(info)
+			progress.QueueWords(names...)
+			progress.MessageChan <- libgobuster.Message{
+				Level:   libgobuster.LevelInfo,
+				Message: fmt.Sprintf("Queued %d vhosts from the certificate", len(names)),
+			}
+		}
+	}
 
 	// request non existent vhost for abnormalBody
-	subdomain := fmt.Sprintf("%s.%s", uuid.New(), v.domain)
-	_, _, _, body, err = v.http.Request(ctx, v.options.URL, libgobuster.RequestOptions{Host: subdomain, ReturnBody: true})
+	subdomain := fmt.Sprintf("%s.%s", libgobuster.RandomUUID(), v.domain)
+	statusCode, _, _, body, err = v.http.Request(ctx, v.options.URL, libgobuster.RequestOptions{Host: subdomain, ReturnBody: true})
 	if err != nil {
 		return fmt.Errorf("unable to connect to %s: %w", v.options.URL, err)
 	}
-	v.abnormalBody = body
+	v.abnormal = baseline{host: subdomain, statusCode: statusCode, body: body}
 	return nil
 }
 
+// certificateNames returns the SANs of the certificate below the domain as
+// words of the wordlist
+func (v *GobusterVhost) certificateNames(info libgobuster.CertificateInfo) []string {
+	domain := v.domain
+	if host, _, err := net.SplitHostPort(domain); err == nil {
+		domain = host
+	}
+	names := info.NamesBelow(domain)
+	if !v.options.AppendDomain {
+		for i := range names {
+			names[i] = fmt.Sprintf("%s.%s", names[i], v.domain)
+		}
+	}
+	return names
+}
+
 // ProcessWord is the process implementation of gobusterdir
 func (v *GobusterVhost) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
 	var subdomain string
@@ -118,39 +193,25 @@ func (v *GobusterVhost) ProcessWord(ctx context.Context, word string, progress *
 		subdomain = word
 	}
 
-	tries := 1
-	if v.options.RetryOnTimeout && v.options.RetryAttempts > 0 {
-		// add it so it will be the overall max requests
-		tries += v.options.RetryAttempts
-	}
-
 	var statusCode int
 	var size int64
 	var header http.Header
 	var body []byte
-	for i := 1; i <= tries; i++ {
-		var err error
-		statusCode, size, header, body, err = v.http.Request(ctx, v.options.URL, libgobuster.RequestOptions{Host: subdomain, ReturnBody: true})
-		if err != nil {
-			// check if it's a timeout and if we should try again and try again
-			// otherwise the timeout error is raised
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && i != tries {
-				continue
-			} else if strings.Contains(err.Error(), "invalid control character in URL") {
-				// put error in error chan so it's printed out and ignore it
-				// so gobuster will not quit
-				progress.ErrorChan <- err
-				continue
-			} else {
-				return err
-			}
+	var timing libgobuster.RequestTiming
+	var err error
+	statusCode, size, header, body, err = v.http.Request(ctx, v.options.URL, libgobuster.RequestOptions{Host: subdomain, ReturnBody: true, Timing: &timing})
+	if err != nil {
+		if !strings.Contains(err.Error(), "invalid control character in URL") {
+			return err
 		}
-		break
+		// put error in error chan so it's printed out and ignore it
+		// so gobuster will not quit
+		progress.ErrorChan <- err
 	}
 
 	// subdomain must not match default vhost and non existent vhost
 	// or verbose mode is enabled
-	found := body != nil && !bytes.Equal(body, v.normalBody) && !bytes.Equal(body, v.abnormalBody)
+	found := body != nil && !v.normal.matches(subdomain, statusCode, body) && !v.abnormal.matches(subdomain, statusCode, body)
 	if (found && !v.options.ExcludeLengthParsed.Contains(int(size))) || v.globalopts.Verbose {
 		resultStatus := false
 		if found {
@@ -162,11 +223,23 @@ func (v *GobusterVhost) ProcessWord(ctx context.Context, word string, progress *
 			StatusCode: statusCode,
 			Size:       size,
 			Header:     header,
+			Timing:     timing,
+			ShowTiming: v.globalopts.ShowTiming,
 		}
 	}
 	return nil
 }
 
+// Warnings returns warnings about potentially destructive or noisy configurations
+func (v *GobusterVhost) Warnings() []string {
+	return libgobuster.HTTPWarnings(&v.options.HTTPOptions, v.globalopts)
+}
+
+// WordKind returns the kind of the words for the word policy
+func (v *GobusterVhost) WordKind() libgobuster.WordKind {
+	return libgobuster.WordKindHost
+}
+
 func (v *GobusterVhost) AdditionalWords(word string) []string {
 	return []string{}
 }
@@ -197,7 +270,7 @@ func (v *GobusterVhost) GetConfigString() (string, error) {
 
 	wordlist := "stdin (pipe)"
 	if v.globalopts.Wordlist != "-" {
-		wordlist = v.globalopts.Wordlist
+		wordlist = v.globalopts.WordlistName()
 	}
 	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
 		return "", err
@@ -215,6 +288,12 @@ func (v *GobusterVhost) GetConfigString() (string, error) {
 		}
 	}
 
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
 	if o.Cookies != "" {
 		if _, err := fmt.Fprintf(tw, "[+] Cookies:\t%s\n", o.Cookies); err != nil {
 			return "", err
@@ -239,16 +318,40 @@ func (v *GobusterVhost) GetConfigString() (string, error) {
 		}
 	}
 
+	if o.CacheBypass {
+		if _, err := fmt.Fprintf(tw, "[+] Cache bypass:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
 	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
 		return "", err
 	}
 
+	if v.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", v.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	if v.globalopts.Script != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Script:\t%s\n", v.globalopts.Script); err != nil {
+			return "", err
+		}
+	}
+
 	if _, err := fmt.Fprintf(tw, "[+] Append Domain:\t%t\n", v.options.AppendDomain); err != nil {
 		return "", err
 	}
 
+	if o.CertInfo {
+		if _, err := fmt.Fprintf(tw, "[+] Certificate names:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
 	if len(o.ExcludeLength) > 0 {
-		if _, err := fmt.Fprintf(tw, "[+] Exclude Length:\t%s\n", v.options.ExcludeLengthParsed.Stringify()); err != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Exclude Length:\t%s\n", v.options.ExcludeLengthParsed.String()); err != nil {
 			return "", err
 		}
 	}
@@ -0,0 +1,97 @@
+package gobustervhost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+func TestBaselineMatches(t *testing.T) {
+	t.Parallel()
+
+	b := baseline{host: "x.example.com", statusCode: 404, body: []byte("no site x.example.com")}
+	tt := []struct {
+		testName   string
+		host       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{"Same body", "a.example.com", 404, "no site x.example.com", true},
+		{"Reflected host", "a.example.com", 404, "no site a.example.com", true},
+		{"Other status", "a.example.com", 200, "no site a.example.com", false},
+		{"Other body", "a.example.com", 404, "no site", false},
+		{"Short host", "a", 404, "no site a", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if m := b.matches(x.host, x.statusCode, []byte(x.body)); m != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, m)
+			}
+		})
+	}
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "app.example.com":
+			fmt.Fprint(w, "app")
+		case "admin.example.com":
+			// same page as unknown hosts, but a different status
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, "no site %s", r.Host)
+		default:
+			// the default and unknown vhosts reflect the requested host
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "no site %s", r.Host)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	globalopts := libgobuster.NewOptions()
+	o := NewOptionsVhost()
+	o.URL = ts.URL
+	o.Method = http.MethodGet
+	o.Timeout = 5 * time.Second
+	o.AppendDomain = true
+	o.Domain = "example.com"
+	v, err := NewGobusterVhost(globalopts, o)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	progress := libgobuster.NewProgress()
+	done := make(chan []Result)
+	go func() {
+		var results []Result
+		for r := range progress.ResultChan {
+			results = append(results, r.(Result))
+		}
+		done <- results
+	}()
+	if err := v.PreRun(context.Background(), progress); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	for _, word := range []string{"app", "www", "admin", "a"} {
+		if err := v.ProcessWord(context.Background(), word, progress); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	close(progress.ResultChan)
+
+	results := <-done
+	if len(results) != 2 || results[0].Vhost != "app.example.com" || results[1].Vhost != "admin.example.com" {
+		t.Fatalf("expected app.example.com and admin.example.com, got %+v", results)
+	}
+}
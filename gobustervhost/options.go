@@ -9,13 +9,14 @@ type OptionsVhost struct {
 	libgobuster.HTTPOptions
 	AppendDomain        bool
 	ExcludeLength       string
-	ExcludeLengthParsed libgobuster.Set[int]
+	ExcludeLengthParsed libgobuster.IntRanges
 	Domain              string
+	// CertInfo reports the certificate of https targets and checks the
+	// names of its SANs
+	CertInfo bool
 }
 
 // NewOptionsVhost returns a new initialized OptionsVhost
 func NewOptionsVhost() *OptionsVhost {
-	return &OptionsVhost{
-		ExcludeLengthParsed: libgobuster.NewSet[int](),
-	}
+	return &OptionsVhost{}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/OJ/gobuster/v3/libgobuster"
 	"github.com/fatih/color"
 )
 
@@ -23,6 +24,8 @@ type Result struct {
 	StatusCode int
 	Size       int64
 	Header     http.Header
+	Timing     libgobuster.RequestTiming
+	ShowTiming bool
 }
 
 // ResultToString converts the Result to it's textual representation
@@ -51,5 +54,22 @@ func (r Result) ResultToString() (string, error) {
 		locationString = blue(fmt.Sprintf(" [--> %s]", location))
 	}
 
-	return fmt.Sprintf("%s: %s %s [Size: %d]%s\n", statusText, r.Vhost, statusCode, r.Size, locationString), nil
+	timingString := ""
+	if r.ShowTiming {
+		timingString = fmt.Sprintf(" [%s]", r.Timing)
+	}
+
+	return fmt.Sprintf("%s: %s %s [Size: %d]%s%s\n", statusText, r.Vhost, statusCode, r.Size, timingString, locationString), nil
+}
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	return libgobuster.ResultRecord{
+		Path:       r.Vhost,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Size:       r.Size,
+		Redirect:   r.Header.Get("Location"),
+		Timing:     r.Timing,
+	}
 }
@@ -0,0 +1,98 @@
+package gobusterwasm
+
+// The plugin ABI is the contract between gobuster and a WebAssembly module
+// implementing a mode. It only changes with a new ABIVersion.
+//
+// A plugin exports:
+//
+//	memory
+//	gobuster_abi_version() -> i32
+//	gobuster_alloc(size i32) -> i32
+//	gobuster_process_word(ptr i32, len i32) -> i64
+//
+// gobuster_alloc returns a buffer of the given size in the linear memory of
+// the plugin which is used to pass data to the plugin. gobuster_process_word
+// receives the word and returns the location of a JSON encoded Output packed
+// as ptr<<32 | len, 0 if there is nothing to report.
+//
+// The host module "gobuster" provides:
+//
+//	http_request(ptr i32, len i32) -> i64
+//	log(ptr i32, len i32)
+//
+// http_request receives a JSON encoded Request and returns the location of a
+// JSON encoded Response allocated with gobuster_alloc. log prints a debug
+// message.
+
+import (
+	"fmt"
+)
+
+const (
+	// ABIVersion is the version of the plugin ABI implemented by gobuster
+	ABIVersion = 1
+	// HostModule is the name of the module providing the host functions
+	HostModule = "gobuster"
+)
+
+const (
+	exportVersion     = "gobuster_abi_version"
+	exportAlloc       = "gobuster_alloc"
+	exportProcessWord = "gobuster_process_word"
+)
+
+// Output is returned by gobuster_process_word
+type Output struct {
+	Results []OutputResult `json:"results"`
+	// Error is reported as an error of the word
+	Error string `json:"error,omitempty"`
+}
+
+// OutputResult is a single result of a word
+type OutputResult struct {
+	Path    string   `json:"path"`
+	Found   bool     `json:"found"`
+	Status  int      `json:"status,omitempty"`
+	Size    int64    `json:"size,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Request is passed to http_request. URL is used as is, otherwise Path is
+// appended to the url of the target
+type Request struct {
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Path    string            `json:"path,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Response is returned by http_request
+type Response struct {
+	Status  int               `json:"status"`
+	Size    int64             `json:"size"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// packLocation packs a pointer and a length into a single value
+func packLocation(ptr, length uint32) uint64 {
+	return uint64(ptr)<<32 | uint64(length)
+}
+
+// unpackLocation splits a packed value into the pointer and the length
+func unpackLocation(v uint64) (uint32, uint32) {
+	return uint32(v >> 32), uint32(v)
+}
+
+// checkExports makes sure the module implements the ABI
+func checkExports(exports map[string]bool) error {
+	for _, name := range []string{exportVersion, exportAlloc, exportProcessWord} {
+		if !exports[name] {
+			return fmt.Errorf("plugin does not export %s", name)
+		}
+	}
+	return nil
+}
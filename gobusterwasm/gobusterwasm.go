@@ -0,0 +1,456 @@
+package gobusterwasm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// ctxKey is used to pass the progress of the current word to the host functions
+type ctxKey struct{}
+
+// GobusterWasm is the main type to implement the interface
+type GobusterWasm struct {
+	options    *OptionsWasm
+	globalopts *libgobuster.Options
+	http       *libgobuster.HTTPClient
+	runtime    wazero.Runtime
+	compiled   wazero.CompiledModule
+	// instances holds one instance per thread as instances are not thread safe
+	instances chan api.Module
+}
+
+// NewGobusterWasm creates a new initialized GobusterWasm. The plugin is
+// compiled once and instantiated for every thread in PreRun
+func NewGobusterWasm(globalopts *libgobuster.Options, opts *OptionsWasm) (*GobusterWasm, error) {
+	if globalopts == nil {
+		return nil, fmt.Errorf("please provide valid global options")
+	}
+
+	if opts == nil {
+		return nil, fmt.Errorf("please provide valid plugin options")
+	}
+
+	g := GobusterWasm{
+		options:    opts,
+		globalopts: globalopts,
+	}
+
+	basicOptions := libgobuster.BasicHTTPOptions{
+		Proxy:           opts.Proxy,
+		Proxies:         opts.Proxies,
+		ProxyRotation:   opts.ProxyRotation,
+		Timeout:         opts.Timeout,
+		UserAgent:       opts.UserAgent,
+		NoTLSValidation: opts.NoTLSValidation,
+		RetryOnTimeout:  opts.RetryOnTimeout,
+		RetryAttempts:   opts.RetryAttempts,
+		RetryWait:       opts.RetryWait,
+		TLSCertificate:  opts.TLSCertificate,
+		Limits:          opts.Limits,
+		TLSPolicy:       opts.TLSPolicy,
+	}
+
+	httpOpts := libgobuster.HTTPOptions{
+		BasicHTTPOptions:      basicOptions,
+		FollowRedirect:        opts.FollowRedirect,
+		Username:              opts.Username,
+		Password:              opts.Password,
+		Headers:               opts.Headers,
+		NoCanonicalizeHeaders: opts.NoCanonicalizeHeaders,
+		CacheBypass:           opts.CacheBypass,
+		Cookies:               opts.Cookies,
+		Method:                opts.Method,
+		Script:                globalopts.Script,
+		Throttle:              globalopts.Throttle,
+		ConnStats:             globalopts.ConnStats,
+	}
+
+	h, err := libgobuster.NewHTTPClient(&httpOpts)
+	if err != nil {
+		return nil, err
+	}
+	g.http = h
+
+	binary, err := os.ReadFile(opts.Plugin)
+	if err != nil {
+		return nil, fmt.Errorf("could not read plugin: %w", err)
+	}
+
+	ctx := context.Background()
+	g.runtime = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	// plugins compiled for WASI need the system interface even if they don't use it
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, g.runtime); err != nil {
+		return nil, fmt.Errorf("could not instantiate wasi: %w", err)
+	}
+	if err := g.instantiateHostModule(ctx); err != nil {
+		return nil, fmt.Errorf("could not instantiate host module: %w", err)
+	}
+
+	g.compiled, err = g.runtime.CompileModule(ctx, binary)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile plugin: %w", err)
+	}
+
+	exports := make(map[string]bool)
+	for name := range g.compiled.ExportedFunctions() {
+		exports[name] = true
+	}
+	if err := checkExports(exports); err != nil {
+		return nil, err
+	}
+	if _, ok := g.compiled.ExportedMemories()["memory"]; !ok {
+		return nil, fmt.Errorf("plugin does not export its memory")
+	}
+
+	return &g, nil
+}
+
+// Name should return the name of the plugin
+func (w *GobusterWasm) Name() string {
+	return fmt.Sprintf("wasm plugin %s", filepath.Base(w.options.Plugin))
+}
+
+// Target returns the target of the run
+func (w *GobusterWasm) Target() string {
+	return w.options.URL
+}
+
+// PreRun instantiates the plugin for every thread and checks the ABI version
+func (w *GobusterWasm) PreRun(ctx context.Context, progress *libgobuster.Progress) error {
+	w.instances = make(chan api.Module, w.globalopts.Threads)
+	for i := 0; i < w.globalopts.Threads; i++ {
+		// reactor style modules export _initialize instead of _start
+		config := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize")
+		mod, err := w.runtime.InstantiateModule(ctx, w.compiled, config)
+		if err != nil {
+			return fmt.Errorf("could not instantiate plugin: %w", err)
+		}
+
+		ret, err := mod.ExportedFunction(exportVersion).Call(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get the abi version of the plugin: %w", err)
+		}
+		if version := uint32(ret[0]); version != ABIVersion {
+			return fmt.Errorf("plugin implements abi version %d but gobuster supports version %d", version, ABIVersion)
+		}
+
+		w.instances <- mod
+	}
+	return nil
+}
+
+// ProcessWord passes the word to the plugin and reports its results
+func (w *GobusterWasm) ProcessWord(ctx context.Context, word string, progress *libgobuster.Progress) error {
+	var mod api.Module
+	select {
+	case <-ctx.Done():
+		return nil
+	case mod = <-w.instances:
+	}
+	defer func() { w.instances <- mod }()
+
+	ctx = context.WithValue(ctx, ctxKey{}, progress)
+	ptr, err := writeBytes(ctx, mod, []byte(word))
+	if err != nil {
+		return err
+	}
+	ret, err := mod.ExportedFunction(exportProcessWord).Call(ctx, uint64(ptr), uint64(len(word)))
+	if err != nil {
+		// the module is closed if the context was canceled
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("plugin failed on %q: %w", word, err)
+	}
+	if ret[0] == 0 {
+		return nil
+	}
+
+	data, err := readBytes(mod, ret[0])
+	if err != nil {
+		return err
+	}
+	var output Output
+	if err := json.Unmarshal(data, &output); err != nil {
+		return fmt.Errorf("plugin returned invalid output for %q: %w", word, err)
+	}
+
+	for _, r := range output.Results {
+		if !r.Found && !w.globalopts.Verbose {
+			continue
+		}
+		path := r.Path
+		if path == "" {
+			path = word
+		}
+		progress.ResultChan <- Result{
+			Verbose:    w.globalopts.Verbose,
+			Found:      r.Found,
+			URL:        w.joinURL(path),
+			Path:       path,
+			StatusCode: r.Status,
+			Size:       r.Size,
+			Message:    r.Message,
+			Tags:       r.Tags,
+		}
+	}
+
+	if output.Error != "" {
+		return fmt.Errorf("plugin error on %q: %s", word, output.Error)
+	}
+	return nil
+}
+
+// instantiateHostModule provides the functions plugins can import
+func (w *GobusterWasm) instantiateHostModule(ctx context.Context) error {
+	_, err := w.runtime.NewHostModuleBuilder(HostModule).
+		NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(w.hostHTTPRequest), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI64}).
+		Export("http_request").
+		NewFunctionBuilder().
+		WithGoModuleFunction(api.GoModuleFunc(w.hostLog), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).
+		Export("log").
+		Instantiate(ctx)
+	return err
+}
+
+// hostHTTPRequest implements the http_request host function. Errors are
+// returned to the plugin inside the response
+func (w *GobusterWasm) hostHTTPRequest(ctx context.Context, mod api.Module, stack []uint64) {
+	var resp Response
+
+	data, err := readBytes(mod, packLocation(uint32(stack[0]), uint32(stack[1])))
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		var req Request
+		if err := json.Unmarshal(data, &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp = w.request(ctx, req)
+		}
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		stack[0] = 0
+		return
+	}
+	ptr, err := writeBytes(ctx, mod, out)
+	if err != nil {
+		stack[0] = 0
+		return
+	}
+	stack[0] = packLocation(ptr, uint32(len(out)))
+}
+
+// hostLog implements the log host function
+func (w *GobusterWasm) hostLog(ctx context.Context, mod api.Module, stack []uint64) {
+	progress, ok := ctx.Value(ctxKey{}).(*libgobuster.Progress)
+	if !ok {
+		return
+	}
+	data, err := readBytes(mod, packLocation(uint32(stack[0]), uint32(stack[1])))
+	if err != nil {
+		return
+	}
+	progress.MessageChan <- libgobuster.Message{
+		Level:   libgobuster.LevelDebug,
+		Message: fmt.Sprintf("%s: %s", filepath.Base(w.options.Plugin), data),
+	}
+}
+
+// request sends the request of the plugin with the http options of gobuster
+func (w *GobusterWasm) request(ctx context.Context, req Request) Response {
+	fullURL := req.URL
+	if fullURL == "" {
+		fullURL = w.joinURL(req.Path)
+	}
+
+	requestOptions := libgobuster.RequestOptions{
+		Method:     req.Method,
+		ReturnBody: true,
+	}
+	if req.Body != "" {
+		requestOptions.Body = strings.NewReader(req.Body)
+	}
+	if len(req.Headers) > 0 {
+		requestOptions.ModifiedHeaders = append(requestOptions.ModifiedHeaders, w.options.Headers...)
+		for name, value := range req.Headers {
+			requestOptions.ModifiedHeaders = append(requestOptions.ModifiedHeaders, libgobuster.HTTPHeader{Name: name, Value: value})
+		}
+	}
+
+	var statusCode int
+	var size int64
+	var header http.Header
+	var body []byte
+	statusCode, size, header, body, err := w.http.Request(ctx, fullURL, requestOptions)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	return Response{Status: statusCode, Size: size, Headers: headers, Body: string(body)}
+}
+
+// joinURL appends the path to the url of the target
+func (w *GobusterWasm) joinURL(path string) string {
+	return strings.TrimSuffix(w.options.URL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// writeBytes copies the data into a buffer allocated by the plugin
+func writeBytes(ctx context.Context, mod api.Module, data []byte) (uint32, error) {
+	ret, err := mod.ExportedFunction(exportAlloc).Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, fmt.Errorf("could not allocate plugin memory: %w", err)
+	}
+	ptr := uint32(ret[0])
+	if !mod.Memory().Write(ptr, data) {
+		return 0, fmt.Errorf("plugin allocated invalid memory at %d (%d bytes)", ptr, len(data))
+	}
+	return ptr, nil
+}
+
+// readBytes copies the packed location out of the memory of the plugin
+func readBytes(mod api.Module, location uint64) ([]byte, error) {
+	ptr, length := unpackLocation(location)
+	data, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("plugin returned invalid memory at %d (%d bytes)", ptr, length)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Warnings returns warnings about potentially destructive or noisy configurations
+func (w *GobusterWasm) Warnings() []string {
+	return libgobuster.HTTPWarnings(&w.options.HTTPOptions, w.globalopts)
+}
+
+// AdditionalWords returns additional words to process
+func (w *GobusterWasm) AdditionalWords(word string) []string {
+	return []string{}
+}
+
+// GetConfigString returns the string representation of the current config
+func (w *GobusterWasm) GetConfigString() (string, error) {
+	var buffer bytes.Buffer
+	bw := bufio.NewWriter(&buffer)
+	tw := tabwriter.NewWriter(bw, 0, 5, 3, ' ', 0)
+	o := w.options
+	if _, err := fmt.Fprintf(tw, "[+] Url:\t%s\n", o.URL); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Plugin:\t%s (ABI version %d)\n", o.Plugin, ABIVersion); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Threads:\t%d\n", w.globalopts.Threads); err != nil {
+		return "", err
+	}
+
+	if w.globalopts.Delay > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Delay:\t%s\n", w.globalopts.Delay); err != nil {
+			return "", err
+		}
+	}
+
+	wordlist := "stdin (pipe)"
+	if w.globalopts.Wordlist != "-" {
+		wordlist = w.globalopts.WordlistName()
+	}
+	if _, err := fmt.Fprintf(tw, "[+] Wordlist:\t%s\n", wordlist); err != nil {
+		return "", err
+	}
+
+	if w.globalopts.PatternFile != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Patterns:\t%s (%d entries)\n", w.globalopts.PatternFile, len(w.globalopts.Patterns)); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Proxy != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Proxy:\t%s\n", o.Proxy); err != nil {
+			return "", err
+		}
+	}
+
+	if len(o.Proxies) > 0 {
+		if _, err := fmt.Fprintf(tw, "[+] Proxies:\t%d (%s)\n", len(o.Proxies), o.ProxyRotation); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Cookies != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Cookies:\t%s\n", o.Cookies); err != nil {
+			return "", err
+		}
+	}
+
+	if o.UserAgent != "" {
+		if _, err := fmt.Fprintf(tw, "[+] User Agent:\t%s\n", o.UserAgent); err != nil {
+			return "", err
+		}
+	}
+
+	if o.Username != "" {
+		if _, err := fmt.Fprintf(tw, "[+] Auth User:\t%s\n", o.Username); err != nil {
+			return "", err
+		}
+	}
+
+	if w.globalopts.Verbose {
+		if _, err := fmt.Fprintf(tw, "[+] Verbose:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if o.CacheBypass {
+		if _, err := fmt.Fprintf(tw, "[+] Cache bypass:\ttrue\n"); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := fmt.Fprintf(tw, "[+] Timeout:\t%s\n", o.Timeout.String()); err != nil {
+		return "", err
+	}
+
+	if w.globalopts.Throttle != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Rate Limit:\t%s\n", w.globalopts.Throttle); err != nil {
+			return "", err
+		}
+	}
+
+	if w.globalopts.Script != nil {
+		if _, err := fmt.Fprintf(tw, "[+] Script:\t%s\n", w.globalopts.Script); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("error on tostring: %w", err)
+	}
+
+	return strings.TrimSpace(buffer.String()), nil
+}
@@ -0,0 +1,164 @@
+package gobusterwasm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// testOutput is returned by the test plugin for every word
+const testOutput = `{"results":[{"path":"/admin","found":true,"status":200,"tags":["custom"]}]}`
+
+func uleb(v uint64) []byte {
+	var ret []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		ret = append(ret, b)
+		if v == 0 {
+			return ret
+		}
+	}
+}
+
+func sleb(v int64) []byte {
+	var ret []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(ret, b)
+		}
+		ret = append(ret, b|0x80)
+	}
+}
+
+func section(id byte, content ...[]byte) []byte {
+	var body []byte
+	for _, c := range content {
+		body = append(body, c...)
+	}
+	return append(append([]byte{id}, uleb(uint64(len(body)))...), body...)
+}
+
+func name(s string) []byte {
+	return append(uleb(uint64(len(s))), s...)
+}
+
+func function(code ...byte) []byte {
+	// no locals
+	body := append([]byte{0x00}, code...)
+	body = append(body, 0x0b)
+	return append(uleb(uint64(len(body))), body...)
+}
+
+// testPlugin assembles a minimal plugin which returns testOutput for every word
+func testPlugin(version int64) []byte {
+	const dataOffset = 1024
+	const allocOffset = 2048
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, section(1,
+		[]byte{0x03},
+		[]byte{0x60, 0x00, 0x01, 0x7f},
+		[]byte{0x60, 0x01, 0x7f, 0x01, 0x7f},
+		[]byte{0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e},
+	)...)
+	module = append(module, section(3, []byte{0x03, 0x00, 0x01, 0x02})...)
+	module = append(module, section(5, []byte{0x01, 0x00, 0x01})...)
+	module = append(module, section(7,
+		[]byte{0x04},
+		name("memory"), []byte{0x02, 0x00},
+		name(exportVersion), []byte{0x00, 0x00},
+		name(exportAlloc), []byte{0x00, 0x01},
+		name(exportProcessWord), []byte{0x00, 0x02},
+	)...)
+	module = append(module, section(10,
+		[]byte{0x03},
+		function(append([]byte{0x41}, sleb(version)...)...),
+		function(append([]byte{0x41}, sleb(allocOffset)...)...),
+		function(append([]byte{0x42}, sleb(int64(packLocation(dataOffset, uint32(len(testOutput)))))...)...),
+	)...)
+	module = append(module, section(11,
+		[]byte{0x01, 0x00, 0x41}, sleb(dataOffset), []byte{0x0b},
+		name(testOutput),
+	)...)
+	return module
+}
+
+func newTestPlugin(t *testing.T, version int64) *GobusterWasm {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "plugin.wasm")
+	if err := os.WriteFile(file, testPlugin(version), 0o600); err != nil {
+		t.Fatalf("could not write plugin: %v", err)
+	}
+
+	globalopts := libgobuster.NewOptions()
+	globalopts.Threads = 1
+	opts := NewOptionsWasm()
+	opts.URL = "http://localhost"
+	opts.Timeout = time.Second
+	opts.Plugin = file
+
+	plugin, err := NewGobusterWasm(globalopts, opts)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	return plugin
+}
+
+func TestProcessWord(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestPlugin(t, ABIVersion)
+	ctx := context.Background()
+	progress := libgobuster.NewProgress()
+	if err := plugin.PreRun(ctx, progress); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- plugin.ProcessWord(ctx, "admin", progress)
+	}()
+
+	r, ok := (<-progress.ResultChan).(Result)
+	if !ok {
+		t.Fatal("expected a result of the wasm plugin")
+	}
+	if r.Path != "/admin" || r.URL != "http://localhost/admin" || r.StatusCode != 200 || !r.Found {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if len(r.Tags) != 1 || r.Tags[0] != "custom" {
+		t.Fatalf("expected the tags of the plugin, got %v", r.Tags)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+}
+
+func TestPreRunVersionMismatch(t *testing.T) {
+	t.Parallel()
+
+	plugin := newTestPlugin(t, ABIVersion+1)
+	if err := plugin.PreRun(context.Background(), libgobuster.NewProgress()); err == nil {
+		t.Fatal("expected an error for an unsupported abi version")
+	}
+}
+
+func TestPackLocation(t *testing.T) {
+	t.Parallel()
+
+	ptr, length := unpackLocation(packLocation(1024, 77))
+	if ptr != 1024 || length != 77 {
+		t.Fatalf("expected 1024 and 77, got %d and %d", ptr, length)
+	}
+}
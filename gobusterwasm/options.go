@@ -0,0 +1,33 @@
+package gobusterwasm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// OptionsWasm is the struct to hold all options for this plugin
+type OptionsWasm struct {
+	libgobuster.HTTPOptions
+	// Plugin is the path to the WebAssembly module implementing the mode
+	Plugin string
+}
+
+// NewOptionsWasm returns a new initialized OptionsWasm
+func NewOptionsWasm() *OptionsWasm {
+	return &OptionsWasm{}
+}
+
+// ValidationProblems returns all problems of the wasm options
+func (opt *OptionsWasm) ValidationProblems() []libgobuster.ValidationProblem {
+	problems := opt.HTTPOptions.ValidationProblems()
+
+	if opt.Plugin == "" {
+		problems = append(problems, libgobuster.ValidationProblem{Option: "plugin", Problem: "is required", Suggestion: "use --plugin mode.wasm"})
+	} else if _, err := os.Stat(opt.Plugin); err != nil {
+		problems = append(problems, libgobuster.ValidationProblem{Option: "plugin", Problem: fmt.Sprintf("file %q can not be read: %v", opt.Plugin, err), Suggestion: "check the path"})
+	}
+
+	return problems
+}
@@ -0,0 +1,81 @@
+package gobusterwasm
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+	"github.com/fatih/color"
+)
+
+var (
+	yellow = color.New(color.FgYellow).FprintfFunc()
+	green  = color.New(color.FgGreen).FprintfFunc()
+	red    = color.New(color.FgRed).FprintfFunc()
+)
+
+// Result represents a single result reported by the plugin
+type Result struct {
+	Verbose    bool
+	Found      bool
+	URL        string
+	Path       string
+	StatusCode int
+	Size       int64
+	Message    string
+	Tags       []string
+}
+
+// ResultToString converts the Result to it's textual representation
+func (r Result) ResultToString() (string, error) {
+	buf := &bytes.Buffer{}
+
+	c := green
+
+	// Prefix if we're in verbose mode
+	if r.Verbose {
+		if r.Found {
+			c(buf, "Found: ")
+		} else {
+			c = yellow
+			c(buf, "Missed: ")
+		}
+	} else if r.Found {
+		c(buf, "Found: ")
+	}
+
+	c(buf, "%s", r.Path)
+
+	if r.StatusCode > 0 {
+		c(buf, " (Status: %d)", r.StatusCode)
+	}
+
+	if r.Size > 0 {
+		c(buf, " [Size: %d]", r.Size)
+	}
+
+	if r.Message != "" {
+		c(buf, " [%s]", r.Message)
+	}
+
+	if len(r.Tags) > 0 {
+		red(buf, " [%s]", strings.Join(r.Tags, ","))
+	}
+
+	c(buf, "\n")
+
+	s := buf.String()
+	return s, nil
+}
+
+// ResultToRecord converts the Result to it's mode independent representation
+func (r Result) ResultToRecord() libgobuster.ResultRecord {
+	return libgobuster.ResultRecord{
+		URL:        r.URL,
+		Path:       r.Path,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Size:       r.Size,
+		Tags:       r.Tags,
+	}
+}
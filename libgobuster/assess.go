@@ -0,0 +1,105 @@
+package libgobuster
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// AssessOrigin is the origin sent in requests to check for permissive CORS configurations
+const AssessOrigin = "https://gobuster.example.com"
+
+const (
+	// TagCORSReflected is added if the server reflects arbitrary origins
+	TagCORSReflected = "cors-reflected"
+	// TagCORSWildcard is added if the server allows all origins
+	TagCORSWildcard = "cors-wildcard"
+	// TagDirectoryListing is added if the response looks like a directory listing
+	TagDirectoryListing = "dir-listing"
+)
+
+// nolint:gochecknoglobals
+var (
+	// security headers and the tag added if they are missing
+	securityHeaders = []struct {
+		name  string
+		tag   string
+		https bool
+	}{
+		{"Content-Security-Policy", "missing-csp", false},
+		{"X-Content-Type-Options", "missing-xcto", false},
+		{"X-Frame-Options", "missing-xfo", false},
+		{"Strict-Transport-Security", "missing-hsts", true},
+	}
+	directoryListingIndicators = [][]byte{
+		[]byte("<title>Index of /"),
+		[]byte("<h1>Index of /"),
+		[]byte("<title>Directory listing for /"),
+		[]byte("[To Parent Directory]"),
+		[]byte("<title>Directory Listing For /"),
+	}
+)
+
+// AssessCORS checks the response headers of a request sent with the
+// AssessOrigin origin for permissive CORS configurations
+func AssessCORS(header http.Header) []string {
+	allowOrigin := header.Get("Access-Control-Allow-Origin")
+	switch allowOrigin {
+	case "":
+		return nil
+	case AssessOrigin:
+		return []string{TagCORSReflected}
+	case "*":
+		return []string{TagCORSWildcard}
+	}
+	return nil
+}
+
+// AssessSecurityHeaders returns a tag for every missing security header.
+// Headers only relevant for https are only checked on https urls
+func AssessSecurityHeaders(url string, header http.Header) []string {
+	var tags []string
+	https := strings.HasPrefix(strings.ToLower(url), "https://")
+	for _, h := range securityHeaders {
+		if h.https && !https {
+			continue
+		}
+		if header.Get(h.name) == "" {
+			tags = append(tags, h.tag)
+		}
+	}
+	// a CSP frame-ancestors directive replaces X-Frame-Options
+	if strings.Contains(header.Get("Content-Security-Policy"), "frame-ancestors") {
+		tags = removeString(tags, "missing-xfo")
+	}
+	return tags
+}
+
+// AssessDirectoryListing checks if the body looks like a directory listing
+func AssessDirectoryListing(body []byte) bool {
+	for _, x := range directoryListingIndicators {
+		if bytes.Contains(body, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssessResponse runs all assessments on a response and returns the tags
+func AssessResponse(url string, header http.Header, body []byte) []string {
+	tags := AssessCORS(header)
+	if AssessDirectoryListing(body) {
+		tags = append(tags, TagDirectoryListing)
+	}
+	return append(tags, AssessSecurityHeaders(url, header)...)
+}
+
+func removeString(s []string, e string) []string {
+	ret := s[:0]
+	for _, x := range s {
+		if x != e {
+			ret = append(ret, x)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,70 @@
+package libgobuster
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAssessCORS(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		origin   string
+		expected []string
+	}{
+		{"No CORS", "", nil},
+		{"Reflected", AssessOrigin, []string{TagCORSReflected}},
+		{"Wildcard", "*", []string{TagCORSWildcard}},
+		{"Fixed", "https://www.example.com", nil},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			if x.origin != "" {
+				header.Set("Access-Control-Allow-Origin", x.origin)
+			}
+			if got := AssessCORS(header); !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestAssessSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	header.Set("X-Content-Type-Options", "nosniff")
+
+	if got := AssessSecurityHeaders("http://localhost/", header); len(got) != 0 {
+		t.Fatalf("expected no tags, got %v", got)
+	}
+
+	expected := []string{"missing-hsts"}
+	if got := AssessSecurityHeaders("https://localhost/", header); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	expected = []string{"missing-csp", "missing-xcto", "missing-xfo", "missing-hsts"}
+	if got := AssessSecurityHeaders("https://localhost/", http.Header{}); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestAssessDirectoryListing(t *testing.T) {
+	t.Parallel()
+
+	if !AssessDirectoryListing([]byte("<html><head><title>Index of /files</title></head></html>")) {
+		t.Fatal("expected a directory listing")
+	}
+	if AssessDirectoryListing([]byte("<html><head><title>Welcome</title></head></html>")) {
+		t.Fatal("expected no directory listing")
+	}
+}
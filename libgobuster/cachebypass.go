@@ -0,0 +1,31 @@
+package libgobuster
+
+import (
+	"net/http"
+)
+
+const (
+	// CacheBypassParameter is the query parameter added by the cache bypass
+	CacheBypassParameter = "gbcb"
+	// cacheBypassValue is replaced with a fresh value in every request
+	cacheBypassValue = "{{rand}}"
+)
+
+// addCacheBypass adds a random query parameter so caches fetch the response
+// from the origin
+func addCacheBypass(req *http.Request, value string) {
+	// keep the existing query as it is, it might contain fuzzed values
+	parameter := CacheBypassParameter + "=" + value
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = parameter
+	} else {
+		req.URL.RawQuery += "&" + parameter
+	}
+}
+
+// addCacheBypassHeaders adds the headers asking caches to fetch the response
+// from the origin
+func addCacheBypassHeaders(header http.Header) {
+	header.Set("Cache-Control", "no-cache, no-store, max-age=0")
+	header.Set("Pragma", "no-cache")
+}
@@ -0,0 +1,45 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheBypass(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+	var cacheControl, pragma string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		cacheControl = r.Header.Get("Cache-Control")
+		pragma = r.Header.Get("Pragma")
+	}))
+	defer ts.Close()
+
+	c, err := NewHTTPClient(&HTTPOptions{CacheBypass: true, Headers: []HTTPHeader{{Name: "Pragma", Value: "custom"}}})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	for _, u := range []string{ts.URL + "/admin", ts.URL + "/admin?a=%2f"} {
+		if _, _, _, _, err := c.Request(context.Background(), u, RequestOptions{}); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+
+	if len(queries) != 2 || len(queries[0]) != len("gbcb=")+16 || queries[1][:len("a=%2f&gbcb=")] != "a=%2f&gbcb=" {
+		t.Fatalf("unexpected queries %v", queries)
+	}
+	if queries[0] == queries[1][len("a=%2f&"):] {
+		t.Fatal("expected a new value in every request")
+	}
+	if cacheControl == "" {
+		t.Fatal("expected a cache control header")
+	}
+	// custom headers win
+	if pragma != "custom" {
+		t.Fatalf("expected the custom pragma header, got %q", pragma)
+	}
+}
@@ -0,0 +1,99 @@
+package libgobuster
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TagCachePoisoning is added if an unkeyed header is reflected in a
+// cacheable response. The header is added to the tag
+const TagCachePoisoning = "cache-poisoning"
+
+// nolint:gochecknoglobals
+var (
+	// UnkeyedHeaders are usually not part of the cache key but often used
+	// by applications to build urls
+	UnkeyedHeaders = []string{"X-Forwarded-Host", "X-Host", "X-Forwarded-Server", "X-Original-Host"}
+	// cacheStatusHeaders are set by caches and CDNs
+	cacheStatusHeaders = []string{"Age", "X-Cache", "X-Cache-Hits", "CF-Cache-Status", "X-Varnish", "X-Served-By", "Akamai-Cache-Status"}
+)
+
+// CachePoisoningProbe holds the headers of a probe request. Every unkeyed
+// header gets its own canary so reflections can be attributed
+type CachePoisoningProbe struct {
+	Headers []HTTPHeader
+}
+
+// NewCachePoisoningProbe returns a probe with random canaries
+func NewCachePoisoningProbe() CachePoisoningProbe {
+	var p CachePoisoningProbe
+	for _, h := range UnkeyedHeaders {
+		canary := strings.ReplaceAll(RandomUUID(), "-", "")[:12] + ".gobuster.example.com"
+		p.Headers = append(p.Headers, HTTPHeader{Name: h, Value: canary})
+	}
+	return p
+}
+
+// Assess returns a tag for every unkeyed header reflected in the response
+// if the response is cacheable
+func (p CachePoisoningProbe) Assess(header http.Header, body []byte) []string {
+	if !IsCacheable(header) {
+		return nil
+	}
+
+	var tags []string
+	for _, h := range p.Headers {
+		if reflects(h.Value, header, body) {
+			tags = append(tags, TagCachePoisoning+":"+h.Name)
+		}
+	}
+	return tags
+}
+
+// reflects checks if the value is contained in the response headers or body
+func reflects(value string, header http.Header, body []byte) bool {
+	if bytes.Contains(body, []byte(value)) {
+		return true
+	}
+	for _, values := range header {
+		for _, v := range values {
+			if strings.Contains(v, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsCacheable checks if a response is likely stored by a cache. Responses
+// marked as private or no-store are never cacheable, otherwise an explicit
+// lifetime or headers of caches are required
+func IsCacheable(header http.Header) bool {
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	if strings.Contains(cacheControl, "no-store") || strings.Contains(cacheControl, "private") {
+		return false
+	}
+
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "public" {
+			return true
+		}
+		for _, prefix := range []string{"max-age=", "s-maxage="} {
+			if strings.HasPrefix(directive, prefix) {
+				if age, err := strconv.Atoi(strings.TrimPrefix(directive, prefix)); err == nil && age > 0 {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, h := range cacheStatusHeaders {
+		if header.Get(h) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package libgobuster
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsCacheable(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		header   http.Header
+		expected bool
+	}{
+		{"Nothing", http.Header{}, false},
+		{"Public", http.Header{"Cache-Control": {"public"}}, true},
+		{"MaxAge", http.Header{"Cache-Control": {"max-age=300"}}, true},
+		{"MaxAgeZero", http.Header{"Cache-Control": {"max-age=0"}}, false},
+		{"SMaxAge", http.Header{"Cache-Control": {"no-cache, s-maxage=60"}}, true},
+		{"Private", http.Header{"Cache-Control": {"private, max-age=300"}}, false},
+		{"NoStore", http.Header{"Cache-Control": {"no-store"}, "Age": {"10"}}, false},
+		{"CacheHeader", http.Header{"X-Cache": {"MISS"}}, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if got := IsCacheable(x.header); got != x.expected {
+				t.Fatalf("expected %t, got %t", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestCachePoisoningProbe(t *testing.T) {
+	t.Parallel()
+
+	p := NewCachePoisoningProbe()
+	if len(p.Headers) != len(UnkeyedHeaders) || p.Headers[0].Value == p.Headers[1].Value {
+		t.Fatalf("expected a different canary for every header, got %+v", p.Headers)
+	}
+
+	body := []byte(`<script src="https://` + p.Headers[0].Value + `/app.js"></script>`)
+	cacheable := http.Header{"Cache-Control": {"public, max-age=60"}}
+	tags := p.Assess(cacheable, body)
+	if len(tags) != 1 || tags[0] != "cache-poisoning:X-Forwarded-Host" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	location := http.Header{"X-Cache": {"HIT"}, "Location": {"https://" + p.Headers[1].Value + "/login"}}
+	if tags := p.Assess(location, nil); len(tags) != 1 || tags[0] != "cache-poisoning:X-Host" {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	if tags := p.Assess(http.Header{}, body); len(tags) != 0 {
+		t.Fatalf("expected no tags for uncacheable responses, got %v", tags)
+	}
+}
@@ -0,0 +1,130 @@
+package libgobuster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// CertificateInfo holds the details of a server certificate
+type CertificateInfo struct {
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	// SANs are the dns names and ip addresses of the certificate
+	SANs []string
+}
+
+// NewCertificateInfo returns the details of the certificate
+func NewCertificateInfo(cert *x509.Certificate) CertificateInfo {
+	info := CertificateInfo{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}
+	info.SANs = append(info.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	return info
+}
+
+// CertificateFromState returns the details of the leaf certificate of the
+// connection. It returns false if the server did not send a certificate
+func CertificateFromState(state tls.ConnectionState) (CertificateInfo, bool) {
+	if len(state.PeerCertificates) == 0 {
+		return CertificateInfo{}, false
+	}
+	return NewCertificateInfo(state.PeerCertificates[0]), true
+}
+
+// FetchCertificate connects to the address (host:port) and returns the
+// details of the certificate. The certificate is not validated
+func FetchCertificate(ctx context.Context, address string, timeout time.Duration) (CertificateInfo, error) {
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config: &tls.Config{
+			// the certificate is only inspected, so invalid ones are fine
+			InsecureSkipVerify: true,
+			MinVersion:         tls.VersionTLS10,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return CertificateInfo{}, fmt.Errorf("could not connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	info, ok := CertificateFromState(conn.(*tls.Conn).ConnectionState())
+	if !ok {
+		return CertificateInfo{}, fmt.Errorf("%s did not send a certificate", address)
+	}
+	return info, nil
+}
+
+// Lines returns the details of the certificate as printable lines
+func (c CertificateInfo) Lines(now time.Time) []string {
+	validity := fmt.Sprintf("%s - %s", c.NotBefore.Format("2006-01-02"), c.NotAfter.Format("2006-01-02"))
+	switch {
+	case now.After(c.NotAfter):
+		validity += " (expired)"
+	case now.Before(c.NotBefore):
+		validity += " (not yet valid)"
+	default:
+		validity += fmt.Sprintf(" (expires in %d days)", int(c.NotAfter.Sub(now).Hours()/24))
+	}
+
+	lines := []string{
+		fmt.Sprintf("Subject: %s", c.Subject),
+		fmt.Sprintf("Issuer: %s", c.Issuer),
+		fmt.Sprintf("Valid: %s", validity),
+	}
+	if len(c.SANs) > 0 {
+		lines = append(lines, fmt.Sprintf("SANs: %s", strings.Join(c.SANs, ", ")))
+	}
+	return lines
+}
+
+// NamesBelow returns the dns names of the certificate below the domain
+// without the domain itself, like "www" for "www.example.com". Wildcard
+// names are reduced to the name they are placed on
+func (c CertificateInfo) NamesBelow(domain string) []string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	seen := NewSet[string]()
+	var names []string
+	for _, san := range c.SANs {
+		name := strings.ToLower(strings.TrimSuffix(san, "."))
+		name = strings.TrimPrefix(name, "*.")
+		if !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+		name = strings.TrimSuffix(name, "."+domain)
+		if seen.Contains(name) {
+			continue
+		}
+		seen.Add(name)
+		names = append(names, name)
+	}
+	return names
+}
+
+// ReportCertificate sends the details of the certificate of the host as
+// info messages
+func ReportCertificate(progress *Progress, host string, info CertificateInfo) {
+	progress.MessageChan <- Message{
+		Level:   LevelInfo,
+		Message: fmt.Sprintf("Certificate of %s:", host),
+	}
+	for _, line := range info.Lines(time.Now()) {
+		progress.MessageChan <- Message{
+			Level:   LevelInfo,
+			Message: fmt.Sprintf("  %s", line),
+		}
+	}
+}
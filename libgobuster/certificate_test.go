@@ -0,0 +1,68 @@
+package libgobuster
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchCertificate(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	info, err := FetchCertificate(context.Background(), ts.Listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	// the certificate of the test server is issued for example.com
+	if !reflect.DeepEqual(info.NamesBelow("com"), []string{"example"}) {
+		t.Fatalf("unexpected SANs %v", info.SANs)
+	}
+
+	// the same certificate is returned by requests of the http client
+	client, err := NewHTTPClient(&HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{NoTLSValidation: true, Timeout: 5 * time.Second}})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	var state tls.ConnectionState
+	if _, _, _, _, err := client.Request(context.Background(), ts.URL, RequestOptions{TLS: &state}); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	fromState, ok := CertificateFromState(state)
+	if !ok || !reflect.DeepEqual(fromState, info) {
+		t.Fatalf("expected %+v, got %+v", info, fromState)
+	}
+}
+
+func TestCertificateInfo(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	info := CertificateInfo{
+		Subject:   "CN=example.com",
+		Issuer:    "CN=Example CA",
+		NotBefore: now.Add(-24 * time.Hour),
+		NotAfter:  now.Add(30 * 24 * time.Hour),
+		SANs:      []string{"example.com", "*.example.com", "www.example.com", "api.dev.Example.com", "example.org", "10.0.0.1"},
+	}
+
+	expected := []string{"www", "api.dev"}
+	if got := info.NamesBelow("example.com."); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	lines := info.Lines(now)
+	if len(lines) != 4 || !strings.HasSuffix(lines[2], "(expires in 30 days)") {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	if lines := info.Lines(now.Add(60 * 24 * time.Hour)); !strings.HasSuffix(lines[2], "(expired)") {
+		t.Fatalf("expected the certificate to be expired, got %q", lines[2])
+	}
+}
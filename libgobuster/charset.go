@@ -0,0 +1,52 @@
+package libgobuster
+
+import (
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// isTextContent checks if the content type is text the body can be
+// transcoded for. Responses without a content type are treated as text
+func isTextContent(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+xml"),
+		strings.HasSuffix(mediaType, "+json"):
+		return true
+	}
+	switch mediaType {
+	case "application/xml", "application/json", "application/javascript", "application/x-javascript", "application/xhtml+xml":
+		return true
+	}
+	return false
+}
+
+// toUTF8 transcodes a text body to UTF-8 so the title extraction, the link
+// extraction and the scripts see the same text on legacy sites. The charset
+// is taken from a byte order mark, the content type or the meta tags, bodies
+// without one which are no valid UTF-8 are read as windows-1252 like
+// browsers do. Binary and UTF-8 bodies are returned unchanged
+func toUTF8(body []byte, contentType string) []byte {
+	if utf8.Valid(body) || !isTextContent(contentType) {
+		return body
+	}
+	e, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		return body
+	}
+	decoded, err := e.NewDecoder().Bytes(body)
+	if err != nil {
+		return body
+	}
+	return decoded
+}
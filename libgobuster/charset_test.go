@@ -0,0 +1,65 @@
+package libgobuster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToUTF8(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName    string
+		body        string
+		contentType string
+		expected    string
+	}{
+		{"UTF-8", "<title>Größe</title>", "text/html; charset=iso-8859-1", "<title>Größe</title>"},
+		{"Content-Type", "<title>Gr\xf6\xdfe</title>", "text/html; charset=iso-8859-1", "<title>Größe</title>"},
+		{"Meta tag", "<meta charset=\"gbk\"><title>\xd6\xd0\xce\xc4</title>", "text/html", "<meta charset=\"gbk\"><title>中文</title>"},
+		{"Meta http-equiv", "<meta http-equiv=\"Content-Type\" content=\"text/html; charset=shift_jis\"><p>\x83e\x83X\x83g</p>", "", "<meta http-equiv=\"Content-Type\" content=\"text/html; charset=shift_jis\"><p>テスト</p>"},
+		{"Undeclared", "caf\xe9", "text/plain", "café"},
+		{"JSON", "{\"name\":\"caf\xe9\"}", "application/json; charset=iso-8859-1", "{\"name\":\"café\"}"},
+		{"Binary", "\x89PNG\xe9\xff", "image/png", "\x89PNG\xe9\xff"},
+		{"Invalid Content-Type", "caf\xe9", "text/html; charset", "caf\xe9"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if got := string(toUTF8([]byte(x.body), x.contentType)); got != x.expected {
+				t.Fatalf("Expected %q but got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestRequestCharset(t *testing.T) {
+	t.Parallel()
+
+	body := "<title>Gr\xf6\xdfe</title>"
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(h.Close)
+
+	c, err := NewHTTPClient(&HTTPOptions{})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	_, length, _, got, err := c.Request(context.Background(), h.URL, RequestOptions{ReturnBody: true})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if string(got) != "<title>Größe</title>" {
+		t.Fatalf("Expected a UTF-8 body but got %q", got)
+	}
+	// the length filters still see the size on the wire
+	if length != int64(len(body)) {
+		t.Fatalf("Expected the length %d but got %d", len(body), length)
+	}
+}
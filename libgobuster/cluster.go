@@ -0,0 +1,90 @@
+package libgobuster
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultClusterDistance is the maximum hamming distance of the simhashes of
+// two responses to be considered similar
+const DefaultClusterDistance = 3
+
+// nolint:gochecknoglobals
+var titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Cluster is a group of similar results
+type Cluster struct {
+	StatusCode int
+	Title      string
+	Simhash    uint64
+	Members    []ResultRecord
+}
+
+// ExtractTitle returns the trimmed content of the html title tag
+func ExtractTitle(body []byte) string {
+	match := titleRegex.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(string(match[1])), " ")
+}
+
+// Simhash calculates a 64 bit fuzzy hash of the body. Similar bodies
+// result in hashes with a small hamming distance
+func Simhash(body []byte) uint64 {
+	var weights [64]int
+	tokens := strings.FieldsFunc(strings.ToLower(string(body)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, token := range tokens {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(token))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+	for i := 0; i < 64; i++ {
+		if weights[i] > 0 {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// SimhashDistance returns the hamming distance of two simhashes
+func SimhashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// ClusterRecords groups records with the same status code and title and a
+// similar body. The clusters are returned in the order they were first seen
+func ClusterRecords(records []ResultRecord, maxDistance int) []Cluster {
+	var clusters []Cluster
+Records:
+	for _, r := range records {
+		for i := range clusters {
+			c := &clusters[i]
+			if c.StatusCode == r.StatusCode && c.Title == r.Title && SimhashDistance(c.Simhash, r.Simhash) <= maxDistance {
+				c.Members = append(c.Members, r)
+				continue Records
+			}
+		}
+		clusters = append(clusters, Cluster{
+			StatusCode: r.StatusCode,
+			Title:      r.Title,
+			Simhash:    r.Simhash,
+			Members:    []ResultRecord{r},
+		})
+	}
+	return clusters
+}
@@ -0,0 +1,82 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractTitle(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		body     string
+		expected string
+	}{
+		{"No Title", "<html><body>test</body></html>", ""},
+		{"Simple", "<html><head><title>Test</title></head></html>", "Test"},
+		{"Whitespace", "<TITLE lang=\"en\">\n  Parked   domain\n</TITLE>", "Parked domain"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ExtractTitle([]byte(x.body)); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestClusterRecords(t *testing.T) {
+	t.Parallel()
+
+	parked := "<html><head><title>Parked</title></head><body>This domain is parked. Buy it now at our registrar for the best price, contact us for more information about %s</body></html>"
+	records := []ResultRecord{
+		{Path: "/a", StatusCode: 200, Title: "Parked", Simhash: Simhash([]byte(parked + "a"))},
+		{Path: "/b", StatusCode: 200, Title: "Parked", Simhash: Simhash([]byte(parked + "b"))},
+		{Path: "/c", StatusCode: 200, Title: "Admin", Simhash: Simhash([]byte("<title>Admin</title> login form username password"))},
+		{Path: "/d", StatusCode: 403, Title: "Parked", Simhash: Simhash([]byte(parked))},
+	}
+
+	clusters := ClusterRecords(records, DefaultClusterDistance)
+	if len(clusters) != 3 {
+		t.Fatalf("expected 3 clusters, got %d", len(clusters))
+	}
+	if len(clusters[0].Members) != 2 {
+		t.Fatalf("expected 2 members in the first cluster, got %d", len(clusters[0].Members))
+	}
+}
+
+func TestSimhashDistance(t *testing.T) {
+	t.Parallel()
+
+	a := Simhash([]byte("the quick brown fox jumps over the lazy dog"))
+	b := Simhash([]byte("the quick brown fox jumps over the lazy dog again"))
+	c := Simhash([]byte("completely different content with nothing in common"))
+
+	if SimhashDistance(a, a) != 0 {
+		t.Fatal("expected a distance of 0 for the same hash")
+	}
+	if SimhashDistance(a, b) >= SimhashDistance(a, c) {
+		t.Fatalf("expected similar content to have a smaller distance (%d >= %d)", SimhashDistance(a, b), SimhashDistance(a, c))
+	}
+}
+
+func FuzzExtractTitle(f *testing.F) {
+	f.Add([]byte("<html><head><title>\n  Login  Page\n</title></head></html>"))
+	f.Add([]byte("<TITLE lang=en>x</title><title>y</title>"))
+	f.Add([]byte("<title>unterminated"))
+	f.Add([]byte("<title>\xff\xfe</title>"))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		title := ExtractTitle(body)
+		if strings.TrimSpace(title) != title || strings.ContainsAny(title, "\r\n\t") {
+			t.Fatalf("title %q is not normalized", title)
+		}
+		// the hash is calculated for every response
+		Simhash(body)
+	})
+}
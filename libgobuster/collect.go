@@ -0,0 +1,77 @@
+package libgobuster
+
+import (
+	"sync"
+)
+
+// collector stores all results and errors of a run in memory so library
+// consumers don't need to drain the channels themselves
+type collector struct {
+	mutex   sync.RWMutex
+	results []Result
+	errors  []error
+}
+
+// collect drains all channels of the progress until they are closed. Messages
+// are passed on to the logger
+func (g *Gobuster) collect(wg *sync.WaitGroup) {
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		for r := range g.Progress.ResultChan {
+			g.collector.mutex.Lock()
+			g.collector.results = append(g.collector.results, r)
+			g.collector.mutex.Unlock()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for e := range g.Progress.ErrorChan {
+			g.collector.mutex.Lock()
+			g.collector.errors = append(g.collector.errors, e)
+			g.collector.mutex.Unlock()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for msg := range g.Progress.MessageChan {
+			if g.Opts.Quiet {
+				continue
+			}
+			switch msg.Level {
+			case LevelDebug:
+				g.Logger.Debug(msg.Message)
+			case LevelError:
+				g.Logger.Error(msg.Message)
+			default:
+				g.Logger.Info(msg.Message)
+			}
+		}
+	}()
+}
+
+// CollectedResults returns a snapshot of all results received so far. Results
+// are only collected if CollectResults is set in the options, the list is
+// complete once Run returned
+func (g *Gobuster) CollectedResults() []Result {
+	g.collector.mutex.RLock()
+	defer g.collector.mutex.RUnlock()
+
+	ret := make([]Result, len(g.collector.results))
+	copy(ret, g.collector.results)
+	return ret
+}
+
+// CollectedErrors returns a snapshot of all errors received so far. Errors
+// are only collected if CollectResults is set in the options
+func (g *Gobuster) CollectedErrors() []error {
+	g.collector.mutex.RLock()
+	defer g.collector.mutex.RUnlock()
+
+	ret := make([]error, len(g.collector.errors))
+	copy(ret, g.collector.errors)
+	return ret
+}
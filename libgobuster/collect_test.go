@@ -0,0 +1,137 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type collectResult struct {
+	word string
+}
+
+func (r collectResult) ResultToString() (string, error) {
+	return r.word, nil
+}
+
+func (r collectResult) ResultToRecord() ResultRecord {
+	return ResultRecord{Path: r.word, Found: true}
+}
+
+// collectPlugin reports every word starting with "found" and fails on words
+// starting with "error"
+type collectPlugin struct{}
+
+func (collectPlugin) Name() string { return "collect" }
+
+func (collectPlugin) PreRun(context.Context, *Progress) error { return nil }
+
+func (collectPlugin) ProcessWord(_ context.Context, word string, progress *Progress) error {
+	if strings.HasPrefix(word, "error") {
+		return fmt.Errorf("error on %s", word)
+	}
+	if strings.HasPrefix(word, "found") {
+		progress.ResultChan <- collectResult{word: word}
+	}
+	progress.MessageChan <- Message{Level: LevelDebug, Message: word}
+	return nil
+}
+
+func (collectPlugin) AdditionalWords(string) []string { return nil }
+
+func (collectPlugin) GetConfigString() (string, error) { return "", nil }
+
+func TestCollectedResults(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp("", "wordlist")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("found1\nmissed\nfound2\nerror1\nfound3\n"); err != nil {
+		t.Fatalf("could not write tempfile: %v", err)
+	}
+	f.Close()
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.Wordlist = f.Name()
+	opts.CollectResults = true
+	g, err := NewGobuster(opts, collectPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	results := g.CollectedResults()
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results but got %d", len(results))
+	}
+	found := NewSet[string]()
+	for _, r := range results {
+		found.Add(NewResultRecord(r).Path)
+	}
+	if !found.Contains("found1") || !found.Contains("found2") || !found.Contains("found3") {
+		t.Fatalf("Expected all found words but got %v", found.Stringify())
+	}
+	if errs := g.CollectedErrors(); len(errs) != 1 {
+		t.Fatalf("Expected 1 error but got %v", errs)
+	}
+}
+
+// variantPlugin only processes the php and txt variants of a word, words
+// ending in .png are processed as they are
+type variantPlugin struct {
+	collectPlugin
+}
+
+func (variantPlugin) AdditionalWords(word string) []string {
+	if strings.HasSuffix(word, ".png") {
+		return nil
+	}
+	return []string{word + ".php", word + ".txt"}
+}
+
+func (variantPlugin) SkipWord(word string) bool { return !strings.HasSuffix(word, ".png") }
+
+func TestRunSkipWord(t *testing.T) {
+	t.Parallel()
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte("found1\nfound2.png"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.Wordlist = wordlist
+	opts.CollectResults = true
+	g, err := NewGobuster(opts, variantPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	var got []string
+	for _, r := range g.CollectedResults() {
+		got = append(got, NewResultRecord(r).Path)
+	}
+	sort.Strings(got)
+	expected := []string{"found1.php", "found1.txt", "found2.png"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	// two variants per word, the png word only has one
+	if requests := g.Progress.RequestsExpected(); requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,117 @@
+package libgobuster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// WordSeparator separates the parts of a word combined from multiple wordlists
+const WordSeparator = "\x00"
+
+const (
+	// StrategyClusterbomb combines every word with all words of the other wordlists
+	StrategyClusterbomb = "clusterbomb"
+	// StrategyPitchfork combines the words of all wordlists line by line
+	StrategyPitchfork = "pitchfork"
+)
+
+// ExtraWordlist is an additional wordlist bound to its own keyword. Only
+// plugins splitting the words with SplitWord support extra wordlists
+type ExtraWordlist struct {
+	Keyword  string
+	Filename string
+}
+
+// SplitWord splits a word combined from multiple wordlists. The first part is
+// always the word from the main wordlist followed by one part per extra wordlist
+func SplitWord(word string) []string {
+	return strings.Split(word, WordSeparator)
+}
+
+// readExtraWordlists reads all extra wordlists into memory. Comments and
+// empty lines are skipped
+func readExtraWordlists(wordlists []ExtraWordlist) ([][]string, error) {
+	ret := make([][]string, 0, len(wordlists))
+	for _, w := range wordlists {
+		f, err := os.Open(w.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open wordlist %q for %s: %w", w.Filename, w.Keyword, err)
+		}
+
+		var words []string
+		scanner := newWordlistScanner(f)
+		for scanner.Scan() {
+			if word, ok := wordlistEntry(scanner.Text()); ok {
+				words = append(words, word)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wordlist %q for %s: %w", w.Filename, w.Keyword, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("wordlist %q for %s is empty", w.Filename, w.Keyword)
+		}
+		ret = append(ret, words)
+	}
+	return ret, nil
+}
+
+// combinations returns the number of words sent for every word of the main wordlist
+func (g *Gobuster) combinations() int {
+	if len(g.extraWords) == 0 || g.Opts.Strategy == StrategyPitchfork {
+		return 1
+	}
+	ret := 1
+	for _, words := range g.extraWords {
+		ret *= len(words)
+	}
+	return ret
+}
+
+// wordlistLines returns the number of lines of the main wordlist which will
+// be used. The pitchfork strategy stops at the end of the shortest wordlist
+func (g *Gobuster) wordlistLines(lines int) int {
+	if len(g.extraWords) == 0 || g.Opts.Strategy != StrategyPitchfork {
+		return lines
+	}
+	for _, words := range g.extraWords {
+		if len(words) < lines {
+			lines = len(words)
+		}
+	}
+	return lines
+}
+
+// combine combines the word with the extra wordlists. index is the index of
+// the word in the main wordlist which is needed for the pitchfork strategy
+func (g *Gobuster) combine(word string, index int) []string {
+	if len(g.extraWords) == 0 {
+		return []string{word}
+	}
+
+	if g.Opts.Strategy == StrategyPitchfork {
+		parts := []string{word}
+		for _, words := range g.extraWords {
+			if index >= len(words) {
+				return nil
+			}
+			parts = append(parts, words[index])
+		}
+		return []string{strings.Join(parts, WordSeparator)}
+	}
+
+	ret := []string{word}
+	for _, words := range g.extraWords {
+		next := make([]string, 0, len(ret)*len(words))
+		for _, prefix := range ret {
+			for _, w := range words {
+				next = append(next, prefix+WordSeparator+w)
+			}
+		}
+		ret = next
+	}
+	return ret
+}
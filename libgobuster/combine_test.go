@@ -0,0 +1,84 @@
+package libgobuster
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	t.Parallel()
+
+	extra := [][]string{{"u1", "u2"}, {"p1", "p2", "p3"}}
+
+	tt := []struct {
+		testName     string
+		strategy     string
+		index        int
+		expected     []string
+		combinations int
+		lines        int
+	}{
+		{"Clusterbomb", StrategyClusterbomb, 5, []string{"w\x00u1\x00p1", "w\x00u1\x00p2", "w\x00u1\x00p3", "w\x00u2\x00p1", "w\x00u2\x00p2", "w\x00u2\x00p3"}, 6, 10},
+		{"Pitchfork", StrategyPitchfork, 1, []string{"w\x00u2\x00p2"}, 1, 2},
+		{"Pitchfork Exhausted", StrategyPitchfork, 2, nil, 1, 2},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			g := Gobuster{Opts: &Options{Strategy: x.strategy}, extraWords: extra}
+			if got := g.combine("w", x.index); !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+			if got := g.combinations(); got != x.combinations {
+				t.Fatalf("expected %d combinations, got %d", x.combinations, got)
+			}
+			if got := g.wordlistLines(10); got != x.lines {
+				t.Fatalf("expected %d lines, got %d", x.lines, got)
+			}
+		})
+	}
+}
+
+func TestRunPitchfork(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	wordlist := dir + "/words"
+	extra := dir + "/extra"
+	if err := os.WriteFile(wordlist, []byte("found1\n# comment\nfound2\nfound3\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+	if err := os.WriteFile(extra, []byte("a\nb\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.Wordlist = wordlist
+	opts.CollectResults = true
+	opts.ExtraWordlists = []ExtraWordlist{{Keyword: "FUZ2Z", Filename: extra}}
+	opts.Strategy = StrategyPitchfork
+	g, err := NewGobuster(opts, collectPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	var got []string
+	for _, r := range g.CollectedResults() {
+		got = append(got, strings.Join(SplitWord(NewResultRecord(r).Path), ","))
+	}
+	sort.Strings(got)
+	expected := []string{"found1,a", "found2,b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,156 @@
+package libgobuster
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConnStats counts how the http requests of a run used their connections,
+// to tell a slow target from a misconfigured scan. A low reuse ratio with
+// many TLS handshakes for example means the target closes the connections.
+// All methods can be called concurrently and on a nil ConnStats
+type ConnStats struct {
+	requests      atomic.Int64
+	reused        atomic.Int64
+	dnsLookups    atomic.Int64
+	tlsHandshakes atomic.Int64
+	// ttfb is the sum of the time to first byte of all responses
+	ttfb      atomic.Int64
+	responses atomic.Int64
+	// certificates holds the certificate problems found per host and if
+	// they were allowed by the TLS policy
+	certMutex    sync.Mutex
+	certificates map[string]map[CertificateProblem]bool
+}
+
+// NewConnStats returns new empty connection statistics
+func NewConnStats() *ConnStats {
+	return &ConnStats{}
+}
+
+// trace returns the client trace of a single request started at start. The
+// time to first byte is also stored in timing if set
+func (s *ConnStats) trace(start time.Time, timing *RequestTiming) *httptrace.ClientTrace {
+	trace := httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			ttfb := time.Since(start)
+			if timing != nil {
+				timing.TTFB = ttfb
+			}
+			if s != nil {
+				s.ttfb.Add(int64(ttfb))
+				s.responses.Add(1)
+			}
+		},
+	}
+	if s != nil {
+		trace.GotConn = func(info httptrace.GotConnInfo) {
+			s.requests.Add(1)
+			if info.Reused {
+				s.reused.Add(1)
+			}
+		}
+		trace.DNSDone = func(httptrace.DNSDoneInfo) {
+			s.dnsLookups.Add(1)
+		}
+		trace.TLSHandshakeDone = func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				s.tlsHandshakes.Add(1)
+			}
+		}
+	}
+	return &trace
+}
+
+// Requests returns the number of requests which got a connection
+func (s *ConnStats) Requests() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.requests.Load()
+}
+
+// ReuseRatio returns the share of requests sent over a reused connection
+func (s *ConnStats) ReuseRatio() float64 {
+	requests := s.Requests()
+	if requests == 0 {
+		return 0
+	}
+	return float64(s.reused.Load()) / float64(requests)
+}
+
+// DNSLookups returns the number of DNS lookups for new connections
+func (s *ConnStats) DNSLookups() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.dnsLookups.Load()
+}
+
+// TLSHandshakes returns the number of successful TLS handshakes
+func (s *ConnStats) TLSHandshakes() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.tlsHandshakes.Load()
+}
+
+// AverageTTFB returns the average time to first byte of all responses
+func (s *ConnStats) AverageTTFB() time.Duration {
+	if s == nil {
+		return 0
+	}
+	responses := s.responses.Load()
+	if responses == 0 {
+		return 0
+	}
+	return time.Duration(s.ttfb.Load() / responses)
+}
+
+// addCertificateProblem records a certificate problem of the host
+func (s *ConnStats) addCertificateProblem(host string, problem CertificateProblem, allowed bool) {
+	if s == nil {
+		return
+	}
+	s.certMutex.Lock()
+	defer s.certMutex.Unlock()
+	if s.certificates == nil {
+		s.certificates = make(map[string]map[CertificateProblem]bool)
+	}
+	if s.certificates[host] == nil {
+		s.certificates[host] = make(map[CertificateProblem]bool)
+	}
+	s.certificates[host][problem] = allowed
+}
+
+// CertificateProblems returns the certificate problems found per host like
+// "example.com: expired (allowed), hostname mismatch", sorted by host
+func (s *ConnStats) CertificateProblems() []string {
+	if s == nil {
+		return nil
+	}
+	s.certMutex.Lock()
+	defer s.certMutex.Unlock()
+
+	lines := make([]string, 0, len(s.certificates))
+	for host, problems := range s.certificates {
+		var list []string
+		for problem, allowed := range problems {
+			if allowed {
+				list = append(list, fmt.Sprintf("%s (allowed)", problem))
+			} else {
+				list = append(list, string(problem))
+			}
+		}
+		sort.Strings(list)
+		lines = append(lines, fmt.Sprintf("%s: %s", host, strings.Join(list, ", ")))
+	}
+	sort.Strings(lines)
+	return lines
+}
@@ -0,0 +1,58 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConnStats(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "test")
+	}))
+	defer ts.Close()
+
+	stats := NewConnStats()
+	c, err := NewHTTPClient(&HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{NoTLSValidation: true, Timeout: 5 * time.Second}, ConnStats: stats})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	var timing RequestTiming
+	for i := 0; i < 4; i++ {
+		if _, _, _, _, err := c.Request(context.Background(), ts.URL, RequestOptions{Timing: &timing}); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+
+	if stats.Requests() != 4 {
+		t.Fatalf("Expected 4 requests but got %d", stats.Requests())
+	}
+	// the first request opens the connection, all others reuse it
+	if stats.ReuseRatio() != 0.75 {
+		t.Fatalf("Expected a reuse ratio of 0.75 but got %f", stats.ReuseRatio())
+	}
+	if stats.TLSHandshakes() != 1 {
+		t.Fatalf("Expected 1 TLS handshake but got %d", stats.TLSHandshakes())
+	}
+	// the server listens on an ip address
+	if stats.DNSLookups() != 0 {
+		t.Fatalf("Expected no DNS lookups but got %d", stats.DNSLookups())
+	}
+	if stats.AverageTTFB() <= 0 || timing.TTFB <= 0 {
+		t.Fatalf("Expected the time to first byte to be measured, got %s and %s", stats.AverageTTFB(), timing.TTFB)
+	}
+}
+
+func TestConnStatsNil(t *testing.T) {
+	t.Parallel()
+
+	var stats *ConnStats
+	if stats.Requests() != 0 || stats.ReuseRatio() != 0 || stats.DNSLookups() != 0 || stats.TLSHandshakes() != 0 || stats.AverageTTFB() != 0 {
+		t.Fatal("expected empty statistics")
+	}
+}
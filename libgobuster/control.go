@@ -0,0 +1,118 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// control holds the state of a run started with Start
+type control struct {
+	mutex   sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+	// resume is closed when a paused run is resumed, nil if not paused
+	resume chan struct{}
+}
+
+// Start runs gobuster in the background and returns immediately. Use Wait or
+// Done to wait for the run to finish and Stop to cancel it. A Gobuster object
+// can only be started once
+func (g *Gobuster) Start(ctx context.Context) error {
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+
+	if g.control.started {
+		return fmt.Errorf("gobuster was already started")
+	}
+	g.control.started = true
+
+	ctx, cancel := context.WithCancel(ctx)
+	g.control.cancel = cancel
+	g.control.done = make(chan struct{})
+
+	go func() {
+		defer cancel()
+		err := g.Run(ctx)
+		g.control.mutex.Lock()
+		g.control.err = err
+		g.control.mutex.Unlock()
+		close(g.control.done)
+	}()
+
+	return nil
+}
+
+// Done returns a channel which is closed once a run started with Start is
+// finished. It returns nil if the run was not started
+func (g *Gobuster) Done() <-chan struct{} {
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+	return g.control.done
+}
+
+// Wait blocks until a run started with Start is finished and returns its error
+func (g *Gobuster) Wait() error {
+	done := g.Done()
+	if done == nil {
+		return fmt.Errorf("gobuster was not started")
+	}
+	<-done
+
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+	return g.control.err
+}
+
+// Stop cancels a run started with Start. Use Wait to wait until all workers
+// are finished
+func (g *Gobuster) Stop() {
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+	if g.control.cancel != nil {
+		g.control.cancel()
+	}
+}
+
+// Pause pauses the processing of new words. Requests already in flight are
+// finished
+func (g *Gobuster) Pause() {
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+	if g.control.resume == nil {
+		g.control.resume = make(chan struct{})
+	}
+}
+
+// Resume continues a paused run
+func (g *Gobuster) Resume() {
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+	if g.control.resume != nil {
+		close(g.control.resume)
+		g.control.resume = nil
+	}
+}
+
+// Paused returns true if the run is paused
+func (g *Gobuster) Paused() bool {
+	g.control.mutex.Lock()
+	defer g.control.mutex.Unlock()
+	return g.control.resume != nil
+}
+
+// waitIfPaused blocks while the run is paused by Pause or the memory guard
+func (g *Gobuster) waitIfPaused(ctx context.Context) {
+	g.control.mutex.Lock()
+	resume := g.control.resume
+	g.control.mutex.Unlock()
+	if resume != nil {
+		select {
+		case <-resume:
+		case <-ctx.Done():
+		}
+	}
+	g.memory.wait(ctx)
+}
@@ -0,0 +1,101 @@
+package libgobuster
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func newControlGobuster(t *testing.T) *Gobuster {
+	t.Helper()
+
+	f, err := os.CreateTemp("", "wordlist")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	if _, err := f.WriteString("found1\nfound2\nfound3\n"); err != nil {
+		t.Fatalf("could not write tempfile: %v", err)
+	}
+	f.Close()
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.Wordlist = f.Name()
+	opts.CollectResults = true
+	g, err := NewGobuster(opts, collectPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	return g
+}
+
+func TestStartWait(t *testing.T) {
+	t.Parallel()
+
+	g := newControlGobuster(t)
+	if err := g.Wait(); err == nil {
+		t.Fatal("expected an error when waiting on a run which was not started")
+	}
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Start(context.Background()); err == nil {
+		t.Fatal("expected an error when starting a run twice")
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	select {
+	case <-g.Done():
+	default:
+		t.Fatal("expected the done channel to be closed after Wait")
+	}
+	if got := len(g.CollectedResults()); got != 3 {
+		t.Fatalf("Expected 3 results but got %d", got)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	t.Parallel()
+
+	g := newControlGobuster(t)
+	g.Pause()
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !g.Paused() {
+		t.Fatal("expected the run to be paused")
+	}
+	if got := len(g.CollectedResults()); got != 0 {
+		t.Fatalf("Expected no results while paused but got %d", got)
+	}
+	g.Resume()
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if got := len(g.CollectedResults()); got != 3 {
+		t.Fatalf("Expected 3 results but got %d", got)
+	}
+}
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	g := newControlGobuster(t)
+	g.Pause()
+	if err := g.Start(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	g.Stop()
+	select {
+	case <-g.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the run to finish after Stop")
+	}
+	if got := len(g.CollectedResults()); got != 0 {
+		t.Fatalf("Expected no results after stopping a paused run but got %d", got)
+	}
+}
@@ -0,0 +1,124 @@
+package libgobuster
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	// registers the pure go sqlite driver, so no cgo is needed
+	_ "modernc.org/sqlite"
+)
+
+// databaseVersion is stored as the user_version of the database
+const databaseVersion = 1
+
+// databaseSchema contains a row in runs for every finished run, the lines of
+// its configuration in options and its found results in findings
+const databaseSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	mode TEXT NOT NULL,
+	target TEXT NOT NULL,
+	wordlist TEXT NOT NULL,
+	started TEXT NOT NULL,
+	finished TEXT NOT NULL,
+	requests INTEGER NOT NULL,
+	stop_reason TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runs_target ON runs (target);
+CREATE TABLE IF NOT EXISTS options (
+	run_id INTEGER NOT NULL REFERENCES runs (id),
+	name TEXT NOT NULL,
+	value TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS options_run ON options (run_id);
+CREATE TABLE IF NOT EXISTS findings (
+	id INTEGER PRIMARY KEY,
+	run_id INTEGER NOT NULL REFERENCES runs (id),
+	url TEXT NOT NULL,
+	path TEXT NOT NULL,
+	status INTEGER,
+	size INTEGER NOT NULL,
+	redirect TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	title TEXT NOT NULL,
+	found_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS findings_run ON findings (run_id);
+`
+
+// databaseTime formats the time so the date functions of sqlite can use it
+func databaseTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// SaveRunDatabase stores the run, its configuration and its findings in the
+// sqlite database, which is created if needed. config is the configuration
+// as printed in the banner. It returns the id of the run in the database
+func SaveRunDatabase(file string, run RunRecord, config string) (int64, error) {
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		return 0, fmt.Errorf("could not open database: %w", err)
+	}
+	defer db.Close()
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("could not open database %s: %w", file, err)
+	}
+	if version > databaseVersion {
+		return 0, fmt.Errorf("database %s was created by a newer version of gobuster", file)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	// rolling back a committed transaction does nothing
+	defer tx.Rollback() // nolint:errcheck
+
+	if _, err := tx.Exec(databaseSchema); err != nil {
+		return 0, fmt.Errorf("could not create tables: %w", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", databaseVersion)); err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec("INSERT INTO runs (mode, target, wordlist, started, finished, requests, stop_reason) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		run.Mode, run.Target, run.Wordlist, databaseTime(run.Start), databaseTime(run.End), run.Requests, string(run.StopReason))
+	if err != nil {
+		return 0, fmt.Errorf("could not save run: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, s := range parseReportConfig(config) {
+		if _, err := tx.Exec("INSERT INTO options (run_id, name, value) VALUES (?, ?, ?)", id, s.Name, s.Value); err != nil {
+			return 0, fmt.Errorf("could not save options: %w", err)
+		}
+	}
+
+	insert, err := tx.Prepare("INSERT INTO findings (run_id, url, path, status, size, redirect, tags, title, found_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer insert.Close()
+	for _, r := range run.Results {
+		// results of modes without status codes have no status
+		var status sql.NullInt64
+		if r.StatusCode > 0 {
+			status = sql.NullInt64{Int64: int64(r.StatusCode), Valid: true}
+		}
+		if _, err := insert.Exec(id, r.URL, r.Path, status, r.Size, r.Redirect, strings.Join(r.Tags, ","), r.Title, databaseTime(r.Timestamp)); err != nil {
+			return 0, fmt.Errorf("could not save findings: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("could not save run: %w", err)
+	}
+	return id, nil
+}
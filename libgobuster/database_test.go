@@ -0,0 +1,81 @@
+package libgobuster
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveRunDatabase(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "gobuster.db")
+	start := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	config := "[+] Url:          http://localhost\n[+] Threads:      10\n"
+	first := RunRecord{
+		Mode: "dir", Target: "http://localhost", Wordlist: "words.txt", Start: start, End: start.Add(time.Minute), Requests: 100,
+		Results: []ResultRecord{
+			{URL: "http://localhost/admin", Path: "/admin", Found: true, StatusCode: 301, Redirect: "/admin/", Timestamp: start},
+			{URL: "http://localhost/old", Path: "/old", Found: true, StatusCode: 200, Timestamp: start},
+		},
+	}
+	second := first
+	second.Start = start.Add(24 * time.Hour)
+	second.StopReason = StopMaxTime
+	second.Results = []ResultRecord{
+		{URL: "http://localhost/admin", Path: "/admin", Found: true, StatusCode: 301, Redirect: "/admin/", Timestamp: start},
+		{URL: "http://localhost/.git/HEAD", Path: "/.git/HEAD", Found: true, StatusCode: 200, Tags: []string{"secret-file", "vcs"}, Timestamp: start},
+	}
+
+	firstID, err := SaveRunDatabase(file, first, config)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	secondID, err := SaveRunDatabase(file, second, config)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if firstID == secondID {
+		t.Fatalf("expected the runs to get different ids, got %d", firstID)
+	}
+
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	defer db.Close()
+
+	// the findings of the second run which are new
+	rows, err := db.Query("SELECT path, tags FROM findings WHERE run_id = ? AND path NOT IN (SELECT path FROM findings WHERE run_id = ?)", secondID, firstID)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var path, tags string
+		if err := rows.Scan(&path, &tags); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		got = append(got, path+" "+tags)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if expected := []string{"/.git/HEAD secret-file,vcs"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	var threads, stopReason, started string
+	if err := db.QueryRow("SELECT value FROM options WHERE run_id = ? AND name = 'Threads'", secondID).Scan(&threads); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := db.QueryRow("SELECT stop_reason, started FROM runs WHERE id = ?", secondID).Scan(&stopReason, &started); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if threads != "10" || stopReason != string(StopMaxTime) || started != "2023-10-02T12:00:00Z" {
+		t.Fatalf("unexpected run %s %s %s", threads, stopReason, started)
+	}
+}
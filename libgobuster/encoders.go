@@ -0,0 +1,87 @@
+package libgobuster
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// Encoder encodes a single word
+type Encoder func(string) string
+
+// nolint:gochecknoglobals
+var encoders = map[string]Encoder{
+	"urlencode":        urlEncode,
+	"double-urlencode": func(s string) string { return urlEncode(urlEncode(s)) },
+	"base64":           func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"html-entity":      html.EscapeString,
+	"hex":              func(s string) string { return hex.EncodeToString([]byte(s)) },
+}
+
+// EncoderNames returns the names of all supported encoders
+func EncoderNames() []string {
+	ret := make([]string, 0, len(encoders))
+	for name := range encoders {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// EncoderChain is a list of encoders which are applied in order
+type EncoderChain struct {
+	Names    []string
+	encoders []Encoder
+}
+
+// ParseEncoderChain parses a comma separated list of encoder names
+func ParseEncoderChain(chain string) (EncoderChain, error) {
+	var ret EncoderChain
+	for _, name := range strings.Split(chain, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e, ok := encoders[name]
+		if !ok {
+			return EncoderChain{}, fmt.Errorf("unknown encoder %q, valid encoders are %s", name, strings.Join(EncoderNames(), ","))
+		}
+		ret.Names = append(ret.Names, name)
+		ret.encoders = append(ret.encoders, e)
+	}
+	return ret, nil
+}
+
+// Encode applies all encoders of the chain to the word
+func (c EncoderChain) Encode(word string) string {
+	for _, e := range c.encoders {
+		word = e(word)
+	}
+	return word
+}
+
+// String returns the chain in the format accepted by ParseEncoderChain
+func (c EncoderChain) String() string {
+	return strings.Join(c.Names, ",")
+}
+
+// urlEncode percent encodes every byte but the unreserved characters of RFC 3986,
+// including slashes, so encoded words can be used to bypass path based filters
+func urlEncode(s string) string {
+	const hexChars = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hexChars[c>>4])
+		sb.WriteByte(hexChars[c&15])
+	}
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package libgobuster
+
+import (
+	"testing"
+)
+
+func TestEncoderChain(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		chain    string
+		word     string
+		expected string
+	}{
+		{"Empty", "", "../admin", "../admin"},
+		{"URL", "urlencode", "../admin me", "..%2Fadmin%20me"},
+		{"Double URL", "double-urlencode", "../admin", "..%252Fadmin"},
+		{"Base64", "base64", "admin", "YWRtaW4="},
+		{"HTML", "html-entity", "<a href='x'>", "&lt;a href=&#39;x&#39;&gt;"},
+		{"Hex", "hex", "admin", "61646d696e"},
+		{"Chain", "base64, urlencode", "admin?", "YWRtaW4%2F"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			c, err := ParseEncoderChain(x.chain)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if got := c.Encode(x.word); got != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseEncoderChainInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseEncoderChain("urlencode,rot13"); err == nil {
+		t.Fatal("expected an error for an unknown encoder")
+	}
+}
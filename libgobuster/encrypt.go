@@ -0,0 +1,68 @@
+package libgobuster
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// OutputEncryption encrypts output files to a recipient so they are not
+// readable at rest. Only age X25519 recipients (age1...) are supported, the
+// files can be decrypted with age -d -i key.txt
+type OutputEncryption struct {
+	Recipient string
+	recipient *age.X25519Recipient
+}
+
+// ParseOutputEncryption parses a value like age:age1...
+func ParseOutputEncryption(s string) (*OutputEncryption, error) {
+	scheme, recipient, found := strings.Cut(strings.TrimSpace(s), ":")
+	if !found || recipient == "" {
+		return nil, fmt.Errorf("invalid encryption %q, expected age:<recipient>", s)
+	}
+	if !strings.EqualFold(scheme, "age") {
+		return nil, fmt.Errorf("unsupported encryption %q, only age:<recipient> is supported", scheme)
+	}
+
+	// bech32 allows upper case strings, age only parses lower case ones
+	if strings.ToUpper(recipient) == recipient {
+		recipient = strings.ToLower(recipient)
+	}
+	r, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age recipient %q: %w", recipient, err)
+	}
+	return &OutputEncryption{Recipient: r.String(), recipient: r}, nil
+}
+
+// String returns the scheme and recipient
+func (e *OutputEncryption) String() string {
+	return fmt.Sprintf("age:%s", e.Recipient)
+}
+
+// Encrypt writes the age header to w and returns a writer encrypting
+// everything written to it. Close needs to be called to write the last chunk,
+// it closes w too
+func (e *OutputEncryption) Encrypt(w io.WriteCloser) (io.WriteCloser, error) {
+	encrypted, err := age.Encrypt(w, e.recipient)
+	if err != nil {
+		return nil, err
+	}
+	return &ageWriter{WriteCloser: encrypted, file: w}, nil
+}
+
+// ageWriter closes the underlying file after the last chunk was written
+type ageWriter struct {
+	io.WriteCloser
+	file io.Closer
+}
+
+func (a *ageWriter) Close() error {
+	if err := a.WriteCloser.Close(); err != nil {
+		a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
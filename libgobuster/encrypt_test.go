@@ -0,0 +1,106 @@
+package libgobuster
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestParseOutputEncryption(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		value         string
+		expectedError bool
+	}{
+		// recipient from the age documentation
+		{"age:age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", false},
+		{"AGE:AGE1QL3Z7HJY54PW3HYWW5AYYFG7ZQGVC7W3J2ELW8ZMRJ2KG5SFN9AQMCAC8P", false},
+		{"age:age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8q", true},
+		{"age:Age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", true},
+		{"pgp:0xDEADBEEF", true},
+		{"age:", true},
+		{"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.value, func(t *testing.T) {
+			t.Parallel()
+			e, err := ParseOutputEncryption(x.value)
+			if x.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error for %q", x.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if expected := "age:age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"; e.String() != expected {
+				t.Fatalf("expected %s, got %s", expected, e.String())
+			}
+		})
+	}
+}
+
+func TestOutputEncryptionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	enc, err := ParseOutputEncryption("age:" + identity.Recipient().String())
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	// sizes around the 64KiB chunks of the payload
+	for _, size := range []int{0, 100, 64 * 1024, 64*1024 + 10, 3 * 64 * 1024} {
+		size := size
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			t.Parallel()
+			plain := bytes.Repeat([]byte("a"), size)
+			var buf bytes.Buffer
+			w, err := enc.Encrypt(nopCloser{&buf})
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			// write in small pieces like the output writers do
+			for i := 0; i < len(plain); i += 1000 {
+				end := i + 1000
+				if end > len(plain) {
+					end = len(plain)
+				}
+				if _, err := w.Write(plain[i:end]); err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			r, err := age.Decrypt(&buf, identity)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Fatalf("expected %d decrypted bytes, got %d", len(plain), len(got))
+			}
+		})
+	}
+}
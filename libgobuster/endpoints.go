@@ -0,0 +1,52 @@
+package libgobuster
+
+import (
+	"regexp"
+)
+
+// nolint:gochecknoglobals
+var (
+	endpointRegex = regexp.MustCompile("[\"'`]" +
+		`(` +
+		// absolute urls
+		`https?://[^"'` + "`" + `\s<>]+` +
+		// absolute and relative paths
+		`|\.{0,2}/[a-zA-Z0-9_\-.~/%]+(?:\?[^"'` + "`" + `\s<>]*)?` +
+		// relative paths with a known extension
+		`|[a-zA-Z0-9_\-]+/[a-zA-Z0-9_\-./]+\.(?:php|asp|aspx|jsp|json|action|html|js|txt|xml)(?:\?[^"'` + "`" + `\s<>]*)?` +
+		`)` + "[\"'`]")
+	inlineScriptRegex = regexp.MustCompile(`(?is)<script[^>]*>(.*?)</script>`)
+)
+
+// ExtractEndpoints returns the unique urls and paths found in string
+// literals of javascript code
+func ExtractEndpoints(body []byte) []string {
+	seen := NewSet[string]()
+	var ret []string
+	for _, m := range endpointRegex.FindAllSubmatch(body, -1) {
+		e := string(m[1])
+		// ignore the root and protocol relative urls to other hosts
+		if e == "/" || e == "./" || e == "../" {
+			continue
+		}
+		if seen.Add(e) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// ExtractInlineScriptEndpoints returns the endpoints found in all inline
+// script tags of a html page
+func ExtractInlineScriptEndpoints(body []byte) []string {
+	seen := NewSet[string]()
+	var ret []string
+	for _, m := range inlineScriptRegex.FindAllSubmatch(body, -1) {
+		for _, e := range ExtractEndpoints(m[1]) {
+			if seen.Add(e) {
+				ret = append(ret, e)
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,50 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractEndpoints(t *testing.T) {
+	t.Parallel()
+
+	js := []byte(`const api = "/api/v1/users"; fetch('/api/v1/users?id=' + id);
+axios.get(` + "`" + `/internal/stats` + "`" + `); var x = "text/html"; var y = "application/json";
+load("static/js/chunk.js"); const u = "https://api.example.com/v2/items"; var r = "/"; var s = "not a path";`)
+	expected := []string{"/api/v1/users", "/api/v1/users?id=", "/internal/stats", "static/js/chunk.js", "https://api.example.com/v2/items"}
+
+	if got := ExtractEndpoints(js); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestExtractInlineScriptEndpoints(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`<html><a href="/link">x</a><script>fetch("/api/a")</script><p>"/not/in/script"</p><script type="module">import x from "./mod/x.js"</script></html>`)
+	expected := []string{"/api/a", "./mod/x.js"}
+
+	if got := ExtractInlineScriptEndpoints(body); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func FuzzExtractEndpoints(f *testing.F) {
+	f.Add([]byte(`<script>fetch("/api/v1/users?id=" + id); location = 'https://example.com/a';</script><a href="/login">x</a><img src=logo.png>`))
+	f.Add([]byte(`<script>"./x" "../y" "/" '</script>`))
+	f.Add([]byte(`<a href='#top'></a><form action="/a&amp;b"></form><a href="`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		for _, extract := range []func([]byte) []string{ExtractEndpoints, ExtractInlineScriptEndpoints, ExtractLinks} {
+			seen := NewSet[string]()
+			for _, e := range extract(body) {
+				if e == "" {
+					t.Fatalf("empty link extracted from %q", body)
+				}
+				if !seen.Add(e) {
+					t.Fatalf("duplicate link %q extracted from %q", e, body)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package libgobuster
+
+import (
+	"context"
+	"sync"
+)
+
+// enrichQueueSize is the number of results waiting for the enrichment
+// before the workers block
+const enrichQueueSize = 100
+
+// startEnrichment starts the pool enriching the results of the
+// Progress.EnrichChan if the current plugin implements EnrichPlugin. The
+// returned function closes the channel and waits for the pool, it must be
+// called once no worker sends results anymore. Runs over multiple targets
+// start a new pool with a new channel for every target
+func (g *Gobuster) startEnrichment(ctx context.Context) func() {
+	enricher, ok := g.plugin.(EnrichPlugin)
+	if !ok {
+		return func() {}
+	}
+	results := make(chan Result, enrichQueueSize)
+	g.Progress.EnrichChan = results
+
+	threads := g.Opts.EnrichThreads
+	if threads <= 0 {
+		threads = g.Opts.Threads
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	for i := 0; i < threads; i++ {
+		go func() {
+			defer wg.Done()
+			for result := range results {
+				// canceled runs still report their results, just without
+				// the enrichment
+				if ctx.Err() == nil {
+					var err error
+					result, err = enricher.Enrich(ctx, result)
+					if err != nil {
+						g.Progress.ErrorChan <- err
+					}
+				}
+				g.Progress.ResultChan <- result
+			}
+		}()
+	}
+
+	return func() {
+		close(results)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,70 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// enrichPlugin sends its results to the enrichment, the enrichment of words
+// starting with fail returns an error
+type enrichPlugin struct {
+	collectPlugin
+}
+
+func (enrichPlugin) ProcessWord(_ context.Context, word string, progress *Progress) error {
+	progress.EnrichChan <- collectResult{word: word}
+	return nil
+}
+
+func (enrichPlugin) Enrich(_ context.Context, r Result) (Result, error) {
+	result := r.(collectResult)
+	if strings.HasPrefix(result.word, "fail") {
+		return result, fmt.Errorf("could not enrich %s", result.word)
+	}
+	result.word += " (enriched)"
+	return result, nil
+}
+
+func TestEnrichment(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp("", "wordlist")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("found1\nfail1\nfound2\n"); err != nil {
+		t.Fatalf("could not write tempfile: %v", err)
+	}
+	f.Close()
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.EnrichThreads = 1
+	opts.Wordlist = f.Name()
+	opts.CollectResults = true
+	g, err := NewGobuster(opts, enrichPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	var words []string
+	for _, r := range g.CollectedResults() {
+		words = append(words, r.(collectResult).word)
+	}
+	sort.Strings(words)
+	want := []string{"fail1", "found1 (enriched)", "found2 (enriched)"}
+	if strings.Join(words, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected %v but got %v", want, words)
+	}
+	if errs := g.CollectedErrors(); len(errs) != 1 {
+		t.Fatalf("Expected 1 error but got %v", errs)
+	}
+}
@@ -0,0 +1,59 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EstimateRequests returns the number of requests the run will issue after
+// applying patterns and the additional words of the plugin. It returns 0 if
+// the wordlist is read from stdin as the number is unknown in this case
+func (g *Gobuster) EstimateRequests() (int, error) {
+	if g.Opts.Wordlist == "-" {
+		return 0, nil
+	}
+
+	wordlist, lines, err := g.openWordlist()
+	if err != nil {
+		return 0, err
+	}
+	wordlist.Close()
+
+	// the offset only applies to the first of the remaining targets
+	lines = g.wordlistLines(lines)*(len(g.plugins)-g.Opts.TargetOffset) - g.Opts.WordlistOffset
+	if lines < 0 {
+		lines = 0
+	}
+
+	return lines * g.requestsPerWord() * g.combinations(), nil
+}
+
+// AssumedLatency is the latency per request used to estimate the duration of
+// a run if the target is not probed
+const AssumedLatency = 100 * time.Millisecond
+
+// ProbeLatency measures the latency of the target with the harmless request
+// of the plugin. Plugins not implementing LatencyPlugin can not be probed as
+// their requests may have side effects
+func (g *Gobuster) ProbeLatency(ctx context.Context) (time.Duration, error) {
+	p, ok := g.plugin.(LatencyPlugin)
+	if !ok {
+		return 0, fmt.Errorf("%s mode does not support latency probes", g.plugin.Name())
+	}
+	return p.ProbeLatency(ctx)
+}
+
+// EstimateDuration returns the estimated duration of a run issuing the given
+// number of requests with the given latency per request
+func (g *Gobuster) EstimateDuration(requests int, latency time.Duration) time.Duration {
+	perRequest := latency + g.Opts.Delay
+	duration := time.Duration(float64(requests) * float64(perRequest) / float64(g.Opts.Threads))
+	// the rate limit is the bottleneck for fast targets
+	if g.Opts.RateLimit > 0 {
+		if limited := time.Duration(requests/g.Opts.RateLimit) * time.Second; limited > duration {
+			return limited
+		}
+	}
+	return duration
+}
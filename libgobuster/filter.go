@@ -0,0 +1,138 @@
+package libgobuster
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ResultFilter is a single rule which matches results based on their status
+// code, size, path and tags. All conditions that are set need to match.
+type ResultFilter struct {
+	StatusCodes Set[int]
+	// Sizes are ranges, so open ranges like 5000- match all bigger sizes
+	Sizes IntRanges
+	Path  *regexp.Regexp
+	// Tags matches if the result has at least one of the tags
+	Tags Set[string]
+}
+
+// ParseResultFilter parses a single filter rule. A rule consists of space
+// separated key=value pairs, for example "status=200,300-399 size=1234 regex=^/static/ tag=secret-file"
+func ParseResultFilter(rule string) (ResultFilter, error) {
+	var f ResultFilter
+
+	fields := strings.Fields(rule)
+	if len(fields) == 0 {
+		return f, fmt.Errorf("empty filter rule")
+	}
+
+	for _, field := range fields {
+		key, value, found := strings.Cut(field, "=")
+		if !found || value == "" {
+			return f, fmt.Errorf("invalid condition %q in rule %q, expected key=value", field, rule)
+		}
+		switch strings.ToLower(key) {
+		case "status":
+			codes, err := ParseCommaSeparatedInt(value)
+			if err != nil {
+				return f, fmt.Errorf("invalid status in rule %q: %w", rule, err)
+			}
+			f.StatusCodes = codes
+		case "size":
+			sizes, err := ParseIntRanges(value)
+			if err != nil {
+				return f, fmt.Errorf("invalid size in rule %q: %w", rule, err)
+			}
+			f.Sizes = sizes
+		case "regex":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return f, fmt.Errorf("invalid regex in rule %q: %w", rule, err)
+			}
+			f.Path = re
+		case "tag":
+			f.Tags = NewSet[string]()
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					f.Tags.Add(tag)
+				}
+			}
+		default:
+			return f, fmt.Errorf("unknown condition %q in rule %q", key, rule)
+		}
+	}
+
+	return f, nil
+}
+
+// Matches checks if the result matches all conditions of the filter
+func (f ResultFilter) Matches(r ResultRecord) bool {
+	if f.StatusCodes.Length() > 0 && !f.StatusCodes.Contains(r.StatusCode) {
+		return false
+	}
+	if len(f.Sizes) > 0 && !f.Sizes.Contains(int(r.Size)) {
+		return false
+	}
+	if f.Path != nil && !f.Path.MatchString(r.Path) && !f.Path.MatchString(r.URL) {
+		return false
+	}
+	if f.Tags.Length() > 0 && !f.matchesTag(r.Tags) {
+		return false
+	}
+	return true
+}
+
+// matchesTag checks if one of the tags is part of the filter. Tags with a
+// value like without-slash:301 also match by their name
+func (f ResultFilter) matchesTag(tags []string) bool {
+	for _, tag := range tags {
+		name, _, _ := strings.Cut(tag, ":")
+		if f.Tags.Contains(tag) || f.Tags.Contains(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesAnyFilter checks if the result matches at least one of the supplied filters
+func MatchesAnyFilter(filters []ResultFilter, r ResultRecord) bool {
+	for _, f := range filters {
+		if f.Matches(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSuppressionFile parses a file containing one filter rule per line.
+// Results matching one of the rules are treated as false positives.
+func ParseSuppressionFile(file string) ([]ResultFilter, error) {
+	stream, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var ret []ResultFilter
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f, err := ParseResultFilter(line)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, f)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
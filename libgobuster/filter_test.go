@@ -0,0 +1,95 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseResultFilter(t *testing.T) {
+	t.Parallel()
+	var tt = []struct {
+		testName      string
+		rule          string
+		expectedError bool
+	}{
+		{"Status", "status=200", false},
+		{"Status range", "status=200,300-399", false},
+		{"Size", "size=1234", false},
+		{"Regex", "regex=^/static/", false},
+		{"Tag", "tag=secret-file,cors-reflected", false},
+		{"Combined", "status=200 size=0-10 regex=\\.css$", false},
+		{"Empty", "", true},
+		{"Missing value", "status=", true},
+		{"Unknown key", "foo=bar", true},
+		{"Invalid status", "status=abc", true},
+		{"Invalid regex", "regex=[", true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseResultFilter(x.rule)
+			if x.expectedError && err == nil {
+				t.Fatalf("Expected error for rule %q", x.rule)
+			} else if !x.expectedError && err != nil {
+				t.Fatalf("Got error for rule %q: %v", x.rule, err)
+			}
+		})
+	}
+}
+
+func TestResultFilterMatches(t *testing.T) {
+	t.Parallel()
+	record := ResultRecord{URL: "http://localhost/static/app.css", Path: "/static/app.css", Found: true, StatusCode: 200, Size: 1234, Tags: []string{"without-slash:301"}}
+	var tt = []struct {
+		rule     string
+		expected bool
+	}{
+		{"status=200", true},
+		{"status=301,302", false},
+		{"size=1000-2000", true},
+		{"size=0", false},
+		{"regex=^/static/", true},
+		{"regex=^/admin/", false},
+		{"status=200 size=1234 regex=\\.css$", true},
+		{"status=200 size=1", false},
+		{"tag=secret-file,without-slash", true},
+		{"tag=without-slash:301", true},
+		{"status=200 tag=secret-file", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(x.rule, func(t *testing.T) {
+			t.Parallel()
+			f, err := ParseResultFilter(x.rule)
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if f.Matches(record) != x.expected {
+				t.Fatalf("Expected %t for rule %q", x.expected, x.rule)
+			}
+		})
+	}
+}
+
+func FuzzParseResultFilter(f *testing.F) {
+	f.Add("status=200,300-399 size=1234 regex=^/static/ tag=secret-file")
+	f.Add("size=5000-")
+	f.Add("tag=,, regex=(a+)+$")
+	f.Add("STATUS=200=300")
+
+	record := ResultRecord{URL: "http://localhost/static/app.css", Path: "/static/app.css", Found: true, StatusCode: 200, Size: 1234, Tags: []string{"without-slash:301"}}
+	f.Fuzz(func(t *testing.T, rule string) {
+		filter, err := ParseResultFilter(rule)
+		if err != nil {
+			return
+		}
+		if filter.StatusCodes.Length() > len(strings.Split(rule, ","))*maxSetRange {
+			t.Fatalf("rule %q resulted in %d status codes", rule, filter.StatusCodes.Length())
+		}
+		filter.Matches(record)
+		filter.Matches(ResultRecord{})
+	})
+}
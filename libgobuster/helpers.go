@@ -3,10 +3,13 @@ package libgobuster
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -36,6 +39,15 @@ func (set *Set[T]) AddRange(ss []T) {
 	}
 }
 
+// Clone returns a copy of the set
+func (set *Set[T]) Clone() Set[T] {
+	clone := Set[T]{Set: make(map[T]bool, len(set.Set))}
+	for s := range set.Set {
+		clone.Set[s] = true
+	}
+	return clone
+}
+
 // Contains tests if an element is in a set
 func (set *Set[T]) Contains(s T) bool {
 	_, found := set.Set[s]
@@ -121,6 +133,10 @@ func ParseExtensionsFile(file string) ([]string, error) {
 	for scanner.Scan() {
 		e := scanner.Text()
 		e = strings.TrimSpace(e)
+		// empty lines would append a bare dot to every word
+		if e == "" || strings.HasPrefix(e, "#") {
+			continue
+		}
 		// remove leading . from extensions
 		ret = append(ret, (strings.TrimPrefix(e, ".")))
 	}
@@ -132,6 +148,98 @@ func ParseExtensionsFile(file string) ([]string, error) {
 	return ret, nil
 }
 
+// ParseKnownFile parses a file containing already known entries, one per line.
+// Leading slashes are removed so paths from previous results can be used as is
+func ParseKnownFile(file string) (Set[string], error) {
+	ret := NewSet[string]()
+
+	stream, err := os.Open(file)
+	if err != nil {
+		return ret, err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		e := strings.TrimSpace(scanner.Text())
+		if e == "" || strings.HasPrefix(e, "#") {
+			continue
+		}
+		ret.Add(strings.TrimPrefix(e, "/"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return NewSet[string](), err
+	}
+
+	return ret, nil
+}
+
+// nolint:gochecknoglobals
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// ParseOutputFile parses the results of a previous run so they can be skipped.
+// It supports the plain text output as well as json objects (one per line)
+// with a path or url property. Misses of verbose runs are ignored, full urls
+// are reduced to their path
+func ParseOutputFile(file string) (Set[string], error) {
+	ret := NewSet[string]()
+
+	stream, err := os.Open(file)
+	if err != nil {
+		return ret, err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := strings.TrimSpace(ansiEscapeRegex.ReplaceAllString(scanner.Text(), ""))
+		if line == "" || strings.HasPrefix(line, "Missed: ") {
+			continue
+		}
+
+		var entry string
+		if strings.HasPrefix(line, "{") {
+			var record struct {
+				Path string
+				URL  string
+			}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				return NewSet[string](), fmt.Errorf("invalid json line %q: %w", line, err)
+			}
+			entry = record.Path
+			if entry == "" {
+				entry = record.URL
+			}
+		} else {
+			line = strings.TrimPrefix(line, "Found: ")
+			entry = strings.Fields(line)[0]
+		}
+
+		if strings.Contains(entry, "://") {
+			u, err := url.Parse(entry)
+			if err != nil {
+				continue
+			}
+			entry = u.Path
+		}
+		entry = strings.TrimPrefix(entry, "/")
+		if entry != "" {
+			ret.Add(entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return NewSet[string](), err
+	}
+
+	return ret, nil
+}
+
+// maxSetRange is the number of values a range of ParseCommaSeparatedInt may
+// contain, every value of a range is added to the set
+const maxSetRange = 100000
+
 // ParseCommaSeparatedInt parses the status codes provided as a comma separated list
 func ParseCommaSeparatedInt(inputString string) (Set[int], error) {
 	ret := NewSet[int]()
@@ -162,6 +270,9 @@ func ParseCommaSeparatedInt(inputString string) (Set[int], error) {
 			if toI < fromI {
 				return NewSet[int](), fmt.Errorf("invalid range given: %s", part)
 			}
+			if toI-fromI >= maxSetRange {
+				return NewSet[int](), fmt.Errorf("range %s is too big, it may contain at most %d values", part, maxSetRange)
+			}
 			for i := fromI; i <= toI; i++ {
 				ret.Add(i)
 			}
@@ -196,3 +307,21 @@ func JoinIntSlice(s []int) string {
 	result := strings.Join(valuesText, ",")
 	return result
 }
+
+// writeFileAtomic replaces the file so readers never see a partial content
+func writeFileAtomic(file string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
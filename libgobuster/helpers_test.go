@@ -3,6 +3,7 @@ package libgobuster
 import (
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -250,6 +251,7 @@ func TestParseCommaSeparatedInt(t *testing.T) {
 		{"230-200", []int{}, "invalid range given: 230-200"},
 		{"A-200", []int{}, "invalid range given: A-200"},
 		{"230-A", []int{}, "invalid range given: 230-A"},
+		{"0-9223372036854775807", []int{}, "range 0-9223372036854775807 is too big, it may contain at most 100000 values"},
 		{"200,202-205,A,206-210", []int{}, "invalid string given: A"},
 		{"200,202-205,A-1,206-210", []int{}, "invalid range given: A-1"},
 		{"200,202-205,1-A,206-210", []int{}, "invalid range given: 1-A"},
@@ -321,3 +323,55 @@ func BenchmarkParseCommaSeparatedInt(b *testing.B) {
 		})
 	}
 }
+
+func TestParseOutputFile(t *testing.T) {
+	t.Parallel()
+	f, err := os.CreateTemp("", "output")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	content := "/admin                (Status: 301) [Size: 0] [--> /admin/]\n" +
+		"/.env \x1b[32m (Status: 200)\x1b[0m [Size: 12] \x1b[31m[secret-file]\x1b[0m\n" +
+		"Missed: /nope                 (Status: 404) [Size: 0]\n" +
+		"http://localhost/test.php (Status: 200) [Size: 12]\n" +
+		"Found: www.example.com\n" +
+		"\n" +
+		`{"url":"http://localhost/","path":"login","status":200}` + "\n" +
+		`{"url":"http://localhost/api/v1/users"}` + "\n"
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("could not write tempfile: %v", err)
+	}
+	f.Close()
+
+	ret, err := ParseOutputFile(f.Name())
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	want := Set[string]{Set: map[string]bool{"admin": true, ".env": true, "test.php": true, "www.example.com": true, "login": true, "api/v1/users": true}}
+	if !reflect.DeepEqual(want, ret) {
+		t.Fatalf("Expected %v but got %v", want, ret)
+	}
+}
+
+func TestParseKnownFile(t *testing.T) {
+	t.Parallel()
+	f, err := os.CreateTemp("", "known")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("/admin\n# comment\n\n  test.php  \nadmin\n"); err != nil {
+		t.Fatalf("could not write tempfile: %v", err)
+	}
+	f.Close()
+
+	ret, err := ParseKnownFile(f.Name())
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	want := Set[string]{Set: map[string]bool{"admin": true, "test.php": true}}
+	if !reflect.DeepEqual(want, ret) {
+		t.Fatalf("Expected %v but got %v", want, ret)
+	}
+}
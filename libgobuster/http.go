@@ -1,14 +1,18 @@
 package libgobuster
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // HTTPHeader holds a single key value pair of a HTTP header
@@ -29,16 +33,55 @@ type HTTPClient struct {
 	cookies               string
 	method                string
 	host                  string
+	script                *Script
+	throttle              *Throttle
+	cacheBypass           bool
+	// stats collects the connection statistics of the run, nil if disabled
+	stats *ConnStats
+	// header is the prototype of the request headers cloned for every
+	// request, nil if the headers contain placeholders
+	header http.Header
+	// retries is the number of retries of requests failing with a
+	// transient error, the wait before them doubles every attempt
+	retries   int
+	retryWait time.Duration
+	// limits protect against hostile responses
+	limits ResponseLimits
+}
+
+// RequestTiming holds the timing information of a single request
+type RequestTiming struct {
+	// Duration is the time from sending the request until the body was read
+	Duration time.Duration
+	// TTFB is the time until the first byte of the response was received
+	TTFB time.Duration
+}
+
+// String returns the timing in milliseconds
+func (t RequestTiming) String() string {
+	return fmt.Sprintf("Time: %dms, TTFB: %dms", t.Duration.Milliseconds(), t.TTFB.Milliseconds())
 }
 
 // RequestOptions is used to pass options to a single individual request
 type RequestOptions struct {
+	// Method overrides the method of the client for this request
+	Method                   string
 	Host                     string
 	Body                     io.Reader
 	ReturnBody               bool
 	ModifiedHeaders          []HTTPHeader
 	UpdatedBasicAuthUsername string
 	UpdatedBasicAuthPassword string
+	// UpdatedCookies overrides the cookies of the client for this request
+	UpdatedCookies string
+	// Timing is filled with the timing of the request if set
+	Timing *RequestTiming
+	// TLS is filled with the state of the connection if set and the
+	// request used https
+	TLS *tls.ConnectionState
+	// RejectTruncated returns an error instead of a returned body cut off at
+	// the maximum body size, for responses which are useless when incomplete
+	RejectTruncated bool
 }
 
 // NewHTTPClient returns a new HTTPClient
@@ -59,6 +102,14 @@ func NewHTTPClient(opt *HTTPOptions) (*HTTPClient, error) {
 		proxyURLFunc = http.ProxyURL(proxyURL)
 	}
 
+	if len(opt.Proxies) > 0 {
+		rotator, err := newProxyRotator(opt.Proxies, opt.ProxyRotation)
+		if err != nil {
+			return nil, err
+		}
+		proxyURLFunc = rotator.proxy
+	}
+
 	var redirectFunc func(req *http.Request, via []*http.Request) error
 	if !opt.FollowRedirect {
 		redirectFunc = func(req *http.Request, via []*http.Request) error {
@@ -77,15 +128,25 @@ func NewHTTPClient(opt *HTTPOptions) (*HTTPClient, error) {
 		tlsConfig.Certificates = []tls.Certificate{*opt.TLSCertificate}
 	}
 
+	client.limits = opt.Limits.withDefaults()
+	transport := &http.Transport{
+		Proxy:                  proxyURLFunc,
+		MaxIdleConns:           100,
+		MaxIdleConnsPerHost:    100,
+		TLSClientConfig:        &tlsConfig,
+		MaxResponseHeaderBytes: client.limits.MaxHeaderSize,
+	}
+	var roundTripper http.RoundTripper = transport
+	if opt.TLSPolicy.Relaxed() && !opt.NoTLSValidation {
+		// the certificates are verified by the policy instead
+		tlsConfig.InsecureSkipVerify = true
+		roundTripper = newPolicyTransport(transport, opt.TLSPolicy, opt.ConnStats)
+	}
 	client.client = &http.Client{
 		Timeout:       opt.Timeout,
 		CheckRedirect: redirectFunc,
-		Transport: &http.Transport{
-			Proxy:               proxyURLFunc,
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 100,
-			TLSClientConfig:     &tlsConfig,
-		}}
+		Transport:     roundTripper,
+	}
 	client.username = opt.Username
 	client.password = opt.Password
 	client.userAgent = opt.UserAgent
@@ -94,9 +155,20 @@ func NewHTTPClient(opt *HTTPOptions) (*HTTPClient, error) {
 	client.noCanonicalizeHeaders = opt.NoCanonicalizeHeaders
 	client.cookies = opt.Cookies
 	client.method = opt.Method
+	client.script = opt.Script
+	client.throttle = opt.Throttle
+	client.cacheBypass = opt.CacheBypass
+	client.stats = opt.ConnStats
+	if opt.RetryOnTimeout {
+		client.retries = opt.RetryAttempts
+		client.retryWait = opt.RetryWait
+	}
 	if client.method == "" {
 		client.method = http.MethodGet
 	}
+	if !hasPlaceholders(client.cookies, client.headers) {
+		client.header = client.buildHeader(NewPlaceholders(), RequestOptions{})
+	}
 	// Host header needs to be set separately
 	for _, h := range opt.Headers {
 		if h.Name == "Host" {
@@ -109,48 +181,237 @@ func NewHTTPClient(opt *HTTPOptions) (*HTTPClient, error) {
 
 // Request makes an http request and returns the status, the content length, the headers, the body and an error
 // if you want the body returned set the corresponding property inside RequestOptions
+// Requests failing with a transient error are retried if configured
+// Once the context is done the request is aborted, also while the body is read, and Request returns as soon as
+// the pending read returned. A canceled context returns no error, an exceeded deadline returns its error
 func (client *HTTPClient) Request(ctx context.Context, fullURL string, opts RequestOptions) (int, int64, http.Header, []byte, error) {
-	resp, err := client.makeRequest(ctx, fullURL, opts)
-	if err != nil {
-		// ignore context canceled errors
-		if errors.Is(ctx.Err(), context.Canceled) {
-			return 0, 0, nil, nil, nil
+	// the body is read once so it can be sent again by retries
+	var content []byte
+	if opts.Body != nil {
+		var err error
+		content, err = io.ReadAll(opts.Body)
+		if err != nil {
+			return 0, 0, nil, nil, err
 		}
-		return 0, 0, nil, nil, err
 	}
-	defer resp.Body.Close()
+
+	var resp *http.Response
+	var start time.Time
+	var err error
+	for attempt := 0; ; attempt++ {
+		if content != nil {
+			opts.Body = bytes.NewReader(content)
+		}
+
+		// wait before measuring so the timing is not affected by the rate limit
+		if client.throttle != nil {
+			u, err := url.Parse(fullURL)
+			if err != nil {
+				return 0, 0, nil, nil, err
+			}
+			if err := client.throttle.Wait(ctx, u.Host); err != nil {
+				// ignore context canceled errors
+				if errors.Is(ctx.Err(), context.Canceled) {
+					return 0, 0, nil, nil, nil
+				}
+				return 0, 0, nil, nil, err
+			}
+		}
+
+		start = time.Now()
+		resp, err = client.makeRequest(ctx, fullURL, opts)
+		if attempt < client.retries && ctx.Err() == nil && retryable(resp, err) {
+			if resp != nil {
+				// drain the body so the connection is reused
+				_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, client.limits.MaxBodySize))
+				resp.Body.Close()
+			}
+			if !waitRetry(ctx, retryBackoff(client.retryWait, attempt)) {
+				return 0, 0, nil, nil, nil
+			}
+			continue
+		}
+		if err != nil {
+			// ignore context canceled errors
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return 0, 0, nil, nil, nil
+			}
+			return 0, 0, nil, nil, err
+		}
+		break
+	}
+	respBody := newBodyReader(ctx, resp.Body)
+	defer respBody.Close()
+
+	if fields := headerFields(resp.Header); fields > client.limits.MaxHeaders {
+		return 0, 0, nil, nil, fmt.Errorf("response of %s has %d header fields, more than the limit of %d", fullURL, fields, client.limits.MaxHeaders)
+	}
+
+	// the limit applies to the decompressed body, so compressed bodies can't
+	// exhaust the memory. Bodies over the limit are truncated and their
+	// connection is closed instead of reused
+	limited := io.LimitReader(respBody, client.limits.MaxBodySize)
 
 	var body []byte
 	var length int64
 	if opts.ReturnBody {
-		body, err = io.ReadAll(resp.Body)
+		body, err = io.ReadAll(limited)
 		if err != nil {
+			// ignore context canceled errors
+			if errors.Is(err, context.Canceled) {
+				return 0, 0, nil, nil, nil
+			}
 			return 0, 0, nil, nil, fmt.Errorf("could not read body %w", err)
 		}
+		if opts.RejectTruncated && int64(len(body)) == client.limits.MaxBodySize && bodyContinues(respBody) {
+			return 0, 0, nil, nil, fmt.Errorf("response of %s is larger than the maximum body size of %s", fullURL, FormatMemorySize(client.limits.MaxBodySize))
+		}
+		// the length stays the size on the wire for the length filters
 		length = int64(len(body))
+		body = toUTF8(body, resp.Header.Get("Content-Type"))
 	} else {
 		// DO NOT REMOVE!
 		// absolutely needed so golang will reuse connections!
-		length, err = io.Copy(io.Discard, resp.Body)
+		length, err = io.Copy(io.Discard, limited)
 		if err != nil {
+			// ignore context canceled errors
+			if errors.Is(err, context.Canceled) {
+				return 0, 0, nil, nil, nil
+			}
 			return 0, 0, nil, nil, err
 		}
 	}
 
-	return resp.StatusCode, length, resp.Header, body, nil
+	if opts.Timing != nil {
+		opts.Timing.Duration = time.Since(start)
+	}
+
+	if opts.TLS != nil && resp.TLS != nil {
+		*opts.TLS = *resp.TLS
+	}
+
+	statusCode, err := client.script.OnResponse(resp.Request.URL.String(), resp.StatusCode, length, resp.Header, body)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+
+	return statusCode, length, resp.Header, body, nil
+}
+
+// ProbeLatency sends a HEAD request to the url and returns how long it took.
+// HEAD requests have no side effects, so the probe is safe to send before
+// the run is confirmed
+func (client *HTTPClient) ProbeLatency(ctx context.Context, fullURL string) (time.Duration, error) {
+	var timing RequestTiming
+	if _, _, _, _, err := client.Request(ctx, fullURL, RequestOptions{Method: http.MethodHead, Timing: &timing}); err != nil {
+		return 0, err
+	}
+	return timing.Duration, nil
 }
 
 func (client *HTTPClient) makeRequest(ctx context.Context, fullURL string, opts RequestOptions) (*http.Response, error) {
-	req, err := http.NewRequest(client.method, fullURL, opts.Body)
+	req, err := client.newRequest(ctx, fullURL, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := client.script.OnRequest(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.client.Do(req)
+	if err != nil {
+		var certErr *CertificateError
+		if !errors.As(err, &certErr) {
+			if problem, ok := classifyCertificateError(err); ok {
+				certErr = &CertificateError{Host: req.URL.Hostname(), Problem: problem, Err: err}
+			}
+		}
+		if certErr != nil {
+			client.stats.addCertificateProblem(certErr.Host, certErr.Problem, false)
+			return nil, certErr
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// bodyContinues checks if more of the body can be read
+func bodyContinues(r io.Reader) bool {
+	var b [1]byte
+	n, _ := io.ReadFull(r, b[:])
+	return n > 0
+}
+
+// bodyReader reads a response body until the context is done. The transport
+// already aborts blocked reads of the body once the context of the request is
+// done, bodyReader makes sure no further reads succeed and report the error of
+// the context
+type bodyReader struct {
+	ctx  context.Context
+	body io.ReadCloser
+}
+
+func newBodyReader(ctx context.Context, body io.ReadCloser) *bodyReader {
+	return &bodyReader{ctx: ctx, body: body}
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.body.Read(p)
+	if err != nil && r.ctx.Err() != nil {
+		// reads aborted by the transport fail with an error of their own
+		return n, r.ctx.Err()
+	}
+	return n, err
+}
+
+func (r *bodyReader) Close() error {
+	return r.body.Close()
+}
+
+// newRequest creates the request for the url. Requests using the headers of
+// the client get a copy of the prebuilt headers, so only the request itself
+// is allocated for every word
+func (client *HTTPClient) newRequest(ctx context.Context, fullURL string, opts RequestOptions) (*http.Request, error) {
+	method := client.method
+	if opts.Method != "" {
+		method = opts.Method
+	}
+
+	// placeholders get fresh values in every request
+	placeholders := NewPlaceholders()
+	body := opts.Body
+	if body != nil {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		body = strings.NewReader(placeholders.Replace(string(content)))
+	}
+
+	if opts.Timing != nil || client.stats != nil {
+		ctx = httptrace.WithClientTrace(ctx, client.stats.trace(time.Now(), opts.Timing))
+	}
+
 	// add the context so we can easily cancel out
-	req = req.WithContext(ctx)
+	req, err := http.NewRequestWithContext(ctx, method, placeholders.Replace(fullURL), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.cacheBypass {
+		addCacheBypass(req, placeholders.Replace(cacheBypassValue))
+	}
 
-	if client.cookies != "" {
-		req.Header.Set("Cookie", client.cookies)
+	if client.header != nil && len(opts.ModifiedHeaders) == 0 && opts.UpdatedCookies == "" && opts.UpdatedBasicAuthUsername == "" {
+		// cloning copies all values with a single allocation
+		req.Header = client.header.Clone()
+	} else {
+		req.Header = client.buildHeader(placeholders, opts)
 	}
 
 	// Use host for VHOST mode on a per request basis, otherwise the one provided from headers
@@ -160,51 +421,83 @@ func (client *HTTPClient) makeRequest(ctx context.Context, fullURL string, opts
 		req.Host = client.host
 	}
 
+	return req, nil
+}
+
+// buildHeader returns the headers of a request. Headers set later overwrite
+// the ones set before, so custom headers can replace the cache bypass
+// headers, the cookies and the user agent
+func (client *HTTPClient) buildHeader(placeholders *Placeholders, opts RequestOptions) http.Header {
+	header := make(http.Header)
+	if client.cacheBypass {
+		addCacheBypassHeaders(header)
+	}
+
+	cookies := client.cookies
+	if opts.UpdatedCookies != "" {
+		cookies = opts.UpdatedCookies
+	}
+	if cookies != "" {
+		header.Set("Cookie", placeholders.Replace(cookies))
+	}
+
 	if client.userAgent != "" {
-		req.Header.Set("User-Agent", client.userAgent)
+		header.Set("User-Agent", client.userAgent)
 	} else {
-		req.Header.Set("User-Agent", client.defaultUserAgent)
+		header.Set("User-Agent", client.defaultUserAgent)
 	}
 
 	// add custom headers
 	// if ModifiedHeaders are supplied use those, otherwise use the original ones
 	// currently only relevant on fuzzing
+	headers := client.headers
 	if len(opts.ModifiedHeaders) > 0 {
-		for _, h := range opts.ModifiedHeaders {
-			if client.noCanonicalizeHeaders {
-				// https://stackoverflow.com/questions/26351716/how-to-keep-key-case-sensitive-in-request-header-using-golang
-				req.Header[h.Name] = []string{h.Value}
-			} else {
-				req.Header.Set(h.Name, h.Value)
-			}
-		}
-	} else {
-		for _, h := range client.headers {
-			if client.noCanonicalizeHeaders {
-				// https://stackoverflow.com/questions/26351716/how-to-keep-key-case-sensitive-in-request-header-using-golang
-				req.Header[h.Name] = []string{h.Value}
-			} else {
-				req.Header.Set(h.Name, h.Value)
-			}
+		headers = opts.ModifiedHeaders
+	}
+	for _, h := range headers {
+		if client.noCanonicalizeHeaders {
+			// https://stackoverflow.com/questions/26351716/how-to-keep-key-case-sensitive-in-request-header-using-golang
+			header[h.Name] = []string{placeholders.Replace(h.Value)}
+		} else {
+			header.Set(h.Name, placeholders.Replace(h.Value))
 		}
 	}
 
 	if opts.UpdatedBasicAuthUsername != "" {
-		req.SetBasicAuth(opts.UpdatedBasicAuthUsername, opts.UpdatedBasicAuthPassword)
+		header.Set("Authorization", basicAuth(opts.UpdatedBasicAuthUsername, opts.UpdatedBasicAuthPassword))
 	} else if client.username != "" {
-		req.SetBasicAuth(client.username, client.password)
+		header.Set("Authorization", basicAuth(client.username, client.password))
 	}
 
-	resp, err := client.client.Do(req)
-	if err != nil {
-		var ue *url.Error
-		if errors.As(err, &ue) {
-			if strings.HasPrefix(ue.Err.Error(), "x509") {
-				return nil, fmt.Errorf("invalid certificate: %w", ue.Err)
-			}
+	return header
+}
+
+// basicAuth returns the value of the Authorization header for basic
+// authentication, like http.Request.SetBasicAuth
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// hasPlaceholders checks if the cookies or one of the headers contain
+// placeholders needing a fresh value in every request
+func hasPlaceholders(cookies string, headers []HTTPHeader) bool {
+	if strings.Contains(cookies, "{{") {
+		return true
+	}
+	for _, h := range headers {
+		if strings.Contains(h.Value, "{{") {
+			return true
 		}
-		return nil, err
 	}
+	return false
+}
 
-	return resp, nil
+// headerFields returns the number of header fields, repeated headers like
+// Set-Cookie count once per value
+func headerFields(header http.Header) int {
+	fields := 0
+	for _, values := range header {
+		fields += len(values)
+	}
+	return fields
 }
@@ -2,13 +2,20 @@ package libgobuster
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func httpServerB(b *testing.B, content string) *httptest.Server {
@@ -70,6 +77,112 @@ func TestRequest(t *testing.T) {
 	}
 }
 
+func TestRequestTiming(t *testing.T) {
+	t.Parallel()
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "test")
+	}))
+	defer h.Close()
+	var o HTTPOptions
+	c, err := NewHTTPClient(&o)
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	var timing RequestTiming
+	if _, _, _, _, err := c.Request(context.Background(), h.URL, RequestOptions{Timing: &timing}); err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	if timing.TTFB < 20*time.Millisecond {
+		t.Fatalf("Expected a TTFB of at least 20ms, got %s", timing.TTFB)
+	}
+	if timing.Duration < timing.TTFB {
+		t.Fatalf("Expected the duration %s to include the TTFB %s", timing.Duration, timing.TTFB)
+	}
+}
+
+func TestRequestUpdatedCookies(t *testing.T) {
+	t.Parallel()
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.Method, r.Header.Get("Cookie"))
+	}))
+	defer h.Close()
+	o := HTTPOptions{Cookies: "session=a"}
+	c, err := NewHTTPClient(&o)
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+
+	tt := []struct {
+		opts     RequestOptions
+		expected string
+	}{
+		{RequestOptions{}, "GET session=a"},
+		{RequestOptions{UpdatedCookies: "session=b", Method: http.MethodPost}, "POST session=b"},
+	}
+	for _, x := range tt {
+		x.opts.ReturnBody = true
+		_, _, _, body, err := c.Request(context.Background(), h.URL, x.opts)
+		if err != nil {
+			t.Fatalf("Got Error: %v", err)
+		}
+		if string(body) != x.expected {
+			t.Fatalf("expected %q, got %q", x.expected, body)
+		}
+	}
+}
+
+func TestRequestRetry(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			// close the connection without a response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("could not hijack connection: %v", err)
+				return
+			}
+			conn.Close()
+		default:
+			body, _ := io.ReadAll(r.Body)
+			fmt.Fprintf(w, "%s", body)
+		}
+	}))
+	defer h.Close()
+
+	o := HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{RetryOnTimeout: true, RetryAttempts: 2, RetryWait: time.Millisecond}}
+	c, err := NewHTTPClient(&o)
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	status, _, _, body, err := c.Request(context.Background(), h.URL, RequestOptions{Method: http.MethodPost, Body: strings.NewReader("payload"), ReturnBody: true})
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	if status != http.StatusOK || string(body) != "payload" {
+		t.Fatalf("expected the body to be sent again, got %d %q", status, body)
+	}
+
+	// the last response is returned once the retries are exhausted
+	atomic.StoreInt32(&requests, 0)
+	o.RetryAttempts = 0
+	c, err = NewHTTPClient(&o)
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	status, _, _, _, err = c.Request(context.Background(), h.URL, RequestOptions{})
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 without retries, got %d", status)
+	}
+}
+
 func BenchmarkRequestWithoutBody(b *testing.B) {
 	r, err := randomString(10000)
 	if err != nil {
@@ -125,3 +238,339 @@ func BenchmarkNewHTTPClient(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkRequestHeaders(b *testing.B) {
+	h := httpServerB(b, "")
+	defer h.Close()
+	o := HTTPOptions{
+		BasicHTTPOptions: BasicHTTPOptions{UserAgent: "gobuster"},
+		Headers:          []HTTPHeader{{Name: "X-Api-Key", Value: "secret"}, {Name: "accept", Value: "*/*"}},
+		Cookies:          "session=1234",
+		Username:         "user",
+		Password:         "pass",
+	}
+	c, err := NewHTTPClient(&o)
+	if err != nil {
+		b.Fatalf("Got Error: %v", err)
+	}
+	b.ReportAllocs()
+	for x := 0; x < b.N; x++ {
+		_, _, _, _, err := c.Request(context.Background(), h.URL+"/admin", RequestOptions{})
+		if err != nil {
+			b.Fatalf("Got Error: %v", err)
+		}
+	}
+}
+
+func BenchmarkNewRequest(b *testing.B) {
+	o := HTTPOptions{
+		BasicHTTPOptions: BasicHTTPOptions{UserAgent: "gobuster"},
+		Headers:          []HTTPHeader{{Name: "X-Api-Key", Value: "secret"}, {Name: "accept", Value: "*/*"}},
+		Cookies:          "session=1234",
+		Username:         "user",
+		Password:         "pass",
+	}
+	c, err := NewHTTPClient(&o)
+	if err != nil {
+		b.Fatalf("Got Error: %v", err)
+	}
+
+	var tt = []struct {
+		testName string
+		opts     RequestOptions
+	}{
+		// the headers of the client are cloned from the prototype
+		{"Prototype", RequestOptions{}},
+		// modified headers are built for every request
+		{"Modified headers", RequestOptions{ModifiedHeaders: o.Headers}},
+	}
+	for _, x := range tt {
+		x := x
+		b.Run(x.testName, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.newRequest(context.Background(), "http://localhost/admin", x.opts); err != nil {
+					b.Fatalf("Got Error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestProbeLatency(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	var requests []string
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		time.Sleep(20 * time.Millisecond)
+	}))
+	t.Cleanup(h.Close)
+
+	c, err := NewHTTPClient(&HTTPOptions{Method: http.MethodPut})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	latency, err := c.ProbeLatency(context.Background(), h.URL+"/base/")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if latency < 20*time.Millisecond {
+		t.Fatalf("expected a latency of at least 20ms, got %s", latency)
+	}
+	// the probe is a HEAD request of the url whatever the method of the run is
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(requests) != 1 || requests[0] != "HEAD /base/" {
+		t.Fatalf("expected a single HEAD request, got %v", requests)
+	}
+}
+
+func TestRequestCanceled(t *testing.T) {
+	t.Parallel()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+		// a slow streaming body
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 200; i++ {
+			if _, err := w.Write([]byte("x")); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}))
+	t.Cleanup(h.Close)
+
+	c, err := NewHTTPClient(&HTTPOptions{})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	tt := []struct {
+		testName   string
+		path       string
+		returnBody bool
+		deadline   bool
+	}{
+		{"Headers canceled", "/slow-headers", false, false},
+		{"Headers deadline", "/slow-headers", false, true},
+		{"Body canceled", "/slow-body", false, false},
+		{"Body deadline", "/slow-body", false, true},
+		{"Returned body canceled", "/slow-body", true, false},
+		{"Returned body deadline", "/slow-body", true, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if x.deadline {
+				ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+			} else {
+				time.AfterFunc(200*time.Millisecond, cancel)
+			}
+			defer cancel()
+
+			start := time.Now()
+			status, _, _, _, err := c.Request(ctx, h.URL+x.path, RequestOptions{ReturnBody: x.returnBody})
+			// the server takes 10 seconds to respond
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("the request returned %s after the context was done", elapsed)
+			}
+			if status != 0 {
+				t.Fatalf("expected no status, got %d", status)
+			}
+			if x.deadline {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("expected the deadline error, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("expected canceled requests to return no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBodyReaderCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := newBodyReader(ctx, io.NopCloser(strings.NewReader("abcdef")))
+	defer r.Close()
+
+	if n, err := r.Read(make([]byte, 3)); err != nil || n != 3 {
+		t.Fatalf("expected to read 3 bytes, got %d and %v", n, err)
+	}
+	cancel()
+	if _, err := r.Read(make([]byte, 3)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected reads after the cancellation to fail, got %v", err)
+	}
+}
+
+func TestRequestStalledBody(t *testing.T) {
+	t.Parallel()
+
+	// the server sends the start of the body and stalls until the test ends
+	stall := make(chan struct{})
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "partial")
+		w.(http.Flusher).Flush()
+		select {
+		case <-stall:
+		case <-r.Context().Done():
+		}
+	}))
+	// parallel subtests run after the test function returned
+	t.Cleanup(h.Close)
+	t.Cleanup(func() { close(stall) })
+
+	tt := []struct {
+		testName   string
+		returnBody bool
+		deadline   bool
+	}{
+		{"Canceled", false, false},
+		{"Canceled with body", true, false},
+		{"Deadline", false, true},
+		{"Deadline with body", true, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			o := HTTPOptions{}
+			o.Timeout = time.Minute
+			c, err := NewHTTPClient(&o)
+			if err != nil {
+				t.Fatalf("Got Error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if x.deadline {
+				ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+			} else {
+				time.AfterFunc(200*time.Millisecond, cancel)
+			}
+			defer cancel()
+
+			start := time.Now()
+			status, _, _, _, err := c.Request(ctx, h.URL, RequestOptions{ReturnBody: x.returnBody})
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("expected the read to return after the context is done, took %s", elapsed)
+			}
+			if x.deadline {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("expected a deadline exceeded error, got %v", err)
+				}
+				return
+			}
+			// canceled reads are not reported
+			if status != 0 || err != nil {
+				t.Fatalf("expected no result and no error, got %d and %v", status, err)
+			}
+		})
+	}
+}
+
+func TestRequestLimits(t *testing.T) {
+	t.Parallel()
+
+	var bomb bytes.Buffer
+	gz := gzip.NewWriter(&bomb)
+	if _, err := gz.Write(make([]byte, 10*1024*1024)); err != nil {
+		t.Fatalf("could not compress: %v", err)
+	}
+	gz.Close()
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bomb":
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = w.Write(bomb.Bytes())
+		case "/endless":
+			chunk := make([]byte, 32*1024)
+			for r.Context().Err() == nil {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+			}
+		case "/headers":
+			for i := 0; i < 20; i++ {
+				w.Header().Add("Set-Cookie", fmt.Sprintf("c%d=x", i))
+			}
+		case "/bigheader":
+			w.Header().Set("X-Big", strings.Repeat("x", 8*1024))
+		case "/exact":
+			_, _ = w.Write(make([]byte, 1024))
+		}
+	}))
+	t.Cleanup(h.Close)
+
+	c, err := NewHTTPClient(&HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{
+		Timeout: 5 * time.Second,
+		Limits:  ResponseLimits{MaxBodySize: 1024, MaxHeaderSize: 4096, MaxHeaders: 10},
+	}})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	tt := []struct {
+		testName        string
+		path            string
+		returnBody      bool
+		rejectTruncated bool
+		expectedLength  int64
+		expectedError   bool
+	}{
+		{"Decompression bomb", "/bomb", true, false, 1024, false},
+		{"Decompression bomb without body", "/bomb", false, false, 1024, false},
+		{"Decompression bomb rejected", "/bomb", true, true, 0, true},
+		{"Endless body", "/endless", false, false, 1024, false},
+		{"Endless body rejected", "/endless", true, true, 0, true},
+		{"Body of the maximum size", "/exact", true, true, 1024, false},
+		{"Too many headers", "/headers", false, false, 0, true},
+		{"Too big header", "/bigheader", false, false, 0, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			_, length, _, body, err := c.Request(context.Background(), h.URL+x.path, RequestOptions{ReturnBody: x.returnBody, RejectTruncated: x.rejectTruncated})
+			if x.expectedError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if length != x.expectedLength {
+				t.Fatalf("Expected the length %d but got %d", x.expectedLength, length)
+			}
+			if x.returnBody && int64(len(body)) != x.expectedLength {
+				t.Fatalf("Expected a body of %d bytes but got %d", x.expectedLength, len(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package libgobuster
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// hostProfile maps internationalized names like idna.Lookup but without the
+// STD3 rules, so labels like _dmarc, _sip._tcp or a_b stay valid
+// nolint:gochecknoglobals
+var hostProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+// HostToASCII returns the punycode encoded form of an internationalized host
+// name. ASCII names are returned unchanged, as DNS allows more than host
+// names do
+func HostToASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+	return hostProfile.ToASCII(name)
+}
+
+// HostToUnicode returns the unicode form of a punycode encoded host name
+func HostToUnicode(name string) (string, error) {
+	return hostProfile.ToUnicode(name)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,9 @@
 package libgobuster
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // GobusterPlugin is an interface which plugins must implement
 type GobusterPlugin interface {
@@ -11,7 +14,58 @@ type GobusterPlugin interface {
 	GetConfigString() (string, error)
 }
 
+// WarningPlugin is an optional interface plugins can implement to warn
+// about potentially destructive or noisy configurations
+type WarningPlugin interface {
+	Warnings() []string
+}
+
+// TargetPlugin is an optional interface plugins can implement to report
+// the target of the run, for example in the workspace
+type TargetPlugin interface {
+	Target() string
+}
+
+// SkipWordPlugin is an optional interface plugins can implement to only
+// process the AdditionalWords of a word and skip the word itself, like the
+// extension variants of a word without the bare word
+type SkipWordPlugin interface {
+	SkipWord(string) bool
+}
+
+// WordKindPlugin is an optional interface plugins can implement if their
+// words become the path of an url or a host name. Words which are invalid
+// for the kind are handled by the WordPolicy of the options before they are
+// passed to ProcessWord
+type WordKindPlugin interface {
+	WordKind() WordKind
+}
+
+// EnrichPlugin is an optional interface plugins can implement to move
+// expensive work on results, like hashing the body or follow up requests,
+// off the workers. Results sent to Progress.EnrichChan are passed to Enrich
+// by a separate pool and the returned result is sent to the ResultChan, even
+// if Enrich also returned an error
+type EnrichPlugin interface {
+	Enrich(context.Context, Result) (Result, error)
+}
+
+// LatencyPlugin is an optional interface plugins can implement to measure
+// the latency of their target with a harmless request, like a HEAD request
+// of the base url. It is used to estimate the duration of the run
+type LatencyPlugin interface {
+	ProbeLatency(context.Context) (time.Duration, error)
+}
+
 // Result is an interface for the Result object
 type Result interface {
 	ResultToString() (string, error)
 }
+
+// RecordResult is an optional interface results can implement to provide
+// their mode independent representation, which is used by filters,
+// suppressions and the structured outputs. Use NewResultRecord to convert
+// any Result
+type RecordResult interface {
+	ResultToRecord() ResultRecord
+}
@@ -4,12 +4,18 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// queuePollInterval is the interval to check for newly queued words once the
+// wordlist is processed
+const queuePollInterval = 20 * time.Millisecond
+
 // PATTERN is the pattern for wordlist replacements in pattern file
 const PATTERN = "{GOBUSTER}"
 
@@ -24,103 +30,269 @@ type ResultToStringFunc func(*Gobuster, *Result) (*string, error)
 
 // Gobuster is the main object when creating a new run
 type Gobuster struct {
-	Opts     *Options
-	Logger   Logger
-	plugin   GobusterPlugin
+	Opts   *Options
+	Logger Logger
+	// plugin is the plugin of the target currently scanned
+	plugin GobusterPlugin
+	// plugins holds one plugin per target, the targets are scanned one
+	// after another by the same workers
+	plugins  []GobusterPlugin
 	Progress *Progress
+	// inFlight is the number of words sent to the workers which are not processed yet
+	inFlight atomic.Int64
+	// collector holds the results if CollectResults is set
+	collector collector
+	// control holds the state of runs started in the background
+	control control
+	// memory pauses the run while the memory usage is above MaxMemory
+	memory memoryGuard
+	// extraWords holds the words of the extra wordlists
+	extraWords [][]string
+	// lines tracks the processed lines of the wordlist for the state file
+	lines lineTracker
+	// stateMutex guards state which is checkpointed if StateFile is set
+	stateMutex sync.Mutex
+	state      RunState
+	// completed is set once the whole wordlist was processed
+	completed atomic.Bool
+	// target is the index of the target currently scanned, it is guarded
+	// by the stateMutex as it is checkpointed with the wordlist offset
+	target int
+	// lineCount is the number of lines of the wordlist
+	lineCount int
+	// wordVariants is the number of words the expected requests assume
+	// for every word of the wordlist, 0 if the wordlist is read from stdin
+	wordVariants int
+}
+
+// wordItem is a word sent to the workers
+type wordItem struct {
+	word string
+	// line is the wordlist line the word belongs to, -1 for words which
+	// were queued by the plugin or are part of a recursion
+	line int
 }
 
 // NewGobuster returns a new Gobuster object
 func NewGobuster(opts *Options, plugin GobusterPlugin, logger Logger) (*Gobuster, error) {
+	return NewGobusterTargets(opts, []GobusterPlugin{plugin}, logger)
+}
+
+// NewGobusterTargets returns a new Gobuster object scanning multiple
+// targets, one plugin per target. The targets are scanned one after another
+// by the same workers, the results and the progress are combined
+func NewGobusterTargets(opts *Options, plugins []GobusterPlugin, logger Logger) (*Gobuster, error) {
+	if len(plugins) == 0 {
+		return nil, fmt.Errorf("please provide at least one target")
+	}
+	if len(plugins) > 1 && opts.Wordlist == "-" {
+		return nil, fmt.Errorf("multiple targets can not read the wordlist from stdin")
+	}
+
 	var g Gobuster
 	g.Opts = opts
-	g.plugin = plugin
+	g.plugin = plugins[0]
+	g.plugins = plugins
 	g.Logger = logger
 	g.Progress = NewProgress()
+	if len(plugins) > 1 {
+		g.Progress.setTargets(TargetNames(plugins))
+	}
+
+	extraWords, err := readExtraWordlists(opts.ExtraWordlists)
+	if err != nil {
+		return nil, err
+	}
+	g.extraWords = extraWords
 
 	return &g, nil
 }
 
-func (g *Gobuster) worker(ctx context.Context, wordChan <-chan string, wg *sync.WaitGroup) {
+func (g *Gobuster) worker(ctx context.Context, wordChan <-chan wordItem, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case word, ok := <-wordChan:
+		case item, ok := <-wordChan:
 			// worker finished
 			if !ok {
 				return
 			}
-			g.Progress.incrementRequests()
-
-			wordCleaned := strings.TrimSpace(word)
-			// Skip "comment" (starts with #), as well as empty lines
-			if strings.HasPrefix(wordCleaned, "#") || len(wordCleaned) == 0 {
-				break
+			g.processWord(ctx, item.word)
+			// words interrupted by a cancel are processed again on resume
+			if item.line >= 0 && ctx.Err() == nil {
+				g.lines.done(item.line)
 			}
+			g.inFlight.Add(-1)
+		}
+	}
+}
 
-			// Mode-specific processing
-			err := g.plugin.ProcessWord(ctx, wordCleaned, g.Progress)
-			if err != nil {
-				// do not exit and continue
-				g.Progress.ErrorChan <- err
-				continue
-			}
+func (g *Gobuster) processWord(ctx context.Context, word string) {
+	g.waitIfPaused(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	g.Progress.incrementRequests()
+
+	// Skip "comment" (starts with #), as well as empty lines
+	wordCleaned, ok := wordlistEntry(word)
+	if !ok {
+		return
+	}
 
-			select {
-			case <-ctx.Done():
-			case <-time.After(g.Opts.Delay):
+	if p, ok := g.plugin.(WordKindPlugin); ok {
+		fixed, problem, ok := ApplyWordPolicy(wordCleaned, p.WordKind(), g.Opts.WordPolicy)
+		if !ok {
+			g.Progress.MessageChan <- Message{
+				Level:   LevelInfo,
+				Message: fmt.Sprintf("Skipped word %q: %s", truncateUTF8(wordCleaned, 80), problem),
+			}
+			return
+		}
+		if problem != "" {
+			g.Progress.MessageChan <- Message{
+				Level:   LevelDebug,
+				Message: fmt.Sprintf("Changed word %q to %q: %s", truncateUTF8(wordCleaned, 80), truncateUTF8(fixed, 80), problem),
 			}
 		}
+		wordCleaned = fixed
 	}
-}
 
-func (g *Gobuster) getWordlist() (*bufio.Scanner, error) {
-	if g.Opts.Wordlist == "-" {
-		// Read directly from stdin
-		return bufio.NewScanner(os.Stdin), nil
+	// Skip entries which are already known from previous runs
+	if g.Opts.KnownWords.Contains(strings.TrimPrefix(wordCleaned, "/")) {
+		return
 	}
-	// Pull content from the wordlist
-	wordlist, err := os.Open(g.Opts.Wordlist)
+
+	// Mode-specific processing
+	err := g.plugin.ProcessWord(ctx, wordCleaned, g.Progress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open wordlist: %w", err)
+		// do not exit and continue
+		g.Progress.ErrorChan <- err
+		return
 	}
 
-	lines, err := lineCounter(wordlist)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get number of lines: %w", err)
+	select {
+	case <-ctx.Done():
+	case <-time.After(g.Opts.Delay):
 	}
+}
 
-	if lines-g.Opts.WordlistOffset <= 0 {
-		return nil, fmt.Errorf("offset is greater than the number of lines in the wordlist")
+// sendWord sends a word of the wordlist line to the workers. It returns
+// false if the context was canceled before the word could be sent
+func (g *Gobuster) sendWord(ctx context.Context, wordChan chan<- wordItem, word string, line int) bool {
+	g.inFlight.Add(1)
+	if line >= 0 {
+		g.lines.add(line)
 	}
+	select {
+	case <-ctx.Done():
+		g.inFlight.Add(-1)
+		return false
+	case wordChan <- wordItem{word: word, line: line}:
+		return true
+	}
+}
 
-	// calcutate expected requests
-	g.Progress.IncrementTotalRequests(lines)
+// sendQueuedWords sends all words queued by the plugin to the workers. It
+// returns false if the context was canceled
+func (g *Gobuster) sendQueuedWords(ctx context.Context, wordChan chan<- wordItem) bool {
+	for _, w := range g.Progress.dequeueWords() {
+		if !g.sendWord(ctx, wordChan, w, -1) {
+			return false
+		}
+	}
+	return true
+}
 
-	// add offset if needed (offset defaults to 0)
-	g.Progress.incrementRequestsIssues(g.Opts.WordlistOffset)
+// drainQueue processes words queued while processing other words until the
+// queue is empty and no worker is busy anymore, so no new words can be queued
+func (g *Gobuster) drainQueue(ctx context.Context, wordChan chan<- wordItem) {
+	tick := time.NewTicker(queuePollInterval)
+	defer tick.Stop()
 
-	// call the function once with a dummy entry to receive the number
-	// of custom words per wordlist word
-	customWordsLen := len(g.plugin.AdditionalWords("dummy"))
-	if customWordsLen > 0 {
-		origExpected := g.Progress.RequestsExpected()
-		inc := origExpected * customWordsLen
-		g.Progress.IncrementTotalRequests(inc)
+	for {
+		if !g.sendQueuedWords(ctx, wordChan) {
+			return
+		}
+		if g.inFlight.Load() == 0 && g.Progress.queueLength() == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// openWordlist opens the wordlist and returns it with its number of lines.
+// Multiple wordlists are merged, the lines are their unique words then
+func (g *Gobuster) openWordlist() (io.ReadCloser, int, error) {
+	if len(g.Opts.Wordlists) > 1 {
+		lines, _, err := CountMergedWordlists(g.Opts.Wordlists)
+		if err != nil {
+			return nil, 0, err
+		}
+		return newMergedWordlist(g.Opts.Wordlists), lines, nil
+	}
+
+	wordlist, err := os.Open(g.Opts.Wordlist)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open wordlist: %w", err)
+	}
+
+	lines, err := lineCounter(wordlist)
+	if err != nil {
+		wordlist.Close()
+		return nil, 0, fmt.Errorf("failed to get number of lines: %w", err)
 	}
 
 	// rewind wordlist
-	_, err = wordlist.Seek(0, 0)
+	if _, err := wordlist.Seek(0, 0); err != nil {
+		wordlist.Close()
+		return nil, 0, fmt.Errorf("failed to rewind wordlist: %w", err)
+	}
+	return wordlist, lines, nil
+}
+
+// getWordlist returns a scanner over the wordlist skipping the first offset
+// lines. The expected requests of the progress are increased by the
+// requests of the given number of passes over the wordlist
+func (g *Gobuster) getWordlist(offset, passes int) (*bufio.Scanner, error) {
+	if g.Opts.Wordlist == "-" {
+		// Read directly from stdin
+		return newWordlistScanner(os.Stdin), nil
+	}
+	// Pull content from the wordlist
+	wordlist, lines, err := g.openWordlist()
 	if err != nil {
-		return nil, fmt.Errorf("failed to rewind wordlist: %w", err)
+		return nil, err
 	}
 
-	wordlistScanner := bufio.NewScanner(wordlist)
+	if lines-offset <= 0 {
+		return nil, fmt.Errorf("offset is greater than the number of lines in the wordlist")
+	}
+
+	g.stateMutex.Lock()
+	g.lineCount = lines
+	g.stateMutex.Unlock()
+	lines = g.wordlistLines(lines)
+	g.wordVariants = g.requestsPerWord()
+	perWord := g.wordVariants * g.combinations()
+
+	// calcutate expected requests
+	g.Progress.expectPasses(passes, lines*perWord)
+
+	// add offset if needed (offset defaults to 0) so the progress reflects
+	// the whole wordlist when resuming
+	g.Progress.resumeRequests(offset * perWord)
+
+	wordlistScanner := newWordlistScanner(wordlist)
 
 	// skip lines
-	for i := 0; i < g.Opts.WordlistOffset; i++ {
+	for i := 0; i < offset; i++ {
 		if !wordlistScanner.Scan() {
 			if err := wordlistScanner.Err(); err != nil {
 				return nil, fmt.Errorf("failed to skip lines in wordlist: %w", err)
@@ -135,18 +307,47 @@ func (g *Gobuster) getWordlist() (*bufio.Scanner, error) {
 // Run the busting of the website with the given
 // set of settings from the command line.
 func (g *Gobuster) Run(ctx context.Context) error {
+	if g.Opts.CollectResults {
+		// wait until all channels are drained after they are closed so
+		// the collected results are complete once we return
+		var collectGroup sync.WaitGroup
+		g.collect(&collectGroup)
+		defer collectGroup.Wait()
+	}
 	defer close(g.Progress.ResultChan)
 	defer close(g.Progress.ErrorChan)
 	defer close(g.Progress.MessageChan)
 
-	if err := g.plugin.PreRun(ctx, g.Progress); err != nil {
-		return err
+	if g.Opts.TargetOffset < 0 || g.Opts.TargetOffset >= len(g.plugins) {
+		return fmt.Errorf("invalid target offset %d for %d targets", g.Opts.TargetOffset, len(g.plugins))
+	}
+	// the targets before the offset were scanned by the resumed run
+	for i := 0; i < g.Opts.TargetOffset; i++ {
+		g.Progress.finishTarget(i, TargetDone)
+	}
+	g.setTarget(g.Opts.TargetOffset, g.Opts.WordlistOffset)
+
+	// runs over multiple targets skip the targets failing their PreRun
+	preRunErr := g.plugin.PreRun(ctx, g.Progress)
+	if preRunErr != nil && len(g.plugins) == 1 {
+		return preRunErr
+	}
+
+	if g.Opts.MaxMemory > 0 {
+		stop := g.startMemoryGuard()
+		defer stop()
+	}
+
+	if g.Opts.StateFile != "" {
+		stop := g.startCheckpoints()
+		defer stop()
 	}
 
 	var workerGroup sync.WaitGroup
 	workerGroup.Add(g.Opts.Threads)
 
-	wordChan := make(chan string, g.Opts.Threads)
+	wordChan := make(chan wordItem, g.Opts.Threads)
+	stopEnrichment := g.startEnrichment(ctx)
 
 	// Create goroutines for each of the number of threads
 	// specified.
@@ -154,49 +355,155 @@ func (g *Gobuster) Run(ctx context.Context) error {
 		go g.worker(ctx, wordChan, &workerGroup)
 	}
 
-	scanner, err := g.getWordlist()
+	// the expected requests of all remaining targets are added with the
+	// first target scanned
+	offset, passes := g.Opts.WordlistOffset, len(g.plugins)-g.Opts.TargetOffset
+	var canceled bool
+	var err error
+	for i := g.Opts.TargetOffset; i < len(g.plugins) && !canceled && err == nil && ctx.Err() == nil; i++ {
+		if i > g.Opts.TargetOffset {
+			// the results of the previous target are enriched by its plugin
+			stopEnrichment()
+			g.setTarget(i, 0)
+			stopEnrichment = g.startEnrichment(ctx)
+			g.Progress.MessageChan <- Message{
+				Level:   LevelInfo,
+				Message: fmt.Sprintf(T("Scanning target %d/%d: %s"), i+1, len(g.plugins), g.Progress.targetName(i)),
+			}
+			preRunErr = g.plugin.PreRun(ctx, g.Progress)
+		}
+		if preRunErr != nil {
+			g.Progress.ErrorChan <- fmt.Errorf("skipping target %s: %w", g.Progress.targetName(i), preRunErr)
+			if passes > 0 {
+				passes--
+			} else {
+				g.Progress.IncrementTotalRequests(-g.targetRequests())
+			}
+			g.Progress.finishTarget(i, TargetSkipped)
+			offset = 0
+			continue
+		}
+		canceled, err = g.scanTarget(ctx, wordChan, offset, passes)
+		if !canceled && err == nil {
+			g.Progress.finishTarget(i, TargetDone)
+		}
+		offset, passes = 0, 0
+	}
+
+	close(wordChan)
+	workerGroup.Wait()
+	stopEnrichment()
+
+	g.completed.Store(!canceled && err == nil && ctx.Err() == nil)
+	return err
+}
+
+// scanTarget sends the wordlist and the recursions queued by the plugin to
+// the workers and waits until all words are processed. passes is the number
+// of wordlist passes added to the expected requests. It returns true if the
+// context was canceled
+func (g *Gobuster) scanTarget(ctx context.Context, wordChan chan<- wordItem, offset, passes int) (bool, error) {
+	scanner, err := g.getWordlist(offset, passes)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	canceled := !g.processWordlist(ctx, wordChan, scanner, offset, "")
+	if !canceled {
+		g.drainQueue(ctx, wordChan)
+	}
+	err = scanner.Err()
+
+	// prefixes queued by the plugin get another pass over the wordlist. The
+	// queue is drained before, so no worker can queue new prefixes meanwhile
+	for !canceled && err == nil {
+		prefix, ok := g.Progress.dequeueRecursion()
+		if !ok {
+			break
+		}
+		scanner, err = g.getWordlist(0, 1)
+		if err != nil {
+			break
+		}
+		canceled = !g.processWordlist(ctx, wordChan, scanner, 0, prefix)
+		if !canceled {
+			g.drainQueue(ctx, wordChan)
+		}
+		err = scanner.Err()
+	}
+	return canceled, err
+}
+
+// processWordlist sends every word of the wordlist prefixed with prefix to
+// the workers. index is the index of the first word in the wordlist. It
+// returns false if the context was canceled. The processed lines are only
+// tracked for the pass without a prefix
+func (g *Gobuster) processWordlist(ctx context.Context, wordChan chan<- wordItem, scanner *bufio.Scanner, index int, prefix string) bool {
+	line := index
 Scan:
-	for scanner.Scan() {
+	for ; scanner.Scan(); line++ {
+		tracked := -1
+		if prefix == "" {
+			tracked = line
+			// all words of the previous lines are sent
+			g.lines.setScanned(line)
+		}
+
+		// words queued by the plugin are processed before the next wordlist entry
+		if !g.sendQueuedWords(ctx, wordChan) {
+			return false
+		}
+
 		select {
 		case <-ctx.Done():
-			break Scan
+			return false
 		default:
 			word := scanner.Text()
-			perms := g.processPatterns(word)
-			// add the original word
-			wordChan <- word
-			// now create perms
-			for _, w := range perms {
-				select {
-				// need to check here too otherwise wordChan will block
-				case <-ctx.Done():
-					break Scan
-				case wordChan <- w:
+			if _, ok := wordlistEntry(word); !ok {
+				// comments and empty lines are skipped by the workers and
+				// can't be combined with the other wordlists or prefixed
+				if len(g.extraWords) > 0 || prefix != "" {
+					if !g.sendWord(ctx, wordChan, word, tracked) {
+						return false
+					}
+					continue
 				}
 			}
 
-			for _, w := range g.plugin.AdditionalWords(word) {
-				select {
-				// need to check here too otherwise wordChan will block
-				case <-ctx.Done():
-					break Scan
-				case wordChan <- w:
+			// the original word, the perms and the additional words of
+			// the plugin are combined with the extra wordlists
+			var words []string
+			if !g.skipWord(word) {
+				words = append(words, word)
+			}
+			words = append(words, g.processPatterns(word)...)
+			words = append(words, g.plugin.AdditionalWords(word)...)
+			// the expected requests assume the number of words of a word
+			// without extension, correct them for words differing from it
+			if g.wordVariants > 0 && len(words) != g.wordVariants {
+				g.Progress.IncrementTotalRequests((len(words) - g.wordVariants) * g.combinations())
+			}
+			combined := 0
+			for _, w := range words {
+				for _, c := range g.combine(w, index) {
+					combined++
+					// need to check here too otherwise wordChan will block
+					if !g.sendWord(ctx, wordChan, prefix+c, tracked) {
+						return false
+					}
 				}
 			}
+			index++
+			// the pitchfork strategy stops at the end of the shortest wordlist
+			if combined == 0 && len(words) > 0 {
+				break Scan
+			}
 		}
 	}
-	close(wordChan)
-	workerGroup.Wait()
-
-	if err := scanner.Err(); err != nil {
-		return err
+	if prefix == "" {
+		g.lines.setScanned(line)
 	}
-
-	return nil
+	return true
 }
 
 // GetConfigString returns the current config as a printable string
@@ -204,6 +511,24 @@ func (g *Gobuster) GetConfigString() (string, error) {
 	return g.plugin.GetConfigString()
 }
 
+// requestsPerWord returns the number of requests issued for every word in the wordlist
+func (g *Gobuster) requestsPerWord() int {
+	// call the function once with a dummy entry to receive the number
+	// of custom words per wordlist word
+	words := 1 + len(g.processPatterns("dummy")) + len(g.plugin.AdditionalWords("dummy"))
+	if g.skipWord("dummy") {
+		words--
+	}
+	return words
+}
+
+// skipWord checks if the plugin only processes the additional words of the
+// word and not the word itself
+func (g *Gobuster) skipWord(word string) bool {
+	p, ok := g.plugin.(SkipWordPlugin)
+	return ok && p.SkipWord(word)
+}
+
 func (g *Gobuster) processPatterns(word string) []string {
 	if g.Opts.PatternFile == "" {
 		return nil
@@ -0,0 +1,63 @@
+package libgobuster
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// nolint:gochecknoglobals
+var linkRegex = regexp.MustCompile(`(?i)(?:href|src|action)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>"']+))`)
+
+// ExtractLinks returns the unique values of all href, src and action
+// attributes in the body
+func ExtractLinks(body []byte) []string {
+	seen := NewSet[string]()
+	var ret []string
+	for _, m := range linkRegex.FindAllSubmatch(body, -1) {
+		var link string
+		for _, g := range m[1:] {
+			if len(g) > 0 {
+				link = string(g)
+				break
+			}
+		}
+		link = strings.TrimSpace(html.UnescapeString(link))
+		if link == "" || strings.HasPrefix(link, "#") {
+			continue
+		}
+		if seen.Add(link) {
+			ret = append(ret, link)
+		}
+	}
+	return ret
+}
+
+// ScopedPath resolves the link relative to the page and returns the path
+// relative to the base url. It returns false if the link points outside of
+// the base url, e.g. to another host
+func ScopedPath(base, page *url.URL, link string) (string, bool) {
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", false
+	}
+	u := page.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+	if !strings.EqualFold(u.Host, base.Host) {
+		return "", false
+	}
+
+	prefix := strings.TrimSuffix(base.EscapedPath(), "/") + "/"
+	p := u.EscapedPath()
+	if !strings.HasPrefix(p, prefix) {
+		return "", false
+	}
+	p = strings.TrimPrefix(p, prefix)
+	if p == "" {
+		return "", false
+	}
+	return p, true
+}
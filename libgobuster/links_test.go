@@ -0,0 +1,52 @@
+package libgobuster
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`<a href="/login">Login</a><a HREF='admin/'>x</a><img src=logo.png><form action="/search?q=1"></form><a href="#top"></a><a href="/login">again</a><a href="/a&amp;b">amp</a>`)
+	expected := []string{"/login", "admin/", "logo.png", "/search?q=1", "/a&b"}
+
+	if got := ExtractLinks(body); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestScopedPath(t *testing.T) {
+	t.Parallel()
+
+	base, _ := url.Parse("http://example.com/app/")
+	page, _ := url.Parse("http://example.com/app/users/list")
+
+	tt := []struct {
+		link     string
+		expected string
+		ok       bool
+	}{
+		{"/app/login", "login", true},
+		{"edit?id=1", "users/edit", true},
+		{"../static/app.js", "static/app.js", true},
+		{"http://example.com/app/admin", "admin", true},
+		{"/other", "", false},
+		{"http://evil.com/app/admin", "", false},
+		{"mailto:test@example.com", "", false},
+		{"/app/", "", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.link, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ScopedPath(base, page, x.link)
+			if got != x.expected || ok != x.ok {
+				t.Fatalf("expected %q (%t), got %q (%t)", x.expected, x.ok, got, ok)
+			}
+		})
+	}
+}
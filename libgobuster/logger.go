@@ -12,6 +12,7 @@ type Logger struct {
 	errorLog *log.Logger
 	debugLog *log.Logger
 	infoLog  *log.Logger
+	warnLog  *log.Logger
 	debug    bool
 }
 
@@ -21,6 +22,7 @@ func NewLogger(debug bool) Logger {
 		errorLog: log.New(os.Stderr, color.New(color.FgRed).Sprint("[ERROR] "), 0),
 		debugLog: log.New(os.Stderr, color.New(color.FgBlue).Sprint("[DEBUG] "), 0),
 		infoLog:  log.New(os.Stderr, color.New(color.FgCyan).Sprint("[INFO] "), 0),
+		warnLog:  log.New(os.Stderr, color.New(color.FgYellow).Sprint("[WARN] "), 0),
 		debug:    debug,
 	}
 }
@@ -47,6 +49,14 @@ func (l Logger) Infof(format string, v ...any) {
 	l.infoLog.Printf(format, v...)
 }
 
+func (l Logger) Warn(v ...any) {
+	l.warnLog.Print(v...)
+}
+
+func (l Logger) Warnf(format string, v ...any) {
+	l.warnLog.Printf(format, v...)
+}
+
 func (l Logger) Print(v ...any) {
 	l.log.Print(v...)
 }
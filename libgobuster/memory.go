@@ -0,0 +1,184 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryInterval is the interval the memory usage is checked in
+const memoryInterval = time.Second
+
+// MinMemoryLimit is the smallest memory limit, the go runtime alone needs a
+// few megabytes
+const MinMemoryLimit = 16 << 20
+
+// memoryResumeRatio is the share of the memory limit the usage needs to drop
+// below before a run paused by the memory guard continues
+const memoryResumeRatio = 0.8
+
+// memoryUnits maps the suffixes of memory sizes to their factor
+// nolint:gochecknoglobals
+var memoryUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"kib", 1 << 10},
+	{"mib", 1 << 20},
+	{"gib", 1 << 30},
+	{"kb", 1 << 10},
+	{"mb", 1 << 20},
+	{"gb", 1 << 30},
+	{"k", 1 << 10},
+	{"m", 1 << 20},
+	{"g", 1 << 30},
+	{"b", 1},
+}
+
+// ParseMemorySize parses a memory size like 512MB or 2GiB. All units are
+// powers of 1024, a number without unit is in bytes
+func ParseMemorySize(size string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(size))
+	factor := int64(1)
+	for _, u := range memoryUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			factor = u.factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid memory size %q", size)
+	}
+	return n * factor, nil
+}
+
+// FormatMemorySize returns the size in the biggest unit with one decimal
+// place, like 512MiB or 6.3MiB
+func FormatMemorySize(size int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0") + units[unit]
+}
+
+// memoryUsage returns the memory the go runtime holds from the os. This is
+// the memory the soft memory limit of the garbage collector applies to
+func memoryUsage() int64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.Sys - m.HeapReleased)
+}
+
+// memoryGuard pauses the intake of new words while the memory usage is
+// above the limit
+type memoryGuard struct {
+	mutex sync.Mutex
+	// resume is closed when the intake continues, nil if not paused
+	resume chan struct{}
+}
+
+// wait blocks while the memory guard paused the intake
+func (m *memoryGuard) wait(ctx context.Context) {
+	m.mutex.Lock()
+	resume := m.resume
+	m.mutex.Unlock()
+	if resume == nil {
+		return
+	}
+	select {
+	case <-resume:
+	case <-ctx.Done():
+	}
+}
+
+// check pauses or resumes the intake for the memory usage. It returns if
+// the intake is paused and if this changed
+func (m *memoryGuard) check(usage, limit int64) (bool, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	switch {
+	case m.resume == nil && usage > limit:
+		m.resume = make(chan struct{})
+		return true, true
+	case m.resume != nil && usage < int64(float64(limit)*memoryResumeRatio):
+		close(m.resume)
+		m.resume = nil
+		return false, true
+	}
+	return m.resume != nil, false
+}
+
+// release continues the intake if it was paused
+func (m *memoryGuard) release() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.resume != nil {
+		close(m.resume)
+		m.resume = nil
+	}
+}
+
+// startMemoryGuard sets the soft memory limit of the garbage collector and
+// checks the memory usage every memoryInterval until the returned function
+// is called. Above the limit no new words are processed and the memory freed
+// by the finished requests is returned to the os until the usage dropped
+// again
+func (g *Gobuster) startMemoryGuard() func() {
+	limit := g.Opts.MaxMemory
+	previous := debug.SetMemoryLimit(limit)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tick := time.NewTicker(memoryInterval)
+		defer tick.Stop()
+		paused := false
+		for {
+			select {
+			case <-stop:
+				return
+			case <-tick.C:
+				usage := memoryUsage()
+				if paused {
+					debug.FreeOSMemory()
+					usage = memoryUsage()
+				}
+				var changed bool
+				paused, changed = g.memory.check(usage, limit)
+				if !changed {
+					continue
+				}
+				if paused {
+					g.Progress.MessageChan <- Message{
+						Level:   LevelInfo,
+						Message: fmt.Sprintf("Memory usage of %s is above the limit of %s, pausing", FormatMemorySize(usage), FormatMemorySize(limit)),
+					}
+				} else {
+					g.Progress.MessageChan <- Message{
+						Level:   LevelInfo,
+						Message: fmt.Sprintf("Memory usage dropped to %s, continuing", FormatMemorySize(usage)),
+					}
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		wg.Wait()
+		g.memory.release()
+		debug.SetMemoryLimit(previous)
+	}
+}
@@ -0,0 +1,100 @@
+package libgobuster
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseMemorySize(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		size     string
+		expected int64
+		err      bool
+	}{
+		{"1024", 1024, false},
+		{"512MB", 512 << 20, false},
+		{"512 mib", 512 << 20, false},
+		{"2G", 2 << 30, false},
+		{"10kb", 10 << 10, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"-1MB", 0, true},
+		{"1TB", 0, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.size, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseMemorySize(x.size)
+			if x.err {
+				if err == nil {
+					t.Fatalf("expected an error for %q", x.size)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if got != x.expected {
+				t.Fatalf("expected %d, got %d", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatMemorySize(t *testing.T) {
+	t.Parallel()
+
+	tt := map[int64]string{
+		512:            "512B",
+		512 << 20:      "512MiB",
+		6606028:        "6.3MiB",
+		3 << 30:        "3GiB",
+		MinMemoryLimit: "16MiB",
+	}
+	for size, expected := range tt {
+		if got := FormatMemorySize(size); got != expected {
+			t.Fatalf("expected %q for %d, got %q", expected, size, got)
+		}
+	}
+}
+
+func TestMemoryGuard(t *testing.T) {
+	t.Parallel()
+
+	var m memoryGuard
+	if paused, changed := m.check(90, 100); paused || changed {
+		t.Fatal("expected no pause below the limit")
+	}
+	if paused, changed := m.check(110, 100); !paused || !changed {
+		t.Fatal("expected a pause above the limit")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.wait(context.Background())
+		close(done)
+	}()
+
+	// the intake only continues well below the limit
+	if paused, changed := m.check(90, 100); !paused || changed {
+		t.Fatal("expected the pause to continue just below the limit")
+	}
+	select {
+	case <-done:
+		t.Fatal("expected the wait to block while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if paused, changed := m.check(70, 100); paused || !changed {
+		t.Fatal("expected the pause to end below the resume ratio")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the wait to return after resuming")
+	}
+}
@@ -0,0 +1,183 @@
+package libgobuster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used if no catalog exists for the selected locale
+const DefaultLocale = "en"
+
+// catalogs contains the translations of the user facing messages. The
+// english message is the key and also used if a translation is missing.
+// Translations need to contain the same formatting verbs in the same order.
+// nolint:gochecknoglobals
+var catalogs = map[string]map[string]string{
+	"de": {
+		"Starting gobuster in %s mode":                                                    "Starte gobuster im Modus %s",
+		"Skipping the first %d elements...":                                               "Überspringe die ersten %d Einträge...",
+		"Skipping %d known entries...":                                                    "Überspringe %d bekannte Einträge...",
+		"Merged %d wordlists into %d unique words, %d duplicates removed":                 "%d Wortlisten zu %d eindeutigen Wörtern zusammengeführt, %d Duplikate entfernt",
+		"Restored %d findings from %s":                                                    "%d Funde aus %s wiederhergestellt",
+		"Scanning %d targets one after another, the configuration is shown for the first": "Scanne %d Ziele nacheinander, die Konfiguration wird für das erste angezeigt",
+		"Scanning target %d/%d: %s":                                                       "Scanne Ziel %d/%d: %s",
+		"Finished":                                                                        "Fertig",
+		"Stopped early (%s): %s":                                                          "Vorzeitig beendet (%s): %s",
+		"Requests: %d total, %d resumed from a previous run":                              "Anfragen: %d gesamt, %d aus einem vorherigen Lauf übernommen",
+		"Connections: %d requests, %.1f%% reused, %d DNS lookups, %d TLS handshakes, average TTFB %s": "Verbindungen: %d Anfragen, %.1f%% wiederverwendet, %d DNS-Abfragen, %d TLS-Handshakes, durchschnittliche TTFB %s",
+		"Certificate problems of %s":                       "Zertifikatsprobleme von %s",
+		"Similar results:":                                 "Ähnliche Ergebnisse:",
+		"%d results (Status: %d)%s":                        "%d Ergebnisse (Status: %d)%s",
+		"... and %d more (use -v to show all)":             "... und %d weitere (-v zeigt alle)",
+		"Findings:":                                        "Funde:",
+		"%s, stopping":                                     "%s, breche ab",
+		"Reached %d findings":                              "%d Funde erreicht",
+		"Found %s tagged %q":                               "%s mit Markierung %q gefunden",
+		"Reached %d errors":                                "%d Fehler erreicht",
+		"Reached the maximum time of %s":                   "Maximale Laufzeit von %s erreicht",
+		"The run was canceled":                             "Der Lauf wurde abgebrochen",
+		"Estimated duration: %s (%s per request)":          "Geschätzte Dauer: %s (%s pro Anfrage)",
+		"Estimated duration: %s (assuming %s per request)": "Geschätzte Dauer: %s (bei angenommenen %s pro Anfrage)",
+		"Estimated requests: %d":                           "Geschätzte Anfragen: %d",
+		"Redaction key: %x":                                "Schwärzungsschlüssel: %x",
+		"This configuration looks destructive or noisy (use --force to skip this check).": "Diese Konfiguration wirkt destruktiv oder auffällig (--force überspringt diese Prüfung).",
+		"This scan will take about %s (use --yes to skip this check).":                    "Dieser Scan dauert etwa %s (--yes überspringt diese Prüfung).",
+		"Can not ask for confirmation when reading the wordlist from STDIN, aborting":     "Bestätigung nicht möglich, da die Wortliste von STDIN gelesen wird, breche ab",
+		"Can not ask for confirmation as STDIN is not a terminal, aborting":               "Bestätigung nicht möglich, da STDIN kein Terminal ist, breche ab",
+		"Continue? [y/N]":             "Fortfahren? [y/N]",
+		"Aborted":                     "Abgebrochen",
+		"Progress: %d":                "Fortschritt: %d",
+		"Progress: %d / %d (%3.2f%%)": "Fortschritt: %d / %d (%3.2f%%)",
+		"[%.0f req/s, ETA: %s]":       "[%.0f Anfragen/s, verbleibend: %s]",
+		"[target %d/%d: %s %3.2f%%]":  "[Ziel %d/%d: %s %3.2f%%]",
+		"Target %d/%d %s: %s, %d / %d requests (%3.2f%%)": "Ziel %d/%d %s: %s, %d / %d Anfragen (%3.2f%%)",
+		"[%.0f req/s, %s]": "[%.0f Anfragen/s, %s]",
+		"Press enter to show the progress of every target": "Enter zeigt den Fortschritt jedes Ziels",
+		"Keyboard interrupt detected, terminating.":        "Tastaturunterbrechung erkannt, beende.",
+		"gobuster finished":                                "gobuster ist fertig",
+		"gobuster failed":                                  "gobuster ist fehlgeschlagen",
+		"gobuster stopped early":                           "gobuster wurde vorzeitig beendet",
+		"gobuster finding":                                 "gobuster Fund",
+		"%s: %d requests, %d findings":                     "%s: %d Anfragen, %d Funde",
+		"%s and %d more":                                   "%s und %d weitere",
+		"%d more findings":                                 "%d weitere Funde",
+	},
+	"es": {
+		"Starting gobuster in %s mode":                                                    "Iniciando gobuster en modo %s",
+		"Skipping the first %d elements...":                                               "Omitiendo los primeros %d elementos...",
+		"Skipping %d known entries...":                                                    "Omitiendo %d entradas conocidas...",
+		"Merged %d wordlists into %d unique words, %d duplicates removed":                 "%d listas de palabras combinadas en %d palabras únicas, %d duplicados eliminados",
+		"Restored %d findings from %s":                                                    "Restaurados %d hallazgos de %s",
+		"Scanning %d targets one after another, the configuration is shown for the first": "Escaneando %d objetivos uno tras otro, se muestra la configuración del primero",
+		"Scanning target %d/%d: %s":                                                       "Escaneando objetivo %d/%d: %s",
+		"Finished":                                                                        "Terminado",
+		"Stopped early (%s): %s":                                                          "Detenido antes de tiempo (%s): %s",
+		"Requests: %d total, %d resumed from a previous run":                              "Peticiones: %d en total, %d reanudadas de una ejecución anterior",
+		"Connections: %d requests, %.1f%% reused, %d DNS lookups, %d TLS handshakes, average TTFB %s": "Conexiones: %d peticiones, %.1f%% reutilizadas, %d consultas DNS, %d handshakes TLS, TTFB medio %s",
+		"Certificate problems of %s":                       "Problemas de certificado de %s",
+		"Similar results:":                                 "Resultados similares:",
+		"%d results (Status: %d)%s":                        "%d resultados (Estado: %d)%s",
+		"... and %d more (use -v to show all)":             "... y %d más (use -v para mostrar todos)",
+		"Findings:":                                        "Hallazgos:",
+		"%s, stopping":                                     "%s, deteniendo",
+		"Reached %d findings":                              "Se alcanzaron %d hallazgos",
+		"Found %s tagged %q":                               "Encontrado %s etiquetado %q",
+		"Reached %d errors":                                "Se alcanzaron %d errores",
+		"Reached the maximum time of %s":                   "Se alcanzó el tiempo máximo de %s",
+		"The run was canceled":                             "La ejecución fue cancelada",
+		"Estimated duration: %s (%s per request)":          "Duración estimada: %s (%s por petición)",
+		"Estimated duration: %s (assuming %s per request)": "Duración estimada: %s (suponiendo %s por petición)",
+		"Estimated requests: %d":                           "Peticiones estimadas: %d",
+		"Redaction key: %x":                                "Clave de redacción: %x",
+		"This configuration looks destructive or noisy (use --force to skip this check).": "Esta configuración parece destructiva o ruidosa (use --force para omitir esta comprobación).",
+		"This scan will take about %s (use --yes to skip this check).":                    "Este escaneo tardará aproximadamente %s (use --yes para omitir esta comprobación).",
+		"Can not ask for confirmation when reading the wordlist from STDIN, aborting":     "No se puede pedir confirmación al leer la lista de palabras desde STDIN, abortando",
+		"Can not ask for confirmation as STDIN is not a terminal, aborting":               "No se puede pedir confirmación porque STDIN no es una terminal, abortando",
+		"Continue? [y/N]":             "¿Continuar? [y/N]",
+		"Aborted":                     "Abortado",
+		"Progress: %d":                "Progreso: %d",
+		"Progress: %d / %d (%3.2f%%)": "Progreso: %d / %d (%3.2f%%)",
+		"[%.0f req/s, ETA: %s]":       "[%.0f peticiones/s, restante: %s]",
+		"[target %d/%d: %s %3.2f%%]":  "[objetivo %d/%d: %s %3.2f%%]",
+		"Target %d/%d %s: %s, %d / %d requests (%3.2f%%)": "Objetivo %d/%d %s: %s, %d / %d peticiones (%3.2f%%)",
+		"[%.0f req/s, %s]": "[%.0f peticiones/s, %s]",
+		"Press enter to show the progress of every target": "Pulse enter para mostrar el progreso de cada objetivo",
+		"Keyboard interrupt detected, terminating.":        "Interrupción de teclado detectada, terminando.",
+		"gobuster finished":                                "gobuster terminó",
+		"gobuster failed":                                  "gobuster falló",
+		"gobuster stopped early":                           "gobuster se detuvo antes de tiempo",
+		"gobuster finding":                                 "hallazgo de gobuster",
+		"%s: %d requests, %d findings":                     "%s: %d peticiones, %d hallazgos",
+		"%s and %d more":                                   "%s y %d más",
+		"%d more findings":                                 "%d hallazgos más",
+	},
+}
+
+// nolint:gochecknoglobals
+var (
+	localeMutex sync.RWMutex
+	locale      = DefaultLocale
+)
+
+// Locales returns all supported locales
+func Locales() []string {
+	ret := []string{DefaultLocale}
+	for l := range catalogs {
+		ret = append(ret, l)
+	}
+	sort.Strings(ret[1:])
+	return ret
+}
+
+// SetLocale selects the catalog used by T. Values like de_DE.UTF-8 are
+// reduced to the language
+func SetLocale(l string) error {
+	lang := localeLanguage(l)
+	if _, ok := catalogs[lang]; !ok && lang != DefaultLocale {
+		return fmt.Errorf("unsupported language %q, valid values are %s", l, strings.Join(Locales(), ", "))
+	}
+	localeMutex.Lock()
+	defer localeMutex.Unlock()
+	locale = lang
+	return nil
+}
+
+// DetectLocale returns the language of the environment (LC_ALL, LC_MESSAGES
+// and LANG) if a catalog exists for it, the default locale otherwise
+func DetectLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		lang := localeLanguage(value)
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// localeLanguage returns the language part of a locale like de_DE.UTF-8
+func localeLanguage(l string) string {
+	l = strings.ToLower(strings.TrimSpace(l))
+	if i := strings.IndexAny(l, "_-.@"); i >= 0 {
+		l = l[:i]
+	}
+	return l
+}
+
+// T returns the translation of the message in the selected locale or the
+// message itself if there is none
+func T(message string) string {
+	localeMutex.RLock()
+	defer localeMutex.RUnlock()
+	if translated, ok := catalogs[locale][message]; ok {
+		return translated
+	}
+	return message
+}
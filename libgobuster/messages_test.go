@@ -0,0 +1,59 @@
+package libgobuster
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestCatalogVerbs(t *testing.T) {
+	t.Parallel()
+
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for lang, catalog := range catalogs {
+		for message, translated := range catalog {
+			if !reflect.DeepEqual(verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1)) {
+				t.Errorf("%s: translation %q does not match the verbs of %q", lang, translated, message)
+			}
+		}
+	}
+}
+
+func TestLocaleLanguage(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]string{
+		"de_DE.UTF-8": "de",
+		"es-ES":       "es",
+		"EN":          "en",
+		"C":           "c",
+		"fr_FR@euro":  "fr",
+	}
+	for value, want := range tt {
+		if got := localeLanguage(value); got != want {
+			t.Fatalf("expected %s for %s but got %s", want, value, got)
+		}
+	}
+}
+
+// TestT is not parallel as it changes the global locale
+func TestT(t *testing.T) {
+	defer func() {
+		if err := SetLocale(DefaultLocale); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}()
+
+	if err := SetLocale("de_DE.UTF-8"); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if got := T("Finished"); got != "Fertig" {
+		t.Fatalf("expected the german translation, got %q", got)
+	}
+	if got := T("not translated"); got != "not translated" {
+		t.Fatalf("expected the message itself, got %q", got)
+	}
+	if err := SetLocale("xx"); err == nil {
+		t.Fatal("expected an error for an unknown locale")
+	}
+}
@@ -1,25 +1,130 @@
 package libgobuster
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Options holds all options that can be passed to libgobuster
 type Options struct {
-	Threads        int
-	Debug          bool
-	Wordlist       string
+	Threads  int
+	Debug    bool
+	Wordlist string
+	// Wordlists are all wordlists of the run if more than one is given,
+	// Wordlist is the first of them. They are merged without duplicates
+	Wordlists      []string
 	WordlistOffset int
-	PatternFile    string
-	Patterns       []string
-	OutputFilename string
-	NoStatus       bool
-	NoProgress     bool
-	NoError        bool
-	Quiet          bool
-	Verbose        bool
-	Delay          time.Duration
+	// TargetOffset is the index of the target a run over multiple targets
+	// starts with, WordlistOffset applies to this target
+	TargetOffset int
+	PatternFile  string
+	Patterns     []string
+	KnownFile    string
+	KnownWords   Set[string]
+	SuppressFile string
+	Suppressions []ResultFilter
+	// Outputs are the files the results are written to, the format of
+	// each file is chosen by its extension
+	Outputs []string
+	// OutputFormat is the format of stdout and all output files without
+	// a json or csv extension
+	OutputFormat string
+	// OutputFilters restrict the results written to single outputs or stdout
+	OutputFilters []OutputFilter
+	// OutputRedactions hide the urls and hostnames of results in single outputs or stdout
+	OutputRedactions []OutputRedaction
+	// RedactionKey keys the hashes of redacted values, see NewRedactionKey
+	RedactionKey []byte
+	// PrintRedactionKey shows the redaction key at the start of the run
+	PrintRedactionKey bool
+	// OutputEncryption encrypts all output files if not nil
+	OutputEncryption *OutputEncryption
+	// OutputSigning signs a manifest of every output file if not nil
+	OutputSigning *OutputSigning
+	NoStatus      bool
+	NoProgress    bool
+	NoError       bool
+	Quiet         bool
+	Verbose       bool
+	Delay         time.Duration
+	// StopAfterFindings stops the run after this number of findings, 0 disables it
+	StopAfterFindings int
+	// StopOnTags stops the run as soon as a finding has one of these tags
+	StopOnTags Set[string]
+	// MaxTime stops the run after this duration, 0 disables it
+	MaxTime time.Duration
+	// MaxErrors stops the run after this number of errors, 0 disables it
+	MaxErrors int
+	// MaxMemory is the memory limit in bytes, new words are not processed
+	// while it is exceeded. 0 disables it
+	MaxMemory int64
+	// DryRun only prints the configuration and the estimations
+	DryRun bool
+	// EstimateDuration probes the latency of the target with a harmless
+	// request before the run to estimate its duration
+	EstimateDuration bool
+	// AssumeYes skips the confirmation for huge scans
+	AssumeYes bool
+	// Force skips the confirmation for dangerous configurations
+	Force bool
+	// Cluster groups similar results at the end of the run
+	Cluster bool
+	// Tree prints all findings as a tree of paths at the end of the run
+	Tree bool
+	// Notify shows a desktop notification when the run is finished
+	Notify bool
+	// NotifyFilters show a desktop notification for every finding matching one of them
+	NotifyFilters []ResultFilter
+	// ShowTiming prints the duration and time to first byte of every result
+	ShowTiming bool
+	// CollectResults drains all channels of the progress internally and
+	// keeps the results in memory, see Gobuster.CollectedResults
+	CollectResults bool
+	// ExtraWordlists are combined with the main wordlist using the Strategy
+	ExtraWordlists []ExtraWordlist
+	// Strategy is either StrategyClusterbomb or StrategyPitchfork
+	Strategy string
+	// RulesFile contains conditional follow up requests
+	RulesFile string
+	Rules     []Rule
+	// Script is a Starlark script implementing hooks, nil if not set
+	Script *Script
+	// RateLimit is the maximum number of requests per second and host, the
+	// budget is shared with other processes using the same RateFile
+	RateLimit int
+	RateFile  string
+	Throttle  *Throttle
+	// Workspace is a directory every finished run is stored in
+	Workspace string
+	// Report is the html report written at the end of the run
+	Report string
+	// Database is the sqlite database every finished run is stored in
+	Database string
+	// StateFile is checkpointed periodically, so the run can be resumed
+	StateFile string
+	// StatusFile is rewritten periodically with the progress of the run
+	// for external monitoring
+	StatusFile string
+	// ConnStats collects the connection statistics of the http clients of
+	// the plugin for the summary, nil disables them
+	ConnStats *ConnStats
+	// EnrichThreads is the number of workers enriching results, 0 uses
+	// the number of Threads
+	EnrichThreads int
+	// WordPolicy handles words which are invalid for the plugin, like
+	// labels longer than 63 bytes in dns mode. Empty skips them
+	WordPolicy string
 }
 
 // NewOptions returns a new initialized Options object
 func NewOptions() *Options {
 	return &Options{}
 }
+
+// WordlistName returns the wordlist or the merged wordlists for display
+func (opt *Options) WordlistName() string {
+	if len(opt.Wordlists) > 1 {
+		return strings.Join(opt.Wordlists, ", ")
+	}
+	return opt.Wordlist
+}
@@ -7,24 +7,87 @@ import (
 
 // BasicHTTPOptions defines only core http options
 type BasicHTTPOptions struct {
-	UserAgent       string
-	Proxy           string
+	UserAgent string
+	Proxy     string
+	// Proxies are rotated for every request instead of using Proxy
+	Proxies []string
+	// ProxyRotation is either ProxyRoundRobin or ProxyRandom
+	ProxyRotation   string
 	NoTLSValidation bool
 	Timeout         time.Duration
-	RetryOnTimeout  bool
-	RetryAttempts   int
-	TLSCertificate  *tls.Certificate
+	// RetryOnTimeout retries requests failing with a transient error like
+	// a timeout, a connection reset or a 502 or 503 response
+	RetryOnTimeout bool
+	RetryAttempts  int
+	// RetryWait is the wait before the first retry, it doubles with every
+	// further attempt
+	RetryWait      time.Duration
+	TLSCertificate *tls.Certificate
+	// TLSPolicy allows single categories of certificate problems
+	TLSPolicy TLSPolicy
+	// Limits protect against hostile responses
+	Limits ResponseLimits
+}
+
+// ResponseLimits protect the scanner against responses attacking it like
+// decompression bombs, endless bodies and huge headers. Zero values use the
+// defaults. Responses reading longer than the timeout are aborted anyway
+type ResponseLimits struct {
+	// MaxBodySize is the maximum number of bytes read of a body after
+	// decompression, longer bodies are truncated
+	MaxBodySize int64
+	// MaxHeaderSize is the maximum size of the response headers in bytes
+	MaxHeaderSize int64
+	// MaxHeaders is the maximum number of header fields of a response
+	MaxHeaders int
+}
+
+const (
+	// DefaultMaxBodySize is the default of ResponseLimits.MaxBodySize
+	DefaultMaxBodySize = 10 * 1024 * 1024
+	// DefaultMaxHeaderSize is the default of ResponseLimits.MaxHeaderSize
+	DefaultMaxHeaderSize = 1024 * 1024
+	// DefaultMaxHeaders is the default of ResponseLimits.MaxHeaders
+	DefaultMaxHeaders = 500
+)
+
+// withDefaults returns the limits with the defaults for all unset limits
+func (l ResponseLimits) withDefaults() ResponseLimits {
+	if l.MaxBodySize <= 0 {
+		l.MaxBodySize = DefaultMaxBodySize
+	}
+	if l.MaxHeaderSize <= 0 {
+		l.MaxHeaderSize = DefaultMaxHeaderSize
+	}
+	if l.MaxHeaders <= 0 {
+		l.MaxHeaders = DefaultMaxHeaders
+	}
+	return l
 }
 
 // HTTPOptions is the struct to pass in all http options to Gobuster
 type HTTPOptions struct {
 	BasicHTTPOptions
-	Password              string
-	URL                   string
+	Password string
+	URL      string
+	// Targets holds all urls of a run over multiple targets, URL is the
+	// first of them. Every target gets its own plugin
+	Targets               []string
 	Username              string
 	Cookies               string
 	Headers               []HTTPHeader
 	NoCanonicalizeHeaders bool
 	FollowRedirect        bool
 	Method                string
+	// CacheBypass adds a random query parameter and cache defeating headers
+	CacheBypass bool
+	// Script implements hooks to modify requests and classify responses
+	Script *Script
+	// Throttle limits the requests per second, nil if not set
+	Throttle *Throttle
+	// ConnStats collects the connection statistics, nil if not set
+	ConnStats *ConnStats
+	// Overrides holds the cookies, headers and credentials of single
+	// targets, nil if not set
+	Overrides *TargetOverrides
 }
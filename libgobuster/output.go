@@ -0,0 +1,317 @@
+package libgobuster
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output formats. The format of output files is chosen by their extension
+const (
+	OutputPlain = "plain"
+	OutputJSON  = "json"
+	OutputCSV   = "csv"
+)
+
+// OutputStdout is the target of output filters applying to the terminal
+const OutputStdout = "stdout"
+
+// OutputWriter writes the results of a run to a single sink
+type OutputWriter interface {
+	// WriteResult writes the result, line is the result as printed to the terminal
+	WriteResult(record ResultRecord, line string) error
+	Close() error
+}
+
+// OutputFormat returns the format of the output file based on its extension.
+// Json files contain one object per line. Files with other extensions get
+// the fallback format
+func OutputFormat(filename, fallback string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json", ".jsonl":
+		return OutputJSON
+	case ".csv":
+		return OutputCSV
+	default:
+		return fallback
+	}
+}
+
+// JSONResult is the representation of a result in the json output
+type JSONResult struct {
+	URL        string    `json:"url,omitempty"`
+	Path       string    `json:"path"`
+	Found      bool      `json:"found"`
+	StatusCode int       `json:"status,omitempty"`
+	Size       int64     `json:"size"`
+	Redirect   string    `json:"redirect,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// NewJSONResult converts the record to its json representation
+func NewJSONResult(r ResultRecord) JSONResult {
+	return JSONResult{
+		URL:        r.URL,
+		Path:       r.Path,
+		Found:      r.Found,
+		StatusCode: r.StatusCode,
+		Size:       r.Size,
+		Redirect:   r.Redirect,
+		Tags:       r.Tags,
+		Title:      r.Title,
+		Timestamp:  r.Timestamp,
+	}
+}
+
+// CSVHeader returns the header row of the csv output
+func CSVHeader() []string {
+	return []string{"url", "path", "found", "status", "size", "redirect", "tags"}
+}
+
+// NewCSVRecord converts the record to a row of the csv output
+func NewCSVRecord(r ResultRecord) []string {
+	status := ""
+	if r.StatusCode > 0 {
+		status = strconv.Itoa(r.StatusCode)
+	}
+	return []string{
+		r.URL,
+		r.Path,
+		strconv.FormatBool(r.Found),
+		status,
+		strconv.FormatInt(r.Size, 10),
+		r.Redirect,
+		strings.Join(r.Tags, ";"),
+	}
+}
+
+// NewOutputWriter creates the file and returns a writer for the format. If
+// encryption is not nil the file is encrypted
+func NewOutputWriter(filename, format string, encryption *OutputEncryption) (OutputWriter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error on creating output file: %w", err)
+	}
+
+	var f io.WriteCloser = file
+	if encryption != nil {
+		f, err = encryption.Encrypt(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error on encrypting output file: %w", err)
+		}
+	}
+
+	switch format {
+	case OutputJSON:
+		return &jsonOutput{f: f, enc: json.NewEncoder(f)}, nil
+	case OutputCSV:
+		w := csv.NewWriter(f)
+		if err := w.Write(CSVHeader()); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("error on writing output file: %w", err)
+		}
+		return &csvOutput{f: f, w: w}, nil
+	default:
+		return &plainOutput{f: f}, nil
+	}
+}
+
+type plainOutput struct {
+	f io.WriteCloser
+}
+
+func (o *plainOutput) WriteResult(_ ResultRecord, line string) error {
+	_, err := fmt.Fprintf(o.f, "%s\n", line)
+	return err
+}
+
+func (o *plainOutput) Close() error {
+	return o.f.Close()
+}
+
+type jsonOutput struct {
+	f   io.WriteCloser
+	enc *json.Encoder
+}
+
+func (o *jsonOutput) WriteResult(record ResultRecord, _ string) error {
+	return o.enc.Encode(NewJSONResult(record))
+}
+
+func (o *jsonOutput) Close() error {
+	return o.f.Close()
+}
+
+type csvOutput struct {
+	f io.WriteCloser
+	w *csv.Writer
+}
+
+func (o *csvOutput) WriteResult(record ResultRecord, _ string) error {
+	if err := o.w.Write(NewCSVRecord(record)); err != nil {
+		return err
+	}
+	// flush every line so the file is usable while the run is going on
+	o.w.Flush()
+	return o.w.Error()
+}
+
+func (o *csvOutput) Close() error {
+	o.w.Flush()
+	if err := o.w.Error(); err != nil {
+		o.f.Close()
+		return err
+	}
+	return o.f.Close()
+}
+
+// OutputFilter restricts the results written to a single output file or the
+// terminal. Results are written if they match one of the filters of the target
+type OutputFilter struct {
+	Target string
+	Filter ResultFilter
+}
+
+// ParseOutputFilter parses a filter like "hits.json tag=secret-file". The
+// first field is the target, the rest is a rule in the --suppress-file syntax
+func ParseOutputFilter(s string) (OutputFilter, error) {
+	target, rule, _ := strings.Cut(strings.TrimSpace(s), " ")
+	if target == "" || strings.TrimSpace(rule) == "" {
+		return OutputFilter{}, fmt.Errorf("invalid output filter %q, expected \"<output> <rule>\"", s)
+	}
+	f, err := ParseResultFilter(rule)
+	if err != nil {
+		return OutputFilter{}, err
+	}
+	return OutputFilter{Target: target, Filter: f}, nil
+}
+
+// AppliesTo checks if the filter belongs to the output file or stdout
+func (f OutputFilter) AppliesTo(target string) bool {
+	return sameOutput(f.Target, target)
+}
+
+// IsOutput checks if the target is stdout or one of the output files
+func IsOutput(target string, outputs []string) bool {
+	if target == OutputStdout {
+		return true
+	}
+	for _, o := range outputs {
+		if sameOutput(target, o) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameOutput(a, b string) bool {
+	if a == OutputStdout || b == OutputStdout {
+		return a == b
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// FiltersFor returns the filters of the target
+func FiltersFor(filters []OutputFilter, target string) []ResultFilter {
+	var ret []ResultFilter
+	for _, f := range filters {
+		if f.AppliesTo(target) {
+			ret = append(ret, f.Filter)
+		}
+	}
+	return ret
+}
+
+// filteredOutput only passes results matching one of the filters
+type filteredOutput struct {
+	OutputWriter
+	filters []ResultFilter
+}
+
+func (o filteredOutput) WriteResult(record ResultRecord, line string) error {
+	if !MatchesAnyFilter(o.filters, record) {
+		return nil
+	}
+	return o.OutputWriter.WriteResult(record, line)
+}
+
+// MultiOutput fans the results out to all configured writers
+type MultiOutput []OutputWriter
+
+// OpenOutputs creates a writer for every output file of the options, applying
+// the format, filters, encryption and signing. Files which were already created are
+// closed again if one of them fails
+func OpenOutputs(opts *Options) (MultiOutput, error) {
+	seen := NewSet[string]()
+	for _, r := range opts.OutputRedactions {
+		if r.Mode == RedactHash && len(opts.RedactionKey) == 0 {
+			return nil, fmt.Errorf("the hash redaction of %s needs a redaction key", r.Target)
+		}
+	}
+
+	var outputs MultiOutput
+	for _, filename := range opts.Outputs {
+		if seen.Contains(filepath.Clean(filename)) {
+			_ = outputs.Close()
+			return nil, fmt.Errorf("output file %s given multiple times", filename)
+		}
+		seen.Add(filepath.Clean(filename))
+
+		format := OutputFormat(filename, opts.OutputFormat)
+		w, err := NewOutputWriter(filename, format, opts.OutputEncryption)
+		if err != nil {
+			_ = outputs.Close()
+			return nil, err
+		}
+		// the manifest covers the results as written to the file
+		if opts.OutputSigning != nil {
+			w = &signedOutput{
+				OutputWriter: w,
+				filename:     filename,
+				format:       format,
+				encrypted:    opts.OutputEncryption != nil,
+				signing:      opts.OutputSigning,
+				started:      time.Now(),
+			}
+		}
+		if mode := RedactionFor(opts.OutputRedactions, filename); mode != "" {
+			w = redactedOutput{OutputWriter: w, mode: mode, key: opts.RedactionKey}
+		}
+		// filters match the original result
+		if f := FiltersFor(opts.OutputFilters, filename); len(f) > 0 {
+			w = filteredOutput{OutputWriter: w, filters: f}
+		}
+		outputs = append(outputs, w)
+	}
+	return outputs, nil
+}
+
+// WriteResult writes the result to all writers
+func (m MultiOutput) WriteResult(record ResultRecord, line string) error {
+	for _, w := range m {
+		if err := w.WriteResult(record, line); err != nil {
+			return fmt.Errorf("error on writing output file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes all writers and returns the first error
+func (m MultiOutput) Close() error {
+	var ret error
+	for _, w := range m {
+		if err := w.Close(); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	return ret
+}
@@ -0,0 +1,144 @@
+package libgobuster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		filename string
+		format   string
+	}{
+		{"hits.txt", OutputPlain},
+		{"hits", OutputPlain},
+		{"hits.out", OutputJSON},
+		{"hits.json", OutputJSON},
+		{"hits.JSONL", OutputJSON},
+		{"dir/hits.csv", OutputCSV},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.filename, func(t *testing.T) {
+			t.Parallel()
+			fallback := OutputPlain
+			if x.filename == "hits.out" {
+				fallback = OutputJSON
+			}
+			if got := OutputFormat(x.filename, fallback); got != x.format {
+				t.Fatalf("expected %s but got %s", x.format, got)
+			}
+		})
+	}
+}
+
+func TestMultiOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "hits.txt"), filepath.Join(dir, "hits.json"), filepath.Join(dir, "hits.csv")}
+	outputs, err := OpenOutputs(&Options{Outputs: files})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	record := ResultRecord{URL: "http://localhost/admin", Path: "/admin", Found: true, StatusCode: 301, Size: 10, Tags: []string{"a", "b"}, Redirect: "/admin/",
+		Timestamp: time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)}
+	if err := outputs.WriteResult(record, "/admin (Status: 301)"); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := outputs.Close(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	want := []string{
+		"/admin (Status: 301)\n",
+		`{"url":"http://localhost/admin","path":"/admin","found":true,"status":301,"size":10,"redirect":"/admin/","tags":["a","b"],"timestamp":"2023-10-01T12:00:00Z"}` + "\n",
+		"url,path,found,status,size,redirect,tags\nhttp://localhost/admin,/admin,true,301,10,/admin/,a;b\n",
+	}
+	for i, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if string(content) != want[i] {
+			t.Fatalf("unexpected content of %s: %q", f, content)
+		}
+	}
+
+	// the json output can be used to resume
+	found, err := ParseOutputFile(files[1])
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !found.Contains("admin") {
+		t.Fatalf("expected admin to be parsed from the json output, got %v", found.Stringify())
+	}
+}
+
+func TestOpenOutputsDuplicate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if _, err := OpenOutputs(&Options{Outputs: []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, ".", "a.txt")}}); err == nil {
+		t.Fatal("expected an error for duplicate output files")
+	}
+}
+
+func TestFilteredOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "high.txt")
+	var filters []OutputFilter
+	for _, s := range []string{file + " tag=secret-file", "stdout status=200-299", file + " status=500"} {
+		f, err := ParseOutputFilter(s)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		filters = append(filters, f)
+	}
+	if got := len(FiltersFor(filters, OutputStdout)); got != 1 {
+		t.Fatalf("expected 1 stdout filter, got %d", got)
+	}
+
+	outputs, err := OpenOutputs(&Options{Outputs: []string{file}, OutputFilters: filters})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	records := []ResultRecord{
+		{Path: "/.env", StatusCode: 200, Tags: []string{"secret-file"}},
+		{Path: "/index.html", StatusCode: 200},
+		{Path: "/error", StatusCode: 500},
+	}
+	for _, r := range records {
+		if err := outputs.WriteResult(r, r.Path); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	if err := outputs.Close(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if string(content) != "/.env\n/error\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+}
+
+func TestParseOutputFilterInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "hits.txt", "hits.txt foo=bar"} {
+		if _, err := ParseOutputFilter(s); err == nil {
+			t.Fatalf("expected an error for %q", s)
+		}
+	}
+}
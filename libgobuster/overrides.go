@@ -0,0 +1,237 @@
+package libgobuster
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TargetOverride holds the cookies, headers and credentials of a single
+// target of a multi target scan. Empty values keep the options of the scan
+type TargetOverride struct {
+	// Target is either an url or a host with an optional port, hosts
+	// apply to all targets on the host
+	Target   string   `yaml:"target"`
+	Cookies  string   `yaml:"cookies"`
+	Headers  []string `yaml:"headers"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+
+	headers []HTTPHeader
+}
+
+// TargetOverrides are the per target options of an overrides file
+type TargetOverrides struct {
+	overrides []TargetOverride
+}
+
+type overridesFile struct {
+	Targets []TargetOverride `yaml:"targets"`
+}
+
+// ParseTargetOverridesFile parses a YAML (.yaml, .yml) or CSV (.csv) file
+// with the options of single targets. YAML files hold a list of targets,
+// CSV files a header row naming the columns target, cookies, username,
+// password and any number of header columns with one header each
+func ParseTargetOverridesFile(file string) (*TargetOverrides, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides []TargetOverride
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		var f overridesFile
+		if err := yaml.Unmarshal(content, &f); err != nil {
+			return nil, fmt.Errorf("invalid target overrides file: %w", err)
+		}
+		overrides = f.Targets
+	case ".csv":
+		overrides, err = parseOverridesCSV(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target overrides file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("target overrides file %s must be a .yaml, .yml or .csv file", file)
+	}
+
+	for i := range overrides {
+		o := &overrides[i]
+		o.Target = strings.TrimSpace(o.Target)
+		if o.Target == "" {
+			return nil, fmt.Errorf("entry %d of the target overrides file has no target", i+1)
+		}
+		if o.Username != "" && o.Password == "" {
+			return nil, fmt.Errorf("target %s has a username but no password", o.Target)
+		}
+		for _, h := range o.Headers {
+			name, value, found := strings.Cut(h, ":")
+			if !found || strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("invalid header %q of target %s", h, o.Target)
+			}
+			o.headers = append(o.headers, HTTPHeader{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+		}
+	}
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("target overrides file %s contains no targets", file)
+	}
+	return &TargetOverrides{overrides: overrides}, nil
+}
+
+// parseOverridesCSV parses the rows of a CSV overrides file
+func parseOverridesCSV(content []byte) ([]TargetOverride, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	columns, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the header row: %w", err)
+	}
+	hasTarget := false
+	for i, c := range columns {
+		columns[i] = strings.ToLower(strings.TrimSpace(c))
+		switch columns[i] {
+		case "target":
+			hasTarget = true
+		case "cookies", "username", "password", "header":
+		default:
+			return nil, fmt.Errorf("unknown column %q", c)
+		}
+	}
+	if !hasTarget {
+		return nil, fmt.Errorf("the header row has no target column")
+	}
+
+	var overrides []TargetOverride
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var o TargetOverride
+		for i, value := range record {
+			switch columns[i] {
+			case "target":
+				o.Target = value
+			case "cookies":
+				o.Cookies = value
+			case "username":
+				o.Username = value
+			case "password":
+				o.Password = value
+			case "header":
+				if strings.TrimSpace(value) != "" {
+					o.Headers = append(o.Headers, value)
+				}
+			}
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+// matches checks if the override applies to the url. Urls match if they
+// are equal apart from a trailing slash, hosts if the host of the url is
+// equal
+func (o TargetOverride) matches(target string) bool {
+	if strings.Contains(o.Target, "://") {
+		return strings.TrimSuffix(o.Target, "/") == strings.TrimSuffix(target, "/")
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(o.Target, u.Host) || strings.EqualFold(o.Target, u.Hostname())
+}
+
+// For returns the override of the target. An override of the url is
+// preferred over an override of the host
+func (t *TargetOverrides) For(target string) (TargetOverride, bool) {
+	if t == nil {
+		return TargetOverride{}, false
+	}
+	var host *TargetOverride
+	for i, o := range t.overrides {
+		if !o.matches(target) {
+			continue
+		}
+		if strings.Contains(o.Target, "://") {
+			return o, true
+		}
+		if host == nil {
+			host = &t.overrides[i]
+		}
+	}
+	if host != nil {
+		return *host, true
+	}
+	return TargetOverride{}, false
+}
+
+// Unmatched returns the entries matching none of the targets, they are
+// most likely typos
+func (t *TargetOverrides) Unmatched(targets []string) []string {
+	if t == nil {
+		return nil
+	}
+	var unmatched []string
+	for _, o := range t.overrides {
+		found := false
+		for _, target := range targets {
+			if o.matches(target) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unmatched = append(unmatched, o.Target)
+		}
+	}
+	return unmatched
+}
+
+// ForTarget returns a copy of the options for the target url with the
+// override of the target applied. Headers of the override replace the
+// headers with the same name
+func (opt HTTPOptions) ForTarget(target string) HTTPOptions {
+	opt.URL = target
+	o, ok := opt.Overrides.For(target)
+	if !ok {
+		return opt
+	}
+
+	if o.Cookies != "" {
+		opt.Cookies = o.Cookies
+	}
+	if o.Username != "" {
+		opt.Username = o.Username
+		opt.Password = o.Password
+	}
+	if len(o.headers) > 0 {
+		replaced := NewSet[string]()
+		for _, h := range o.headers {
+			replaced.Add(strings.ToLower(h.Name))
+		}
+		headers := make([]HTTPHeader, 0, len(opt.Headers)+len(o.headers))
+		for _, h := range opt.Headers {
+			if !replaced.Contains(strings.ToLower(h.Name)) {
+				headers = append(headers, h)
+			}
+		}
+		opt.Headers = append(headers, o.headers...)
+	}
+	return opt
+}
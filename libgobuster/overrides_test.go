@@ -0,0 +1,118 @@
+package libgobuster
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTargetOverridesFile(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		file     string
+		content  string
+		expected int
+		wantErr  bool
+	}{
+		{"YAML", "overrides.yaml", "targets:\n  - target: https://a.example.com\n    cookies: session=a\n    headers:\n      - 'Authorization: Bearer a'\n  - target: b.example.com\n    username: admin\n    password: secret\n", 2, false},
+		{"CSV", "overrides.csv", "target,cookies,header,header\n# tenant b\nhttps://a.example.com,session=a,Authorization: Bearer a,X-Tenant: a\nb.example.com,session=b,,\n", 2, false},
+		{"CSV without target column", "overrides.csv", "cookies\nsession=a\n", 0, true},
+		{"CSV unknown column", "overrides.csv", "target,token\na.example.com,x\n", 0, true},
+		{"Missing target", "overrides.yml", "targets:\n  - cookies: session=a\n", 0, true},
+		{"Username without password", "overrides.yaml", "targets:\n  - target: a.example.com\n    username: admin\n", 0, true},
+		{"Invalid header", "overrides.yaml", "targets:\n  - target: a.example.com\n    headers: ['no colon']\n", 0, true},
+		{"Empty", "overrides.yaml", "targets: []\n", 0, true},
+		{"Unknown format", "overrides.txt", "a.example.com\n", 0, true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), x.file)
+			if err := os.WriteFile(path, []byte(x.content), 0o600); err != nil {
+				t.Fatalf("could not write file: %v", err)
+			}
+			overrides, err := ParseTargetOverridesFile(path)
+			if x.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if len(overrides.overrides) != x.expected {
+				t.Fatalf("expected %d overrides, got %d", x.expected, len(overrides.overrides))
+			}
+		})
+	}
+}
+
+func TestForTarget(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "overrides.csv")
+	content := "target,cookies,username,password,header\n" +
+		"https://a.example.com/,session=a,,,Authorization: Bearer a\n" +
+		"a.example.com,session=host,,,\n" +
+		"b.example.com:8443,,admin,secret,\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("could not write file: %v", err)
+	}
+	overrides, err := ParseTargetOverridesFile(path)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	base := HTTPOptions{
+		Cookies:   "session=base",
+		Headers:   []HTTPHeader{{Name: "authorization", Value: "Bearer base"}, {Name: "X-Scan", Value: "1"}},
+		Overrides: overrides,
+	}
+
+	tt := []struct {
+		target   string
+		cookies  string
+		username string
+		headers  string
+	}{
+		// the url entry is preferred over the host entry
+		{"https://a.example.com", "session=a", "", "[{X-Scan 1} {Authorization Bearer a}]"},
+		{"http://a.example.com/admin", "session=host", "", "[{authorization Bearer base} {X-Scan 1}]"},
+		{"https://b.example.com:8443", "session=base", "admin", "[{authorization Bearer base} {X-Scan 1}]"},
+		{"https://b.example.com", "session=base", "", "[{authorization Bearer base} {X-Scan 1}]"},
+		{"https://c.example.com", "session=base", "", "[{authorization Bearer base} {X-Scan 1}]"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.target, func(t *testing.T) {
+			t.Parallel()
+			opts := base.ForTarget(x.target)
+			if opts.URL != x.target {
+				t.Fatalf("expected url %s, got %s", x.target, opts.URL)
+			}
+			if opts.Cookies != x.cookies || opts.Username != x.username {
+				t.Fatalf("expected cookies %q and username %q, got %q and %q", x.cookies, x.username, opts.Cookies, opts.Username)
+			}
+			if headers := fmt.Sprint(opts.Headers); headers != x.headers {
+				t.Fatalf("expected headers %s, got %s", x.headers, headers)
+			}
+		})
+	}
+
+	// the options of the scan are not changed
+	if base.Cookies != "session=base" || len(base.Headers) != 2 {
+		t.Fatalf("the base options were changed: %+v", base)
+	}
+
+	unmatched := overrides.Unmatched([]string{"https://a.example.com", "https://b.example.com"})
+	if fmt.Sprint(unmatched) != "[b.example.com:8443]" {
+		t.Fatalf("expected b.example.com:8443 to match no target, got %v", unmatched)
+	}
+}
@@ -0,0 +1,61 @@
+package libgobuster
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// placeholderRegex matches the placeholders replaced in every request
+var placeholderRegex = regexp.MustCompile(`\{\{(rand|randint|uuid|timestamp|timestamp_ms)\}\}`)
+
+// Placeholders are replaced with a fresh value in the url, the headers, the
+// cookies and the body of every request. All occurrences of a placeholder
+// within a single request get the same value so nonces can be repeated
+type Placeholders struct {
+	now    func() time.Time
+	values map[string]string
+}
+
+// NewPlaceholders returns the placeholder values for a single request
+func NewPlaceholders() *Placeholders {
+	return &Placeholders{
+		now:    time.Now,
+		values: make(map[string]string),
+	}
+}
+
+// Replace replaces all placeholders in s
+func (p *Placeholders) Replace(s string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return placeholderRegex.ReplaceAllStringFunc(s, p.value)
+}
+
+// value returns the value of the placeholder, it is generated on first use
+func (p *Placeholders) value(placeholder string) string {
+	if v, ok := p.values[placeholder]; ok {
+		return v
+	}
+
+	var v string
+	switch strings.Trim(placeholder, "{}") {
+	case "rand":
+		b := make([]byte, 8)
+		RandomBytes(b)
+		v = hex.EncodeToString(b)
+	case "randint":
+		v = fmt.Sprintf("%d", RandomIntn(1000000000))
+	case "uuid":
+		v = RandomUUID()
+	case "timestamp":
+		v = fmt.Sprintf("%d", p.now().Unix())
+	case "timestamp_ms":
+		v = fmt.Sprintf("%d", p.now().UnixNano()/int64(time.Millisecond))
+	}
+	p.values[placeholder] = v
+	return v
+}
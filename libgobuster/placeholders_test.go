@@ -0,0 +1,81 @@
+package libgobuster
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	p := NewPlaceholders()
+	p.now = func() time.Time { return time.Unix(1700000000, 5000000) }
+
+	tt := []struct {
+		testName string
+		input    string
+		expected string
+	}{
+		{"Timestamp", "t={{timestamp}}", "t=1700000000"},
+		{"TimestampMs", "t={{timestamp_ms}}", "t=1700000000005"},
+		{"Rand", "{{rand}}", "^[0-9a-f]{16}$"},
+		{"RandInt", "{{randint}}", "^[0-9]+$"},
+		{"UUID", "{{uuid}}", "^[0-9a-f-]{36}$"},
+		{"Unknown", "{{unknown}}", "{{unknown}}"},
+	}
+
+	for _, x := range tt {
+		got := p.Replace(x.input)
+		if strings.HasPrefix(x.expected, "^") {
+			if !regexp.MustCompile(x.expected).MatchString(got) {
+				t.Fatalf("%s: %q does not match %q", x.testName, got, x.expected)
+			}
+			continue
+		}
+		if got != x.expected {
+			t.Fatalf("%s: expected %q, got %q", x.testName, x.expected, got)
+		}
+	}
+
+	// the same value within a request, a new one in the next
+	same := p.Replace("{{uuid}}/{{uuid}}")
+	if parts := strings.Split(same, "/"); parts[0] != parts[1] {
+		t.Fatalf("expected the same value within a request, got %q", same)
+	}
+	if NewPlaceholders().Replace("{{uuid}}") == NewPlaceholders().Replace("{{uuid}}") {
+		t.Fatal("expected different values in different requests")
+	}
+}
+
+func TestRequestPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, r.URL.Query().Get("cb"), r.Header.Get("X-Nonce"), string(body))
+	}))
+	defer ts.Close()
+
+	c, err := NewHTTPClient(&HTTPOptions{Headers: []HTTPHeader{{Name: "X-Nonce", Value: "{{uuid}}"}}})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	_, _, _, _, err = c.Request(context.Background(), ts.URL+"/?cb={{rand}}", RequestOptions{Method: http.MethodPost, Body: strings.NewReader("nonce={{uuid}}")})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	if len(got) != 3 || strings.Contains(strings.Join(got, ""), "{{") {
+		t.Fatalf("expected all placeholders to be replaced, got %v", got)
+	}
+	if got[2] != "nonce="+got[1] {
+		t.Fatalf("expected the same uuid in header and body, got %v", got)
+	}
+}
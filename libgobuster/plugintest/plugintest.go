@@ -0,0 +1,282 @@
+// Package plugintest provides a conformance suite for gobuster plugins. Every
+// plugin, built-in or third party, should pass it against a test target:
+//
+//	func TestConformance(t *testing.T) {
+//		ts := httptest.NewServer(...)
+//		t.Cleanup(ts.Close)
+//		plugintest.Run(t, func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin {
+//			p, err := NewGobusterX(globalopts, &OptionsX{URL: ts.URL})
+//			if err != nil {
+//				t.Fatalf("Got error: %v", err)
+//			}
+//			return p
+//		})
+//	}
+package plugintest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OJ/gobuster/v3/libgobuster"
+)
+
+// Timeout is the time a plugin has to finish a single call and a whole run
+// nolint:gochecknoglobals
+var Timeout = 30 * time.Second
+
+// canceledTimeout is the time a plugin has to return once the context is
+// canceled
+const canceledTimeout = 5 * time.Second
+
+// Words are passed to the plugin by the suite. The test target should find
+// at least one of them to cover the formatting of found results
+// nolint:gochecknoglobals
+var Words = []string{"admin", "index.html", "test", "backup", "a"}
+
+// Factory creates a plugin configured against the test target. The global
+// options are prepared by the suite, the factory only adds the plugin
+// options. It is called once per check, so plugins are never reused
+type Factory func(t *testing.T, globalopts *libgobuster.Options) libgobuster.GobusterPlugin
+
+// Run runs all checks of the conformance suite as subtests of t
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("Name", func(t *testing.T) {
+		p := newPlugin(t, factory, false)
+		if strings.TrimSpace(p.Name()) == "" {
+			t.Fatal("Name must not be empty")
+		}
+	})
+
+	t.Run("GetConfigString", func(t *testing.T) {
+		for _, verbose := range []bool{false, true} {
+			p := newPlugin(t, factory, verbose)
+			c, err := p.GetConfigString()
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if strings.TrimSpace(c) == "" {
+				t.Fatal("GetConfigString must describe the configuration")
+			}
+		}
+	})
+
+	t.Run("AdditionalWords", func(t *testing.T) {
+		p := newPlugin(t, factory, false)
+		for _, word := range Words {
+			for _, w := range p.AdditionalWords(word) {
+				if w == "" {
+					t.Fatalf("AdditionalWords returned an empty word for %q", word)
+				}
+			}
+		}
+	})
+
+	t.Run("ProcessWord", func(t *testing.T) {
+		for _, verbose := range []bool{false, true} {
+			p := newPlugin(t, factory, verbose)
+			checkResults(t, process(t, p, Words))
+		}
+	})
+
+	t.Run("ProcessWordCanceled", func(t *testing.T) {
+		p := newPlugin(t, factory, false)
+		progress := libgobuster.NewProgress()
+		wait := drain(t, p, progress)
+		if err := call(t, canceledTimeout, "PreRun", func() error { return p.PreRun(context.Background(), progress) }); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, word := range Words {
+			err := call(t, canceledTimeout, "ProcessWord", func() error { return p.ProcessWord(ctx, word, progress) })
+			if err != nil && !errors.Is(err, context.Canceled) {
+				t.Fatalf("ProcessWord must ignore or return the canceled context, got %v", err)
+			}
+		}
+		closeProgress(progress)
+		wait()
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		globalopts := newOptions(t, false)
+		g, err := libgobuster.NewGobuster(globalopts, factory(t, globalopts), libgobuster.NewLogger(false))
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if err := call(t, Timeout, "Run", func() error { return g.Run(context.Background()) }); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		checkResults(t, g.CollectedResults())
+	})
+
+	t.Run("RunCanceled", func(t *testing.T) {
+		globalopts := newOptions(t, false)
+		g, err := libgobuster.NewGobuster(globalopts, factory(t, globalopts), libgobuster.NewLogger(false))
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = call(t, canceledTimeout, "Run", func() error { return g.Run(ctx) })
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatalf("Run must ignore or return the canceled context, got %v", err)
+		}
+	})
+}
+
+// newOptions returns the global options of the suite with a wordlist
+// containing the Words
+func newOptions(t *testing.T, verbose bool) *libgobuster.Options {
+	t.Helper()
+
+	wordlist := filepath.Join(t.TempDir(), "words")
+	if err := os.WriteFile(wordlist, []byte(strings.Join(Words, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	globalopts := libgobuster.NewOptions()
+	globalopts.Threads = 2
+	globalopts.Wordlist = wordlist
+	globalopts.Quiet = true
+	globalopts.NoProgress = true
+	globalopts.Verbose = verbose
+	globalopts.CollectResults = true
+	return globalopts
+}
+
+func newPlugin(t *testing.T, factory Factory, verbose bool) libgobuster.GobusterPlugin {
+	t.Helper()
+	p := factory(t, newOptions(t, verbose))
+	if p == nil {
+		t.Fatal("the factory returned no plugin")
+	}
+	return p
+}
+
+// call runs f and fails the test if it does not return within the timeout
+func call(t *testing.T, timeout time.Duration, name string, f func() error) error {
+	t.Helper()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		t.Fatalf("%s did not return within %s", name, timeout)
+		return nil
+	}
+}
+
+// process runs PreRun and ProcessWord for all words like the workers do and
+// returns the results. Errors of ProcessWord are reported by the workers and
+// the run goes on, so they are not checked
+func process(t *testing.T, p libgobuster.GobusterPlugin, words []string) []libgobuster.Result {
+	t.Helper()
+
+	progress := libgobuster.NewProgress()
+	wait := drain(t, p, progress)
+	if err := call(t, Timeout, "PreRun", func() error { return p.PreRun(context.Background(), progress) }); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	for _, word := range words {
+		_ = call(t, Timeout, "ProcessWord", func() error { return p.ProcessWord(context.Background(), word, progress) })
+	}
+	closeProgress(progress)
+	return wait()
+}
+
+func closeProgress(progress *libgobuster.Progress) {
+	close(progress.EnrichChan)
+	close(progress.ResultChan)
+	close(progress.ErrorChan)
+	close(progress.MessageChan)
+}
+
+// drain receives everything the plugin sends to the progress until it is
+// closed and returns a function waiting for the results. Results sent for
+// enrichment are passed to Enrich
+func drain(t *testing.T, p libgobuster.GobusterPlugin, progress *libgobuster.Progress) func() []libgobuster.Result {
+	t.Helper()
+
+	var mutex sync.Mutex
+	var results []libgobuster.Result
+	add := func(r libgobuster.Result) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		results = append(results, r)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		for r := range progress.ResultChan {
+			add(r)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for r := range progress.EnrichChan {
+			if e, ok := p.(libgobuster.EnrichPlugin); ok {
+				// the result is kept even if the enrichment failed
+				r, _ = e.Enrich(context.Background(), r)
+			}
+			add(r)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range progress.ErrorChan {
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range progress.MessageChan {
+		}
+	}()
+
+	return func() []libgobuster.Result {
+		wg.Wait()
+		return results
+	}
+}
+
+// checkResults checks that every result can be printed and converted to a
+// record, and that at least one result was found. Empty strings are allowed,
+// they are not printed
+func checkResults(t *testing.T, results []libgobuster.Result) {
+	t.Helper()
+
+	found := false
+	for _, r := range results {
+		if r == nil {
+			t.Fatal("got a nil result")
+		}
+		if _, err := r.ResultToString(); err != nil {
+			t.Fatalf("ResultToString failed: %v", err)
+		}
+		record := libgobuster.NewResultRecord(r)
+		if record.Path == "" && record.URL == "" {
+			t.Fatalf("the record has neither a path nor an url for %#v", r)
+		}
+		if record.Found {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("the test target should find at least one of the words")
+	}
+}
@@ -1,6 +1,10 @@
 package libgobuster
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type MessageLevel int
 
@@ -15,14 +19,75 @@ type Message struct {
 	Message string
 }
 
+// TargetState is the state of a target of a run over multiple targets
+type TargetState string
+
+// States of the targets of a run over multiple targets
+const (
+	TargetPending  TargetState = "pending"
+	TargetScanning TargetState = "scanning"
+	TargetDone     TargetState = "done"
+	TargetSkipped  TargetState = "skipped"
+)
+
+// TargetProgress is the progress of a single target of a run over multiple
+// targets
+type TargetProgress struct {
+	Name             string      `json:"name"`
+	State            TargetState `json:"state"`
+	RequestsIssued   int         `json:"requests_done"`
+	RequestsExpected int         `json:"requests_total"`
+	Started          time.Time   `json:"started"`
+	Finished         time.Time   `json:"finished"`
+}
+
+// Elapsed returns the time the target was scanned
+func (t TargetProgress) Elapsed() time.Duration {
+	switch {
+	case t.Started.IsZero():
+		return 0
+	case t.Finished.IsZero():
+		return time.Since(t.Started)
+	default:
+		return t.Finished.Sub(t.Started)
+	}
+}
+
+// Rate returns the requests per second of the target
+func (t TargetProgress) Rate() float64 {
+	elapsed := t.Elapsed().Seconds()
+	if elapsed <= 0 || t.RequestsIssued <= 0 {
+		return 0
+	}
+	return float64(t.RequestsIssued) / elapsed
+}
+
 type Progress struct {
 	requestsExpectedMutex *sync.RWMutex
 	requestsExpected      int
 	requestsCountMutex    *sync.RWMutex
 	requestsIssued        int
-	ResultChan            chan Result
-	ErrorChan             chan error
-	MessageChan           chan Message
+	// requestsResumed holds the requests already done in previous runs
+	requestsResumed int
+	startTime       time.Time
+	queueMutex      *sync.Mutex
+	queue           []string
+	// recursion holds the prefixes queued for another pass over the wordlist
+	recursion   []string
+	ResultChan  chan Result
+	ErrorChan   chan error
+	MessageChan chan Message
+	// EnrichChan takes results of plugins implementing EnrichPlugin. It is
+	// bounded, so the workers only wait if the enrichment falls behind
+	EnrichChan chan Result
+	// targets holds the names of the targets of a run over multiple
+	// targets, target is the index of the one currently scanned
+	targets []string
+	target  atomic.Int64
+	// targetsMutex guards targetProgress, which holds the progress of every
+	// target of a run over multiple targets
+	targetsMutex   *sync.Mutex
+	targetProgress []TargetProgress
 }
 
 func NewProgress() *Progress {
@@ -30,9 +95,13 @@ func NewProgress() *Progress {
 	p.requestsIssued = 0
 	p.requestsExpectedMutex = new(sync.RWMutex)
 	p.requestsCountMutex = new(sync.RWMutex)
+	p.startTime = time.Now()
+	p.queueMutex = new(sync.Mutex)
+	p.targetsMutex = new(sync.Mutex)
 	p.ResultChan = make(chan Result)
 	p.ErrorChan = make(chan error)
 	p.MessageChan = make(chan Message)
+	p.EnrichChan = make(chan Result, enrichQueueSize)
 	return &p
 }
 
@@ -48,20 +117,206 @@ func (p *Progress) RequestsIssued() int {
 	return p.requestsIssued
 }
 
-func (p *Progress) incrementRequestsIssues(by int) {
+// RequestsResumed returns the number of requests which were already
+// done in a previous run and are skipped in this one
+func (p *Progress) RequestsResumed() int {
+	p.requestsCountMutex.RLock()
+	defer p.requestsCountMutex.RUnlock()
+	return p.requestsResumed
+}
+
+// resumeRequests marks requests as already done by a previous run
+func (p *Progress) resumeRequests(by int) {
 	p.requestsCountMutex.Lock()
 	defer p.requestsCountMutex.Unlock()
 	p.requestsIssued += by
+	p.requestsResumed += by
+	p.updateTarget(func(t *TargetProgress) { t.RequestsIssued += by })
+}
+
+// Elapsed returns the time since the progress was started
+func (p *Progress) Elapsed() time.Duration {
+	return time.Since(p.startTime)
+}
+
+// Rate returns the requests per second of the current run. Requests
+// restored from a previous run are not taken into account
+func (p *Progress) Rate() float64 {
+	p.requestsCountMutex.RLock()
+	done := p.requestsIssued - p.requestsResumed
+	p.requestsCountMutex.RUnlock()
+
+	elapsed := p.Elapsed().Seconds()
+	if elapsed <= 0 || done <= 0 {
+		return 0
+	}
+	return float64(done) / elapsed
+}
+
+// ETA returns the estimated remaining time of the run. It returns 0 if
+// no estimation is possible yet
+func (p *Progress) ETA() time.Duration {
+	rate := p.Rate()
+	if rate <= 0 {
+		return 0
+	}
+	remaining := p.RequestsExpected() - p.RequestsIssued()
+	if remaining <= 0 {
+		return 0
+	}
+	return time.Duration(float64(remaining) / rate * float64(time.Second))
 }
 
 func (p *Progress) incrementRequests() {
 	p.requestsCountMutex.Lock()
 	defer p.requestsCountMutex.Unlock()
 	p.requestsIssued++
+	p.updateTarget(func(t *TargetProgress) { t.RequestsIssued++ })
+}
+
+// QueueWords adds words which are processed before the remaining wordlist.
+// Patterns and additional words of the plugin are not applied to them
+func (p *Progress) QueueWords(words ...string) {
+	if len(words) == 0 {
+		return
+	}
+	p.queueMutex.Lock()
+	p.queue = append(p.queue, words...)
+	p.queueMutex.Unlock()
+	p.IncrementTotalRequests(len(words))
+}
+
+// QueueRecursion queues another pass over the whole wordlist once the
+// current one is finished. Every word of the pass is prefixed with prefix
+func (p *Progress) QueueRecursion(prefix string) {
+	p.queueMutex.Lock()
+	defer p.queueMutex.Unlock()
+	p.recursion = append(p.recursion, prefix)
+}
+
+// dequeueRecursion returns and removes the first queued prefix. It returns
+// false if no prefix is queued
+func (p *Progress) dequeueRecursion() (string, bool) {
+	p.queueMutex.Lock()
+	defer p.queueMutex.Unlock()
+	if len(p.recursion) == 0 {
+		return "", false
+	}
+	prefix := p.recursion[0]
+	p.recursion = p.recursion[1:]
+	return prefix, true
+}
+
+// queueLength returns the number of queued words
+func (p *Progress) queueLength() int {
+	p.queueMutex.Lock()
+	defer p.queueMutex.Unlock()
+	return len(p.queue)
+}
+
+// dequeueWords returns and removes all queued words
+func (p *Progress) dequeueWords() []string {
+	p.queueMutex.Lock()
+	defer p.queueMutex.Unlock()
+	words := p.queue
+	p.queue = nil
+	return words
+}
+
+// setTargets sets the names of the targets of a run over multiple targets
+func (p *Progress) setTargets(targets []string) {
+	p.targets = targets
+	p.targetProgress = make([]TargetProgress, len(targets))
+	for i, name := range targets {
+		p.targetProgress[i] = TargetProgress{Name: name, State: TargetPending}
+	}
+}
+
+// setTarget sets the index of the target currently scanned
+func (p *Progress) setTarget(index int) {
+	p.target.Store(int64(index))
+	p.updateTarget(func(t *TargetProgress) {
+		t.State = TargetScanning
+		t.Started = time.Now()
+	})
+}
+
+// finishTarget sets the final state of the target with the index
+func (p *Progress) finishTarget(index int, state TargetState) {
+	p.targetsMutex.Lock()
+	defer p.targetsMutex.Unlock()
+	if index < 0 || index >= len(p.targetProgress) {
+		return
+	}
+	p.targetProgress[index].State = state
+	p.targetProgress[index].Finished = time.Now()
+}
+
+// updateTarget calls update with the progress of the target currently
+// scanned. It does nothing unless multiple targets are scanned
+func (p *Progress) updateTarget(update func(*TargetProgress)) {
+	if len(p.targetProgress) == 0 {
+		return
+	}
+	p.targetsMutex.Lock()
+	defer p.targetsMutex.Unlock()
+	update(&p.targetProgress[p.target.Load()])
+}
+
+// expectPasses adds passes over the wordlist of requests each to the
+// expected requests. Every pass belongs to another target, starting with the
+// one currently scanned
+func (p *Progress) expectPasses(passes, requests int) {
+	p.requestsExpectedMutex.Lock()
+	defer p.requestsExpectedMutex.Unlock()
+	p.requestsExpected += passes * requests
+
+	if len(p.targetProgress) == 0 {
+		return
+	}
+	p.targetsMutex.Lock()
+	defer p.targetsMutex.Unlock()
+	for i := int(p.target.Load()); i < len(p.targetProgress) && passes > 0; i++ {
+		p.targetProgress[i].RequestsExpected += requests
+		passes--
+	}
+}
+
+// Targets returns the progress of every target of a run over multiple
+// targets. It returns nil unless multiple targets are scanned
+func (p *Progress) Targets() []TargetProgress {
+	if len(p.targetProgress) == 0 {
+		return nil
+	}
+	p.targetsMutex.Lock()
+	defer p.targetsMutex.Unlock()
+	targets := make([]TargetProgress, len(p.targetProgress))
+	copy(targets, p.targetProgress)
+	return targets
+}
+
+// targetName returns the name of the target with the index
+func (p *Progress) targetName(index int) string {
+	if index < 0 || index >= len(p.targets) {
+		return ""
+	}
+	return p.targets[index]
+}
+
+// Target returns the index and the name of the target currently scanned and
+// the number of targets. The name is empty and the number is 1 unless
+// multiple targets are scanned
+func (p *Progress) Target() (int, string, int) {
+	if len(p.targets) == 0 {
+		return 0, "", 1
+	}
+	index := int(p.target.Load())
+	return index, p.targetName(index), len(p.targets)
 }
 
 func (p *Progress) IncrementTotalRequests(by int) {
-	p.requestsCountMutex.Lock()
-	defer p.requestsCountMutex.Unlock()
+	p.requestsExpectedMutex.Lock()
+	defer p.requestsExpectedMutex.Unlock()
 	p.requestsExpected += by
+	p.updateTarget(func(t *TargetProgress) { t.RequestsExpected += by })
 }
@@ -0,0 +1,55 @@
+package libgobuster
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestProgressResume(t *testing.T) {
+	t.Parallel()
+
+	p := NewProgress()
+	p.IncrementTotalRequests(100)
+	p.resumeRequests(40)
+
+	if p.RequestsIssued() != 40 {
+		t.Fatalf("expected 40 issued requests, got %d", p.RequestsIssued())
+	}
+	if p.RequestsResumed() != 40 {
+		t.Fatalf("expected 40 resumed requests, got %d", p.RequestsResumed())
+	}
+	// no requests were done in this run so no estimation is possible
+	if p.Rate() != 0 || p.ETA() != 0 {
+		t.Fatalf("expected no rate and ETA, got %f and %s", p.Rate(), p.ETA())
+	}
+
+	p.incrementRequests()
+	if p.Rate() <= 0 {
+		t.Fatalf("expected a positive rate, got %f", p.Rate())
+	}
+	if p.ETA() <= 0 {
+		t.Fatalf("expected a positive ETA, got %s", p.ETA())
+	}
+}
+
+func TestProgressConcurrentQueueWords(t *testing.T) {
+	t.Parallel()
+
+	p := NewProgress()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p.QueueWords("a")
+				_ = p.RequestsExpected()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p.RequestsExpected() != 1000 {
+		t.Fatalf("expected 1000 expected requests, got %d", p.RequestsExpected())
+	}
+}
@@ -0,0 +1,83 @@
+package libgobuster
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// ProxyRoundRobin uses the proxies of a list one after another
+	ProxyRoundRobin = "round-robin"
+	// ProxyRandom uses a random proxy of a list for every request
+	ProxyRandom = "random"
+)
+
+// ReadProxyFile reads a list of proxies, one per line. Empty lines and lines
+// starting with # are skipped
+func ReadProxyFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open proxy file: %w", err)
+	}
+	defer f.Close()
+
+	var proxies []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		proxies = append(proxies, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read proxy file: %w", err)
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("proxy file %s contains no proxies", path)
+	}
+	return proxies, nil
+}
+
+// proxyRotator picks the proxy of every request from a list
+type proxyRotator struct {
+	proxies []*url.URL
+	random  bool
+	next    atomic.Uint64
+}
+
+// newProxyRotator parses the proxies for the rotation, round robin if
+// rotation is empty
+func newProxyRotator(proxies []string, rotation string) (*proxyRotator, error) {
+	r := proxyRotator{}
+	switch rotation {
+	case "", ProxyRoundRobin:
+	case ProxyRandom:
+		r.random = true
+	default:
+		return nil, fmt.Errorf("invalid proxy rotation %q", rotation)
+	}
+
+	for _, p := range proxies {
+		u, err := url.Parse(p)
+		if err != nil {
+			return nil, fmt.Errorf("proxy URL %s is invalid (%w)", p, err)
+		}
+		r.proxies = append(r.proxies, u)
+	}
+	return &r, nil
+}
+
+// proxy implements the Proxy function of http.Transport, it is called for
+// every request
+func (r *proxyRotator) proxy(_ *http.Request) (*url.URL, error) {
+	if r.random {
+		return r.proxies[RandomIntn(len(r.proxies))], nil
+	}
+	return r.proxies[(r.next.Add(1)-1)%uint64(len(r.proxies))], nil
+}
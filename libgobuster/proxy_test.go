@@ -0,0 +1,79 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestReadProxyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "proxies.txt")
+	content := "# office\nhttp://127.0.0.1:8080\n\n  socks5://127.0.0.1:1080  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	proxies, err := ReadProxyFile(path)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	want := []string{"http://127.0.0.1:8080", "socks5://127.0.0.1:1080"}
+	if !reflect.DeepEqual(proxies, want) {
+		t.Fatalf("Expected %v but got %v", want, proxies)
+	}
+
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, []byte("# nothing\n"), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if _, err := ReadProxyFile(empty); err == nil {
+		t.Fatal("expected an error for a file without proxies")
+	}
+}
+
+func TestProxyRotation(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	var used []string
+	// a plain http proxy gets the absolute url in the request line
+	newProxy := func(name string) *httptest.Server {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			used = append(used, name)
+			mutex.Unlock()
+			fmt.Fprint(w, name)
+		}))
+		t.Cleanup(ts.Close)
+		return ts
+	}
+	a := newProxy("a")
+	b := newProxy("b")
+
+	c, err := NewHTTPClient(&HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{Proxies: []string{a.URL, b.URL}, ProxyRotation: ProxyRoundRobin}})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, _, _, _, err := c.Request(context.Background(), "http://target.invalid/", RequestOptions{}); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+
+	want := []string{"a", "b", "a", "b"}
+	if !reflect.DeepEqual(used, want) {
+		t.Fatalf("Expected proxies %v but got %v", want, used)
+	}
+
+	if _, err := NewHTTPClient(&HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{Proxies: []string{a.URL}, ProxyRotation: "sticky"}}); err == nil {
+		t.Fatal("expected an error for an invalid rotation")
+	}
+}
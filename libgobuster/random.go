@@ -0,0 +1,93 @@
+package libgobuster
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lockedRand is a seeded random generator safe for concurrent use. It is
+// used for everything random a scan sends, so a scan can be reproduced with
+// the same seed. Keys and nonces of encrypted outputs still use crypto/rand
+type lockedRand struct {
+	mu   sync.Mutex
+	rng  *rand.Rand
+	seed int64
+}
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{rng: rand.New(rand.NewSource(seed)), seed: seed} // nolint:gosec
+}
+
+func (r *lockedRand) intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
+
+// Read implements io.Reader, it never fails
+func (r *lockedRand) Read(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Read(b)
+}
+
+func (r *lockedRand) uuid() string {
+	// reading from the generator never fails
+	u, _ := uuid.NewRandomFromReader(r)
+	return u.String()
+}
+
+// nolint:gochecknoglobals
+var (
+	randomMutex sync.RWMutex
+	random      = newLockedRand(randomSeed())
+)
+
+// randomSeed returns a random seed for runs without --seed
+func randomSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.BigEndian.Uint64(b[:]))
+}
+
+func generator() *lockedRand {
+	randomMutex.RLock()
+	defer randomMutex.RUnlock()
+	return random
+}
+
+// SetSeed seeds the generator of all random values like wildcard probes,
+// placeholders, random user agents and proxies
+func SetSeed(seed int64) {
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+	random = newLockedRand(seed)
+}
+
+// Seed returns the seed of the current generator, it is random unless it
+// was set with SetSeed
+func Seed() int64 {
+	return generator().seed
+}
+
+// RandomIntn returns a random number in [0,n)
+func RandomIntn(n int) int {
+	return generator().intn(n)
+}
+
+// RandomBytes fills b with random bytes
+func RandomBytes(b []byte) {
+	_, _ = generator().Read(b)
+}
+
+// RandomUUID returns a random version 4 uuid
+func RandomUUID() string {
+	return generator().uuid()
+}
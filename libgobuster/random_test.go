@@ -0,0 +1,28 @@
+package libgobuster
+
+import "testing"
+
+func TestLockedRand(t *testing.T) {
+	t.Parallel()
+
+	sequence := func(r *lockedRand) []interface{} {
+		b := make([]byte, 8)
+		_, _ = r.Read(b)
+		return []interface{}{r.intn(1000), r.uuid(), string(b), r.intn(10)}
+	}
+
+	a := sequence(newLockedRand(42))
+	b := sequence(newLockedRand(42))
+	c := sequence(newLockedRand(43))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("Expected the same values for the same seed but got %v and %v", a, b)
+		}
+	}
+	if a[1] == c[1] {
+		t.Fatalf("Expected different values for different seeds but got %v", a[1])
+	}
+	if len(a[1].(string)) != 36 {
+		t.Fatalf("Expected a uuid but got %v", a[1])
+	}
+}
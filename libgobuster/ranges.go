@@ -0,0 +1,81 @@
+package libgobuster
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IntRange is an inclusive range of integers
+type IntRange struct {
+	From int
+	To   int
+}
+
+// IntRanges is a list of integer ranges like response lengths. Unlike a Set
+// big ranges don't need memory for every value
+type IntRanges []IntRange
+
+// ParseIntRanges parses a comma separated list of values and ranges like
+// 1234,100-200. The end of a range can be omitted to match all bigger
+// values, like 5000-
+func ParseIntRanges(input string) (IntRanges, error) {
+	var ranges IntRanges
+	if strings.TrimSpace(input) == "" {
+		return ranges, nil
+	}
+
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid value given: %s", part)
+			}
+			ranges = append(ranges, IntRange{From: i, To: i})
+			continue
+		}
+
+		fromI, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || fromI < 0 {
+			return nil, fmt.Errorf("invalid range given: %s", part)
+		}
+		toI := math.MaxInt
+		if to = strings.TrimSpace(to); to != "" {
+			toI, err = strconv.Atoi(to)
+			if err != nil || toI < fromI {
+				return nil, fmt.Errorf("invalid range given: %s", part)
+			}
+		}
+		ranges = append(ranges, IntRange{From: fromI, To: toI})
+	}
+	return ranges, nil
+}
+
+// Contains checks if one of the ranges contains the value
+func (r IntRanges) Contains(i int) bool {
+	for _, x := range r {
+		if i >= x.From && i <= x.To {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the ranges in the syntax of ParseIntRanges
+func (r IntRanges) String() string {
+	parts := make([]string, len(r))
+	for i, x := range r {
+		switch {
+		case x.From == x.To:
+			parts[i] = strconv.Itoa(x.From)
+		case x.To == math.MaxInt:
+			parts[i] = fmt.Sprintf("%d-", x.From)
+		default:
+			parts[i] = fmt.Sprintf("%d-%d", x.From, x.To)
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,106 @@
+package libgobuster
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseIntRanges(t *testing.T) {
+	t.Parallel()
+	var tt = []struct {
+		input         string
+		expected      IntRanges
+		expectedError string
+	}{
+		{"", nil, ""},
+		{"1234", IntRanges{{1234, 1234}}, ""},
+		{"100-200", IntRanges{{100, 200}}, ""},
+		{"100 - 200, 1234", IntRanges{{100, 200}, {1234, 1234}}, ""},
+		{"5000-", IntRanges{{5000, math.MaxInt}}, ""},
+		{"0,10-", IntRanges{{0, 0}, {10, math.MaxInt}}, ""},
+		{"AAA", nil, "invalid value given: AAA"},
+		{"-5", nil, "invalid range given: -5"},
+		{"200-100", nil, "invalid range given: 200-100"},
+		{"A-200", nil, "invalid range given: A-200"},
+		{"200-A", nil, "invalid range given: 200-A"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(x.input, func(t *testing.T) {
+			t.Parallel()
+			ret, err := ParseIntRanges(x.input)
+			if x.expectedError != "" {
+				if err == nil || err.Error() != x.expectedError {
+					t.Fatalf("Expected error %q but got %v", x.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if !reflect.DeepEqual(x.expected, ret) {
+				t.Fatalf("Expected %v but got %v", x.expected, ret)
+			}
+		})
+	}
+}
+
+func TestIntRangesContains(t *testing.T) {
+	t.Parallel()
+	ranges, err := ParseIntRanges("1234,100-200,5000-")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	var tt = []struct {
+		value    int
+		expected bool
+	}{
+		{1234, true},
+		{1235, false},
+		{99, false},
+		{100, true},
+		{200, true},
+		{201, false},
+		{4999, false},
+		{5000, true},
+		{math.MaxInt, true},
+	}
+	for _, x := range tt {
+		if got := ranges.Contains(x.value); got != x.expected {
+			t.Fatalf("Contains(%d): expected %t but got %t", x.value, x.expected, got)
+		}
+	}
+	if s := ranges.String(); s != "1234,100-200,5000-" {
+		t.Fatalf("unexpected string %q", s)
+	}
+}
+
+func FuzzParseIntRanges(f *testing.F) {
+	f.Add("1234,100-200")
+	f.Add("5000-")
+	f.Add(" 1 - 2 ,3")
+	f.Add("-1")
+	f.Add("9223372036854775807")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		ranges, err := ParseIntRanges(input)
+		if err != nil {
+			return
+		}
+		for _, r := range ranges {
+			if r.From < 0 || r.To < r.From {
+				t.Fatalf("invalid range %+v of %q", r, input)
+			}
+		}
+		// the string representation is parsed to the same ranges
+		again, err := ParseIntRanges(ranges.String())
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if !reflect.DeepEqual(ranges, again) {
+			t.Fatalf("%q parsed to %v, its string %q to %v", input, ranges, ranges.String(), again)
+		}
+	})
+}
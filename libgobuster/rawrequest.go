@@ -0,0 +1,102 @@
+package libgobuster
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// RawRequest is a raw http request like the ones exported by intercepting
+// proxies. It is used as the base of the requests
+type RawRequest struct {
+	Method  string
+	Path    string
+	Headers []HTTPHeader
+	Body    string
+}
+
+// LoadRawRequest reads a raw http request from a file
+func LoadRawRequest(file string) (*RawRequest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not open request file: %w", err)
+	}
+	defer f.Close()
+
+	r, err := ParseRawRequest(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request file %s: %w", file, err)
+	}
+	return r, nil
+}
+
+// ParseRawRequest parses a raw http request. The Content-Length header is
+// dropped as the length changes once the body is modified
+func ParseRawRequest(reader io.Reader) (*RawRequest, error) {
+	br := bufio.NewReader(reader)
+
+	line, err := readRawLine(br)
+	if err != nil {
+		return nil, fmt.Errorf("could not read request line: %w", err)
+	}
+	parts := strings.Fields(line)
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid request line %q", line)
+	}
+	r := RawRequest{Method: parts[0], Path: parts[1]}
+
+	for {
+		line, err := readRawLine(br)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line == "" {
+			if err == io.EOF {
+				return &r, nil
+			}
+			break
+		}
+		keyAndValue := strings.SplitN(line, ":", 2)
+		if len(keyAndValue) != 2 || strings.TrimSpace(keyAndValue[0]) == "" {
+			return nil, fmt.Errorf("invalid header %q", line)
+		}
+		name := strings.TrimSpace(keyAndValue[0])
+		if strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		r.Headers = append(r.Headers, HTTPHeader{Name: name, Value: strings.TrimSpace(keyAndValue[1])})
+	}
+
+	body, err := io.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("could not read body: %w", err)
+	}
+	r.Body = string(body)
+	return &r, nil
+}
+
+// readRawLine reads a line without the trailing line break
+func readRawLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// URL returns the url of the request. Raw requests only contain the path so
+// the scheme and host are taken from base, absolute urls are used as they are
+func (r *RawRequest) URL(base string) (string, error) {
+	if strings.HasPrefix(r.Path, "http://") || strings.HasPrefix(r.Path, "https://") {
+		return r.Path, nil
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %w", base, err)
+	}
+	path := r.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, path), nil
+}
@@ -0,0 +1,96 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRawRequest(t *testing.T) {
+	t.Parallel()
+
+	raw := "POST /api/FUZZ?x=1 HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\nContent-Length: 15\r\nX-Token: abc\r\n\r\n{\"id\": \"FUZZ\"}\n"
+	r, err := ParseRawRequest(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if r.Method != "POST" || r.Path != "/api/FUZZ?x=1" {
+		t.Fatalf("unexpected request line %s %s", r.Method, r.Path)
+	}
+	if len(r.Headers) != 3 || r.Headers[0] != (HTTPHeader{Name: "Host", Value: "example.com"}) {
+		t.Fatalf("unexpected headers %+v", r.Headers)
+	}
+	for _, h := range r.Headers {
+		if h.Name == "Content-Length" {
+			t.Fatal("expected the content length to be dropped")
+		}
+	}
+	if r.Body != "{\"id\": \"FUZZ\"}\n" {
+		t.Fatalf("unexpected body %q", r.Body)
+	}
+
+	u, err := r.URL("https://example.com:8443/ignored")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if u != "https://example.com:8443/api/FUZZ?x=1" {
+		t.Fatalf("unexpected url %q", u)
+	}
+}
+
+func TestParseRawRequestInvalid(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		raw      string
+	}{
+		{"Empty", ""},
+		{"RequestLine", "GET\n\n"},
+		{"Header", "GET / HTTP/1.1\nInvalid\n\n"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParseRawRequest(strings.NewReader(x.raw)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseRawRequestWithoutBody(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRawRequest(strings.NewReader("GET /FUZZ HTTP/1.1\nHost: example.com"))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(r.Headers) != 1 || r.Body != "" {
+		t.Fatalf("unexpected request %+v", r)
+	}
+}
+
+func FuzzParseRawRequest(f *testing.F) {
+	f.Add("POST /api/FUZZ?x=1 HTTP/1.1\r\nHost: example.com\r\nContent-Length: 15\r\n\r\n{\"id\": \"FUZZ\"}\n", "https://example.com")
+	f.Add("GET http://example.com/ HTTP/1.1\n\n", "http://127.0.0.1:8080/base")
+	f.Add("GET /\r\nX: \r\n", "")
+	f.Add("GET relative HTTP/1.0\r\n:x\r\n", "%zz")
+
+	f.Fuzz(func(t *testing.T, raw, base string) {
+		r, err := ParseRawRequest(strings.NewReader(raw))
+		if err != nil {
+			return
+		}
+		if r.Method == "" || r.Path == "" {
+			t.Fatalf("request %q parsed without method or path: %+v", raw, r)
+		}
+		for _, h := range r.Headers {
+			if h.Name == "" || strings.EqualFold(h.Name, "Content-Length") {
+				t.Fatalf("invalid header %+v of request %q", h, raw)
+			}
+		}
+		_, _ = r.URL(base)
+	})
+}
@@ -0,0 +1,169 @@
+package libgobuster
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Redaction modes
+const (
+	// RedactHash replaces values with a short HMAC keyed with the redaction
+	// key of the run, equal values keep the same hash within the run
+	RedactHash = "hash"
+	// RedactTruncate keeps the first characters of values
+	RedactTruncate = "truncate"
+)
+
+const (
+	redactHashLength     = 12
+	redactTruncateLength = 3
+	redactionKeySize     = 32
+)
+
+// NewRedactionKey returns a random key for the hashes of a run. Without the
+// key the hashes can not be reversed by hashing the words of a wordlist
+func NewRedactionKey() ([]byte, error) {
+	key := make([]byte, redactionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("could not create the redaction key: %w", err)
+	}
+	return key, nil
+}
+
+// OutputRedaction hides the urls, paths and hostnames of results written to
+// a single output file or the terminal, for outputs which are shared with
+// less trusted parties
+type OutputRedaction struct {
+	Target string
+	Mode   string
+}
+
+// ParseOutputRedaction parses a redaction like "stdout hash"
+func ParseOutputRedaction(s string) (OutputRedaction, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return OutputRedaction{}, fmt.Errorf("invalid output redaction %q, expected \"<output> %s|%s\"", s, RedactHash, RedactTruncate)
+	}
+	mode := strings.ToLower(fields[1])
+	if mode != RedactHash && mode != RedactTruncate {
+		return OutputRedaction{}, fmt.Errorf("invalid redaction mode %q, valid values are %s and %s", fields[1], RedactHash, RedactTruncate)
+	}
+	return OutputRedaction{Target: fields[0], Mode: mode}, nil
+}
+
+// RedactionFor returns the redaction mode of the target, empty if the output
+// is not redacted
+func RedactionFor(redactions []OutputRedaction, target string) string {
+	for _, r := range redactions {
+		if sameOutput(r.Target, target) {
+			return r.Mode
+		}
+	}
+	return ""
+}
+
+// Redact returns the record and its printed line with the url, path,
+// redirect location and hostname redacted. The title is removed as it often names the target.
+// key is the redaction key of the run used by the hash mode
+func Redact(record ResultRecord, line, mode string, key []byte) (ResultRecord, string) {
+	replacements := make(map[string]string)
+
+	if record.URL != "" {
+		redacted := redactURL(record.URL, mode, key)
+		replacements[record.URL] = redacted
+		if u, err := url.Parse(record.URL); err == nil && u.Hostname() != "" {
+			replacements[u.Hostname()] = redactValue(u.Hostname(), mode, key)
+		}
+		record.URL = redacted
+	}
+	if record.Path != "" {
+		// dns and vhost results contain a hostname instead of a path
+		redacted := redactValue(record.Path, mode, key)
+		if strings.HasPrefix(record.Path, "/") {
+			redacted = redactPath(record.Path, mode, key)
+		}
+		replacements[record.Path] = redacted
+		record.Path = redacted
+	}
+	if record.Redirect != "" {
+		// relative locations are redacted like paths
+		redacted := redactURL(record.Redirect, mode, key)
+		if strings.HasPrefix(record.Redirect, "/") {
+			redacted = redactPath(record.Redirect, mode, key)
+		}
+		replacements[record.Redirect] = redacted
+		record.Redirect = redacted
+	}
+	if record.Title != "" {
+		replacements[record.Title] = ""
+		record.Title = ""
+	}
+
+	// replace the longest values first so an url is not partially replaced
+	// by its path
+	for len(replacements) > 0 {
+		longest := ""
+		for value := range replacements {
+			if len(value) > len(longest) {
+				longest = value
+			}
+		}
+		line = strings.ReplaceAll(line, longest, replacements[longest])
+		delete(replacements, longest)
+	}
+	return record, line
+}
+
+func redactURL(s, mode string, key []byte) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return redactValue(s, mode, key)
+	}
+	host := redactValue(u.Hostname(), mode, key)
+	if port := u.Port(); port != "" {
+		host = fmt.Sprintf("%s:%s", host, port)
+	}
+	return fmt.Sprintf("%s://%s%s", u.Scheme, host, redactPath(u.EscapedPath(), mode, key))
+}
+
+func redactPath(path, mode string, key []byte) string {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return "/"
+	}
+	return fmt.Sprintf("/%s", redactValue(path, mode, key))
+}
+
+func redactValue(s, mode string, key []byte) string {
+	switch mode {
+	case RedactHash:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(s))
+		return hex.EncodeToString(mac.Sum(nil))[:redactHashLength]
+	case RedactTruncate:
+		r := []rune(s)
+		if len(r) <= redactTruncateLength {
+			return s
+		}
+		return fmt.Sprintf("%s...", string(r[:redactTruncateLength]))
+	default:
+		return s
+	}
+}
+
+// redactedOutput redacts all results before writing them
+type redactedOutput struct {
+	OutputWriter
+	mode string
+	key  []byte
+}
+
+func (o redactedOutput) WriteResult(record ResultRecord, line string) error {
+	record, line = Redact(record, line, o.mode, o.key)
+	return o.OutputWriter.WriteResult(record, line)
+}
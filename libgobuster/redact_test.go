@@ -0,0 +1,132 @@
+package libgobuster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testKey is the redaction key of the tests
+// nolint:gochecknoglobals
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	record := ResultRecord{URL: "http://intranet.example.com:8080/backup/db.sql", Path: "/backup/db.sql", StatusCode: 200, Title: "Intranet",
+		Redirect: "http://intranet.example.com:8080/login"}
+	line := "/backup/db.sql (Status: 200) [Size: 12] [Title: Intranet] [--> http://intranet.example.com:8080/login]"
+
+	tt := []struct {
+		mode     string
+		url      string
+		path     string
+		redirect string
+		line     string
+	}{
+		{RedactTruncate, "http://int...:8080/bac...", "/bac...", "http://int...:8080/log...", "/bac... (Status: 200) [Size: 12] [Title: ] [--> http://int...:8080/log...]"},
+		{RedactHash, "http://" + redactValue("intranet.example.com", RedactHash, testKey) + ":8080/" + redactValue("backup/db.sql", RedactHash, testKey), "/" + redactValue("backup/db.sql", RedactHash, testKey),
+			"http://" + redactValue("intranet.example.com", RedactHash, testKey) + ":8080/" + redactValue("login", RedactHash, testKey), ""},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.mode, func(t *testing.T) {
+			t.Parallel()
+			r, l := Redact(record, line, x.mode, testKey)
+			if r.URL != x.url || r.Path != x.path || r.Redirect != x.redirect || r.Title != "" {
+				t.Fatalf("unexpected redacted record %+v", r)
+			}
+			if x.line != "" && l != x.line {
+				t.Fatalf("unexpected redacted line %q", l)
+			}
+			if strings.Contains(l, "intranet") || strings.Contains(l, "backup") {
+				t.Fatalf("line was not redacted: %q", l)
+			}
+		})
+	}
+
+	// dns results contain the hostname as path
+	r, _ := Redact(ResultRecord{Path: "admin.example.com"}, "Found: admin.example.com", RedactHash, testKey)
+	if r.Path != redactValue("admin.example.com", RedactHash, testKey) || len(r.Path) != redactHashLength {
+		t.Fatalf("unexpected redacted hostname %q", r.Path)
+	}
+}
+
+func TestRedactHashKeyed(t *testing.T) {
+	t.Parallel()
+
+	// every run has its own key, so the hashes of two runs differ and can not
+	// be reversed by hashing the words of a wordlist
+	first, err := NewRedactionKey()
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	second, err := NewRedactionKey()
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	a, _ := Redact(ResultRecord{Path: "/admin"}, "/admin", RedactHash, first)
+	b, _ := Redact(ResultRecord{Path: "/admin"}, "/admin", RedactHash, second)
+	if a.Path == b.Path {
+		t.Fatalf("expected different hashes for different runs, got %s twice", a.Path)
+	}
+	unkeyed := sha256.Sum256([]byte("admin"))
+	if a.Path == "/"+hex.EncodeToString(unkeyed[:])[:redactHashLength] {
+		t.Fatalf("expected a keyed hash, got the plain sha256 %s", a.Path)
+	}
+	// equal values keep their hash within a run
+	if again, _ := Redact(ResultRecord{Path: "/admin"}, "/admin", RedactHash, first); again.Path != a.Path {
+		t.Fatalf("expected the same hash within a run, got %s and %s", a.Path, again.Path)
+	}
+
+	// outputs can not hash without a key
+	if _, err := OpenOutputs(&Options{OutputRedactions: []OutputRedaction{{Target: OutputStdout, Mode: RedactHash}}}); err == nil {
+		t.Fatal("expected an error for a hash redaction without key")
+	}
+}
+
+func TestRedactedOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	full := filepath.Join(dir, "full.txt")
+	shared := filepath.Join(dir, "shared.txt")
+	redaction, err := ParseOutputRedaction(shared + " truncate")
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	outputs, err := OpenOutputs(&Options{Outputs: []string{full, shared}, OutputRedactions: []OutputRedaction{redaction}})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := outputs.WriteResult(ResultRecord{Path: "/secret"}, "/secret (Status: 200)"); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := outputs.Close(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	for file, want := range map[string]string{full: "/secret (Status: 200)\n", shared: "/sec... (Status: 200)\n"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if string(content) != want {
+			t.Fatalf("unexpected content of %s: %q", file, content)
+		}
+	}
+}
+
+func TestParseOutputRedactionInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "stdout", "stdout encrypt", "stdout hash truncate"} {
+		if _, err := ParseOutputRedaction(s); err == nil {
+			t.Fatalf("expected an error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package libgobuster
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportSection contains the results of a single status code
+type reportSection struct {
+	ID      string
+	Title   string
+	Results []ResultRecord
+}
+
+// reportSetting is a line of the configuration of the run
+type reportSetting struct {
+	Name  string
+	Value string
+}
+
+type reportData struct {
+	Run       RunRecord
+	Duration  time.Duration
+	Version   string
+	Generated time.Time
+	Config    []reportSetting
+	Results   []ResultRecord
+	Sections  []reportSection
+}
+
+// WriteReport renders the findings of the run as a html report with the
+// configuration, a sortable table of all results and a section per status
+// code. config is the configuration as printed in the banner
+func WriteReport(filename string, run RunRecord, config string) error {
+	data := reportData{
+		Run:       run,
+		Duration:  run.End.Sub(run.Start).Round(time.Second),
+		Version:   VERSION,
+		Generated: time.Now(),
+		Config:    parseReportConfig(config),
+		Results:   run.Results,
+		Sections:  reportSections(run.Results),
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("could not create report: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	if err := reportTemplate.Execute(w, data); err != nil {
+		f.Close()
+		return fmt.Errorf("could not render report: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseReportConfig splits the "[+] Name: value" lines of the banner
+func parseReportConfig(config string) []reportSetting {
+	var settings []reportSetting
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "[+]"))
+		if line == "" {
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			settings = append(settings, reportSetting{Value: line})
+			continue
+		}
+		settings = append(settings, reportSetting{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return settings
+}
+
+// reportSections groups the results by status code, results without a status
+// code like the ones of dns mode are grouped at the end
+func reportSections(results []ResultRecord) []reportSection {
+	byStatus := make(map[int][]ResultRecord)
+	for _, r := range results {
+		byStatus[r.StatusCode] = append(byStatus[r.StatusCode], r)
+	}
+	codes := make([]int, 0, len(byStatus))
+	for code := range byStatus {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	sections := make([]reportSection, 0, len(codes))
+	for _, code := range codes {
+		if code == 0 {
+			continue
+		}
+		sections = append(sections, reportSection{
+			ID:      fmt.Sprintf("status-%d", code),
+			Title:   fmt.Sprintf("Status %d", code),
+			Results: byStatus[code],
+		})
+	}
+	if r, ok := byStatus[0]; ok {
+		sections = append(sections, reportSection{ID: "status-none", Title: "Without status code", Results: r})
+	}
+	return sections
+}
+
+// statusClass returns the css class of the status code
+func statusClass(code int) string {
+	switch {
+	case code >= 200 && code < 300:
+		return "s2xx"
+	case code >= 300 && code < 400:
+		return "s3xx"
+	case code >= 400 && code < 500:
+		return "s4xx"
+	case code >= 500:
+		return "s5xx"
+	default:
+		return ""
+	}
+}
+
+// nolint:gochecknoglobals
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"statusClass": statusClass,
+	"join":        strings.Join,
+	"time":        func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Gobuster report: {{.Run.Target}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0; }
+.meta { color: #666; margin-top: .2em; }
+table { border-collapse: collapse; margin: 1em 0; width: 100%; }
+th, td { border: 1px solid #ccc; padding: .3em .6em; text-align: left; vertical-align: top; }
+th { background: #eee; }
+table.sortable th { cursor: pointer; user-select: none; }
+table.sortable th.asc::after { content: " \25B2"; }
+table.sortable th.desc::after { content: " \25BC"; }
+td.num { text-align: right; }
+table.summary, table.config { width: auto; }
+.s2xx { color: #1a7f37; }
+.s3xx { color: #0969da; }
+.s4xx { color: #bc4c00; }
+.s5xx { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Gobuster report</h1>
+<p class="meta">Generated {{time .Generated}} by gobuster {{.Version}}</p>
+
+<h2>Summary</h2>
+<table class="summary">
+<tr><th>Mode</th><td>{{.Run.Mode}}</td></tr>
+<tr><th>Target</th><td>{{.Run.Target}}</td></tr>
+<tr><th>Wordlist</th><td>{{.Run.Wordlist}}</td></tr>
+<tr><th>Start</th><td>{{time .Run.Start}}</td></tr>
+<tr><th>End</th><td>{{time .Run.End}} ({{.Duration}})</td></tr>
+<tr><th>Requests</th><td>{{.Run.Requests}}</td></tr>
+<tr><th>Findings</th><td>{{len .Results}}</td></tr>
+{{- if .Run.StopReason}}
+<tr><th>Stopped early</th><td>{{.Run.StopReason}}</td></tr>
+{{- end}}
+</table>
+{{- if .Sections}}
+<ul>
+{{- range .Sections}}
+<li><a href="#{{.ID}}">{{.Title}}</a>: {{len .Results}}</li>
+{{- end}}
+</ul>
+{{- end}}
+
+{{- if .Config}}
+<h2>Configuration</h2>
+<table class="config">
+{{- range .Config}}
+<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>All results</h2>
+{{template "results" .Results}}
+
+{{- range .Sections}}
+<h2 id="{{.ID}}">{{.Title}}</h2>
+{{template "results" .Results}}
+{{- end}}
+
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, column) {
+    th.addEventListener("click", function () {
+      var asc = !th.classList.contains("asc");
+      table.querySelectorAll("th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].dataset.sort || a.cells[column].textContent;
+        var y = b.cells[column].dataset.sort || b.cells[column].textContent;
+        var cmp = th.classList.contains("num") ? Number(x) - Number(y) : x.localeCompare(y);
+        return asc ? cmp : -cmp;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+{{define "results"}}
+<table class="sortable">
+<thead><tr><th>Path</th><th>URL</th><th class="num">Status</th><th class="num">Size</th><th>Redirect</th><th>Tags</th><th>Title</th><th>Time</th></tr></thead>
+<tbody>
+{{- range .}}
+<tr><td>{{.Path}}</td><td>{{if .URL}}<a href="{{.URL}}">{{.URL}}</a>{{end}}</td><td class="num {{statusClass .StatusCode}}">{{if .StatusCode}}{{.StatusCode}}{{end}}</td><td class="num">{{.Size}}</td><td>{{.Redirect}}</td><td>{{join .Tags ", "}}</td><td>{{.Title}}</td><td data-sort="{{.Timestamp.Unix}}">{{time .Timestamp}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+`))
@@ -0,0 +1,71 @@
+package libgobuster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteReport(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	run := RunRecord{
+		Mode:     "directory enumeration",
+		Target:   "http://localhost",
+		Wordlist: "words.txt",
+		Start:    start,
+		End:      start.Add(90 * time.Second),
+		Requests: 1000,
+		Results: []ResultRecord{
+			{URL: "http://localhost/admin", Path: "/admin", Found: true, StatusCode: 301, Redirect: "/admin/", Timestamp: start},
+			{URL: "http://localhost/index.php", Path: "/index.php", Found: true, StatusCode: 200, Size: 42, Title: "<script>alert(1)</script>", Timestamp: start},
+			{URL: "http://localhost/.git/HEAD", Path: "/.git/HEAD", Found: true, StatusCode: 200, Tags: []string{"secret-file", "vcs"}, Timestamp: start},
+		},
+	}
+	config := "[+] Url:          http://localhost\n[+] Threads:      10\n[+] Extensions:   php,txt\n"
+
+	filename := filepath.Join(t.TempDir(), "report.html")
+	if err := WriteReport(filename, run, config); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	report := string(content)
+
+	for _, want := range []string{
+		"<th>Threads</th><td>10</td>",
+		"<th>Extensions</th><td>php,txt</td>",
+		"<th>End</th><td>2023-10-01 12:01:30 (1m30s)</td>",
+		`<a href="#status-200">Status 200</a>: 2`,
+		`<a href="#status-301">Status 301</a>: 1`,
+		`<h2 id="status-200">Status 200</h2>`,
+		"<td>/admin/</td>",
+		"<td>secret-file, vcs</td>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("expected the report to contain %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "<script>alert(1)") {
+		t.Fatal("the title was not escaped")
+	}
+	// the status sections are ordered by their code
+	if strings.Index(report, `id="status-200"`) > strings.Index(report, `id="status-301"`) {
+		t.Fatal("expected the sections to be ordered by status code")
+	}
+}
+
+func TestReportSectionsWithoutStatus(t *testing.T) {
+	t.Parallel()
+
+	sections := reportSections([]ResultRecord{{Path: "www.example.com"}, {Path: "/admin", StatusCode: 403}})
+	if len(sections) != 2 || sections[0].ID != "status-403" || sections[1].ID != "status-none" {
+		t.Fatalf("unexpected sections %+v", sections)
+	}
+}
@@ -0,0 +1,38 @@
+package libgobuster
+
+import (
+	"strings"
+	"time"
+)
+
+// ResultRecord is the mode independent representation of a single result.
+// It is used to apply generic rules like suppressions to the results of
+// every plugin.
+type ResultRecord struct {
+	URL        string
+	Path       string
+	Found      bool
+	StatusCode int
+	Size       int64
+	Tags       []string
+	// Redirect is the location of redirect responses of http based plugins
+	Redirect string
+	// Title and Simhash are only set if the plugin supports clustering
+	Title   string
+	Simhash uint64
+	// Timing is only set by http based plugins
+	Timing RequestTiming
+	// Timestamp is the time the result was received by the cli
+	Timestamp time.Time
+}
+
+// NewResultRecord returns the mode independent representation of the result.
+// Results not implementing RecordResult are treated as found with their
+// string representation as path
+func NewResultRecord(r Result) ResultRecord {
+	if rr, ok := r.(RecordResult); ok {
+		return rr.ResultToRecord()
+	}
+	s, _ := r.ResultToString()
+	return ResultRecord{Path: strings.TrimSpace(s), Found: true}
+}
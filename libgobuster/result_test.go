@@ -0,0 +1,36 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+// stringResult is a result of an external plugin without a record
+type stringResult string
+
+func (r stringResult) ResultToString() (string, error) {
+	return string(r), nil
+}
+
+func TestNewResultRecord(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		result   Result
+		want     ResultRecord
+	}{
+		{"record", collectResult{word: "found1"}, ResultRecord{Path: "found1", Found: true}},
+		{"string", stringResult("/admin (Status: 200)\n"), ResultRecord{Path: "/admin (Status: 200)", Found: true}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if got := NewResultRecord(x.result); !reflect.DeepEqual(got, x.want) {
+				t.Fatalf("got %#v, want %#v", got, x.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package libgobuster
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxRetryWait caps the exponential backoff between retries
+const maxRetryWait = 30 * time.Second
+
+// retryable returns true if the request failed with a transient error which
+// is worth retrying: timeouts, connection resets and 502 or 503 responses
+func retryable(resp *http.Response, err error) bool {
+	if err == nil {
+		return resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// the server closed the connection without sending a response
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryBackoff returns the time to wait before the retry after the given
+// number of failed attempts. The wait doubles with every attempt
+func retryBackoff(wait time.Duration, attempt int) time.Duration {
+	for i := 0; i < attempt && wait < maxRetryWait; i++ {
+		wait *= 2
+	}
+	if wait > maxRetryWait {
+		return maxRetryWait
+	}
+	return wait
+}
+
+// waitRetry waits before the next attempt. It returns false if the context
+// was canceled meanwhile
+func waitRetry(ctx context.Context, wait time.Duration) bool {
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(wait):
+		return true
+	}
+}
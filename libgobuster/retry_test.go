@@ -0,0 +1,29 @@
+package libgobuster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		wait     time.Duration
+		attempt  int
+		expected time.Duration
+	}{
+		{time.Second, 0, time.Second},
+		{time.Second, 1, 2 * time.Second},
+		{time.Second, 3, 8 * time.Second},
+		{time.Second, 10, maxRetryWait},
+		{time.Minute, 0, maxRetryWait},
+		{0, 5, 0},
+	}
+
+	for _, x := range tt {
+		if got := retryBackoff(x.wait, x.attempt); got != x.expected {
+			t.Fatalf("expected %s for attempt %d with %s, got %s", x.expected, x.attempt, x.wait, got)
+		}
+	}
+}
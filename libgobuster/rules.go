@@ -0,0 +1,120 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a conditional follow up action executed for every matching response
+type Rule struct {
+	Name string `yaml:"name"`
+	// If is a filter rule like "status=401,403 regex=^/admin"
+	If string `yaml:"if"`
+	// Location is a regex the Location header of the response needs to match
+	Location string      `yaml:"location"`
+	Then     RuleActions `yaml:"then"`
+
+	filter   *ResultFilter
+	location *regexp.Regexp
+	headers  []HTTPHeader
+}
+
+// RuleActions are the actions of a rule
+type RuleActions struct {
+	// Headers retries the request with these additional headers
+	Headers []string `yaml:"headers"`
+	// FollowLocation also requests the url of the Location header
+	FollowLocation bool `yaml:"follow-location"`
+	// Request also requests these paths, {path} is replaced with the path of the response
+	Request []string `yaml:"request"`
+}
+
+type rulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// ParseRulesFile parses a YAML file containing the rules
+func ParseRulesFile(file string) ([]Rule, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var f rulesFile
+	if err := yaml.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+
+	for i := range f.Rules {
+		r := &f.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule%d", i+1)
+		}
+		if r.If != "" {
+			filter, err := ParseResultFilter(r.If)
+			if err != nil {
+				return nil, fmt.Errorf("invalid condition of rule %s: %w", r.Name, err)
+			}
+			r.filter = &filter
+		}
+		if r.Location != "" {
+			r.location, err = regexp.Compile(r.Location)
+			if err != nil {
+				return nil, fmt.Errorf("invalid location of rule %s: %w", r.Name, err)
+			}
+		}
+		for _, h := range r.Then.Headers {
+			name, value, found := strings.Cut(h, ":")
+			if !found || strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("invalid header %q of rule %s", h, r.Name)
+			}
+			r.headers = append(r.headers, HTTPHeader{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+		}
+		if len(r.headers) == 0 && !r.Then.FollowLocation && len(r.Then.Request) == 0 {
+			return nil, fmt.Errorf("rule %s has no actions", r.Name)
+		}
+	}
+
+	return f.Rules, nil
+}
+
+// Matches checks if the response matches all conditions of the rule
+func (r Rule) Matches(record ResultRecord, header http.Header) bool {
+	if r.filter != nil && !r.filter.Matches(record) {
+		return false
+	}
+	if r.location != nil && !r.location.MatchString(header.Get("Location")) {
+		return false
+	}
+	return true
+}
+
+// Headers returns the additional headers to retry the request with
+func (r Rule) Headers() []HTTPHeader {
+	return r.headers
+}
+
+// Requests returns the additional paths to request for the given path
+func (r Rule) Requests(path string) []string {
+	ret := make([]string, 0, len(r.Then.Request))
+	for _, req := range r.Then.Request {
+		ret = append(ret, strings.ReplaceAll(req, "{path}", path))
+	}
+	return ret
+}
+
+// MatchingRules returns all rules matching the response
+func MatchingRules(rules []Rule, record ResultRecord, header http.Header) []Rule {
+	var ret []Rule
+	for _, r := range rules {
+		if r.Matches(record, header) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,103 @@
+package libgobuster
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRulesFile(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	content := `rules:
+  - name: bypass
+    if: "status=401,403"
+    then:
+      headers:
+        - "X-Original-URL: /admin"
+  - location: "^/login"
+    then:
+      follow-location: true
+      request:
+        - "{path}.bak"
+`
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("could not write rules file: %v", err)
+	}
+
+	rules, err := ParseRulesFile(file)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+	if rules[1].Name != "rule2" {
+		t.Fatalf("expected a default name, got %q", rules[1].Name)
+	}
+	if expected := []HTTPHeader{{Name: "X-Original-URL", Value: "/admin"}}; !reflect.DeepEqual(rules[0].Headers(), expected) {
+		t.Fatalf("expected %v, got %v", expected, rules[0].Headers())
+	}
+	if expected := []string{"admin.bak"}; !reflect.DeepEqual(rules[1].Requests("admin"), expected) {
+		t.Fatalf("expected %v, got %v", expected, rules[1].Requests("admin"))
+	}
+
+	redirect := http.Header{}
+	redirect.Set("Location", "/login?next=/admin")
+
+	tt := []struct {
+		testName string
+		record   ResultRecord
+		header   http.Header
+		expected []string
+	}{
+		{"Status", ResultRecord{Path: "/admin", StatusCode: 403}, http.Header{}, []string{"bypass"}},
+		{"Location", ResultRecord{Path: "/admin", StatusCode: 302}, redirect, []string{"rule2"}},
+		{"None", ResultRecord{Path: "/admin", StatusCode: 200}, http.Header{}, nil},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, r := range MatchingRules(rules, x.record, x.header) {
+				got = append(got, r.Name)
+			}
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseRulesFileInvalid(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+	}{
+		{"No Actions", "rules:\n  - if: status=200\n"},
+		{"Invalid Condition", "rules:\n  - if: foo=bar\n    then:\n      follow-location: true\n"},
+		{"Invalid Header", "rules:\n  - then:\n      headers:\n        - nocolon\n"},
+		{"Invalid YAML", "rules: [\n"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			file := filepath.Join(t.TempDir(), "rules.yaml")
+			if err := os.WriteFile(file, []byte(x.content), 0o600); err != nil {
+				t.Fatalf("could not write rules file: %v", err)
+			}
+			if _, err := ParseRulesFile(file); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// Script is a user supplied Starlark script implementing hooks which are
+// called for every request, response and result. The globals of the script
+// are frozen after loading so the hooks can be called from all threads.
+type Script struct {
+	File       string
+	onRequest  starlark.Callable
+	onResponse starlark.Callable
+	onResult   starlark.Callable
+}
+
+// LoadScript executes the script and looks up the on_request, on_response
+// and on_result hooks. At least one of them needs to be defined
+func LoadScript(file string) (*Script, error) {
+	thread := &starlark.Thread{Name: "load"}
+	globals, err := starlark.ExecFile(thread, file, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Script{File: file}
+	for name, hook := range map[string]*starlark.Callable{
+		"on_request":  &s.onRequest,
+		"on_response": &s.onResponse,
+		"on_result":   &s.onResult,
+	} {
+		v, ok := globals[name]
+		if !ok {
+			continue
+		}
+		fn, ok := v.(starlark.Callable)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a function but %s", name, v.Type())
+		}
+		*hook = fn
+	}
+
+	if len(s.Hooks()) == 0 {
+		return nil, fmt.Errorf("script defines none of on_request, on_response or on_result")
+	}
+
+	return &s, nil
+}
+
+// Hooks returns the names of the hooks defined by the script
+func (s *Script) Hooks() []string {
+	var ret []string
+	if s.onRequest != nil {
+		ret = append(ret, "on_request")
+	}
+	if s.onResponse != nil {
+		ret = append(ret, "on_response")
+	}
+	if s.onResult != nil {
+		ret = append(ret, "on_result")
+	}
+	return ret
+}
+
+// String returns the file and the defined hooks of the script
+func (s *Script) String() string {
+	return fmt.Sprintf("%s (%s)", s.File, strings.Join(s.Hooks(), ", "))
+}
+
+// OnRequest calls the on_request hook with a dict containing the method, url
+// and headers of the request. If the hook returns a dict the request is
+// updated with its values, None leaves the request unchanged
+func (s *Script) OnRequest(req *http.Request) error {
+	if s == nil || s.onRequest == nil {
+		return nil
+	}
+
+	d := starlark.NewDict(3)
+	_ = d.SetKey(starlark.String("method"), starlark.String(req.Method))
+	_ = d.SetKey(starlark.String("url"), starlark.String(req.URL.String()))
+	_ = d.SetKey(starlark.String("headers"), headerToDict(req.Header))
+
+	v, err := s.call(s.onRequest, d)
+	if err != nil {
+		return err
+	}
+	if v == starlark.None {
+		return nil
+	}
+	ret, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("on_request must return a dict or None, got %s", v.Type())
+	}
+
+	if method, err := dictString(ret, "method"); err != nil {
+		return fmt.Errorf("on_request: %w", err)
+	} else if method != "" {
+		req.Method = method
+	}
+
+	if u, err := dictString(ret, "url"); err != nil {
+		return fmt.Errorf("on_request: %w", err)
+	} else if u != "" {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return fmt.Errorf("on_request returned an invalid url %q: %w", u, err)
+		}
+		// keep a custom Host header
+		if req.Host == req.URL.Host {
+			req.Host = parsed.Host
+		}
+		req.URL = parsed
+	}
+
+	headers, found, err := ret.Get(starlark.String("headers"))
+	if err != nil {
+		return fmt.Errorf("on_request: %w", err)
+	}
+	if found {
+		h, ok := headers.(*starlark.Dict)
+		if !ok {
+			return fmt.Errorf("on_request: headers must be a dict, got %s", headers.Type())
+		}
+		req.Header = http.Header{}
+		for _, item := range h.Items() {
+			name, ok1 := starlark.AsString(item[0])
+			value, ok2 := starlark.AsString(item[1])
+			if !ok1 || !ok2 {
+				return fmt.Errorf("on_request: headers must only contain strings")
+			}
+			req.Header[name] = []string{value}
+		}
+	}
+
+	return nil
+}
+
+// OnResponse calls the on_response hook with a dict containing the url,
+// status, size, headers and body of the response. The body is only set if it
+// is read by the plugin. The hook can return a new status code to classify
+// the response, for example 404 for a soft 404 page, or None to keep it
+func (s *Script) OnResponse(fullURL string, statusCode int, size int64, header http.Header, body []byte) (int, error) {
+	if s == nil || s.onResponse == nil {
+		return statusCode, nil
+	}
+
+	d := starlark.NewDict(5)
+	_ = d.SetKey(starlark.String("url"), starlark.String(fullURL))
+	_ = d.SetKey(starlark.String("status"), starlark.MakeInt(statusCode))
+	_ = d.SetKey(starlark.String("size"), starlark.MakeInt64(size))
+	_ = d.SetKey(starlark.String("headers"), headerToDict(header))
+	_ = d.SetKey(starlark.String("body"), starlark.String(body))
+
+	v, err := s.call(s.onResponse, d)
+	if err != nil {
+		return 0, err
+	}
+	if v == starlark.None {
+		return statusCode, nil
+	}
+	code, err := starlark.AsInt32(v)
+	if err != nil {
+		return 0, fmt.Errorf("on_response must return a status code or None, got %s", v.Type())
+	}
+	return code, nil
+}
+
+// OnResult calls the on_result hook with a dict containing the url, path,
+// found, status, size and tags of the result. It returns false if the hook
+// returned False to hide the result
+func (s *Script) OnResult(record ResultRecord) (bool, error) {
+	if s == nil || s.onResult == nil {
+		return true, nil
+	}
+
+	tags := make([]starlark.Value, 0, len(record.Tags))
+	for _, t := range record.Tags {
+		tags = append(tags, starlark.String(t))
+	}
+
+	d := starlark.NewDict(6)
+	_ = d.SetKey(starlark.String("url"), starlark.String(record.URL))
+	_ = d.SetKey(starlark.String("path"), starlark.String(record.Path))
+	_ = d.SetKey(starlark.String("found"), starlark.Bool(record.Found))
+	_ = d.SetKey(starlark.String("status"), starlark.MakeInt(record.StatusCode))
+	_ = d.SetKey(starlark.String("size"), starlark.MakeInt64(record.Size))
+	_ = d.SetKey(starlark.String("tags"), starlark.NewList(tags))
+
+	v, err := s.call(s.onResult, d)
+	if err != nil {
+		return true, err
+	}
+	if v == starlark.None {
+		return true, nil
+	}
+	keep, ok := v.(starlark.Bool)
+	if !ok {
+		return true, fmt.Errorf("on_result must return a bool or None, got %s", v.Type())
+	}
+	return bool(keep), nil
+}
+
+// call calls the hook in a new thread as starlark threads must not be shared
+func (s *Script) call(fn starlark.Callable, arg starlark.Value) (starlark.Value, error) {
+	thread := &starlark.Thread{Name: fn.Name()}
+	v, err := starlark.Call(thread, fn, starlark.Tuple{arg}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error in %s of %s: %w", fn.Name(), s.File, err)
+	}
+	return v, nil
+}
+
+// headerToDict converts the header to a dict, multiple values are joined
+func headerToDict(header http.Header) *starlark.Dict {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	d := starlark.NewDict(len(names))
+	for _, name := range names {
+		_ = d.SetKey(starlark.String(name), starlark.String(strings.Join(header[name], ", ")))
+	}
+	return d
+}
+
+// dictString returns the string value of the key or an empty string if the
+// key does not exist
+func dictString(d *starlark.Dict, key string) (string, error) {
+	v, found, err := d.Get(starlark.String(key))
+	if err != nil || !found {
+		return "", err
+	}
+	s, ok := starlark.AsString(v)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string, got %s", key, v.Type())
+	}
+	return s, nil
+}
@@ -0,0 +1,122 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "hooks.star")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("could not write script: %v", err)
+	}
+	return file
+}
+
+func TestScriptHooks(t *testing.T) {
+	t.Parallel()
+
+	file := writeScript(t, `
+def on_request(req):
+    req["headers"]["X-Token"] = "secret"
+    return req
+
+def on_response(resp):
+    if "not found" in resp["body"]:
+        return 404
+
+def on_result(result):
+    return not result["path"].startswith("/static")
+`)
+	script, err := LoadScript(file)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if expected := []string{"on_request", "on_response", "on_result"}; !reflect.DeepEqual(script.Hooks(), expected) {
+		t.Fatalf("expected %v, got %v", expected, script.Hooks())
+	}
+
+	h := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "page not found")
+	}))
+	defer h.Close()
+
+	c, err := NewHTTPClient(&HTTPOptions{Script: script})
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	status, _, _, _, err := c.Request(context.Background(), h.URL, RequestOptions{ReturnBody: true})
+	if err != nil {
+		t.Fatalf("Got Error: %v", err)
+	}
+	if status != http.StatusNotFound {
+		t.Fatalf("expected the status of the hook, got %d", status)
+	}
+
+	for path, expected := range map[string]bool{"/admin": true, "/static/app.js": false} {
+		keep, err := script.OnResult(ResultRecord{Path: path})
+		if err != nil {
+			t.Fatalf("Got Error: %v", err)
+		}
+		if keep != expected {
+			t.Fatalf("expected %t for %s, got %t", expected, path, keep)
+		}
+	}
+}
+
+func TestScriptNil(t *testing.T) {
+	t.Parallel()
+
+	var script *Script
+	keep, err := script.OnResult(ResultRecord{Path: "/admin"})
+	if err != nil || !keep {
+		t.Fatalf("expected results to be kept without a script, got %t %v", keep, err)
+	}
+}
+
+func TestLoadScriptInvalid(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		testName string
+		content  string
+	}{
+		{"No Hooks", "x = 1\n"},
+		{"Not Callable", "on_request = 1\n"},
+		{"Syntax Error", "def on_request(req)\n"},
+		{"Runtime Error", "fail(\"broken\")\n"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			if _, err := LoadScript(writeScript(t, x.content)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestScriptInvalidReturn(t *testing.T) {
+	t.Parallel()
+
+	script, err := LoadScript(writeScript(t, "def on_result(result):\n    return \"yes\"\n"))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if _, err := script.OnResult(ResultRecord{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
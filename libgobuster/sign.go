@@ -0,0 +1,268 @@
+package libgobuster
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestVersion is the version of the manifest format
+const manifestVersion = 1
+
+// OutputSigning signs a manifest of every output file with an ed25519 key, so
+// the results can be shown to be unmodified since the scan. Keys can be
+// created with openssl genpkey -algorithm ed25519
+type OutputSigning struct {
+	key ed25519.PrivateKey
+}
+
+// ParseOutputSigning reads the ed25519 private key from a PKCS #8 PEM file
+func ParseOutputSigning(file string) (*OutputSigning, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s is not a PEM encoded private key", file)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in %s: %w", file, err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an ed25519 key", file)
+	}
+	return &OutputSigning{key: private}, nil
+}
+
+// ParsePublicKey reads an ed25519 public key from a PKIX PEM file, like the
+// output of openssl pkey -pubout
+func ParsePublicKey(file string) (ed25519.PublicKey, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("%s is not a PEM encoded public key", file)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", file, err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an ed25519 key", file)
+	}
+	return public, nil
+}
+
+// PublicKey returns the base64 encoded public key of the signing key
+func (s *OutputSigning) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// OutputManifest describes a finished output file. Chain is the head of a
+// hash chain over the written results, every link is the sha256 hash of the
+// previous link and the json representation of the result
+type OutputManifest struct {
+	Version   int       `json:"version"`
+	File      string    `json:"file"`
+	Format    string    `json:"format"`
+	Encrypted bool      `json:"encrypted,omitempty"`
+	SHA256    string    `json:"sha256"`
+	Results   int       `json:"results"`
+	Chain     string    `json:"chain"`
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+	PublicKey string    `json:"public_key"`
+	Signature string    `json:"signature,omitempty"`
+}
+
+// ManifestFile returns the name of the manifest of the output file
+func ManifestFile(output string) string {
+	return fmt.Sprintf("%s.manifest.json", output)
+}
+
+// signedContent returns the bytes the signature is created over, the
+// manifest without its signature
+func (m OutputManifest) signedContent() ([]byte, error) {
+	m.Signature = ""
+	return json.Marshal(m)
+}
+
+// resultChain is a hash chain over the results written to an output
+type resultChain struct {
+	head    [sha256.Size]byte
+	results int
+}
+
+func (c *resultChain) add(record ResultRecord) error {
+	line, err := json.Marshal(NewJSONResult(record))
+	if err != nil {
+		return err
+	}
+	c.addLine(line)
+	return nil
+}
+
+func (c *resultChain) addLine(line []byte) {
+	h := sha256.New()
+	h.Write(c.head[:])
+	h.Write(line)
+	copy(c.head[:], h.Sum(nil))
+	c.results++
+}
+
+// signedOutput chains the written results and signs the manifest of the
+// file once it is closed
+type signedOutput struct {
+	OutputWriter
+	filename  string
+	format    string
+	encrypted bool
+	signing   *OutputSigning
+	started   time.Time
+	chain     resultChain
+}
+
+func (o *signedOutput) WriteResult(record ResultRecord, line string) error {
+	if err := o.OutputWriter.WriteResult(record, line); err != nil {
+		return err
+	}
+	return o.chain.add(record)
+}
+
+func (o *signedOutput) Close() error {
+	if err := o.OutputWriter.Close(); err != nil {
+		return err
+	}
+
+	sum, err := fileSHA256(o.filename)
+	if err != nil {
+		return fmt.Errorf("error on hashing output file: %w", err)
+	}
+	m := OutputManifest{
+		Version:   manifestVersion,
+		File:      filepath.Base(o.filename),
+		Format:    o.format,
+		Encrypted: o.encrypted,
+		SHA256:    sum,
+		Results:   o.chain.results,
+		Chain:     hex.EncodeToString(o.chain.head[:]),
+		Started:   o.started.UTC(),
+		Finished:  time.Now().UTC(),
+		PublicKey: o.signing.PublicKey(),
+	}
+	content, err := m.signedContent()
+	if err != nil {
+		return err
+	}
+	m.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(o.signing.key, content))
+
+	content, err = json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ManifestFile(o.filename), append(content, '\n'), 0o644); err != nil { // nolint:gosec
+		return fmt.Errorf("error on writing manifest: %w", err)
+	}
+	return nil
+}
+
+func fileSHA256(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyOutput checks the signature of the manifest of the output file and
+// that the file matches it. If publicKey is not nil the manifest has to be
+// signed by it. The result chain can only be recomputed for json files which
+// are not encrypted, chainChecked reports if it was
+func VerifyOutput(filename string, publicKey ed25519.PublicKey) (m OutputManifest, chainChecked bool, err error) {
+	content, err := os.ReadFile(ManifestFile(filename))
+	if err != nil {
+		return m, false, fmt.Errorf("could not read manifest: %w", err)
+	}
+	if err := json.Unmarshal(content, &m); err != nil {
+		return m, false, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.Version != manifestVersion {
+		return m, false, fmt.Errorf("unsupported manifest version %d", m.Version)
+	}
+
+	signer, err := base64.StdEncoding.DecodeString(m.PublicKey)
+	if err != nil || len(signer) != ed25519.PublicKeySize {
+		return m, false, fmt.Errorf("invalid public key in manifest")
+	}
+	if publicKey != nil && !bytes.Equal(signer, publicKey) {
+		return m, false, fmt.Errorf("manifest is signed by %s, not by the given key", m.PublicKey)
+	}
+	signature, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return m, false, fmt.Errorf("invalid signature in manifest")
+	}
+	signed, err := m.signedContent()
+	if err != nil {
+		return m, false, err
+	}
+	if !ed25519.Verify(signer, signed, signature) {
+		return m, false, fmt.Errorf("invalid signature, the manifest was modified")
+	}
+
+	sum, err := fileSHA256(filename)
+	if err != nil {
+		return m, false, err
+	}
+	if sum != m.SHA256 {
+		return m, false, fmt.Errorf("sha256 of %s does not match the manifest, the file was modified", filename)
+	}
+
+	if m.Format != OutputJSON || m.Encrypted {
+		return m, false, nil
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return m, false, err
+	}
+	defer f.Close()
+	var chain resultChain
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			chain.addLine(bytes.TrimSuffix(line, []byte("\n")))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, false, err
+		}
+	}
+	if chain.results != m.Results || hex.EncodeToString(chain.head[:]) != m.Chain {
+		return m, false, fmt.Errorf("the results of %s do not match the result chain of the manifest", filename)
+	}
+	return m, true, nil
+}
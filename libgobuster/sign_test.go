@@ -0,0 +1,126 @@
+package libgobuster
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeKeys writes a new ed25519 key pair in the PEM files used by openssl
+func writeKeys(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	privateFile := filepath.Join(dir, "signing.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	der, err = x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	publicFile := filepath.Join(dir, "signing.pub")
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	return privateFile, publicFile
+}
+
+func TestSignedOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	privateFile, publicFile := writeKeys(t, dir)
+	signing, err := ParseOutputSigning(privateFile)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	publicKey, err := ParsePublicKey(publicFile)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	_, otherFile := writeKeys(t, t.TempDir())
+	otherKey, err := ParsePublicKey(otherFile)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	files := []string{filepath.Join(dir, "hits.json"), filepath.Join(dir, "hits.txt")}
+	outputs, err := OpenOutputs(&Options{Outputs: files, OutputSigning: signing})
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	for _, path := range []string{"/admin", "/backup"} {
+		record := ResultRecord{URL: "http://localhost" + path, Path: path, Found: true, StatusCode: 200, Timestamp: time.Now()}
+		if err := outputs.WriteResult(record, path+" (Status: 200)"); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+	if err := outputs.Close(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	m, chainChecked, err := VerifyOutput(files[0], publicKey)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !chainChecked || m.Results != 2 || m.File != "hits.json" || m.PublicKey != signing.PublicKey() {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+	if _, chainChecked, err := VerifyOutput(files[1], nil); err != nil || chainChecked {
+		t.Fatalf("expected the plain file to be verified without its chain, got %v", err)
+	}
+	if _, _, err := VerifyOutput(files[0], otherKey); err == nil {
+		t.Fatal("expected an error for a different key")
+	}
+
+	// dropping a result breaks the file hash
+	content, err := os.ReadFile(files[1])
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := os.WriteFile(files[1], []byte(strings.SplitAfter(string(content), "\n")[1]), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if _, _, err := VerifyOutput(files[1], publicKey); err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Fatalf("expected a hash mismatch, got %v", err)
+	}
+
+	// updating the manifest to the modified file breaks the signature
+	manifest, err := os.ReadFile(ManifestFile(files[0]))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := os.WriteFile(ManifestFile(files[0]), []byte(strings.Replace(string(manifest), `"results": 2`, `"results": 1`, 1)), 0o600); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if _, _, err := VerifyOutput(files[0], publicKey); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("expected an invalid signature, got %v", err)
+	}
+}
+
+func TestParseOutputSigningInvalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, publicFile := writeKeys(t, dir)
+	if _, err := ParseOutputSigning(publicFile); err == nil {
+		t.Fatal("expected an error for a public key")
+	}
+	if _, err := ParseOutputSigning(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,198 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// stateInterval is the interval the state of a run is checkpointed in
+const stateInterval = 10 * time.Second
+
+// RunState is checkpointed to the state file of a run, so the run can be
+// resumed after a crash or an abort
+type RunState struct {
+	Mode     string
+	Target   string
+	Wordlist string
+	// Args are the command line arguments of the run
+	Args []string
+	// Offset is the number of wordlist lines which are completely processed
+	Offset int
+	// TargetIndex is the index of the target of a run over multiple targets
+	// the offset belongs to, the targets before are completely processed
+	TargetIndex int `json:",omitempty"`
+	// Results contains the findings of the run so far
+	Results []StateResult
+	// StopReason is set if the run was stopped early
+	StopReason StopReason `json:",omitempty"`
+	Updated    time.Time
+}
+
+// StateResult is a single finding of a run
+type StateResult struct {
+	Record ResultRecord
+	// Line is the textual representation of the result
+	Line string
+}
+
+// Compatible returns an error if the state belongs to another run
+func (s RunState) Compatible(other RunState) error {
+	switch {
+	case s.Mode != other.Mode:
+		return fmt.Errorf("the state belongs to a run in %s mode", s.Mode)
+	case s.Target != other.Target:
+		return fmt.Errorf("the state belongs to a run against %s", s.Target)
+	case s.Wordlist != other.Wordlist:
+		return fmt.Errorf("the state belongs to a run with the wordlist %s", s.Wordlist)
+	}
+	return nil
+}
+
+// LoadState reads the state file. It returns nil if the file does not exist
+func LoadState(file string) (*RunState, error) {
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state RunState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", file, err)
+	}
+	return &state, nil
+}
+
+// SetState sets the state which is checkpointed to the state file
+func (g *Gobuster) SetState(state RunState) {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+	g.state = state
+}
+
+// AddStateResult adds a finding to the state
+func (g *Gobuster) AddStateResult(record ResultRecord, line string) {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+	g.state.Results = append(g.state.Results, StateResult{Record: record, Line: line})
+}
+
+// SetStopReason records why the run was stopped early in the state
+func (g *Gobuster) SetStopReason(reason StopReason) {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+	g.state.StopReason = reason
+}
+
+// SaveState writes the state with the current wordlist offset to the state
+// file. Words queued by the plugin are not part of the state
+func (g *Gobuster) SaveState() error {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+
+	g.state.Offset = g.lines.offset()
+	g.state.TargetIndex = g.target
+	// a target whose wordlist was sent completely is resumed with the next
+	if g.lineCount > 0 && g.state.Offset >= g.lineCount && g.target+1 < len(g.plugins) {
+		g.state.TargetIndex++
+		g.state.Offset = 0
+	}
+	g.state.Updated = time.Now()
+	content, err := json.Marshal(g.state)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(g.Opts.StateFile, content); err != nil {
+		return fmt.Errorf("could not write state file: %w", err)
+	}
+	return nil
+}
+
+// Completed returns true if the run processed the whole wordlist
+func (g *Gobuster) Completed() bool {
+	return g.completed.Load()
+}
+
+// startCheckpoints saves the state periodically until the returned function
+// is called
+func (g *Gobuster) startCheckpoints() func() {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tick := time.NewTicker(stateInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-tick.C:
+				if err := g.SaveState(); err != nil {
+					g.Progress.ErrorChan <- err
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		wg.Wait()
+	}
+}
+
+// lineTracker keeps track of the wordlist lines which are completely
+// processed. Words are processed out of order by the workers, so a line
+// is only done once all its words are processed
+type lineTracker struct {
+	mutex sync.Mutex
+	// pending holds the number of unprocessed words per line
+	pending map[int]int
+	// scanned is the number of lines all words were sent for
+	scanned int
+}
+
+// add marks a word of the line as sent
+func (t *lineTracker) add(line int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.pending == nil {
+		t.pending = make(map[int]int)
+	}
+	t.pending[line]++
+}
+
+// done marks a word of the line as processed
+func (t *lineTracker) done(line int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.pending[line]--
+	if t.pending[line] <= 0 {
+		delete(t.pending, line)
+	}
+}
+
+// setScanned sets the number of lines all words were sent for
+func (t *lineTracker) setScanned(lines int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.scanned = lines
+}
+
+// offset returns the number of lines from the start of the wordlist which
+// are completely processed
+func (t *lineTracker) offset() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	offset := t.scanned
+	for line := range t.pending {
+		if line < offset {
+			offset = line
+		}
+	}
+	return offset
+}
@@ -0,0 +1,83 @@
+package libgobuster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLineTracker(t *testing.T) {
+	t.Parallel()
+
+	var lines lineTracker
+	lines.setScanned(5)
+	lines.add(5)
+	lines.add(5)
+	lines.add(6)
+	lines.setScanned(7)
+	if got := lines.offset(); got != 5 {
+		t.Fatalf("expected offset 5, got %d", got)
+	}
+
+	// line 6 is done before line 5
+	lines.done(6)
+	lines.done(5)
+	if got := lines.offset(); got != 5 {
+		t.Fatalf("expected offset 5 while a word of line 5 is pending, got %d", got)
+	}
+	lines.done(5)
+	if got := lines.offset(); got != 7 {
+		t.Fatalf("expected offset 7, got %d", got)
+	}
+}
+
+func TestRunState(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	wordlist := filepath.Join(dir, "words")
+	if err := os.WriteFile(wordlist, []byte("found1\n# comment\nmissed\nfound2\n"), 0o600); err != nil {
+		t.Fatalf("could not write wordlist: %v", err)
+	}
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.Wordlist = wordlist
+	opts.WordlistOffset = 1
+	opts.CollectResults = true
+	opts.StateFile = filepath.Join(dir, "state.json")
+	g, err := NewGobuster(opts, collectPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	g.SetState(RunState{Mode: "collect", Wordlist: wordlist})
+	g.AddStateResult(ResultRecord{Path: "found1", Found: true}, "found1")
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if !g.Completed() {
+		t.Fatal("expected the run to be completed")
+	}
+	if err := g.SaveState(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	state, err := LoadState(opts.StateFile)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if state.Offset != 4 || len(state.Results) != 1 || state.Results[0].Line != "found1" {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	if err := state.Compatible(RunState{Mode: "collect", Wordlist: wordlist}); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := state.Compatible(RunState{Mode: "collect", Wordlist: "other.txt"}); err == nil {
+		t.Fatal("expected an error for a different wordlist")
+	}
+
+	if state, err := LoadState(filepath.Join(dir, "missing.json")); state != nil || err != nil {
+		t.Fatalf("expected no state for a missing file, got %v and %v", state, err)
+	}
+}
@@ -0,0 +1,155 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// statusInterval is the interval the status file is rewritten in
+const statusInterval = 5 * time.Second
+
+// States of a run in the status file
+const (
+	StatusRunning  = "running"
+	StatusFinished = "finished"
+	StatusStopped  = "stopped"
+	StatusFailed   = "failed"
+)
+
+// RunStatus is written to the status file so external supervisors can
+// monitor a run
+type RunStatus struct {
+	Mode          string     `json:"mode"`
+	Target        string     `json:"target,omitempty"`
+	State         string     `json:"state"`
+	StopReason    StopReason `json:"stop_reason,omitempty"`
+	RequestsDone  int        `json:"requests_done"`
+	RequestsTotal int        `json:"requests_total"`
+	// Rate is the number of requests per second
+	Rate float64 `json:"rate"`
+	// ETA is the estimated remaining time in seconds, 0 if unknown
+	ETA      int64     `json:"eta_seconds"`
+	Errors   int64     `json:"errors"`
+	Findings int64     `json:"findings"`
+	Started  time.Time `json:"started"`
+	Updated  time.Time `json:"updated"`
+	// Targets holds the progress of every target of a run over multiple
+	// targets
+	Targets []TargetProgress `json:"targets,omitempty"`
+}
+
+// StatusWriter rewrites the status file periodically with the progress of
+// the run. All methods can be called on a nil StatusWriter and do nothing
+type StatusWriter struct {
+	file     string
+	mode     string
+	target   string
+	progress *Progress
+	log      Logger
+	started  time.Time
+	errors   atomic.Int64
+	findings atomic.Int64
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewStatusWriter returns a StatusWriter for the progress of the run
+func NewStatusWriter(file, mode, target string, progress *Progress, log Logger) *StatusWriter {
+	return &StatusWriter{
+		file:     file,
+		mode:     mode,
+		target:   target,
+		progress: progress,
+		log:      log,
+		started:  time.Now(),
+		stop:     make(chan struct{}),
+	}
+}
+
+// AddError counts an error of the run
+func (w *StatusWriter) AddError() {
+	if w != nil {
+		w.errors.Add(1)
+	}
+}
+
+// AddFinding counts a finding of the run
+func (w *StatusWriter) AddFinding() {
+	if w != nil {
+		w.findings.Add(1)
+	}
+}
+
+// Status returns the current status of the run
+func (w *StatusWriter) Status(state string, reason StopReason) RunStatus {
+	return RunStatus{
+		Mode:          w.mode,
+		Target:        w.target,
+		State:         state,
+		StopReason:    reason,
+		RequestsDone:  w.progress.RequestsIssued(),
+		RequestsTotal: w.progress.RequestsExpected(),
+		Rate:          w.progress.Rate(),
+		ETA:           int64(w.progress.ETA().Seconds()),
+		Errors:        w.errors.Load(),
+		Findings:      w.findings.Load(),
+		Started:       w.started,
+		Updated:       time.Now(),
+		Targets:       w.progress.Targets(),
+	}
+}
+
+// write replaces the status file atomically, so readers never see a
+// partially written file
+func (w *StatusWriter) write(status RunStatus) error {
+	content, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(w.file, content); err != nil {
+		return fmt.Errorf("could not write status file: %w", err)
+	}
+	return nil
+}
+
+// Start writes the status file now and every statusInterval until Finish
+// is called. Errors of the periodic updates are logged as the run may
+// already be finished
+func (w *StatusWriter) Start() error {
+	if w == nil {
+		return nil
+	}
+	if err := w.write(w.Status(StatusRunning, StopNone)); err != nil {
+		return err
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		tick := time.NewTicker(statusInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-tick.C:
+				if err := w.write(w.Status(StatusRunning, StopNone)); err != nil {
+					w.log.Error(err.Error())
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Finish stops the periodic updates and writes the final status
+func (w *StatusWriter) Finish(state string, reason StopReason) error {
+	if w == nil {
+		return nil
+	}
+	close(w.stop)
+	w.wg.Wait()
+	return w.write(w.Status(state, reason))
+}
@@ -0,0 +1,47 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatusWriter(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "status.json")
+	progress := NewProgress()
+	progress.IncrementTotalRequests(10)
+	progress.incrementRequests()
+
+	w := NewStatusWriter(file, "dir", "http://example.com", progress, NewLogger(false))
+	if err := w.Start(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	w.AddFinding()
+	w.AddError()
+	w.AddError()
+	if err := w.Finish(StatusStopped, StopMaxTime); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	var status RunStatus
+	if err := json.Unmarshal(content, &status); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if status.State != StatusStopped || status.StopReason != StopMaxTime || status.RequestsDone != 1 || status.RequestsTotal != 10 || status.Findings != 1 || status.Errors != 2 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	// a nil writer is a noop
+	var disabled *StatusWriter
+	disabled.AddFinding()
+	if err := disabled.Start(); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package libgobuster
+
+import "fmt"
+
+// StopReason describes why a run ended before processing the whole wordlist
+type StopReason string
+
+const (
+	// StopNone is used for runs which were not stopped early
+	StopNone StopReason = ""
+	// StopInterrupted is used if the run was canceled, like by CTRL+C
+	StopInterrupted StopReason = "interrupted"
+	// StopFindings is used if the run was stopped by a finding limit or tag
+	StopFindings StopReason = "findings"
+	// StopMaxTime is used if the maximum run time was reached
+	StopMaxTime StopReason = "max-time"
+	// StopErrorBudget is used if the maximum number of errors was reached
+	StopErrorBudget StopReason = "error-budget"
+)
+
+// Exit codes of runs which were stopped early. Runs which failed with an
+// error exit with 1
+const (
+	ExitFindings    = 2
+	ExitMaxTime     = 3
+	ExitErrorBudget = 4
+	// ExitInterrupted follows the shell convention of 128 + SIGINT
+	ExitInterrupted = 130
+)
+
+// ExitCode returns the exit code for a run stopped for this reason
+func (r StopReason) ExitCode() int {
+	switch r {
+	case StopNone:
+		return 0
+	case StopFindings:
+		return ExitFindings
+	case StopMaxTime:
+		return ExitMaxTime
+	case StopErrorBudget:
+		return ExitErrorBudget
+	case StopInterrupted:
+		return ExitInterrupted
+	default:
+		return 1
+	}
+}
+
+// StoppedError is returned if a run was stopped early
+type StoppedError struct {
+	Reason StopReason
+	// Detail is the human readable cause
+	Detail string
+}
+
+// Error is the implementation of the error interface
+func (e *StoppedError) Error() string {
+	return fmt.Sprintf("stopped early (%s): %s", e.Reason, e.Detail)
+}
@@ -0,0 +1,47 @@
+package libgobuster
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStopReasonExitCode(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		reason   StopReason
+		expected int
+	}{
+		{StopNone, 0},
+		{StopFindings, ExitFindings},
+		{StopMaxTime, ExitMaxTime},
+		{StopErrorBudget, ExitErrorBudget},
+		{StopInterrupted, ExitInterrupted},
+		{StopReason("unknown"), 1},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(string(x.reason), func(t *testing.T) {
+			t.Parallel()
+			if got := x.reason.ExitCode(); got != x.expected {
+				t.Fatalf("expected exit code %d, got %d", x.expected, got)
+			}
+		})
+	}
+}
+
+func TestStoppedError(t *testing.T) {
+	t.Parallel()
+
+	// the stop reason survives wrapping by the commands
+	err := fmt.Errorf("error on running gobuster: %w", &StoppedError{Reason: StopMaxTime, Detail: "Reached the maximum time of 1m0s"})
+	var stopped *StoppedError
+	if !errors.As(err, &stopped) || stopped.Reason != StopMaxTime {
+		t.Fatalf("expected a max-time stop, got %v", err)
+	}
+	if got := stopped.Reason.ExitCode(); got != ExitMaxTime {
+		t.Fatalf("expected exit code %d, got %d", ExitMaxTime, got)
+	}
+}
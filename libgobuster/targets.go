@@ -0,0 +1,33 @@
+package libgobuster
+
+// setTarget makes the target with the index the current one and sets the
+// processed lines of its wordlist. It must only be called while no worker
+// is busy
+func (g *Gobuster) setTarget(index, offset int) {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+	g.target = index
+	g.plugin = g.plugins[index]
+	g.lines.setScanned(offset)
+	g.Progress.setTarget(index)
+}
+
+// targetRequests returns the expected requests of a single target
+func (g *Gobuster) targetRequests() int {
+	g.stateMutex.Lock()
+	lines := g.lineCount
+	g.stateMutex.Unlock()
+	return g.wordlistLines(lines) * g.requestsPerWord() * g.combinations()
+}
+
+// TargetNames returns the targets of the plugins. The target of plugins not
+// implementing TargetPlugin is empty
+func TargetNames(plugins []GobusterPlugin) []string {
+	names := make([]string, len(plugins))
+	for i, p := range plugins {
+		if t, ok := p.(TargetPlugin); ok {
+			names[i] = t.Target()
+		}
+	}
+	return names
+}
@@ -0,0 +1,178 @@
+package libgobuster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// targetPlugin reports every word prefixed with its target. The results are
+// enriched with the target too, so results enriched by the plugin of another
+// target are noticed
+type targetPlugin struct {
+	collectPlugin
+	target  string
+	failing bool
+}
+
+func (p targetPlugin) Target() string { return p.target }
+
+func (p targetPlugin) PreRun(context.Context, *Progress) error {
+	if p.failing {
+		return fmt.Errorf("%s is not reachable", p.target)
+	}
+	return nil
+}
+
+func (p targetPlugin) ProcessWord(_ context.Context, word string, progress *Progress) error {
+	progress.EnrichChan <- collectResult{word: p.target + "/" + word}
+	return nil
+}
+
+func (p targetPlugin) Enrich(_ context.Context, r Result) (Result, error) {
+	result := r.(collectResult)
+	result.word += " by " + p.target
+	return result, nil
+}
+
+func TestRunTargets(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp("", "wordlist")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	if _, err := f.WriteString("x\ny\n"); err != nil {
+		t.Fatalf("could not write tempfile: %v", err)
+	}
+	f.Close()
+
+	tt := []struct {
+		testName       string
+		failing        string
+		targetOffset   int
+		expected       []string
+		expectedErrors int
+	}{
+		{"All targets", "", 0, []string{"a/x by a", "a/y by a", "b/x by b", "b/y by b", "c/x by c", "c/y by c"}, 0},
+		{"Failing target is skipped", "b", 0, []string{"a/x by a", "a/y by a", "c/x by c", "c/y by c"}, 1},
+		{"Failing first target is skipped", "a", 0, []string{"b/x by b", "b/y by b", "c/x by c", "c/y by c"}, 1},
+		{"Resumed from the second target", "", 1, []string{"b/x by b", "b/y by b", "c/x by c", "c/y by c"}, 0},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var plugins []GobusterPlugin
+			for _, target := range []string{"a", "b", "c"} {
+				plugins = append(plugins, targetPlugin{target: target, failing: target == x.failing})
+			}
+
+			opts := NewOptions()
+			opts.Threads = 3
+			opts.Wordlist = f.Name()
+			opts.CollectResults = true
+			opts.TargetOffset = x.targetOffset
+			g, err := NewGobusterTargets(opts, plugins, NewLogger(false))
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if err := g.Run(context.Background()); err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+
+			var words []string
+			for _, r := range g.CollectedResults() {
+				words = append(words, r.(collectResult).word)
+			}
+			sort.Strings(words)
+			if strings.Join(words, ",") != strings.Join(x.expected, ",") {
+				t.Fatalf("Expected %v but got %v", x.expected, words)
+			}
+			if errs := g.CollectedErrors(); len(errs) != x.expectedErrors {
+				t.Fatalf("Expected %d errors but got %v", x.expectedErrors, errs)
+			}
+			// skipped targets are not part of the expected requests
+			scanned := len(x.expected) / 2
+			if expected := g.Progress.RequestsExpected(); expected != scanned*g.targetRequests() {
+				t.Fatalf("Expected the requests of %d targets but got %d", scanned, expected)
+			}
+			if index, name, count := g.Progress.Target(); index != 2 || name != "c" || count != 3 {
+				t.Fatalf("Expected the last target but got %d %s %d", index, name, count)
+			}
+			for i, target := range g.Progress.Targets() {
+				// both words of the wordlist are requested
+				issued, expected := 2, g.targetRequests()
+				state := TargetDone
+				switch {
+				case target.Name == x.failing:
+					issued, expected, state = 0, 0, TargetSkipped
+				case i < x.targetOffset:
+					// scanned by the resumed run
+					issued, expected = 0, 0
+				}
+				if target.State != state || target.RequestsIssued != issued || target.RequestsExpected != expected {
+					t.Fatalf("Expected %s with %d / %d requests but got %+v", state, issued, expected, target)
+				}
+			}
+		})
+	}
+}
+
+func TestNewGobusterTargetsStdin(t *testing.T) {
+	t.Parallel()
+
+	opts := NewOptions()
+	opts.Wordlist = "-"
+	if _, err := NewGobusterTargets(opts, []GobusterPlugin{targetPlugin{target: "a"}, targetPlugin{target: "b"}}, NewLogger(false)); err == nil {
+		t.Fatal("expected an error for multiple targets reading the wordlist from stdin")
+	}
+	if _, err := NewGobusterTargets(opts, []GobusterPlugin{targetPlugin{target: "a"}}, NewLogger(false)); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+}
+
+func TestSaveStateTargets(t *testing.T) {
+	t.Parallel()
+
+	opts := NewOptions()
+	opts.StateFile = filepath.Join(t.TempDir(), "state.json")
+	g, err := NewGobusterTargets(opts, []GobusterPlugin{targetPlugin{target: "a"}, targetPlugin{target: "b"}}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	g.lineCount = 4
+
+	tt := []struct {
+		target         int
+		offset         int
+		expectedTarget int
+		expectedOffset int
+	}{
+		{0, 2, 0, 2},
+		// the wordlist of the first target was sent completely
+		{0, 4, 1, 0},
+		{1, 3, 1, 3},
+		{1, 4, 1, 4},
+	}
+	for _, x := range tt {
+		g.setTarget(x.target, x.offset)
+		if err := g.SaveState(); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		state, err := LoadState(opts.StateFile)
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if state.TargetIndex != x.expectedTarget || state.Offset != x.expectedOffset {
+			t.Fatalf("Expected target %d offset %d but got target %d offset %d", x.expectedTarget, x.expectedOffset, state.TargetIndex, state.Offset)
+		}
+	}
+}
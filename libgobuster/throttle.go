@@ -0,0 +1,268 @@
+package libgobuster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// throttleBatches is the number of batches the limit of a second is split
+// into when reserving requests from the rate file, so the file is not read and
+// written for every request
+const throttleBatches = 10
+
+// Throttle limits the number of requests per second to a host. Without a
+// rate file every host has a token bucket holding a single token which is
+// refilled limit times per second, so the requests are evenly spaced. If a
+// rate file is set the budget is shared with all processes using the same
+// file, for example other tools scanning the same target at the same time.
+//
+// The rate file contains JSON like
+//
+//	{"hosts": {"example.com": {"limit": 50, "window": 1700000000, "count": 12}}}
+//
+// where window is the current second as unix timestamp and count the number
+// of requests sent in this second. The lowest limit of all participants wins.
+// Participants need to hold an exclusive lock (flock on unix, LockFileEx on
+// windows) of the lock file, the rate file with a .lock suffix, while updating
+// the file. Requests are reserved in batches of a tenth of the limit, the
+// reserved requests are sent without accessing the file again until the
+// second is over.
+type Throttle struct {
+	Limit int
+	File  string
+	mutex sync.Mutex
+	// buckets are only used without a rate file
+	buckets map[string]*tokenBucket
+	// reserved holds the requests reserved from the rate file
+	reserved map[string]*throttleReservation
+}
+
+// throttleReservation holds the requests to a host reserved for the window
+type throttleReservation struct {
+	window int64
+	count  int
+}
+
+// tokenBucket holds the tokens of a single host
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type throttleState struct {
+	Hosts map[string]*throttleHost `json:"hosts"`
+}
+
+type throttleHost struct {
+	Limit  int   `json:"limit"`
+	Window int64 `json:"window"`
+	Count  int   `json:"count"`
+}
+
+// NewThrottle returns a new Throttle allowing limit requests per second and
+// host. The budget is shared using the file if it is not empty
+func NewThrottle(limit int, file string) *Throttle {
+	return &Throttle{
+		Limit:    limit,
+		File:     file,
+		buckets:  make(map[string]*tokenBucket),
+		reserved: make(map[string]*throttleReservation),
+	}
+}
+
+// String returns the limit and the rate file
+func (t *Throttle) String() string {
+	if t.File == "" {
+		return fmt.Sprintf("%d requests/s per host", t.Limit)
+	}
+	return fmt.Sprintf("%d requests/s per host (shared via %s)", t.Limit, t.File)
+}
+
+// Wait blocks until a request to the host is allowed or the context is done
+func (t *Throttle) Wait(ctx context.Context, host string) error {
+	if t == nil {
+		return nil
+	}
+
+	for {
+		wait, err := t.take(ctx, host, time.Now())
+		if err != nil {
+			return err
+		}
+		if wait == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// take tries to take a request from the budget of the host. It returns how
+// long to wait before trying again, 0 if the request is allowed
+func (t *Throttle) take(ctx context.Context, host string, now time.Time) (time.Duration, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.File == "" {
+		return t.takeToken(host, now), nil
+	}
+
+	window := now.Unix()
+	if r, ok := t.reserved[host]; ok && r.window == window && r.count > 0 {
+		r.count--
+		return 0, nil
+	}
+
+	unlock, err := lockFile(ctx, t.File+".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	state, err := readThrottleState(t.File)
+	if err != nil {
+		return 0, err
+	}
+	reserved, wait := state.reserve(host, t.Limit, throttleBatch(t.Limit), now)
+	if err := writeThrottleState(t.File, state); err != nil {
+		return 0, err
+	}
+	if reserved > 0 {
+		t.reserved[host] = &throttleReservation{window: window, count: reserved - 1}
+	}
+	return wait, nil
+}
+
+// throttleBatch returns the number of requests reserved from the rate file at
+// once
+func throttleBatch(limit int) int {
+	if batch := limit / throttleBatches; batch > 1 {
+		return batch
+	}
+	return 1
+}
+
+// takeToken takes a token from the bucket of the host and returns how long
+// to wait for the next token if the bucket is empty
+func (t *Throttle) takeToken(host string, now time.Time) time.Duration {
+	b, ok := t.buckets[host]
+	if !ok {
+		b = &tokenBucket{tokens: 1, last: now}
+		t.buckets[host] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * float64(t.Limit)
+	if b.tokens > 1 {
+		b.tokens = 1
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / float64(t.Limit) * float64(time.Second))
+}
+
+// reserve reserves up to batch requests to the host in the current window.
+// It returns the number of reserved requests and if there are none how long
+// to wait for the next window
+func (s *throttleState) reserve(host string, limit, batch int, now time.Time) (int, time.Duration) {
+	h, ok := s.Hosts[host]
+	if !ok {
+		h = &throttleHost{}
+		s.Hosts[host] = h
+	}
+	if h.Limit <= 0 || limit < h.Limit {
+		h.Limit = limit
+	}
+
+	window := now.Unix()
+	if h.Window != window {
+		h.Window = window
+		h.Count = 0
+	}
+	if h.Count < h.Limit {
+		reserved := h.Limit - h.Count
+		if reserved > batch {
+			reserved = batch
+		}
+		h.Count += reserved
+		return reserved, 0
+	}
+	// wait until the next window starts
+	return 0, time.Unix(window+1, 0).Sub(now)
+}
+
+func readThrottleState(file string) (throttleState, error) {
+	state := throttleState{Hosts: make(map[string]*throttleHost)}
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(content) == 0) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("could not read rate file: %w", err)
+	}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return state, fmt.Errorf("invalid rate file %s: %w", file, err)
+	}
+	if state.Hosts == nil {
+		state.Hosts = make(map[string]*throttleHost)
+	}
+	return state, nil
+}
+
+// writeThrottleState replaces the file so readers never see a partial state
+func writeThrottleState(file string, state throttleState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(file, content); err != nil {
+		return fmt.Errorf("could not write rate file: %w", err)
+	}
+	return nil
+}
+
+// lockFile locks the lock file exclusively and returns a function releasing
+// the lock. The lock file is kept, a lock left behind by a crashed process is
+// released by the system
+func lockFile(ctx context.Context, lock string) (func(), error) {
+	f, err := os.OpenFile(lock, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the lock of the rate file: %w", err)
+	}
+
+	locked := make(chan error, 1)
+	go func() {
+		locked <- lockExclusive(f)
+	}()
+
+	select {
+	case err := <-locked:
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("could not lock rate file: %w", err)
+		}
+		return func() {
+			_ = unlockFile(f)
+			f.Close()
+		}, nil
+	case <-ctx.Done():
+		// release the lock as soon as the pending call acquired it
+		go func() {
+			if err := <-locked; err == nil {
+				_ = unlockFile(f)
+			}
+			f.Close()
+		}()
+		return nil, ctx.Err()
+	}
+}
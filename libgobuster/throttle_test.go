@@ -0,0 +1,149 @@
+package libgobuster
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThrottleTake(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	throttle := NewThrottle(2, "")
+	now := time.Unix(1700000000, 0)
+
+	if wait, err := throttle.take(ctx, "example.com", now); err != nil || wait != 0 {
+		t.Fatalf("expected the first request to be allowed, got %s %v", wait, err)
+	}
+	// the requests are evenly spaced instead of sent in a burst
+	if wait, _ := throttle.take(ctx, "example.com", now); wait != 500*time.Millisecond {
+		t.Fatalf("expected to wait for the next token, got %s", wait)
+	}
+	if wait, _ := throttle.take(ctx, "example.com", now.Add(250*time.Millisecond)); wait != 250*time.Millisecond {
+		t.Fatalf("expected to wait for the rest of the next token, got %s", wait)
+	}
+	if wait, _ := throttle.take(ctx, "other.com", now); wait != 0 {
+		t.Fatalf("expected other hosts to have their own budget, got %s", wait)
+	}
+	if wait, _ := throttle.take(ctx, "example.com", now.Add(500*time.Millisecond)); wait != 0 {
+		t.Fatalf("expected the token to be refilled, got %s", wait)
+	}
+	// idle time does not allow a burst
+	if wait, _ := throttle.take(ctx, "example.com", now.Add(10*time.Second)); wait != 0 {
+		t.Fatalf("expected the token to be refilled, got %s", wait)
+	}
+	if wait, _ := throttle.take(ctx, "example.com", now.Add(10*time.Second)); wait != 500*time.Millisecond {
+		t.Fatalf("expected the bucket to hold a single token, got %s", wait)
+	}
+}
+
+func TestThrottleSharedFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "rate.json")
+	first := NewThrottle(3, file)
+	// the lowest limit of all participants wins
+	second := NewThrottle(2, file)
+	now := time.Unix(1700000000, 0)
+
+	if wait, err := first.take(ctx, "example.com", now); err != nil || wait != 0 {
+		t.Fatalf("expected the request to be allowed, got %s %v", wait, err)
+	}
+	if wait, err := second.take(ctx, "example.com", now); err != nil || wait != 0 {
+		t.Fatalf("expected the request to be allowed, got %s %v", wait, err)
+	}
+	if wait, err := first.take(ctx, "example.com", now); err != nil || wait == 0 {
+		t.Fatalf("expected the shared budget to be used up, got %s %v", wait, err)
+	}
+
+	// the lock is released
+	lockCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	unlock, err := lockFile(lockCtx, file+".lock")
+	if err != nil {
+		t.Fatalf("expected the lock to be released, got %v", err)
+	}
+	unlock()
+}
+
+func TestThrottleSharedFileBatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "rate.json")
+	first := NewThrottle(20, file)
+	second := NewThrottle(20, file)
+	now := time.Unix(1700000000, 0)
+
+	// every file access reserves a tenth of the limit
+	for i := 0; i < 2; i++ {
+		if wait, err := first.take(ctx, "example.com", now); err != nil || wait != 0 {
+			t.Fatalf("expected the request to be allowed, got %s %v", wait, err)
+		}
+	}
+	state, err := readThrottleState(file)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if count := state.Hosts["example.com"].Count; count != 2 {
+		t.Fatalf("expected 2 reserved requests, got %d", count)
+	}
+
+	// the other participant can only reserve the rest of the budget
+	for i := 0; i < 18; i++ {
+		if wait, err := second.take(ctx, "example.com", now); err != nil || wait != 0 {
+			t.Fatalf("expected request %d to be allowed, got %s %v", i, wait, err)
+		}
+	}
+	if wait, err := second.take(ctx, "example.com", now); err != nil || wait == 0 {
+		t.Fatalf("expected the shared budget to be used up, got %s %v", wait, err)
+	}
+	if wait, err := first.take(ctx, "example.com", now); err != nil || wait == 0 {
+		t.Fatalf("expected the shared budget to be used up, got %s %v", wait, err)
+	}
+
+	// reservations expire with the window
+	if wait, err := first.take(ctx, "example.com", now.Add(time.Second)); err != nil || wait != 0 {
+		t.Fatalf("expected the request to be allowed in the next window, got %s %v", wait, err)
+	}
+}
+
+func TestThrottleLockCanceled(t *testing.T) {
+	t.Parallel()
+
+	lock := filepath.Join(t.TempDir(), "rate.json.lock")
+	unlock, err := lockFile(context.Background(), lock)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	defer unlock()
+
+	// a held lock does not block a canceled caller
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := lockFile(ctx, lock); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the lock to return after the deadline, took %s", elapsed)
+	}
+}
+
+func TestThrottleWaitCanceled(t *testing.T) {
+	t.Parallel()
+
+	throttle := NewThrottle(1, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := throttle.Wait(ctx, "example.com"); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	cancel()
+	if err := throttle.Wait(ctx, "example.com"); err == nil {
+		t.Fatal("expected an error for a canceled context")
+	}
+}
@@ -0,0 +1,215 @@
+package libgobuster
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CertificateProblem is the category of a failed certificate verification
+type CertificateProblem string
+
+const (
+	// CertificateExpired is a certificate in the chain which is expired or
+	// not yet valid
+	CertificateExpired CertificateProblem = "expired"
+	// CertificateSelfSigned is a leaf certificate signed by itself
+	CertificateSelfSigned CertificateProblem = "self-signed"
+	// CertificateUnknownAuthority is a chain which does not end in a
+	// trusted root
+	CertificateUnknownAuthority CertificateProblem = "unknown authority"
+	// CertificateHostnameMismatch is a certificate issued for other names
+	CertificateHostnameMismatch CertificateProblem = "hostname mismatch"
+	// CertificateInvalid is every other verification failure
+	CertificateInvalid CertificateProblem = "invalid"
+)
+
+// TLSPolicy relaxes the certificate verification for single categories of
+// problems, all other problems still fail the connection. Use
+// NoTLSValidation to skip the verification completely
+type TLSPolicy struct {
+	AllowExpired          bool
+	AllowSelfSigned       bool
+	AllowHostnameMismatch bool
+}
+
+// Relaxed returns true if any problem is allowed
+func (p TLSPolicy) Relaxed() bool {
+	return p.AllowExpired || p.AllowSelfSigned || p.AllowHostnameMismatch
+}
+
+func (p TLSPolicy) allows(problem CertificateProblem) bool {
+	switch problem {
+	case CertificateExpired:
+		return p.AllowExpired
+	case CertificateSelfSigned:
+		return p.AllowSelfSigned
+	case CertificateHostnameMismatch:
+		return p.AllowHostnameMismatch
+	}
+	return false
+}
+
+// CertificateError is returned for requests failing the verification of the
+// certificate of the host
+type CertificateError struct {
+	Host    string
+	Problem CertificateProblem
+	Err     error
+}
+
+func (e *CertificateError) Error() string {
+	return fmt.Sprintf("invalid certificate of %s (%s): %v", e.Host, e.Problem, e.Err)
+}
+
+func (e *CertificateError) Unwrap() error {
+	return e.Err
+}
+
+// classifyCertificateError returns the problem of a failed verification by
+// the standard library
+func classifyCertificateError(err error) (CertificateProblem, bool) {
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &hostnameErr):
+		return CertificateHostnameMismatch, true
+	case errors.As(err, &authorityErr):
+		if authorityErr.Cert != nil && isSelfSigned(authorityErr.Cert) {
+			return CertificateSelfSigned, true
+		}
+		return CertificateUnknownAuthority, true
+	case errors.As(err, &invalidErr):
+		if invalidErr.Reason == x509.Expired {
+			return CertificateExpired, true
+		}
+		return CertificateInvalid, true
+	}
+	return "", false
+}
+
+// isSelfSigned checks if the certificate is signed by its own key
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		return false
+	}
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// certificateProblem is a problem found by certificateProblems with the
+// error describing it
+type certificateProblem struct {
+	problem CertificateProblem
+	err     error
+}
+
+// certificateProblems verifies the certificates of the connection to host
+// against roots, or the system roots if nil, and returns all problems found.
+// An expired leaf is verified at a time it was valid, so the problems of the
+// rest of the chain are still found. host is the name or ip address which
+// was dialed, ip addresses are not sent as server name
+func certificateProblems(cs tls.ConnectionState, host string, roots *x509.CertPool, now time.Time) []certificateProblem {
+	if len(cs.PeerCertificates) == 0 {
+		return []certificateProblem{{CertificateInvalid, errors.New("no certificate sent")}}
+	}
+	leaf := cs.PeerCertificates[0]
+
+	var problems []certificateProblem
+	verifyTime := now
+	switch {
+	case now.After(leaf.NotAfter):
+		problems = append(problems, certificateProblem{CertificateExpired, fmt.Errorf("certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))})
+		verifyTime = leaf.NotAfter
+	case now.Before(leaf.NotBefore):
+		problems = append(problems, certificateProblem{CertificateExpired, fmt.Errorf("certificate is not valid before %s", leaf.NotBefore.Format(time.RFC3339))})
+		verifyTime = leaf.NotBefore
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   verifyTime,
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(opts); err != nil {
+		problem, ok := classifyCertificateError(err)
+		if !ok {
+			problem = CertificateInvalid
+		}
+		problems = append(problems, certificateProblem{problem, err})
+	}
+
+	if err := leaf.VerifyHostname(host); err != nil {
+		problems = append(problems, certificateProblem{CertificateHostnameMismatch, err})
+	}
+	return problems
+}
+
+// verifyConnection returns the verification of the connections of a client
+// to host with a relaxed policy. Allowed problems are recorded in stats, the
+// first problem not allowed fails the connection
+func (p TLSPolicy) verifyConnection(host string, roots *x509.CertPool, stats *ConnStats) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		for _, found := range certificateProblems(cs, host, roots, time.Now()) {
+			if !p.allows(found.problem) {
+				return &CertificateError{Host: host, Problem: found.problem, Err: found.err}
+			}
+			stats.addCertificateProblem(host, found.problem, true)
+		}
+		return nil
+	}
+}
+
+// policyTransport verifies the certificates of https requests with a relaxed
+// policy. The connection state lacks the dialed host for ip addresses, as
+// they are not sent as server name, so every host gets its own transport
+// verifying against it
+type policyTransport struct {
+	base   *http.Transport
+	policy TLSPolicy
+	stats  *ConnStats
+	mutex  sync.Mutex
+	hosts  map[string]*http.Transport
+}
+
+func newPolicyTransport(base *http.Transport, policy TLSPolicy, stats *ConnStats) *policyTransport {
+	return &policyTransport{base: base, policy: policy, stats: stats, hosts: make(map[string]*http.Transport)}
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return t.base.RoundTrip(req)
+	}
+	return t.transport(req.URL.Hostname()).RoundTrip(req)
+}
+
+// transport returns the transport of the host
+func (t *policyTransport) transport(host string) *http.Transport {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if tr, ok := t.hosts[host]; ok {
+		return tr
+	}
+	tr := t.base.Clone()
+	tr.TLSClientConfig.VerifyConnection = t.policy.verifyConnection(host, nil, t.stats)
+	t.hosts[host] = tr
+	return tr
+}
+
+// CloseIdleConnections closes the idle connections of all hosts
+func (t *policyTransport) CloseIdleConnections() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.base.CloseIdleConnections()
+	for _, tr := range t.hosts {
+		tr.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,162 @@
+package libgobuster
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate creates a certificate signed by parent, or by itself if
+// parent is nil
+func testCertificate(t *testing.T, name string, notBefore, notAfter time.Time, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if !isCA {
+		template.DNSNames = []string{name}
+	}
+	if parent == nil {
+		parent, parentKey = &template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("could not parse certificate: %v", err)
+	}
+	return cert, key
+}
+
+func TestCertificateProblems(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	valid := [2]time.Time{now.Add(-time.Hour), now.Add(time.Hour)}
+	expired := [2]time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)}
+
+	ca, caKey := testCertificate(t, "Test CA", valid[0], valid[1], true, nil, nil)
+	otherCA, otherKey := testCertificate(t, "Other CA", valid[0], valid[1], true, nil, nil)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	leaf, _ := testCertificate(t, "www.example.com", valid[0], valid[1], false, ca, caKey)
+	expiredLeaf, _ := testCertificate(t, "www.example.com", expired[0], expired[1], false, ca, caKey)
+	selfSigned, _ := testCertificate(t, "www.example.com", valid[0], valid[1], false, nil, nil)
+	unknown, _ := testCertificate(t, "www.example.com", valid[0], valid[1], false, otherCA, otherKey)
+
+	tt := []struct {
+		testName string
+		cert     *x509.Certificate
+		host     string
+		expected []CertificateProblem
+	}{
+		{"Valid", leaf, "www.example.com", nil},
+		{"Expired", expiredLeaf, "www.example.com", []CertificateProblem{CertificateExpired}},
+		{"Self-signed", selfSigned, "www.example.com", []CertificateProblem{CertificateSelfSigned}},
+		{"Unknown authority", unknown, "www.example.com", []CertificateProblem{CertificateUnknownAuthority}},
+		{"Hostname mismatch", leaf, "mail.example.com", []CertificateProblem{CertificateHostnameMismatch}},
+		{"Expired and hostname mismatch", expiredLeaf, "mail.example.com", []CertificateProblem{CertificateExpired, CertificateHostnameMismatch}},
+		// ip addresses are not sent as server name but still verified
+		{"IP address", leaf, "127.0.0.1", []CertificateProblem{CertificateHostnameMismatch}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			cs := tls.ConnectionState{PeerCertificates: []*x509.Certificate{x.cert}}
+			var found []CertificateProblem
+			for _, p := range certificateProblems(cs, x.host, roots, now) {
+				found = append(found, p.problem)
+			}
+			if fmt.Sprint(found) != fmt.Sprint(x.expected) {
+				t.Fatalf("expected %v, got %v", x.expected, found)
+			}
+		})
+	}
+}
+
+func TestTLSPolicyRequest(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "test")
+	})
+	// the certificate of the test server is self-signed and valid for
+	// 127.0.0.1
+	ts := httptest.NewTLSServer(handler)
+	t.Cleanup(ts.Close)
+
+	// the certificate of this one is self-signed for www.example.com only
+	cert, key := testCertificate(t, "www.example.com", time.Now().Add(-time.Hour), time.Now().Add(time.Hour), false, nil, nil)
+	mismatch := httptest.NewUnstartedServer(handler)
+	mismatch.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key}}}
+	mismatch.StartTLS()
+	t.Cleanup(mismatch.Close)
+
+	tt := []struct {
+		testName string
+		url      string
+		policy   TLSPolicy
+		wantErr  CertificateProblem
+		expected string
+	}{
+		{"Verified", ts.URL, TLSPolicy{}, CertificateSelfSigned, "127.0.0.1: self-signed"},
+		{"Other problem allowed", ts.URL, TLSPolicy{AllowExpired: true}, CertificateSelfSigned, "127.0.0.1: self-signed"},
+		{"Self-signed allowed", ts.URL, TLSPolicy{AllowSelfSigned: true}, "", "127.0.0.1: self-signed (allowed)"},
+		// ip addresses are not sent as server name, the mismatch is still found
+		{"Hostname mismatch", mismatch.URL, TLSPolicy{AllowSelfSigned: true}, CertificateHostnameMismatch, "127.0.0.1: hostname mismatch, self-signed (allowed)"},
+		{"Hostname mismatch allowed", mismatch.URL, TLSPolicy{AllowSelfSigned: true, AllowHostnameMismatch: true}, "", "127.0.0.1: hostname mismatch (allowed), self-signed (allowed)"},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			stats := NewConnStats()
+			c, err := NewHTTPClient(&HTTPOptions{BasicHTTPOptions: BasicHTTPOptions{TLSPolicy: x.policy, Timeout: 5 * time.Second}, ConnStats: stats})
+			if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			_, _, _, _, err = c.Request(context.Background(), x.url, RequestOptions{})
+			if x.wantErr != "" {
+				var certErr *CertificateError
+				if !errors.As(err, &certErr) || certErr.Problem != x.wantErr || certErr.Host != "127.0.0.1" {
+					t.Fatalf("expected a %s certificate error, got %v", x.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Got error: %v", err)
+			}
+			if problems := strings.Join(stats.CertificateProblems(), "; "); problems != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package libgobuster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PathNode is a single path segment of the tree of findings
+type PathNode struct {
+	Name string
+	// Record is nil for segments which were not found themselves but only
+	// contain findings
+	Record   *ResultRecord
+	Children []*PathNode
+	dir      bool
+}
+
+// BuildPathTree arranges the paths of the records as a tree. The returned
+// node is the root of the host
+func BuildPathTree(records []ResultRecord) *PathNode {
+	root := &PathNode{Name: "/", dir: true}
+	for i := range records {
+		path := strings.TrimPrefix(records[i].Path, "/")
+		if path == "" {
+			root.Record = &records[i]
+			continue
+		}
+		node := root
+		segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+		for _, segment := range segments {
+			node.dir = true
+			node = node.child(segment)
+		}
+		if strings.HasSuffix(path, "/") {
+			node.dir = true
+		}
+		if node.Record == nil {
+			node.Record = &records[i]
+		}
+	}
+	root.sort()
+	return root
+}
+
+func (n *PathNode) child(name string) *PathNode {
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	c := &PathNode{Name: name}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+func (n *PathNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// Lines renders the tree with two spaces of indentation per level
+func (n *PathNode) Lines() []string {
+	var lines []string
+	n.render(0, &lines)
+	return lines
+}
+
+func (n *PathNode) render(depth int, lines *[]string) {
+	name := n.Name
+	if n.dir && name != "/" {
+		name = fmt.Sprintf("%s/", name)
+	}
+	line := fmt.Sprintf("%s%s", strings.Repeat("  ", depth), name)
+	if n.Record != nil && n.Record.StatusCode > 0 {
+		line = fmt.Sprintf("%s (Status: %d) [Size: %d]", line, n.Record.StatusCode, n.Record.Size)
+	}
+	*lines = append(*lines, line)
+	for _, c := range n.Children {
+		c.render(depth+1, lines)
+	}
+}
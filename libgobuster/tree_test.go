@@ -0,0 +1,32 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildPathTree(t *testing.T) {
+	t.Parallel()
+
+	records := []ResultRecord{
+		{Path: "/api/v1/users", StatusCode: 200, Size: 12},
+		{Path: "/admin/", StatusCode: 301, Size: 0},
+		{Path: "/admin/users", StatusCode: 403, Size: 5},
+		{Path: "/api/v1/", StatusCode: 200, Size: 3},
+		{Path: "/robots.txt", StatusCode: 200, Size: 20},
+	}
+	want := []string{
+		"/",
+		"  admin/ (Status: 301) [Size: 0]",
+		"    users (Status: 403) [Size: 5]",
+		"  api/",
+		"    v1/ (Status: 200) [Size: 3]",
+		"      users (Status: 200) [Size: 12]",
+		"  robots.txt (Status: 200) [Size: 20]",
+	}
+
+	got := BuildPathTree(records).Lines()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tree\ngot:  %q\nwant: %q", got, want)
+	}
+}
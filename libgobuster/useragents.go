@@ -1,10 +1,5 @@
 package libgobuster
 
-import (
-	"crypto/rand"
-	"math/big"
-)
-
 // molint:gochecknoglobals
 var userAgents = [...]string{
 	"Mozilla/5.0 (X11; Linux i686; rv:64.0) Gecko/20100101 Firefox/64.0",
@@ -4668,9 +4663,5 @@ var userAgents = [...]string{
 
 // GetRandomUserAgent picks a random user agent from a predefined list
 func GetRandomUserAgent() (string, error) {
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userAgents))))
-	if err != nil {
-		return "", err
-	}
-	return userAgents[n.Int64()], err
+	return userAgents[RandomIntn(len(userAgents))], nil
 }
@@ -0,0 +1,216 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// maxThreads is the maximum number of threads which still makes sense
+const maxThreads = 10000
+
+// ValidationProblem describes a single invalid option
+type ValidationProblem struct {
+	Option     string
+	Problem    string
+	Suggestion string
+}
+
+func (p ValidationProblem) String() string {
+	s := fmt.Sprintf("%s: %s", p.Option, p.Problem)
+	if p.Suggestion != "" {
+		s = fmt.Sprintf("%s (%s)", s, p.Suggestion)
+	}
+	return s
+}
+
+// ValidationError holds all problems found while validating the options
+type ValidationError struct {
+	Mode     string
+	Problems []ValidationProblem
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "found %d invalid options for %s mode", len(e.Problems), e.Mode)
+	for _, p := range e.Problems {
+		fmt.Fprintf(&sb, "\n  - %s", p)
+	}
+	return sb.String()
+}
+
+// Validator is implemented by plugin options which can validate themselves
+type Validator interface {
+	ValidationProblems() []ValidationProblem
+}
+
+// Validate checks the options and the given plugin options for the mode and
+// returns all problems at once as a *ValidationError
+func (opt *Options) Validate(mode string, pluginOpts ...Validator) error {
+	var problems []ValidationProblem
+
+	if opt.Threads <= 0 {
+		problems = append(problems, ValidationProblem{"threads", "must be bigger than 0", "the default of 10 works for most targets"})
+	} else if opt.Threads > maxThreads {
+		problems = append(problems, ValidationProblem{"threads", fmt.Sprintf("%d threads will exhaust your system before the target", opt.Threads), "use a few hundred at most"})
+	}
+
+	if opt.EnrichThreads < 0 {
+		problems = append(problems, ValidationProblem{"enrich-threads", "must be bigger or equal to 0", "0 uses the number of threads"})
+	} else if opt.EnrichThreads > maxThreads {
+		problems = append(problems, ValidationProblem{"enrich-threads", fmt.Sprintf("%d threads will exhaust your system before the target", opt.EnrichThreads), "use a few hundred at most"})
+	}
+
+	if opt.Delay < 0 {
+		problems = append(problems, ValidationProblem{"delay", "must be positive", "e.g. 100ms"})
+	}
+
+	switch {
+	case opt.Wordlist == "":
+		problems = append(problems, ValidationProblem{"wordlist", "is required", "use -w <file> or -w - to read from STDIN"})
+	case len(opt.Wordlists) > 1:
+		for _, w := range opt.Wordlists {
+			if w == "-" {
+				problems = append(problems, ValidationProblem{"wordlist", "STDIN can not be merged with other wordlists", "use a single -w -"})
+			} else if _, err := os.Stat(w); err != nil {
+				problems = append(problems, ValidationProblem{"wordlist", fmt.Sprintf("file %q can not be read: %v", w, err), "check the path"})
+			}
+		}
+	case opt.Wordlist == "-":
+		if opt.WordlistOffset > 0 {
+			problems = append(problems, ValidationProblem{"wordlist-offset", "is not supported when reading from STDIN", "skip the lines before piping them in"})
+		}
+	default:
+		if _, err := os.Stat(opt.Wordlist); err != nil {
+			problems = append(problems, ValidationProblem{"wordlist", fmt.Sprintf("file %q can not be read: %v", opt.Wordlist, err), "check the path"})
+		}
+	}
+
+	if opt.WordlistOffset < 0 {
+		problems = append(problems, ValidationProblem{"wordlist-offset", "must be bigger or equal to 0", ""})
+	}
+
+	if opt.StopAfterFindings < 0 {
+		problems = append(problems, ValidationProblem{"stop-after-findings", "must be bigger or equal to 0", "0 disables it"})
+	}
+	if opt.MaxTime < 0 {
+		problems = append(problems, ValidationProblem{"max-time", "must be bigger or equal to 0", "0 disables it"})
+	}
+	if opt.MaxMemory != 0 && opt.MaxMemory < MinMemoryLimit {
+		problems = append(problems, ValidationProblem{"max-memory", fmt.Sprintf("must be at least %s", FormatMemorySize(MinMemoryLimit)), "0 disables it"})
+	}
+	if opt.MaxErrors < 0 {
+		problems = append(problems, ValidationProblem{"max-errors", "must be bigger or equal to 0", "0 disables it"})
+	}
+
+	if opt.RateLimit < 0 {
+		problems = append(problems, ValidationProblem{"rate-limit", "must be bigger or equal to 0", "0 disables it"})
+	}
+	if opt.RateFile != "" && opt.RateLimit == 0 {
+		problems = append(problems, ValidationProblem{"rate-file", "is set without a rate limit", "add --rate-limit"})
+	}
+
+	switch opt.WordPolicy {
+	case "", WordPolicySkip, WordPolicyEncode, WordPolicyTruncate:
+	default:
+		problems = append(problems, ValidationProblem{"word-policy", fmt.Sprintf("%q is not supported", opt.WordPolicy), fmt.Sprintf("use one of %s", strings.Join(WordPolicies(), ", "))})
+	}
+
+	if len(opt.ExtraWordlists) > 0 && opt.Strategy != StrategyClusterbomb && opt.Strategy != StrategyPitchfork {
+		problems = append(problems, ValidationProblem{"strategy", fmt.Sprintf("%q is not supported", opt.Strategy), fmt.Sprintf("use %s or %s", StrategyClusterbomb, StrategyPitchfork)})
+	}
+	for _, w := range opt.ExtraWordlists {
+		if _, err := os.Stat(w.Filename); err != nil {
+			problems = append(problems, ValidationProblem{"extra-wordlist", fmt.Sprintf("file %q for %s can not be read: %v", w.Filename, w.Keyword, err), "check the path"})
+		}
+	}
+
+	for _, p := range pluginOpts {
+		problems = append(problems, p.ValidationProblems()...)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Mode: mode, Problems: problems}
+	}
+	return nil
+}
+
+// ValidationProblems returns all problems of the basic http options
+func (opt *BasicHTTPOptions) ValidationProblems() []ValidationProblem {
+	var problems []ValidationProblem
+
+	if opt.Proxy != "" {
+		if p := proxyProblem("proxy", "", opt.Proxy); p != nil {
+			problems = append(problems, *p)
+		}
+	}
+
+	if len(opt.Proxies) > 0 {
+		if opt.Proxy != "" {
+			problems = append(problems, ValidationProblem{"proxy-file", "can not be used together with proxy", ""})
+		}
+		for _, proxy := range opt.Proxies {
+			if p := proxyProblem("proxy-file", fmt.Sprintf("%s ", proxy), proxy); p != nil {
+				problems = append(problems, *p)
+			}
+		}
+	}
+
+	if opt.ProxyRotation != "" && opt.ProxyRotation != ProxyRoundRobin && opt.ProxyRotation != ProxyRandom {
+		problems = append(problems, ValidationProblem{"proxy-rotation", fmt.Sprintf("%q is not supported", opt.ProxyRotation), fmt.Sprintf("use %s or %s", ProxyRoundRobin, ProxyRandom)})
+	}
+
+	if opt.Timeout <= 0 {
+		problems = append(problems, ValidationProblem{"timeout", "must be bigger than 0", "e.g. 10s"})
+	}
+
+	if opt.RetryAttempts < 0 {
+		problems = append(problems, ValidationProblem{"retry-attempts", "must be bigger or equal to 0", ""})
+	}
+	if opt.RetryWait < 0 {
+		problems = append(problems, ValidationProblem{"retry-wait", "must be bigger or equal to 0", "0 retries immediately"})
+	}
+
+	return problems
+}
+
+// ValidationProblems returns all problems of the http options
+func (opt *HTTPOptions) ValidationProblems() []ValidationProblem {
+	problems := opt.BasicHTTPOptions.ValidationProblems()
+
+	if opt.URL == "" {
+		problems = append(problems, ValidationProblem{"url", "is required", "use -u http://example.com"})
+	} else {
+		u, err := url.Parse(opt.URL)
+		switch {
+		case err != nil:
+			problems = append(problems, ValidationProblem{"url", fmt.Sprintf("is invalid: %v", err), "e.g. http://example.com"})
+		case u.Scheme != "http" && u.Scheme != "https":
+			problems = append(problems, ValidationProblem{"url", fmt.Sprintf("scheme %q is not supported", u.Scheme), "use http or https"})
+		case u.Host == "":
+			problems = append(problems, ValidationProblem{"url", "host is missing", "e.g. http://example.com"})
+		}
+	}
+
+	if opt.Password != "" && opt.Username == "" {
+		problems = append(problems, ValidationProblem{"password", "is set without a username", "add --username"})
+	}
+
+	return problems
+}
+
+// proxyProblem returns the problem of a proxy URL or nil if it is valid. The
+// prefix is put in front of the problem to name the proxy of a list
+func proxyProblem(option, prefix, proxy string) *ValidationProblem {
+	u, err := url.Parse(proxy)
+	switch {
+	case err != nil:
+		return &ValidationProblem{option, fmt.Sprintf("%sis invalid: %v", prefix, err), "e.g. http://127.0.0.1:8080"}
+	case u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5":
+		return &ValidationProblem{option, fmt.Sprintf("%sscheme %q is not supported", prefix, u.Scheme), "use http, https or socks5"}
+	case u.Host == "":
+		return &ValidationProblem{option, fmt.Sprintf("%shost is missing", prefix), "e.g. http://127.0.0.1:8080"}
+	}
+	return nil
+}
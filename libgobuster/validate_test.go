@@ -0,0 +1,70 @@
+package libgobuster
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp("", "wordlist")
+	if err != nil {
+		t.Fatalf("could not create tempfile: %v", err)
+	}
+	// subtests run in parallel after this function returned
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	f.Close()
+
+	validHTTP := &HTTPOptions{URL: "http://localhost", BasicHTTPOptions: BasicHTTPOptions{Timeout: 10 * time.Second}}
+
+	tt := []struct {
+		testName string
+		opts     Options
+		plugin   []Validator
+		expected []string
+	}{
+		{"Valid", Options{Threads: 10, Wordlist: f.Name()}, []Validator{validHTTP}, nil},
+		{"Stdin", Options{Threads: 10, Wordlist: "-"}, nil, nil},
+		{"Stdin Offset", Options{Threads: 10, Wordlist: "-", WordlistOffset: 10}, nil, []string{"wordlist-offset"}},
+		{"Multiple", Options{Threads: 0, Delay: -1, Wordlist: ""}, nil, []string{"threads", "delay", "wordlist"}},
+		{"Too Many Threads", Options{Threads: maxThreads + 1, Wordlist: f.Name()}, nil, []string{"threads"}},
+		{"Rate File Without Limit", Options{Threads: 10, Wordlist: f.Name(), RateFile: "rate.json"}, nil, []string{"rate-file"}},
+		{"Negative Rate Limit", Options{Threads: 10, Wordlist: f.Name(), RateLimit: -1}, nil, []string{"rate-limit"}},
+		{"Missing Wordlist", Options{Threads: 10, Wordlist: "/does/not/exist"}, nil, []string{"wordlist"}},
+		{"Merged Wordlists", Options{Threads: 10, Wordlist: f.Name(), Wordlists: []string{f.Name(), f.Name()}}, nil, nil},
+		{"Merged Missing Wordlist", Options{Threads: 10, Wordlist: f.Name(), Wordlists: []string{f.Name(), "/does/not/exist"}}, nil, []string{"wordlist"}},
+		{"Merged Stdin", Options{Threads: 10, Wordlist: "-", Wordlists: []string{"-", f.Name()}}, nil, []string{"wordlist"}},
+		{"HTTP", Options{Threads: 10, Wordlist: f.Name()}, []Validator{&HTTPOptions{URL: "ftp://localhost", Password: "x", BasicHTTPOptions: BasicHTTPOptions{Proxy: "socks4://localhost"}}}, []string{"proxy", "timeout", "url", "password"}},
+		{"Basic HTTP", Options{Threads: 10, Wordlist: f.Name()}, []Validator{&BasicHTTPOptions{Proxy: "http://", Timeout: time.Second, RetryAttempts: -1}}, []string{"proxy", "retry-attempts"}},
+		{"Proxy File", Options{Threads: 10, Wordlist: f.Name()}, []Validator{&BasicHTTPOptions{Proxy: "http://localhost:8080", Proxies: []string{"http://localhost:8081", "ftp://localhost"}, ProxyRotation: "sticky", Timeout: time.Second}}, []string{"proxy-file", "proxy-file", "proxy-rotation"}},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			err := x.opts.Validate("test", x.plugin...)
+			if x.expected == nil {
+				if err != nil {
+					t.Fatalf("Got error: %v", err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("Expected a ValidationError but got %v", err)
+			}
+			var got []string
+			for _, p := range vErr.Problems {
+				got = append(got, p.Option)
+			}
+			if !reflect.DeepEqual(got, x.expected) {
+				t.Fatalf("Expected problems with %v but got %v", x.expected, got)
+			}
+		})
+	}
+}
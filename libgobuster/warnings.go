@@ -0,0 +1,78 @@
+package libgobuster
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// HugeScanRequests is the number of requests after which a scan is considered noisy
+const HugeScanRequests = 1_000_000
+
+// aggressiveThreads is the number of threads which is considered aggressive
+// when no delay is used
+const aggressiveThreads = 50
+
+// nolint:gochecknoglobals
+var (
+	dangerousMethods = []string{"PUT", "DELETE", "PATCH", "MKCOL", "MOVE", "COPY"}
+	// hostnames containing one of these labels do not look like production systems
+	nonProductionLabels = []string{"dev", "test", "staging", "stage", "qa", "uat", "local", "localhost", "sandbox", "lab", "internal"}
+)
+
+// Warnings returns warnings about potentially destructive or noisy
+// configurations of the current run
+func (g *Gobuster) Warnings(requests int) []string {
+	var warnings []string
+	if requests > HugeScanRequests {
+		warnings = append(warnings, fmt.Sprintf("this scan will issue %d requests", requests))
+	}
+	if p, ok := g.plugin.(WarningPlugin); ok {
+		warnings = append(warnings, p.Warnings()...)
+	}
+	return warnings
+}
+
+// HTTPWarnings returns warnings about potentially destructive or noisy http
+// configurations
+func HTTPWarnings(opts *HTTPOptions, globalopts *Options) []string {
+	var warnings []string
+
+	method := strings.ToUpper(opts.Method)
+	for _, m := range dangerousMethods {
+		if method == m {
+			warnings = append(warnings, fmt.Sprintf("the %s method may modify data on the target", method))
+			break
+		}
+	}
+
+	if globalopts.Threads >= aggressiveThreads && globalopts.Delay == 0 && globalopts.RateLimit == 0 {
+		if u, err := url.Parse(opts.URL); err == nil && looksLikeProduction(u.Hostname()) {
+			warnings = append(warnings, fmt.Sprintf("aggressive configuration (%d threads without delay) against %s which looks like a production system", globalopts.Threads, u.Hostname()))
+		}
+	}
+
+	return warnings
+}
+
+// looksLikeProduction checks if the hostname is public and does not contain
+// labels commonly used for non production systems
+func looksLikeProduction(host string) bool {
+	if host == "" {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast()
+	}
+
+	for _, label := range strings.FieldsFunc(strings.ToLower(host), func(r rune) bool { return r == '.' || r == '-' }) {
+		for _, x := range nonProductionLabels {
+			if label == x {
+				return false
+			}
+		}
+	}
+	return true
+}
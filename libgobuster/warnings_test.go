@@ -0,0 +1,49 @@
+package libgobuster
+
+import "testing"
+
+func TestLooksLikeProduction(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		host     string
+		expected bool
+	}{
+		{"www.example.com", true},
+		{"8.8.8.8", true},
+		{"localhost", false},
+		{"127.0.0.1", false},
+		{"192.168.1.1", false},
+		{"staging.example.com", false},
+		{"app-dev.example.com", false},
+		{"", false},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.host, func(t *testing.T) {
+			t.Parallel()
+
+			if got := looksLikeProduction(x.host); got != x.expected {
+				t.Fatalf("looksLikeProduction(%q) = %t, expected %t", x.host, got, x.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPWarnings(t *testing.T) {
+	t.Parallel()
+
+	globalopts := NewOptions()
+	globalopts.Threads = 100
+
+	opts := HTTPOptions{URL: "https://www.example.com", Method: "delete"}
+	if w := HTTPWarnings(&opts, globalopts); len(w) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", w)
+	}
+
+	opts = HTTPOptions{URL: "http://localhost", Method: "GET"}
+	if w := HTTPWarnings(&opts, globalopts); len(w) != 0 {
+		t.Fatalf("expected no warnings, got %v", w)
+	}
+}
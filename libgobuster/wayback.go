@@ -0,0 +1,70 @@
+package libgobuster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WaybackCDXURL is the endpoint of the Wayback Machine CDX API
+const WaybackCDXURL = "https://web.archive.org/cdx/search/cdx"
+
+// FetchWaybackURLs returns the unique urls archived by the Wayback Machine
+// for the target. endpoint is the CDX API, normally WaybackCDXURL. If
+// subdomains is true all subdomains of the target are included, otherwise
+// target is used as an url prefix. A limit of 0 means no limit
+func FetchWaybackURLs(ctx context.Context, client *HTTPClient, endpoint, target string, subdomains bool, limit int) ([]string, error) {
+	params := url.Values{}
+	params.Set("url", target)
+	params.Set("matchType", "prefix")
+	if subdomains {
+		params.Set("matchType", "domain")
+	}
+	params.Set("fl", "original")
+	params.Set("collapse", "urlkey")
+	params.Set("output", "json")
+	if limit > 0 {
+		params.Set("limit", fmt.Sprint(limit))
+	}
+
+	statusCode, _, _, body, err := client.Request(ctx, fmt.Sprintf("%s?%s", endpoint, params.Encode()), RequestOptions{Method: http.MethodGet, ReturnBody: true, RejectTruncated: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not query the wayback machine: %w", err)
+	}
+	// request was canceled
+	if statusCode == 0 {
+		return nil, nil
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("wayback machine returned status %d", statusCode)
+	}
+
+	return parseWaybackResponse(body)
+}
+
+// parseWaybackResponse parses the json output of the CDX API. The first row
+// contains the field names
+func parseWaybackResponse(body []byte) ([]string, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	var rows [][]string
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("could not parse wayback machine response: %w", err)
+	}
+
+	seen := NewSet[string]()
+	var ret []string
+	for i, row := range rows {
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+		if seen.Add(row[0]) {
+			ret = append(ret, row[0])
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,25 @@
+package libgobuster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWaybackResponse(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`[["original"],["http://example.com/"],["http://example.com/admin"],["http://example.com/admin"],["https://www.example.com/login?x=1"]]`)
+	expected := []string{"http://example.com/", "http://example.com/admin", "https://www.example.com/login?x=1"}
+
+	got, err := parseWaybackResponse(body)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := parseWaybackResponse([]byte("invalid")); err == nil {
+		t.Fatal("expected an error on invalid json")
+	}
+}
@@ -0,0 +1,300 @@
+package libgobuster
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxWordlistLine is the longest wordlist line that can be read. Lines of
+// binary files or minified data are often longer than the 64KiB a default
+// scanner accepts
+const maxWordlistLine = 1024 * 1024
+
+// newWordlistScanner returns a scanner over the lines of a wordlist
+func newWordlistScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxWordlistLine)
+	return scanner
+}
+
+// wordlistEntry returns the word of a wordlist line. Empty lines and
+// comments starting with # have no word
+func wordlistEntry(line string) (string, bool) {
+	word := strings.TrimSpace(line)
+	if word == "" || strings.HasPrefix(word, "#") {
+		return "", false
+	}
+	return word, true
+}
+
+// WordlistIssue describes a problem with a single line of a wordlist
+type WordlistIssue struct {
+	Line   int
+	Word   string
+	Reason string
+}
+
+// WordlistReport holds the result of a wordlist analysis
+type WordlistReport struct {
+	// Lines is the total number of lines
+	Lines int
+	// Words is the number of lines which will be processed
+	Words int
+	// Skipped is the number of empty and comment lines
+	Skipped int
+	// Unique is the number of unique words
+	Unique int
+	// Duplicates contains all lines which were already seen before
+	Duplicates []WordlistIssue
+	// Encoding contains all lines which are no valid UTF-8
+	Encoding []WordlistIssue
+	// Invalid contains all words which are invalid for the mode
+	Invalid []WordlistIssue
+}
+
+// EstimatedRequests returns the number of requests a scan with this wordlist
+// will issue if every word results in perWord requests
+func (r *WordlistReport) EstimatedRequests(perWord int) int {
+	return r.Words * perWord
+}
+
+// AnalyzeWordlist reads a wordlist and reports duplicates, encoding problems
+// and words which are not valid for the given mode
+func AnalyzeWordlist(reader io.Reader, mode string) (*WordlistReport, error) {
+	report := WordlistReport{}
+	seen := NewSet[string]()
+
+	scanner := newWordlistScanner(reader)
+	for scanner.Scan() {
+		report.Lines++
+		// same rules as the worker
+		word, ok := wordlistEntry(scanner.Text())
+		if !ok {
+			report.Skipped++
+			continue
+		}
+		report.Words++
+
+		if !seen.Add(word) {
+			report.Duplicates = append(report.Duplicates, WordlistIssue{Line: report.Lines, Word: word, Reason: "duplicate"})
+		}
+
+		if !utf8.ValidString(word) {
+			report.Encoding = append(report.Encoding, WordlistIssue{Line: report.Lines, Word: word, Reason: "invalid UTF-8"})
+			continue
+		}
+
+		if reason := invalidWordReason(word, mode); reason != "" {
+			report.Invalid = append(report.Invalid, WordlistIssue{Line: report.Lines, Word: word, Reason: reason})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+
+	report.Unique = seen.Length()
+	return &report, nil
+}
+
+// invalidWordReason returns why a word can not be used in the given mode or
+// an empty string if the word is valid
+func invalidWordReason(word, mode string) string {
+	for _, r := range word {
+		if unicode.IsControl(r) {
+			return "contains control characters"
+		}
+	}
+
+	switch mode {
+	case "dns", "vhost":
+		if strings.ContainsAny(word, " \t") {
+			return "contains whitespace"
+		}
+		for _, label := range strings.Split(strings.TrimSuffix(word, "."), ".") {
+			if label == "" {
+				return "contains an empty label"
+			}
+			if len(label) > 63 {
+				return "label is longer than 63 characters"
+			}
+			for _, r := range label {
+				if r > unicode.MaxASCII {
+					// will be punycode encoded
+					continue
+				}
+				if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+					return fmt.Sprintf("contains invalid character %q", r)
+				}
+			}
+		}
+	case "s3", "gcs":
+		if len(word) < 3 || len(word) > 63 {
+			return "bucket names must be between 3 and 63 characters"
+		}
+		for _, r := range word {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' && r != '.' && r != '_' {
+				return fmt.Sprintf("contains invalid character %q", r)
+			}
+		}
+	case "azure":
+		if len(word) < 3 || len(word) > 63 {
+			return "account and container names must be between 3 and 63 characters"
+		}
+		for _, r := range word {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' {
+				return fmt.Sprintf("contains invalid character %q", r)
+			}
+		}
+	}
+
+	return ""
+}
+
+// mergedWordlist reads multiple wordlists one after another and returns
+// every word only once, one per line. Empty lines and comments are dropped
+type mergedWordlist struct {
+	paths      []string
+	file       *os.File
+	scanner    *bufio.Scanner
+	seen       Set[string]
+	pending    []byte
+	words      int
+	duplicates int
+}
+
+func newMergedWordlist(paths []string) *mergedWordlist {
+	return &mergedWordlist{paths: paths, seen: NewSet[string]()}
+}
+
+// Read implements io.Reader
+func (m *mergedWordlist) Read(p []byte) (int, error) {
+	for len(m.pending) == 0 {
+		if m.scanner == nil {
+			if len(m.paths) == 0 {
+				return 0, io.EOF
+			}
+			f, err := os.Open(m.paths[0])
+			if err != nil {
+				return 0, fmt.Errorf("failed to open wordlist: %w", err)
+			}
+			m.paths = m.paths[1:]
+			m.file = f
+			m.scanner = newWordlistScanner(f)
+		}
+
+		if !m.scanner.Scan() {
+			err := m.scanner.Err()
+			m.file.Close()
+			m.file = nil
+			m.scanner = nil
+			if err != nil {
+				return 0, fmt.Errorf("failed to read wordlist: %w", err)
+			}
+			continue
+		}
+
+		word, ok := wordlistEntry(m.scanner.Text())
+		if !ok {
+			continue
+		}
+		if !m.seen.Add(word) {
+			m.duplicates++
+			continue
+		}
+		m.words++
+		m.pending = append(append(m.pending, word...), '\n')
+	}
+
+	n := copy(p, m.pending)
+	m.pending = m.pending[n:]
+	return n, nil
+}
+
+// Close closes the wordlist currently read
+func (m *mergedWordlist) Close() error {
+	if m.file == nil {
+		return nil
+	}
+	return m.file.Close()
+}
+
+// CountMergedWordlists returns the number of unique words and the number of
+// removed duplicates of the merged wordlists
+func CountMergedWordlists(paths []string) (int, int, error) {
+	m := newMergedWordlist(paths)
+	defer m.Close()
+	if _, err := io.Copy(io.Discard, m); err != nil {
+		return 0, 0, err
+	}
+	return m.words, m.duplicates, nil
+}
+
+// OptimizedWordlist is a wordlist reordered by the findings of previous runs
+type OptimizedWordlist struct {
+	Words []string
+	// Productive is the number of words which produced findings before
+	Productive int
+	// Trimmed is the number of words removed because of too few findings
+	Trimmed int
+	// Duplicates is the number of removed duplicate words
+	Duplicates int
+}
+
+// OptimizeWordlist reads a wordlist and moves the words which produced
+// findings in previous runs to the front, most productive first. All other
+// words keep their order. Words with findings in less than minRuns runs are
+// removed if minRuns is greater than 0
+func OptimizeWordlist(reader io.Reader, stats WorkspaceStats, minRuns int) (*OptimizedWordlist, error) {
+	productive := make(map[string]WordStats, len(stats.Words))
+	for _, w := range stats.Words {
+		productive[w.Word] = w
+	}
+
+	var known, rest []WordStats
+	ret := OptimizedWordlist{}
+	seen := NewSet[string]()
+
+	scanner := newWordlistScanner(reader)
+	for scanner.Scan() {
+		word, ok := wordlistEntry(scanner.Text())
+		if !ok {
+			continue
+		}
+		if !seen.Add(word) {
+			ret.Duplicates++
+			continue
+		}
+		w, ok := productive[word]
+		if !ok {
+			w = WordStats{Word: word}
+		}
+		if minRuns > 0 && w.Runs < minRuns {
+			ret.Trimmed++
+			continue
+		}
+		if ok {
+			known = append(known, w)
+		} else {
+			rest = append(rest, w)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+
+	sort.SliceStable(known, func(i, j int) bool { return known[i].less(known[j]) })
+	ret.Productive = len(known)
+	ret.Words = make([]string, 0, len(known)+len(rest))
+	for _, w := range append(known, rest...) {
+		ret.Words = append(ret.Words, w.Word)
+	}
+	return &ret, nil
+}
@@ -0,0 +1,219 @@
+package libgobuster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeWordlist(t *testing.T) {
+	t.Parallel()
+
+	wordlist := "admin\n# comment\n\nadmin\nfoo bar\nlogin\n\xff\xfe\n"
+
+	tt := []struct {
+		mode       string
+		words      int
+		unique     int
+		duplicates int
+		encoding   int
+		invalid    int
+	}{
+		{"dir", 5, 4, 1, 1, 0},
+		{"dns", 5, 4, 1, 1, 1},
+		{"s3", 5, 4, 1, 1, 1},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.mode, func(t *testing.T) {
+			t.Parallel()
+
+			report, err := AnalyzeWordlist(strings.NewReader(wordlist), x.mode)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if report.Lines != 7 {
+				t.Fatalf("expected 7 lines, got %d", report.Lines)
+			}
+			if report.Skipped != 2 {
+				t.Fatalf("expected 2 skipped lines, got %d", report.Skipped)
+			}
+			if report.Words != x.words {
+				t.Fatalf("expected %d words, got %d", x.words, report.Words)
+			}
+			if report.Unique != x.unique {
+				t.Fatalf("expected %d unique words, got %d", x.unique, report.Unique)
+			}
+			if len(report.Duplicates) != x.duplicates {
+				t.Fatalf("expected %d duplicates, got %d", x.duplicates, len(report.Duplicates))
+			}
+			if len(report.Encoding) != x.encoding {
+				t.Fatalf("expected %d encoding issues, got %d", x.encoding, len(report.Encoding))
+			}
+			if len(report.Invalid) != x.invalid {
+				t.Fatalf("expected %d invalid words, got %d: %v", x.invalid, len(report.Invalid), report.Invalid)
+			}
+			if report.EstimatedRequests(3) != x.words*3 {
+				t.Fatalf("expected %d estimated requests, got %d", x.words*3, report.EstimatedRequests(3))
+			}
+		})
+	}
+}
+
+func TestOptimizeWordlist(t *testing.T) {
+	t.Parallel()
+
+	stats := WorkspaceStats{Runs: 4, Words: []WordStats{
+		{Word: "login", Runs: 3, Targets: 2},
+		{Word: "admin", Runs: 1, Targets: 1},
+		{Word: "unused", Runs: 2, Targets: 1},
+	}}
+	wordlist := "# comment\nindex\nadmin\nfoo\nlogin\nadmin\n"
+
+	tt := []struct {
+		testName string
+		minRuns  int
+		expected []string
+		trimmed  int
+	}{
+		{"Reorder", 0, []string{"login", "admin", "index", "foo"}, 0},
+		{"Trim", 1, []string{"login", "admin"}, 2},
+		{"TrimMore", 2, []string{"login"}, 3},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+
+			optimized, err := OptimizeWordlist(strings.NewReader(wordlist), stats, x.minRuns)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if strings.Join(optimized.Words, ",") != strings.Join(x.expected, ",") {
+				t.Fatalf("expected %v, got %v", x.expected, optimized.Words)
+			}
+			if optimized.Trimmed != x.trimmed || optimized.Duplicates != 1 {
+				t.Fatalf("expected %d trimmed and 1 duplicate, got %d and %d", x.trimmed, optimized.Trimmed, optimized.Duplicates)
+			}
+		})
+	}
+}
+
+func TestMergedWordlist(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var paths []string
+	for i, content := range []string{"found1\n# comment\nfound2\n\nfound1\n", "found2\n  found3  \nfound4"} {
+		path := filepath.Join(dir, fmt.Sprintf("wordlist%d.txt", i))
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("could not write wordlist: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	words, duplicates, err := CountMergedWordlists(paths)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if words != 4 || duplicates != 2 {
+		t.Fatalf("Expected 4 words and 2 duplicates but got %d and %d", words, duplicates)
+	}
+
+	content, err := io.ReadAll(newMergedWordlist(paths))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if expected := "found1\nfound2\nfound3\nfound4\n"; string(content) != expected {
+		t.Fatalf("Expected %q but got %q", expected, content)
+	}
+
+	if _, _, err := CountMergedWordlists(append(paths, filepath.Join(dir, "missing.txt"))); err == nil {
+		t.Fatal("expected an error for a missing wordlist")
+	}
+
+	opts := NewOptions()
+	opts.Threads = 2
+	opts.Wordlist = paths[0]
+	opts.Wordlists = paths
+	opts.CollectResults = true
+	g, err := NewGobuster(opts, collectPlugin{}, NewLogger(false))
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if found := len(g.CollectedResults()); found != 4 {
+		t.Fatalf("Expected 4 results but got %d", found)
+	}
+	if expected := g.Progress.RequestsExpected(); expected != 4 {
+		t.Fatalf("Expected 4 requests but got %d", expected)
+	}
+}
+
+func FuzzAnalyzeWordlist(f *testing.F) {
+	f.Add([]byte("admin\n# comment\n\n  login  \r\nadmin\n"), "dir")
+	f.Add([]byte("www\nsub.domain\n-\n\xff\xfe\n"), "dns")
+	f.Add([]byte("bucket\nBucket\nab\n"), "s3")
+	f.Add([]byte("no trailing newline"), "vhost")
+	// longer than the 64KiB of a default scanner
+	f.Add(append(bytes.Repeat([]byte("a"), 100*1024), '\n'), "dir")
+
+	f.Fuzz(func(t *testing.T, wordlist []byte, mode string) {
+		report, err := AnalyzeWordlist(bytes.NewReader(wordlist), mode)
+		if err != nil {
+			// only lines longer than the scanner accepts fail
+			if len(wordlist) <= maxWordlistLine {
+				t.Fatalf("Got error: %v", err)
+			}
+			return
+		}
+		if report.Words+report.Skipped != report.Lines {
+			t.Fatalf("%d words and %d skipped lines do not add up to %d lines", report.Words, report.Skipped, report.Lines)
+		}
+		if report.Unique+len(report.Duplicates) != report.Words {
+			t.Fatalf("%d unique words and %d duplicates do not add up to %d words", report.Unique, len(report.Duplicates), report.Words)
+		}
+		lines, err := lineCounter(bytes.NewReader(wordlist))
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		if report.Lines > lines {
+			t.Fatalf("read %d lines, the line counter counted %d", report.Lines, lines)
+		}
+	})
+}
+
+func FuzzWordlistEntry(f *testing.F) {
+	f.Add("admin")
+	f.Add("  admin\t\r")
+	f.Add("# comment")
+	f.Add("   #comment")
+	f.Add("a#b")
+	f.Add(" ")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		word, ok := wordlistEntry(line)
+		if !ok {
+			if word != "" {
+				t.Fatalf("skipped line %q returned the word %q", line, word)
+			}
+			return
+		}
+		if word == "" || strings.HasPrefix(word, "#") || strings.TrimSpace(word) != word {
+			t.Fatalf("invalid word %q of line %q", word, line)
+		}
+		// the workers parse the words of the merged wordlist again
+		if again, ok := wordlistEntry(word); !ok || again != word {
+			t.Fatalf("word %q of line %q changed to %q", word, line, again)
+		}
+	})
+}
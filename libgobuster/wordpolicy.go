@@ -0,0 +1,197 @@
+package libgobuster
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordKind is what the words of a plugin become in its requests
+type WordKind int
+
+const (
+	// WordKindAny words are used as they are
+	WordKindAny WordKind = iota
+	// WordKindPath words become the path of an url
+	WordKindPath
+	// WordKindHost words become a host name or its first labels
+	WordKindHost
+)
+
+// policies for words which are invalid for the kind of the plugin
+const (
+	// WordPolicySkip skips the word with a message
+	WordPolicySkip = "skip"
+	// WordPolicyEncode percent encodes the invalid characters of paths,
+	// words which can not be encoded are skipped
+	WordPolicyEncode = "encode"
+	// WordPolicyTruncate cuts the word at the first invalid character and
+	// at the maximum length
+	WordPolicyTruncate = "truncate"
+)
+
+const (
+	// maxPathWordLength is the longest word used in an url path, servers
+	// reject request lines longer than 8KiB
+	maxPathWordLength = 4096
+	// maxLabelLength is the longest label of a host name
+	maxLabelLength = 63
+	// maxHostLength is the longest host name
+	maxHostLength = 253
+)
+
+// WordPolicies returns the valid word policies
+func WordPolicies() []string {
+	return []string{WordPolicySkip, WordPolicyEncode, WordPolicyTruncate}
+}
+
+// wordProblem returns why the word is invalid for the kind, empty if it is
+// valid
+func wordProblem(word string, kind WordKind) string {
+	switch kind {
+	case WordKindPath:
+		if len(word) > maxPathWordLength {
+			return fmt.Sprintf("longer than %d bytes", maxPathWordLength)
+		}
+		if i := strings.IndexFunc(word, isControl); i >= 0 {
+			return fmt.Sprintf("contains the control character %q", word[i])
+		}
+		if i := invalidEscape(word); i >= 0 {
+			end := i + 3
+			if end > len(word) {
+				end = len(word)
+			}
+			return fmt.Sprintf("contains the invalid escape %q", word[i:end])
+		}
+	case WordKindHost:
+		if strings.IndexFunc(word, isHostSeparator) >= 0 {
+			return "contains whitespace or control characters"
+		}
+		ace, err := HostToASCII(word)
+		if err != nil {
+			// the plugins report invalid internationalized names
+			ace = word
+		}
+		if len(strings.TrimSuffix(ace, ".")) > maxHostLength {
+			return fmt.Sprintf("longer than %d bytes", maxHostLength)
+		}
+		for _, label := range strings.Split(strings.TrimSuffix(ace, "."), ".") {
+			if len(label) > maxLabelLength {
+				return fmt.Sprintf("label %q is longer than %d bytes", label, maxLabelLength)
+			}
+		}
+	}
+	return ""
+}
+
+// invalidEscape returns the index of the first % not followed by two hex
+// digits, -1 if there is none. Such words fail the parsing of the url
+func invalidEscape(word string) int {
+	for i := 0; i < len(word); i++ {
+		if word[i] != '%' {
+			continue
+		}
+		if i+2 >= len(word) || !isHex(word[i+1]) || !isHex(word[i+2]) {
+			return i
+		}
+		i += 2
+	}
+	return -1
+}
+
+// isControl checks for the control characters urls can not contain
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// isHostSeparator checks for characters host names can not contain
+func isHostSeparator(r rune) bool {
+	return isControl(r) || unicode.IsSpace(r)
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// encodeWord percent encodes the control characters and invalid escapes of
+// a path
+func encodeWord(word string) string {
+	var b strings.Builder
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		switch {
+		case isControl(rune(c)):
+			fmt.Fprintf(&b, "%%%02X", c)
+		case c == '%' && invalidEscape(word[i:]) == 0:
+			b.WriteString("%25")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// truncateWord cuts the word at the first invalid character and shortens it
+// to the maximum length of the kind. Labels of host names are shortened to
+// 63 bytes each
+func truncateWord(word string, kind WordKind) string {
+	switch kind {
+	case WordKindPath:
+		if i := strings.IndexFunc(word, isControl); i >= 0 {
+			word = word[:i]
+		}
+		if i := invalidEscape(word); i >= 0 {
+			word = word[:i]
+		}
+		return truncateUTF8(word, maxPathWordLength)
+	case WordKindHost:
+		if i := strings.IndexFunc(word, isHostSeparator); i >= 0 {
+			word = word[:i]
+		}
+		labels := strings.Split(word, ".")
+		for i := range labels {
+			labels[i] = truncateUTF8(labels[i], maxLabelLength)
+		}
+		word = strings.Join(labels, ".")
+		for len(word) > maxHostLength && strings.Contains(word, ".") {
+			word = word[:strings.LastIndex(word, ".")]
+		}
+	}
+	return word
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a rune
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// ApplyWordPolicy checks the word for the kind and returns the word to use.
+// Invalid words are fixed according to the policy, problem is why the word
+// was invalid and ok is false if the word has to be skipped
+func ApplyWordPolicy(word string, kind WordKind, policy string) (fixed string, problem string, ok bool) {
+	problem = wordProblem(word, kind)
+	if problem == "" {
+		return word, "", true
+	}
+
+	switch policy {
+	case WordPolicyEncode:
+		if kind == WordKindPath {
+			fixed = encodeWord(word)
+		}
+	case WordPolicyTruncate:
+		fixed = truncateWord(word, kind)
+	}
+	// the fix may not help, like encoding a word which is too long
+	if fixed == "" || wordProblem(fixed, kind) != "" {
+		return "", problem, false
+	}
+	return fixed, problem, true
+}
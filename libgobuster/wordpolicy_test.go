@@ -0,0 +1,61 @@
+package libgobuster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyWordPolicy(t *testing.T) {
+	t.Parallel()
+
+	longPath := strings.Repeat("a", maxPathWordLength+10)
+	longLabel := strings.Repeat("b", maxLabelLength+10)
+	tt := []struct {
+		testName string
+		word     string
+		kind     WordKind
+		policy   string
+		expected string
+		ok       bool
+	}{
+		{"Valid path", "admin", WordKindPath, WordPolicySkip, "admin", true},
+		{"Any kind", "a\nb", WordKindAny, WordPolicySkip, "a\nb", true},
+		{"Control skip", "a\nb", WordKindPath, WordPolicySkip, "", false},
+		{"Control encode", "a\nb", WordKindPath, WordPolicyEncode, "a%0Ab", true},
+		{"Control truncate", "a\nb", WordKindPath, WordPolicyTruncate, "a", true},
+		{"Escape encode", "100%", WordKindPath, WordPolicyEncode, "100%25", true},
+		{"Valid escape", "a%20b%zz", WordKindPath, WordPolicyEncode, "a%20b%25zz", true},
+		{"Escape truncate", "100%zz", WordKindPath, WordPolicyTruncate, "100", true},
+		{"Truncated to nothing", "%zz", WordKindPath, WordPolicyTruncate, "", false},
+		{"Long path skip", longPath, WordKindPath, WordPolicySkip, "", false},
+		{"Long path encode", longPath, WordKindPath, WordPolicyEncode, "", false},
+		{"Long path truncate", longPath, WordKindPath, WordPolicyTruncate, longPath[:maxPathWordLength], true},
+		{"Valid host", "www.dev", WordKindHost, WordPolicySkip, "www.dev", true},
+		{"Long label skip", longLabel, WordKindHost, WordPolicySkip, "", false},
+		{"Long label encode", longLabel, WordKindHost, WordPolicyEncode, "", false},
+		{"Long label truncate", "www." + longLabel, WordKindHost, WordPolicyTruncate, "www." + longLabel[:maxLabelLength], true},
+		{"Whitespace truncate", "www dev", WordKindHost, WordPolicyTruncate, "www", true},
+		{"Unicode label", strings.Repeat("ä", 40), WordKindHost, WordPolicySkip, strings.Repeat("ä", 40), true},
+		{"Underscore label", "_dmarc", WordKindHost, WordPolicySkip, "_dmarc", true},
+		{"Underscore labels", "_sip._tcp", WordKindHost, WordPolicyTruncate, "_sip._tcp", true},
+		{"Underscore in label", "a_b", WordKindHost, WordPolicyEncode, "a_b", true},
+		{"Underscore unicode label", "_" + strings.Repeat("ä", 40), WordKindHost, WordPolicySkip, "_" + strings.Repeat("ä", 40), true},
+	}
+
+	for _, x := range tt {
+		x := x // NOTE: https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
+		t.Run(x.testName, func(t *testing.T) {
+			t.Parallel()
+			fixed, problem, ok := ApplyWordPolicy(x.word, x.kind, x.policy)
+			if ok != x.ok {
+				t.Fatalf("expected ok %t, got %t (%s)", x.ok, ok, problem)
+			}
+			if fixed != x.expected {
+				t.Fatalf("expected %q, got %q", x.expected, fixed)
+			}
+			if (problem == "") != (x.word == x.expected) {
+				t.Fatalf("unexpected problem %q", problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package libgobuster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunRecord is stored in the workspace for every finished run
+type RunRecord struct {
+	Mode     string
+	Target   string
+	Wordlist string
+	Start    time.Time
+	End      time.Time
+	Requests int
+	// StopReason is set if the run was stopped early
+	StopReason StopReason `json:",omitempty"`
+	// Results only contains the findings of the run
+	Results []ResultRecord
+}
+
+// SaveRun stores the run as a new file in the workspace directory
+func SaveRun(workspace string, run RunRecord) error {
+	if err := os.MkdirAll(workspace, 0o750); err != nil {
+		return fmt.Errorf("could not create workspace: %w", err)
+	}
+
+	content, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("run-%s-%s.json", run.Start.UTC().Format("20060102T150405"), uuid.New().String()[:8])
+	return os.WriteFile(filepath.Join(workspace, name), content, 0o600)
+}
+
+// LoadRuns reads all runs of the workspace ordered by their start time
+func LoadRuns(workspace string) ([]RunRecord, error) {
+	files, err := filepath.Glob(filepath.Join(workspace, "run-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]RunRecord, 0, len(files))
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var run RunRecord
+		if err := json.Unmarshal(content, &run); err != nil {
+			return nil, fmt.Errorf("invalid run file %s: %w", f, err)
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Start.Before(runs[j].Start)
+	})
+	return runs, nil
+}
+
+// Word returns the word of the wordlist which most likely produced the result
+func (r RunRecord) Word(record ResultRecord) string {
+	word := strings.Trim(record.Path, "/")
+	// dns and vhost results contain the domain
+	if r.Target != "" {
+		word = strings.TrimSuffix(word, "."+r.Target)
+	}
+	return word
+}
+
+// DayStats holds the runs and findings of a single day
+type DayStats struct {
+	Day      string
+	Runs     int
+	Findings int
+}
+
+// TargetStats holds the coverage of a single target
+type TargetStats struct {
+	Target      string
+	Runs        int
+	Requests    int
+	Findings    int
+	UniquePaths int
+	LastRun     time.Time
+}
+
+// CountStats is a value and how often it occurred
+type CountStats struct {
+	Value string
+	Count int
+}
+
+// WordStats holds how productive a single word was
+type WordStats struct {
+	Word string
+	// Runs is the number of runs the word produced a finding in
+	Runs int
+	// Targets is the number of distinct targets the word produced a finding on
+	Targets int
+}
+
+// WordlistStats holds the hit rate of a wordlist
+type WordlistStats struct {
+	Wordlist string
+	Runs     int
+	Requests int
+	Findings int
+}
+
+// HitRate returns the findings per request in percent
+func (w WordlistStats) HitRate() float64 {
+	if w.Requests == 0 {
+		return 0
+	}
+	return float64(w.Findings) * 100 / float64(w.Requests)
+}
+
+// WorkspaceStats are the aggregated statistics of all runs in a workspace
+type WorkspaceStats struct {
+	Runs        int
+	Findings    int
+	Days        []DayStats
+	Targets     []TargetStats
+	StatusCodes []CountStats
+	Wordlists   []WordlistStats
+	// Words contains every word which produced a finding, most productive first
+	Words []WordStats
+}
+
+// WordHitRate returns the percentage of runs the word produced a finding in
+func (s WorkspaceStats) WordHitRate(w WordStats) float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(w.Runs) * 100 / float64(s.Runs)
+}
+
+// ComputeStats aggregates the runs
+func ComputeStats(runs []RunRecord) WorkspaceStats {
+	var stats WorkspaceStats
+
+	days := make(map[string]*DayStats)
+	targets := make(map[string]*TargetStats)
+	targetPaths := make(map[string]*Set[string])
+	statusCodes := make(map[string]int)
+	wordlists := make(map[string]*WordlistStats)
+	words := make(map[string]*WordStats)
+	wordTargets := make(map[string]*Set[string])
+
+	for _, run := range runs {
+		stats.Runs++
+		stats.Findings += len(run.Results)
+
+		day := run.Start.Format("2006-01-02")
+		if days[day] == nil {
+			days[day] = &DayStats{Day: day}
+		}
+		days[day].Runs++
+		days[day].Findings += len(run.Results)
+
+		target := run.Target
+		if target == "" {
+			target = "unknown"
+		}
+		if targets[target] == nil {
+			targets[target] = &TargetStats{Target: target}
+			paths := NewSet[string]()
+			targetPaths[target] = &paths
+		}
+		t := targets[target]
+		t.Runs++
+		t.Requests += run.Requests
+		t.Findings += len(run.Results)
+		if run.Start.After(t.LastRun) {
+			t.LastRun = run.Start
+		}
+
+		if wordlists[run.Wordlist] == nil {
+			wordlists[run.Wordlist] = &WordlistStats{Wordlist: run.Wordlist}
+		}
+		w := wordlists[run.Wordlist]
+		w.Runs++
+		w.Requests += run.Requests
+		w.Findings += len(run.Results)
+
+		runWords := NewSet[string]()
+		for _, r := range run.Results {
+			targetPaths[target].Add(r.Path)
+			if r.StatusCode > 0 {
+				statusCodes[fmt.Sprintf("%d", r.StatusCode)]++
+			}
+			if word := run.Word(r); word != "" {
+				runWords.Add(word)
+			}
+		}
+		for word := range runWords.Set {
+			if words[word] == nil {
+				words[word] = &WordStats{Word: word}
+				t := NewSet[string]()
+				wordTargets[word] = &t
+			}
+			words[word].Runs++
+			wordTargets[word].Add(target)
+		}
+	}
+
+	for _, d := range days {
+		stats.Days = append(stats.Days, *d)
+	}
+	sort.Slice(stats.Days, func(i, j int) bool { return stats.Days[i].Day < stats.Days[j].Day })
+
+	for name, t := range targets {
+		t.UniquePaths = targetPaths[name].Length()
+		stats.Targets = append(stats.Targets, *t)
+	}
+	sort.Slice(stats.Targets, func(i, j int) bool {
+		if stats.Targets[i].Findings != stats.Targets[j].Findings {
+			return stats.Targets[i].Findings > stats.Targets[j].Findings
+		}
+		return stats.Targets[i].Target < stats.Targets[j].Target
+	})
+
+	for _, w := range wordlists {
+		stats.Wordlists = append(stats.Wordlists, *w)
+	}
+	sort.Slice(stats.Wordlists, func(i, j int) bool {
+		if stats.Wordlists[i].HitRate() != stats.Wordlists[j].HitRate() {
+			return stats.Wordlists[i].HitRate() > stats.Wordlists[j].HitRate()
+		}
+		return stats.Wordlists[i].Wordlist < stats.Wordlists[j].Wordlist
+	})
+
+	for word, w := range words {
+		w.Targets = wordTargets[word].Length()
+		stats.Words = append(stats.Words, *w)
+	}
+	sort.Slice(stats.Words, func(i, j int) bool { return stats.Words[i].less(stats.Words[j]) })
+
+	stats.StatusCodes = sortedCounts(statusCodes)
+
+	return stats
+}
+
+// less orders words by the number of runs and targets they were productive in
+func (w WordStats) less(other WordStats) bool {
+	if w.Runs != other.Runs {
+		return w.Runs > other.Runs
+	}
+	if w.Targets != other.Targets {
+		return w.Targets > other.Targets
+	}
+	return w.Word < other.Word
+}
+
+// sortedCounts returns the counts ordered by the count and the value
+func sortedCounts(counts map[string]int) []CountStats {
+	ret := make([]CountStats, 0, len(counts))
+	for value, count := range counts {
+		ret = append(ret, CountStats{Value: value, Count: count})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Count != ret[j].Count {
+			return ret[i].Count > ret[j].Count
+		}
+		return ret[i].Value < ret[j].Value
+	})
+	return ret
+}
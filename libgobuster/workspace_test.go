@@ -0,0 +1,74 @@
+package libgobuster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkspaceRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	start := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	runs := []RunRecord{
+		{Mode: "directory enumeration", Target: "http://b.example.com", Wordlist: "common.txt", Start: start.Add(time.Hour), Requests: 100},
+		{Mode: "directory enumeration", Target: "http://a.example.com", Wordlist: "common.txt", Start: start, Requests: 50,
+			Results: []ResultRecord{{Path: "/admin", Found: true, StatusCode: 301}}},
+	}
+	for _, r := range runs {
+		if err := SaveRun(workspace, r); err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+	}
+
+	loaded, err := LoadRuns(workspace)
+	if err != nil {
+		t.Fatalf("Got error: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(loaded))
+	}
+	if loaded[0].Target != "http://a.example.com" || len(loaded[0].Results) != 1 {
+		t.Fatalf("expected the runs to be ordered by start time, got %+v", loaded[0])
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	t.Parallel()
+
+	day1 := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	runs := []RunRecord{
+		{Target: "http://a.example.com", Wordlist: "small.txt", Start: day1, Requests: 10, Results: []ResultRecord{
+			{Path: "/admin", StatusCode: 301},
+			{Path: "/login", StatusCode: 200},
+		}},
+		{Target: "http://a.example.com", Wordlist: "big.txt", Start: day2, Requests: 100, Results: []ResultRecord{
+			{Path: "/admin", StatusCode: 301},
+		}},
+		{Target: "example.com", Wordlist: "subdomains.txt", Start: day2, Requests: 10, Results: []ResultRecord{
+			{Path: "admin.example.com"},
+		}},
+	}
+
+	stats := ComputeStats(runs)
+	if stats.Runs != 3 || stats.Findings != 4 {
+		t.Fatalf("expected 3 runs and 4 findings, got %d and %d", stats.Runs, stats.Findings)
+	}
+	if len(stats.Days) != 2 || stats.Days[1].Runs != 2 || stats.Days[1].Findings != 2 {
+		t.Fatalf("unexpected findings over time %+v", stats.Days)
+	}
+	if stats.Targets[0].Target != "http://a.example.com" || stats.Targets[0].Runs != 2 || stats.Targets[0].UniquePaths != 2 || !stats.Targets[0].LastRun.Equal(day2) {
+		t.Fatalf("unexpected target stats %+v", stats.Targets[0])
+	}
+	if stats.StatusCodes[0] != (CountStats{Value: "301", Count: 2}) {
+		t.Fatalf("unexpected status codes %+v", stats.StatusCodes)
+	}
+	if stats.Wordlists[0].Wordlist != "small.txt" || stats.Wordlists[0].HitRate() != 20 {
+		t.Fatalf("expected small.txt to have the best hit rate, got %+v", stats.Wordlists)
+	}
+	// the dns result is reduced to the word of the wordlist
+	if stats.Words[0] != (WordStats{Word: "admin", Runs: 3, Targets: 2}) {
+		t.Fatalf("unexpected words %+v", stats.Words)
+	}
+}